@@ -0,0 +1,100 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// actionsInstructions asks the model for its next steps as a parseable
+// block
+const actionsInstructions = "After your answer, list the recommended next actions, most important first, in a fenced block tagged `actions` holding a JSON array:\n\n" +
+	"```actions\n" +
+	`[{"command": "<shell command to run, or empty if the step is not a command>", "description": "<what the step does and why>", "risk": "<low, medium, or high>"}]` + "\n" +
+	"```\n\n" +
+	"Risk is high for anything destructive or hard to undo, medium for changes that are easy to revert, and low for read-only steps."
+
+// Action risks
+const (
+	riskLow     = "low"
+	riskMedium  = "medium"
+	riskHigh    = "high"
+	riskUnknown = "unknown"
+)
+
+// Action is one recommended next step parsed from --actions
+type Action struct {
+	Command     string `json:"command,omitempty"`
+	Description string `json:"description"`
+	Risk        string `json:"risk"` // low, medium, high, or unknown
+}
+
+// parseActions finds the last actions block in an answer and returns the
+// answer without it. ok is false when there is no block or it is not a
+// list of actions.
+func parseActions(answer string) (body string, actions []Action, ok bool) {
+	lines := strings.Split(answer, "\n")
+	start, end := -1, -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		if strings.TrimSpace(lines[i]) == "```actions" {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return answer, nil, false
+	}
+	for i := start + 1; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return answer, nil, false
+	}
+
+	var parsed []Action
+	if err := json.Unmarshal([]byte(strings.Join(lines[start+1:end], "\n")), &parsed); err != nil {
+		return answer, nil, false
+	}
+	actions = []Action{}
+	for _, a := range parsed {
+		a.Command = strings.TrimSpace(a.Command)
+		a.Description = strings.TrimSpace(a.Description)
+		if a.Command == "" && a.Description == "" {
+			continue
+		}
+		switch a.Risk = strings.ToLower(strings.TrimSpace(a.Risk)); a.Risk {
+		case riskLow, riskMedium, riskHigh:
+		default:
+			a.Risk = riskUnknown
+		}
+		actions = append(actions, a)
+	}
+
+	before := strings.TrimRight(strings.Join(lines[:start], "\n"), "\n ")
+	after := strings.TrimSpace(strings.Join(lines[end+1:], "\n"))
+	if after == "" {
+		return before, actions, true
+	}
+	return before + "\n\n" + after, actions, true
+}
+
+// writeActions renders the actions, numbered, below a plain-text answer
+func writeActions(w io.Writer, actions []Action) {
+	if len(actions) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "\n---\nNext steps:")
+	for i, a := range actions {
+		_, _ = fmt.Fprintf(w, "  %d. [%s] %s\n", i+1, a.Risk, orDefault(a.Description, a.Command))
+		if a.Command != "" && a.Description != "" {
+			_, _ = fmt.Fprintf(w, "     $ %s\n", a.Command)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultCacheMaxMB bounds the response cache unless ask.yaml sets cache_max_mb
+const defaultCacheMaxMB = 100
+
+// cacheGCInterval spaces out the eviction scans that writes start, so the
+// cache may outgrow its limit for up to this long
+const cacheGCInterval = time.Hour
+
+// responseCache stores answers keyed by provider, model, and prompt. Entries
+// are sharded by the first byte of their key and evicted least recently
+// used first once the cache outgrows maxBytes.
+type responseCache struct {
+	dir      string
+	maxBytes int64
+}
+
+type cachedResponse struct {
+	Template string    `json:"template,omitempty"`
+	Answer   string    `json:"answer"`
+	Created  time.Time `json:"created"`
+}
+
+// cacheStats summarizes the response cache
+type cacheStats struct {
+	Entries  int   `json:"entries"`
+	Bytes    int64 `json:"bytes"`
+	MaxBytes int64 `json:"max_bytes"`
+	Shards   int   `json:"shards"`
+}
+
+func newResponseCache() *responseCache {
+	maxMB := defaultCacheMaxMB
+	if c, err := loadConfig(); err == nil && c.CacheMaxMB > 0 {
+		maxMB = c.CacheMaxMB
+	}
+	return &responseCache{
+		dir:      filepath.Join(cacheDir(), "responses"),
+		maxBytes: int64(maxMB) << 20,
+	}
+}
+
+// responseKey identifies an answer; any change to the prompt, routing, or
+// generation settings misses
+func responseKey(c *BridgeClient, prompt string) string {
+	temp := ""
+	if c.temperature != nil {
+		temp = strconv.FormatFloat(*c.temperature, 'f', -1, 64)
+	}
+	settings := strings.Join([]string{c.provider, c.model, strconv.Itoa(c.maxTokens), temp}, "\x00")
+	if c.thinking != "" {
+		settings += "\x00thinking=" + c.thinking
+	}
+	sum := sha256.Sum256([]byte(settings + "\x00" + c.folded(prompt)))
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *responseCache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// get returns a cached answer and marks it recently used
+func (c *responseCache) get(key string) (string, bool) {
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	var r cachedResponse
+	if json.Unmarshal(data, &r) != nil {
+		_ = os.Remove(path)
+		return "", false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return r.Answer, true
+}
+
+// put stores an answer and evicts old entries if the cache is over size;
+// like the template cache it is best effort
+func (c *responseCache) put(key, template, answer string) {
+	if stateless {
+		return
+	}
+	data, err := json.Marshal(cachedResponse{Template: template, Answer: answer, Created: time.Now()})
+	if err != nil {
+		return
+	}
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return
+	}
+	tmp := path + ".tmp"
+	if os.WriteFile(tmp, data, 0o600) != nil || os.Rename(tmp, path) != nil {
+		return
+	}
+	if c.gcDue() {
+		_, _, _ = c.gc()
+	}
+}
+
+// gcDue reports whether cacheGCInterval has passed since the last
+// eviction scan a write started, and if so marks one as started now
+func (c *responseCache) gcDue() bool {
+	stamp := filepath.Join(c.dir, ".gc")
+	if info, err := os.Stat(stamp); err == nil && time.Since(info.ModTime()) < cacheGCInterval {
+		return false
+	}
+	return os.WriteFile(stamp, nil, 0o600) == nil
+}
+
+type cacheEntry struct {
+	path string
+	size int64
+	used time.Time
+}
+
+// entries lists every cached answer
+func (c *responseCache) entries() ([]cacheEntry, int, error) {
+	shards, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	var (
+		out    []cacheEntry
+		nShard int
+	)
+	for _, s := range shards {
+		if !s.IsDir() {
+			continue
+		}
+		nShard++
+		files, err := os.ReadDir(filepath.Join(c.dir, s.Name()))
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, f := range files {
+			info, err := f.Info()
+			if err != nil || f.IsDir() || filepath.Ext(f.Name()) != ".json" {
+				continue
+			}
+			out = append(out, cacheEntry{filepath.Join(c.dir, s.Name(), f.Name()), info.Size(), info.ModTime()})
+		}
+	}
+	return out, nShard, nil
+}
+
+func (c *responseCache) stats() (cacheStats, error) {
+	entries, shards, err := c.entries()
+	st := cacheStats{Entries: len(entries), MaxBytes: c.maxBytes, Shards: shards}
+	for _, e := range entries {
+		st.Bytes += e.size
+	}
+	return st, err
+}
+
+// gc evicts least recently used answers until the cache fits in maxBytes.
+// It returns how many entries and bytes it removed.
+func (c *responseCache) gc() (int, int64, error) {
+	entries, _, err := c.entries()
+	if err != nil {
+		return 0, 0, err
+	}
+	var total int64
+	for _, e := range entries {
+		total += e.size
+	}
+	if total <= c.maxBytes {
+		return 0, 0, nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].used.Before(entries[j].used) })
+	var (
+		removed int
+		freed   int64
+	)
+	for _, e := range entries {
+		if total-freed <= c.maxBytes {
+			break
+		}
+		if os.Remove(e.path) == nil {
+			removed++
+			freed += e.size
+		}
+	}
+	return removed, freed, nil
+}
+
+// clear removes every cached answer
+func (c *responseCache) clear() error {
+	return os.RemoveAll(c.dir)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+func newCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the response cache",
+		Long: `Answers to identical prompts (same provider, model, and prompt text) are
+reused from a cache in the state directory. The cache is sharded by key and
+kept under cache_max_mb from ask.yaml (default 100) by evicting the least
+recently used answers. Templates opt out with "cache: false"; a single run
+bypasses it with --no-cache.`,
+	}
+	cmd.AddCommand(newCacheStatsCmd(), newCacheClearCmd(), newCacheGCCmd())
+	return cmd
+}
+
+func newCacheStatsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "stats",
+		Short: "Show response cache size and usage",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			c := newResponseCache()
+			st, err := c.stats()
+			if err != nil {
+				return errors.NewCLIError("read response cache").WithCause(err)
+			}
+			w := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(w, "Location: %s\n", c.dir)
+			_, _ = fmt.Fprintf(w, "Entries:  %d in %d shards\n", st.Entries, st.Shards)
+			_, _ = fmt.Fprintf(w, "Size:     %s of %s\n", formatMB(st.Bytes), formatMB(st.MaxBytes))
+			return nil
+		},
+	}
+}
+
+func newCacheClearCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "clear",
+		Short: "Remove every cached answer",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := refuseStateless("cache clear"); err != nil {
+				return err
+			}
+			if err := newResponseCache().clear(); err != nil {
+				return errors.NewCLIError("clear response cache").WithCause(err)
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Response cache cleared")
+			return nil
+		},
+	}
+}
+
+func newCacheGCCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "gc",
+		Short: "Evict least recently used answers until the cache fits its size limit",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := refuseStateless("cache gc"); err != nil {
+				return err
+			}
+			removed, freed, err := newResponseCache().gc()
+			if err != nil {
+				return errors.NewCLIError("garbage-collect response cache").WithCause(err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Evicted %d entries (%s)\n", removed, formatMB(freed))
+			return nil
+		},
+	}
+}
+
+func formatMB(n int64) string {
+	return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+}
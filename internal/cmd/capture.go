@@ -0,0 +1,196 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Capture filter modes
+const (
+	captureSmart  = "smart"  // score lines, keep signal, drop noise
+	captureTail   = "tail"   // last N lines, unfiltered
+	captureErrors = "errors" // only error lines and their surroundings
+)
+
+// captureScrollback is how much more history smart filtering scans than it keeps
+const captureScrollback = 4
+
+// captureFilter selects which pane lines fit the --lines budget.
+// The zero value is the smart filter.
+type captureFilter struct {
+	mode string
+	keep *regexp.Regexp
+	drop *regexp.Regexp
+}
+
+var (
+	errorLinePattern    = regexp.MustCompile(`(?i)\b(error|fatal|panic|exception|traceback|failed|failure|segmentation fault|denied|refused|timed? ?out)\b|exit (status|code):? ?[1-9]`)
+	warnLinePattern     = regexp.MustCompile(`(?i)\b(warn(ing)?|deprecated|retry(ing)?)\b`)
+	stackLinePattern    = regexp.MustCompile(`^\s+at |^\s+File "|^goroutine \d+|\.(go|py|js|ts|java|rb|rs):\d+|^\s+\S+\(.*\)$`)
+	promptLinePattern   = regexp.MustCompile(`^\S*[$#%❯›»] \S`)
+	progressLinePattern = regexp.MustCompile(`[=#>\-]{8,}|[█▓▒░]{3,}|\d{1,3}(\.\d+)?%|[⠋⠙⠹⠸⠼⠴⠦⠧⠇⠏]|\r`)
+	digitsPattern       = regexp.MustCompile(`\d+`)
+)
+
+// parseCaptureFilter parses --capture-filter: a mode optionally followed by
+// comma-separated keep=REGEX and drop=REGEX tuning options
+func parseCaptureFilter(spec string) (captureFilter, error) {
+	var f captureFilter
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, hasValue := strings.Cut(part, "=")
+		if !hasValue {
+			switch part {
+			case captureSmart, captureTail, captureErrors:
+				f.mode = part
+				continue
+			}
+		}
+
+		var err error
+		switch key {
+		case "keep":
+			f.keep, err = regexp.Compile(value)
+		case "drop":
+			f.drop, err = regexp.Compile(value)
+		default:
+			return f, errors.NewCLIError(fmt.Sprintf("invalid --capture-filter option %q", part)).
+				WithSuggestions("Modes: smart, tail, errors", "Tuning: smart,keep=REGEX,drop=REGEX")
+		}
+		if err != nil {
+			return f, errors.NewCLIError(fmt.Sprintf("invalid --capture-filter pattern %q", value)).WithCause(err)
+		}
+	}
+	return f, nil
+}
+
+// scrollback returns how many lines to capture for a budget
+func (f captureFilter) scrollback(budget int) int {
+	if f.mode == captureTail {
+		return budget
+	}
+	return budget * captureScrollback
+}
+
+// apply reduces captured pane content to at most budget lines
+func (f captureFilter) apply(content string, budget int) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if f.mode == captureTail || budget <= 0 {
+		if budget > 0 && len(lines) > budget {
+			lines = lines[len(lines)-budget:]
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	scores := f.score(lines)
+
+	// Pick the highest scoring lines, favoring recent ones on ties
+	idx := make([]int, 0, len(lines))
+	for i, s := range scores {
+		if s > 0 {
+			idx = append(idx, i)
+		}
+	}
+	sort.SliceStable(idx, func(a, b int) bool {
+		if scores[idx[a]] != scores[idx[b]] {
+			return scores[idx[a]] > scores[idx[b]]
+		}
+		return idx[a] > idx[b]
+	})
+	if len(idx) > budget {
+		idx = idx[:budget]
+	}
+	sort.Ints(idx)
+
+	var b strings.Builder
+	prev := -1
+	for _, i := range idx {
+		if gap := i - prev - 1; gap > 0 {
+			_, _ = fmt.Fprintf(&b, "[... %d lines omitted]\n", gap)
+		}
+		b.WriteString(lines[i])
+		b.WriteString("\n")
+		prev = i
+	}
+	if gap := len(lines) - prev - 1; gap > 0 && prev >= 0 {
+		_, _ = fmt.Fprintf(&b, "[... %d lines omitted]\n", gap)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// score rates each line; lines scoring <= 0 are never kept
+func (f captureFilter) score(lines []string) []int {
+	scores := make([]int, len(lines))
+	important := make([]bool, len(lines))
+	prevNorm := ""
+
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		norm := digitsPattern.ReplaceAllString(trimmed, "#")
+		repeated := norm == prevNorm
+		prevNorm = norm
+
+		switch {
+		case trimmed == "":
+			scores[i] = 0
+			continue
+		case f.drop != nil && f.drop.MatchString(line):
+			scores[i] = -100
+			continue
+		case f.keep != nil && f.keep.MatchString(line):
+			scores[i] = 100
+			important[i] = true
+			continue
+		}
+
+		s := 1
+		switch {
+		case errorLinePattern.MatchString(line):
+			s += 10
+			important[i] = true
+		case stackLinePattern.MatchString(line):
+			s += 8
+			important[i] = true
+		case warnLinePattern.MatchString(line):
+			s += 5
+		case promptLinePattern.MatchString(line):
+			s += 3
+		}
+		if progressLinePattern.MatchString(line) && !important[i] {
+			s -= 5
+		}
+		if repeated && !important[i] {
+			s -= 10
+		}
+		if f.mode == captureErrors && !important[i] {
+			scores[i] = 0
+			continue
+		}
+		// Recency bonus: later output is usually more relevant
+		s += 3 * i / max(len(lines), 1)
+		scores[i] = s
+	}
+
+	// Keep a little context around important lines
+	for i := range lines {
+		if !important[i] {
+			continue
+		}
+		for _, j := range []int{i - 2, i - 1, i + 1, i + 2} {
+			if j >= 0 && j < len(lines) && scores[j] > -5 && strings.TrimSpace(lines[j]) != "" {
+				scores[j] += 4
+			}
+		}
+	}
+	return scores
+}
@@ -0,0 +1,241 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+const chatHelp = `Commands:
+  /branch NAME   Fork the conversation here and continue on the branch
+  /compact       Summarize all but the most recent turns now
+  /switch ID     Continue another session or branch
+  /copy N        Copy code block N of the last answer to the clipboard
+  /save N FILE   Save code block N to FILE
+  /apply N       Apply code block N as a patch with git apply (asks first)
+  /run N         Run code block N in the shell (asks first)
+  /help          Show this help
+  /exit          Leave chat (also Ctrl-D)`
+
+func newChatCmd(client *BridgeClient) *cobra.Command {
+	var (
+		sessionID    string
+		contextFiles []string
+		budget       sessionBudget
+	)
+
+	cmd := &cobra.Command{
+		Use:   "chat",
+		Short: "Interactive conversation with saved, branchable sessions",
+		Long: `Start an interactive conversation. Every turn is saved to a session in
+` + defaultSessionDir + `, so a chat can be continued later with --session.
+
+Use /branch NAME to fork the conversation at the current point and explore
+an alternative without losing the original thread; view the result with
+arc-ask sessions tree ID.
+
+Code blocks in the last answer can be used directly: /copy N, /save N
+FILE, /apply N (a diff, through git apply), and /run N (a shell command).
+Applying and running always ask first.
+
+When the conversation outgrows its token budget (by default three
+quarters of the model's context window), older turns are summarized into
+a rolling summary that is sent in their place, while the most recent
+turns are kept verbatim. The saved session keeps every turn.`,
+		Example: `  arc-ask chat --session debug-auth
+  arc-ask chat --session debug-auth --context auth.go
+  arc-ask sessions tree debug-auth`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := NewSessionStore()
+
+			var (
+				sess *Session
+				err  error
+			)
+			if sessionID != "" && store.Exists(sessionID) {
+				sess, err = store.Load(sessionID)
+			} else {
+				sess, err = newSession(sessionID)
+			}
+			if err != nil {
+				return err
+			}
+
+			if len(contextFiles) > 0 {
+				ctxText, _, err := mergeContext("", contextFiles, contextOptions{})
+				if err != nil {
+					return err
+				}
+				sess.Append(RoleUser, "Use the following files as context for this conversation."+ctxText)
+			}
+
+			if budget.keepLast < 0 || budget.summaryMaxTokens < 0 || budget.tokens < 0 {
+				return errors.NewCLIError("--keep-last, --summary-tokens, and --budget-tokens cannot be negative")
+			}
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			budget = newSessionBudget(client, cfg.Chat, budget)
+
+			repl := &chatREPL{client: client, store: store, sess: sess, budget: budget, out: cmd.OutOrStdout()}
+			return repl.run(cmd.InOrStdin())
+		},
+	}
+
+	cmd.Flags().StringVar(&sessionID, "session", "", "Session to create or continue")
+	cmd.Flags().StringArrayVarP(&contextFiles, "context", "c", nil, "Add context file(s)")
+	cmd.Flags().IntVar(&budget.keepLast, "keep-last", 0, "Recent turns always sent verbatim (default 6)")
+	cmd.Flags().IntVar(&budget.summaryMaxTokens, "summary-tokens", 0, "Longest summary of older turns, in tokens (default 1000)")
+	cmd.Flags().IntVar(&budget.tokens, "budget-tokens", 0, "Prompt size that triggers summarizing older turns (default: 3/4 of the context window)")
+	return cmd
+}
+
+// chatREPL is an interactive conversation bound to a session
+type chatREPL struct {
+	client *BridgeClient
+	store  *SessionStore
+	sess   *Session
+	budget sessionBudget
+	out    io.Writer
+}
+
+func (r *chatREPL) run(in io.Reader) error {
+	_, _ = fmt.Fprintf(r.out, "Session %s (%d turns). Type /help for commands.\n", r.sess.ID, len(r.sess.Turns))
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for {
+		_, _ = fmt.Fprint(r.out, "> ")
+		if !scanner.Scan() {
+			_, _ = fmt.Fprintln(r.out)
+			return scanner.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "/") {
+			quit, err := r.command(line)
+			if err != nil {
+				_, _ = fmt.Fprintf(r.out, "error: %v\n", err)
+			}
+			if quit {
+				return nil
+			}
+			continue
+		}
+
+		if err := r.ask(line); err != nil {
+			_, _ = fmt.Fprintf(r.out, "error: %v\n", err)
+		}
+	}
+}
+
+// command handles a slash command and reports whether chat should end
+func (r *chatREPL) command(line string) (bool, error) {
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch name {
+	case "/exit", "/quit":
+		return true, nil
+	case "/help":
+		_, _ = fmt.Fprintln(r.out, chatHelp)
+	case "/branch":
+		if arg == "" {
+			return false, errors.NewCLIError("usage: /branch NAME")
+		}
+		// Persist the current point so the parent exists in the tree
+		if err := r.store.Save(r.sess); err != nil {
+			return false, err
+		}
+		branch, err := r.store.Branch(r.sess, arg)
+		if err != nil {
+			return false, err
+		}
+		_, _ = fmt.Fprintf(r.out, "Branched %s at turn %d. Now on %s.\n", r.sess.ID, branch.ForkedAt, branch.ID)
+		r.sess = branch
+	case "/compact":
+		ctx, cancel := context.WithTimeout(context.Background(), r.client.timeout)
+		defer cancel()
+		n, err := r.budget.compact(ctx, r.client, r.sess)
+		if err != nil {
+			return false, err
+		}
+		if n == 0 {
+			_, _ = fmt.Fprintf(r.out, "Nothing to summarize: only the last %d turns are unsummarized.\n", r.budget.keepLast)
+			return false, nil
+		}
+		if err := r.store.Save(r.sess); err != nil {
+			return false, err
+		}
+		_, _ = fmt.Fprintf(r.out, "Summarized %d earlier turns.\n", n)
+	case "/switch":
+		if arg == "" {
+			return false, errors.NewCLIError("usage: /switch ID")
+		}
+		sess, err := r.store.Load(arg)
+		if err != nil {
+			return false, err
+		}
+		r.sess = sess
+		_, _ = fmt.Fprintf(r.out, "Now on %s (%d turns).\n", sess.ID, len(sess.Turns))
+	case "/copy":
+		return false, r.copyBlock(arg)
+	case "/save":
+		return false, r.saveBlock(arg)
+	case "/apply":
+		return false, r.applyBlock(arg)
+	case "/run":
+		return false, r.runBlock(arg)
+	default:
+		return false, errors.NewCLIError(fmt.Sprintf("unknown command %s", name)).
+			WithSuggestions("Type /help for commands")
+	}
+	return false, nil
+}
+
+func (r *chatREPL) ask(message string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), r.client.timeout)
+	defer cancel()
+
+	if !r.budget.fits(r.sess, message) {
+		n, err := r.budget.compact(ctx, r.client, r.sess)
+		if err != nil {
+			return errors.NewCLIError("conversation is over its token budget").
+				WithCause(err).
+				WithSuggestions("Retry, or start a branch with /branch NAME")
+		}
+		if n > 0 {
+			_, _ = fmt.Fprintf(r.out, "(Summarized %d earlier turns to stay within %s tokens)\n", n, formatTokens(r.budget.tokens))
+		}
+	}
+
+	answer, err := r.client.Ask(ctx, r.sess.Prompt(message))
+	if err != nil {
+		return errors.NewCLIError("AI query failed").WithCause(err)
+	}
+
+	r.sess.Append(RoleUser, message)
+	r.sess.Append(RoleAssistant, answer)
+	titleSession(r.client, r.sess)
+	if err := r.store.Save(r.sess); err != nil {
+		return err
+	}
+	_, _ = fmt.Fprintf(r.out, "\n%s\n\n", answer)
+	if hint := blockHint(answer); hint != "" {
+		_, _ = fmt.Fprintf(r.out, "%s\n\n", hint)
+	}
+	return nil
+}
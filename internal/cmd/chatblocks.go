@@ -0,0 +1,213 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// shellLangs are the code block languages /run accepts
+var shellLangs = map[string]bool{"": true, "sh": true, "bash": true, "zsh": true, "shell": true, "console": true}
+
+// clipboardCommands are tried in order by /copy; without any, the text is
+// sent to the terminal as an OSC 52 sequence
+var clipboardCommands = [][]string{
+	{"pbcopy"},
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+	{"clip.exe"},
+}
+
+// lastBlocks returns the code blocks of the latest answer in the session
+func (r *chatREPL) lastBlocks() []ask.CodeBlock {
+	for i := len(r.sess.Turns) - 1; i >= 0; i-- {
+		if r.sess.Turns[i].Role == RoleAssistant {
+			return ask.CodeBlocks(r.sess.Turns[i].Content)
+		}
+	}
+	return nil
+}
+
+// block resolves the N in /copy N and friends; N may be left out when the
+// answer has a single block. It returns the rest of the arguments.
+func (r *chatREPL) block(usage, arg string) (ask.CodeBlock, string, error) {
+	blocks := r.lastBlocks()
+	if len(blocks) == 0 {
+		return ask.CodeBlock{}, "", errors.NewCLIError("the last answer has no code blocks")
+	}
+	first, rest, _ := strings.Cut(arg, " ")
+	n, err := strconv.Atoi(first)
+	if err != nil {
+		if len(blocks) > 1 {
+			return ask.CodeBlock{}, "", errors.NewCLIError("usage: " + usage).
+				WithSuggestions(fmt.Sprintf("The last answer has %d code blocks", len(blocks)))
+		}
+		n, rest = 1, arg
+	}
+	if n < 1 || n > len(blocks) {
+		return ask.CodeBlock{}, "", errors.NewCLIError(fmt.Sprintf("no code block %d (the last answer has %d)", n, len(blocks)))
+	}
+	return blocks[n-1], strings.TrimSpace(rest), nil
+}
+
+// blockHint lists an answer's code blocks after it is printed
+func blockHint(answer string) string {
+	blocks := ask.CodeBlocks(answer)
+	if len(blocks) == 0 {
+		return ""
+	}
+	var names []string
+	for i, b := range blocks {
+		name := strconv.Itoa(i + 1)
+		if b.Lang != "" {
+			name += " " + b.Lang
+		}
+		names = append(names, name)
+	}
+	return fmt.Sprintf("(code blocks: %s; /copy, /save, /apply, or /run N)", strings.Join(names, ", "))
+}
+
+func (r *chatREPL) copyBlock(arg string) error {
+	b, _, err := r.block("/copy N", arg)
+	if err != nil {
+		return err
+	}
+	return copyToClipboard(r.out, b.Code)
+}
+
+// copyToClipboard copies text with the first clipboard command found, else
+// through the terminal w, and says which
+func copyToClipboard(w io.Writer, text string) error {
+	for _, c := range clipboardCommands {
+		if _, err := exec.LookPath(c[0]); err != nil {
+			continue
+		}
+		cmd := execCommand(c[0], c[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		if err := cmd.Run(); err != nil {
+			return errors.NewCLIError("copy failed").WithCause(err)
+		}
+		_, _ = fmt.Fprintln(w, "Copied to the clipboard.")
+		return nil
+	}
+	// OSC 52 reaches the local clipboard over ssh and through tmux
+	_, _ = fmt.Fprintf(w, "\x1b]52;c;%s\x07", base64.StdEncoding.EncodeToString([]byte(text)))
+	_, _ = fmt.Fprintln(w, "Sent to the terminal clipboard (OSC 52).")
+	return nil
+}
+
+func (r *chatREPL) saveBlock(arg string) error {
+	b, path, err := r.block("/save N FILE", arg)
+	if err != nil {
+		return err
+	}
+	if path == "" {
+		return errors.NewCLIError("usage: /save N FILE")
+	}
+	path = ask.ExpandHome(path)
+	if fileExists(path) {
+		ok, err := confirm(fmt.Sprintf("%s exists. Overwrite it?", path))
+		if err != nil || !ok {
+			return errors.NewCLIError("not saved")
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(b.Code), 0o644); err != nil {
+		return errors.NewCLIError("save failed").WithCause(err)
+	}
+	_, _ = fmt.Fprintf(r.out, "Saved to %s.\n", path)
+	return nil
+}
+
+func (r *chatREPL) applyBlock(arg string) error {
+	b, _, err := r.block("/apply N", arg)
+	if err != nil {
+		return err
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return errors.NewCLIError("/apply needs git")
+	}
+	patch := strings.TrimRight(b.Code, "\n") + "\n"
+	git := func(args ...string) (string, error) {
+		cmd := execCommand("git", append([]string{"apply", "--recount"}, args...)...)
+		cmd.Stdin = strings.NewReader(patch)
+		out, err := cmd.CombinedOutput()
+		return strings.TrimSpace(string(out)), err
+	}
+	if out, err := git("--check", "-"); err != nil {
+		return errors.NewCLIError("the block does not apply").WithCause(fmt.Errorf("%s", out))
+	}
+	stat, _ := git("--stat", "-")
+	ok, err := confirm(fmt.Sprintf("%s\nApply this patch?", stat))
+	if err != nil || !ok {
+		return errors.NewCLIError("not applied")
+	}
+	if out, err := git("-"); err != nil {
+		return errors.NewCLIError("git apply failed").WithCause(fmt.Errorf("%s", out))
+	}
+	_, _ = fmt.Fprintln(r.out, "Applied.")
+	return nil
+}
+
+func (r *chatREPL) runBlock(arg string) error {
+	b, _, err := r.block("/run N", arg)
+	if err != nil {
+		return err
+	}
+	if !shellLangs[b.Lang] {
+		return errors.NewCLIError(fmt.Sprintf("the block is %s, not a shell command", b.Lang))
+	}
+	script := shellScript(b.Code)
+	ok, err := confirm(fmt.Sprintf("Run this in %s?\n\n%s\n", currentDir(), indent(script, "    ")))
+	if err != nil || !ok {
+		return errors.NewCLIError("not run")
+	}
+	cmd := execCommand("sh", "-c", script)
+	cmd.Stdout, cmd.Stderr = r.out, r.out
+	if err := cmd.Run(); err != nil {
+		return errors.NewCLIError("command failed").WithCause(err)
+	}
+	return nil
+}
+
+// shellScript drops the "$ " prompts of a console transcript, and its
+// output lines, leaving the commands
+func shellScript(code string) string {
+	lines := strings.Split(strings.TrimRight(code, "\n"), "\n")
+	var prompted []string
+	for _, l := range lines {
+		if cmd, ok := strings.CutPrefix(strings.TrimLeft(l, " "), "$ "); ok {
+			prompted = append(prompted, cmd)
+		}
+	}
+	if len(prompted) > 0 {
+		return strings.Join(prompted, "\n")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func indent(text, prefix string) string {
+	return prefix + strings.ReplaceAll(text, "\n", "\n"+prefix)
+}
+
+func currentDir() string {
+	dir, err := os.Getwd()
+	if err != nil {
+		return "the current directory"
+	}
+	return dir
+}
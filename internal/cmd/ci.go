@@ -0,0 +1,183 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Output formats handled by arc-ask itself rather than the SDK
+const (
+	outputGitHubAnnotations = "github-annotations"
+	outputJUnit             = "junit"
+	outputSARIF             = "sarif"
+	outputReview            = "review"
+	outputReviewJSON        = "review-json"
+)
+
+// reportFormat is an arc-ask specific --output value
+type reportFormat struct {
+	// instructions are appended to the prompt so findings can be parsed
+	instructions string
+	write        func(w io.Writer, r report) error
+}
+
+// reportFormats maps arc-ask specific --output values to their writers
+var reportFormats = map[string]reportFormat{
+	outputGitHubAnnotations: {instructions: findingInstructions, write: writeGitHubAnnotations},
+	outputJUnit:             {instructions: findingInstructions, write: writeJUnit},
+	outputSARIF:             {instructions: structuredFindingInstructions, write: writeSARIF},
+	outputReview:            {instructions: reviewInstructions, write: writeReviewDiff},
+	outputReviewJSON:        {instructions: reviewInstructions, write: writeReviewJSON},
+	outputLocations:         {instructions: citationInstructions, write: writeLocations},
+}
+
+// report is a verdict-style answer prepared for machine consumption
+type report struct {
+	Name     string
+	Answer   string
+	Verdict  string
+	Findings []Finding
+	Diff     string // the reviewed diff, for the review formats
+}
+
+func newReport(name, answer string) report {
+	verdict, _ := parseVerdict(answer)
+	return report{
+		Name:     name,
+		Answer:   answer,
+		Verdict:  verdict,
+		Findings: parseFindings(answer),
+	}
+}
+
+// requestedReportFormat returns the --output value when it names an
+// arc-ask report format, or "" to defer to the SDK output options
+func requestedReportFormat(cmd *cobra.Command) string {
+	flag := cmd.Flags().Lookup("output")
+	if flag == nil {
+		return ""
+	}
+	if _, ok := reportFormats[flag.Value.String()]; ok {
+		return flag.Value.String()
+	}
+	return ""
+}
+
+// writeGitHubAnnotations emits GitHub Actions workflow commands
+func writeGitHubAnnotations(w io.Writer, r report) error {
+	for _, f := range r.Findings {
+		var props []string
+		if f.File != "" {
+			props = append(props, "file="+escapeAnnotationProperty(f.File))
+			if f.Line > 0 {
+				props = append(props, fmt.Sprintf("line=%d", f.Line))
+			}
+		}
+		props = append(props, "title="+escapeAnnotationProperty(r.Name))
+		if _, err := fmt.Fprintf(w, "::%s %s::%s\n", f.Severity, strings.Join(props, ","), escapeAnnotationData(f.Message)); err != nil {
+			return err
+		}
+	}
+
+	if len(r.Findings) > 0 {
+		return nil
+	}
+
+	// No located findings: surface the whole answer at verdict level
+	level := SeverityNotice
+	switch r.Verdict {
+	case VerdictFail:
+		level = SeverityError
+	case VerdictWarn:
+		level = SeverityWarning
+	}
+	_, err := fmt.Fprintf(w, "::%s title=%s::%s\n", level, escapeAnnotationProperty(r.Name), escapeAnnotationData(r.Answer))
+	return err
+}
+
+func escapeAnnotationData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+func escapeAnnotationProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Line      int           `xml:"line,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Body    string `xml:",chardata"`
+}
+
+// writeJUnit emits one test case per finding, or a single verdict case
+func writeJUnit(w io.Writer, r report) error {
+	suite := junitTestSuite{Name: r.Name}
+
+	for i, f := range r.Findings {
+		tc := junitTestCase{
+			Name:      fmt.Sprintf("finding %d", i+1),
+			Classname: r.Name,
+			File:      f.File,
+			Line:      f.Line,
+		}
+		if f.File != "" {
+			tc.Name = fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		if f.Severity != SeverityNotice {
+			tc.Failure = &junitFailure{Message: f.Message, Type: f.Severity, Body: f.Message}
+			suite.Failures++
+		} else {
+			tc.SystemOut = f.Message
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	if len(r.Findings) == 0 {
+		tc := junitTestCase{Name: "verdict", Classname: r.Name, SystemOut: r.Answer}
+		if r.Verdict == VerdictFail {
+			tc.Failure = &junitFailure{Message: "verdict FAIL", Type: SeverityError, Body: r.Answer}
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	suite.Tests = len(suite.Cases)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitTestSuites{Suites: []junitTestSuite{suite}}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
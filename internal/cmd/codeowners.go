@@ -0,0 +1,211 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// codeownersLocations are checked in the order GitHub uses
+var codeownersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// noOwner groups files and findings no CODEOWNERS rule covers
+const noOwner = "(unowned)"
+
+// codeownersRule maps a path pattern to its owners
+type codeownersRule struct {
+	pattern string
+	re      *regexp.Regexp
+	owners  []string
+}
+
+// Codeowners is a parsed CODEOWNERS file; the last matching rule wins
+type Codeowners struct {
+	rules []codeownersRule
+}
+
+// loadCodeowners finds and parses CODEOWNERS under root.
+// It returns nil when the repository has none.
+func loadCodeowners(root string) (*Codeowners, error) {
+	for _, loc := range codeownersLocations {
+		f, err := os.Open(filepath.Join(root, loc))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return parseCodeowners(f)
+	}
+	return nil, nil
+}
+
+func parseCodeowners(r io.Reader) (*Codeowners, error) {
+	co := &Codeowners{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		rule := codeownersRule{pattern: fields[0], re: codeownersPattern(fields[0])}
+		for _, owner := range fields[1:] {
+			if strings.HasPrefix(owner, "#") {
+				break
+			}
+			rule.owners = append(rule.owners, owner)
+		}
+		co.rules = append(co.rules, rule)
+	}
+	return co, scanner.Err()
+}
+
+// codeownersPattern converts a gitignore-style CODEOWNERS pattern to a regexp
+func codeownersPattern(pattern string) *regexp.Regexp {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	p := strings.Trim(pattern, "/")
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(p, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored {
+		b.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(p); i++ {
+		switch {
+		case strings.HasPrefix(p[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(p[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case p[i] == '*':
+			b.WriteString("[^/]*")
+		case p[i] == '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(p[i])))
+		}
+	}
+	if dirOnly {
+		b.WriteString("/.*$")
+	} else {
+		b.WriteString("(?:/.*)?$")
+	}
+	return regexp.MustCompile(b.String())
+}
+
+// Owners returns the owners of a repository-relative path
+func (c *Codeowners) Owners(path string) []string {
+	path = strings.TrimPrefix(filepath.ToSlash(path), "./")
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].re.MatchString(path) {
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// ownerKey is the grouping key for a path's owners
+func (c *Codeowners) ownerKey(path string) string {
+	owners := c.Owners(path)
+	if len(owners) == 0 {
+		return noOwner
+	}
+	return strings.Join(owners, " ")
+}
+
+// ownershipContext describes who owns the files touched by a diff, for
+// inclusion in the prompt
+func ownershipContext(co *Codeowners, files []string) string {
+	if co == nil || len(files) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Code owners of the changed files (from CODEOWNERS):\n")
+	for _, f := range files {
+		_, _ = fmt.Fprintf(&b, "- %s: %s\n", f, co.ownerKey(f))
+	}
+	return b.String()
+}
+
+// groupFindingsByOwner splits findings by owning team; findings without a
+// file land under noOwner
+func groupFindingsByOwner(co *Codeowners, findings []Finding) map[string][]Finding {
+	groups := make(map[string][]Finding)
+	for _, f := range findings {
+		key := noOwner
+		if f.File != "" && co != nil {
+			key = co.ownerKey(f.File)
+		}
+		groups[key] = append(groups[key], f)
+	}
+	return groups
+}
+
+// writeFindingsByOwner renders grouped findings as markdown sections
+func writeFindingsByOwner(w io.Writer, groups map[string][]Finding) {
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		// Unowned findings go last
+		if (keys[i] == noOwner) != (keys[j] == noOwner) {
+			return keys[j] == noOwner
+		}
+		return keys[i] < keys[j]
+	})
+
+	for i, k := range keys {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintf(w, "## %s\n\n", k)
+		for _, f := range groups[k] {
+			loc := ""
+			if f.File != "" {
+				loc = f.File
+				if f.Line > 0 {
+					loc = fmt.Sprintf("%s:%d", f.File, f.Line)
+				}
+				loc += ": "
+			}
+			_, _ = fmt.Fprintf(w, "- [%s] %s%s\n", f.Severity, loc, f.Message)
+		}
+	}
+}
+
+// repoCodeowners loads CODEOWNERS for the current repository, warning on
+// parse errors; it returns nil outside a repository or without a file
+func repoCodeowners() *Codeowners {
+	root, err := gitRoot()
+	if err != nil {
+		return nil
+	}
+	co, err := loadCodeowners(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring CODEOWNERS: %v\n", err)
+		return nil
+	}
+	return co
+}
+
+// gitRoot returns the top-level directory of the current repository
+func gitRoot() (string, error) {
+	out, err := execCommand("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// maxSearchFileSize skips generated and data files
+	maxSearchFileSize = 1 << 20
+	// hitSpacing merges hits this close together in one file
+	hitSpacing = 3
+)
+
+// defaultSearchExcludes are skipped in addition to hidden files, binaries,
+// and (in a git repository) ignored files
+var defaultSearchExcludes = []string{
+	"node_modules/**", "vendor/**", "dist/**", "build/**", "target/**",
+	"*.min.js", "*.map", "*.lock", "go.sum", "package-lock.json",
+}
+
+// searchStopWords carry no signal in a question about code
+var searchStopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"can": true, "code": true, "do": true, "does": true, "done": true, "file": true, "files": true,
+	"find": true, "for": true, "from": true, "get": true, "how": true, "i": true, "in": true,
+	"is": true, "it": true, "of": true, "on": true, "or": true, "our": true, "set": true,
+	"the": true, "this": true, "to": true, "we": true, "what": true, "when": true, "where": true,
+	"which": true, "who": true, "why": true, "with": true, "implemented": true, "defined": true,
+	"handled": true, "happen": true, "happens": true, "located": true, "live": true, "lives": true,
+}
+
+var (
+	searchWord = regexp.MustCompile(`[A-Za-z][A-Za-z0-9]*`)
+	// searchFold makes rate_limit, rate-limit, and RateLimit match ratelimit
+	searchFold = strings.NewReplacer("_", "", "-", "")
+)
+
+// searchHit is a line matching some of the search terms
+type searchHit struct {
+	Path  string   `json:"path"`
+	Line  int      `json:"line"`
+	Score int      `json:"score"`
+	Terms []string `json:"terms"`
+}
+
+// searchTerms turns a question into lowercase terms: content words reduced
+// to a rough stem, plus adjacent pairs joined as identifiers
+func searchTerms(question string) []string {
+	var words []string
+	for _, w := range searchWord.FindAllString(question, -1) {
+		w = strings.ToLower(w)
+		if searchStopWords[w] || len(w) < 3 {
+			continue
+		}
+		words = append(words, stem(w))
+	}
+	seen := make(map[string]bool)
+	var terms []string
+	add := func(t string) {
+		if !seen[t] {
+			seen[t] = true
+			terms = append(terms, t)
+		}
+	}
+	for i, w := range words {
+		add(w)
+		if i > 0 {
+			add(words[i-1] + w)
+		}
+	}
+	return terms
+}
+
+// stem strips common English suffixes so "limiting" matches "limiter"
+func stem(w string) string {
+	for _, suffix := range []string{"ing", "ed", "er", "es", "s"} {
+		if strings.HasSuffix(w, suffix) && len(w)-len(suffix) >= 4 {
+			return strings.TrimSuffix(w, suffix)
+		}
+	}
+	return w
+}
+
+// normalizeTerm folds a term the way lines are folded for matching
+func normalizeTerm(t string) string {
+	return searchFold.Replace(strings.ToLower(strings.TrimSpace(t)))
+}
+
+// searchFiles lists the files to search under dir: git's view of the tree
+// (tracked and untracked, not ignored) when dir is in a repository, else
+// every text file that is not hidden
+func searchFiles(dir string, exclude []*regexp.Regexp) ([]string, error) {
+	out, err := execCommand("git", "-C", dir, "ls-files", "--cached", "--others", "--exclude-standard", "-z").Output()
+	if err != nil {
+		return expandContextDirs([]string{dir}, exclude, map[string]int{})
+	}
+	var files []string
+	for _, rel := range strings.Split(string(out), "\x00") {
+		if rel == "" {
+			continue
+		}
+		path := filepath.Join(dir, rel)
+		info, err := os.Lstat(path)
+		if err != nil || !info.Mode().IsRegular() || info.Size() > maxSearchFileSize {
+			continue
+		}
+		if excluded(exclude, rel, path) || isBinaryFile(path) {
+			continue
+		}
+		files = append(files, path)
+	}
+	return files, nil
+}
+
+// searchCode scores every line by the terms it contains, longer (more
+// specific) terms weighing more and terms in the file's path adding to
+// each of its lines, and returns the best hits spread across locations
+func searchCode(files []string, terms []string, limit int) []searchHit {
+	var hits []searchHit
+	for _, path := range files {
+		hits = append(hits, searchFile(path, terms)...)
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+
+	var picked []searchHit
+	for _, h := range hits {
+		if len(picked) == limit {
+			break
+		}
+		near := false
+		for _, p := range picked {
+			if p.Path == h.Path && h.Line-p.Line <= hitSpacing && p.Line-h.Line <= hitSpacing {
+				near = true
+				break
+			}
+		}
+		if !near {
+			picked = append(picked, h)
+		}
+	}
+	return picked
+}
+
+func searchFile(path string, terms []string) []searchHit {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	foldedPath := normalizeTerm(filepath.ToSlash(path))
+	pathScore := 0
+	for _, t := range terms {
+		if strings.Contains(foldedPath, t) {
+			pathScore += len(t)
+		}
+	}
+
+	var hits []searchHit
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxSearchFileSize)
+	for n := 1; scanner.Scan(); n++ {
+		line := normalizeTerm(scanner.Text())
+		var matched []string
+		score := 0
+		for _, t := range terms {
+			if strings.Contains(line, t) {
+				matched = append(matched, t)
+				score += len(t)
+			}
+		}
+		if len(matched) == 0 {
+			continue
+		}
+		// Lines matching several terms beat lines repeating one
+		score = score*len(matched) + pathScore
+		hits = append(hits, searchHit{Path: path, Line: n, Score: score, Terms: matched})
+	}
+	return hits
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// composedPrompt is the prompt arc-ask would send, for --format json
+type composedPrompt struct {
+	System    string            `json:"system"`
+	User      string            `json:"user"`
+	Template  string            `json:"template,omitempty"`
+	Tokens    int               `json:"prompt_tokens"`
+	Context   []includedContext `json:"context,omitempty"`
+	Omitted   []omittedContext  `json:"omitted,omitempty"`
+	Untrusted []string          `json:"untrusted,omitempty"`
+}
+
+func newComposeCmd() *cobra.Command {
+	var (
+		pane                string
+		lines               int
+		captureSpec         string
+		contextFiles        []string
+		untrustedContext    []string
+		contextBudget       int
+		excludes            []string
+		excludeLinePatterns []string
+		vars                []string
+		redactInput         bool
+		format              string
+	)
+	cmd := &cobra.Command{
+		Use:   "compose [@template|question]",
+		Short: "Print the assembled prompt without asking a model",
+		Long: `Gather input from stdin or a pane, merge context files, and render the
+template exactly as a query would, then print the system and user prompts
+instead of sending them. Other tools can reuse arc-ask's gathering and
+templating with their own inference.
+
+Untrusted sources are fenced and the instructions for them added, as for
+a query. Nothing is sent to a provider.`,
+		Example: `  git diff | arc-ask compose @code-review
+  arc-ask compose "what failed?" --pane dev:1.0 --format json
+  arc-ask compose @explain -c main.go --var level=beginner | my-llm`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return errors.NewCLIError(fmt.Sprintf("invalid --format %q", format)).
+					WithSuggestions("Use text or json")
+			}
+			templateVars, err := parseVars(vars)
+			if err != nil {
+				return err
+			}
+			capture, err := parseCaptureFilter(captureSpec)
+			if err != nil {
+				return err
+			}
+			input, err := gatherInput(cmd, pane, lines, capture)
+			if err != nil {
+				return err
+			}
+			lineExcludes, err := compileExcludeLines(excludeLinePatterns)
+			if err != nil {
+				return err
+			}
+			if text, n := excludeLines(input, lineExcludes); n > 0 {
+				input = text
+				fmt.Fprintf(os.Stderr, "Excluded %d input lines matching --exclude-lines\n", n)
+			}
+
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			trust, err := newTrustPolicy(cfg.Trust, hardenOff, untrustedContext)
+			if err != nil {
+				return err
+			}
+			guard := &sourceGuard{}
+			inputName := ""
+			if trust.input {
+				inputName = "stdin"
+				if pane != "" {
+					inputName = "pane " + pane
+				}
+			}
+			input, ctxResult, err := mergeContext(input, append(contextFiles, untrustedContext...), contextOptions{
+				budget:         contextBudget,
+				order:          orderExplicit,
+				exclude:        compileExcludes(excludes),
+				untrusted:      trust.untrusted,
+				inputUntrusted: inputName,
+				fence:          guard.fence,
+			})
+			if err != nil {
+				return err
+			}
+			if redactInput {
+				var hits []redactionHit
+				input, hits = redact(input)
+				if len(hits) > 0 {
+					fmt.Fprintf(os.Stderr, "Redacted from input: %s\n", describeHits(hits))
+				}
+			}
+
+			arg := ""
+			if len(args) > 0 {
+				arg = args[0]
+			}
+			if arg == "" && input == "" {
+				return errors.NewCLIError("no prompt or input provided").
+					WithSuggestions("Pipe input: git diff | arc-ask compose @code-review")
+			}
+			system, user, err := buildPrompt(arg, input, templateVars)
+			if err != nil {
+				return err
+			}
+			if len(guard.sources) > 0 {
+				if system == "" {
+					system = untrustedInstructions
+				} else {
+					system = untrustedInstructions + "\n\n" + system
+				}
+			}
+			if t := templateContract(arg); t != nil {
+				user += "\n\n" + t.Output.Instructions()
+			}
+			if t := templateOutputs(arg); t != nil {
+				user += "\n\n" + t.OutputsInstructions()
+			}
+
+			if format == "json" {
+				c := composedPrompt{
+					System:    system,
+					User:      user,
+					Tokens:    ask.EstimateTokens(ask.JoinPrompt(system, user)),
+					Context:   ctxResult.Included,
+					Omitted:   ctxResult.Omitted,
+					Untrusted: guard.sources,
+				}
+				if ask.IsTemplateRef(arg) {
+					c.Template = arg
+				}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(c)
+			}
+			if system != "" {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "=== system ===\n%s\n\n", system)
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "=== user ===\n%s\n", user)
+			return err
+		},
+	}
+	cmd.Flags().StringVar(&pane, "pane", "", "Capture from tmux pane (e.g., session:0.0)")
+	cmd.Flags().IntVar(&lines, "lines", 200, "Lines to capture from pane")
+	cmd.Flags().StringVar(&captureSpec, "capture-filter", captureSmart, "Pane line selection: smart, tail, errors (tune with ,keep=RE,drop=RE)")
+	cmd.Flags().StringArrayVarP(&contextFiles, "context", "c", nil, "Add context file(s)")
+	cmd.Flags().StringArrayVar(&untrustedContext, "context-untrusted", nil, "Add context file(s), directories, or URLs treated as untrusted data")
+	cmd.Flags().IntVar(&contextBudget, "context-budget", 0, "Max tokens for input plus context (0 = unlimited)")
+	cmd.Flags().StringArrayVar(&excludes, "exclude", nil, "Skip matching paths inside context directories (glob, e.g. 'vendor/**')")
+	cmd.Flags().StringArrayVar(&excludeLinePatterns, "exclude-lines", nil, "Drop pane/stdin lines matching a regular expression")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Template variable (key=value)")
+	_ = cmd.RegisterFlagCompletionFunc("var", completeVars)
+	cmd.Flags().BoolVar(&redactInput, "redact", false, "Redact secrets and PII from input")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json")
+	return cmd
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// confidenceInstructions asks the model for a calibrated, parseable trailer
+const confidenceInstructions = `After your answer, add a trailer in exactly this form:
+
+CONFIDENCE: <probability from 0.0 to 1.0 that your answer is correct>
+ASSUMPTIONS:
+- <each assumption the answer depends on, one per line, or "- none">
+
+Be calibrated: 0.9 means you expect to be wrong about one time in ten.`
+
+var (
+	confidencePattern  = regexp.MustCompile(`(?i)^\**confidence\**\s*:\s*\**\s*([0-9]*\.?[0-9]+)\s*(%?)`)
+	assumptionsPattern = regexp.MustCompile(`(?i)^\**assumptions\**\s*:\s*\**$`)
+)
+
+// Confidence is the parsed --confidence trailer
+type Confidence struct {
+	Score       float64  `json:"score"`
+	Assumptions []string `json:"assumptions"`
+}
+
+// parseConfidence finds the last CONFIDENCE trailer in an answer and
+// returns the answer without it. ok is false when there is no trailer.
+func parseConfidence(answer string) (body string, c Confidence, ok bool) {
+	lines := strings.Split(answer, "\n")
+
+	start := -1
+	for i := len(lines) - 1; i >= 0; i-- {
+		m := confidencePattern.FindStringSubmatch(strings.TrimSpace(lines[i]))
+		if m == nil {
+			continue
+		}
+		score, err := strconv.ParseFloat(m[1], 64)
+		if err != nil {
+			return answer, Confidence{}, false
+		}
+		if m[2] == "%" || score > 1 {
+			score /= 100
+		}
+		c.Score = min(max(score, 0), 1)
+		start = i
+		break
+	}
+	if start < 0 {
+		return answer, Confidence{}, false
+	}
+
+	c.Assumptions = []string{}
+	inList := false
+	for _, line := range lines[start+1:] {
+		line = strings.TrimSpace(line)
+		switch {
+		case assumptionsPattern.MatchString(line):
+			inList = true
+		case inList && (strings.HasPrefix(line, "-") || strings.HasPrefix(line, "*")):
+			item := strings.TrimSpace(line[1:])
+			if item != "" && !strings.EqualFold(item, "none") {
+				c.Assumptions = append(c.Assumptions, item)
+			}
+		}
+	}
+
+	return strings.TrimRight(strings.Join(lines[:start], "\n"), "\n "), c, true
+}
+
+// writeConfidenceFooter renders the trailer below a plain-text answer
+func writeConfidenceFooter(w io.Writer, c Confidence) {
+	_, _ = fmt.Fprintf(w, "\n---\nConfidence: %.0f%%\n", c.Score*100)
+	if len(c.Assumptions) == 0 {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Assumptions:")
+	for _, a := range c.Assumptions {
+		_, _ = fmt.Fprintf(w, "  - %s\n", a)
+	}
+}
@@ -0,0 +1,203 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/yourorg/arc-sdk/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigPath is the user configuration written by arc-ask init
+const defaultConfigPath = "~/.config/arc/ask.yaml"
+
+// Config is the user configuration in ask.yaml. Empty fields keep the
+// built-in defaults.
+type Config struct {
+	Provider      string `yaml:"provider,omitempty"`
+	Model         string `yaml:"model,omitempty"`
+	APIKey        string `yaml:"api_key,omitempty"`
+	TemplateDir   string `yaml:"template_dir,omitempty"`
+	TemplateIndex string `yaml:"template_index,omitempty"` // URL or file for arc-ask template browse
+
+	// TemplateRoots are further template directories, such as a team's
+	// shared checkout, searched after TemplateDir in order
+	TemplateRoots []string    `yaml:"template_roots,omitempty"`
+	CacheMaxMB    int         `yaml:"cache_max_mb,omitempty"` // response cache size, default 100
+	Cache         CacheConfig `yaml:"cache,omitempty"`
+	NotesDir      string      `yaml:"notes_dir,omitempty"` // base for relative --save-note folders
+	TempDir       string      `yaml:"temp_dir,omitempty"`  // private dir for large prompts and audio
+
+	// ConfirmCost asks before requests estimated to cost more, in USD
+	// (default 0.50; 0 never asks)
+	ConfirmCost *float64 `yaml:"confirm_cost,omitempty"`
+
+	// Trust sets which sources are treated as untrusted data
+	Trust TrustConfig `yaml:"trust,omitempty"`
+
+	// Transcription configures --mic
+	Transcription TranscriptionConfig `yaml:"transcription,omitempty"`
+
+	// Chat sets when long chat sessions are summarized
+	Chat ChatConfig `yaml:"chat,omitempty"`
+
+	// Speech configures --speak
+	Speech SpeechConfig `yaml:"speech,omitempty"`
+
+	// RateLimits is keyed by provider, with "*" for any other provider
+	RateLimits map[string]RateLimit `yaml:"rate_limits,omitempty"`
+
+	// Profiles are named provider settings a directory's .arc-ask.env can select
+	Profiles map[string]Profile `yaml:"profiles,omitempty"`
+
+	// Experiment trials a candidate model on a share of requests
+	Experiment ExperimentConfig `yaml:"experiment,omitempty"`
+
+	// ModelAliases are short names, such as fast or smart, accepted
+	// anywhere a model is
+	ModelAliases map[string]string `yaml:"model_aliases,omitempty"`
+
+	// ModelMigration replaces or refuses retired model IDs
+	ModelMigration ModelMigrationConfig `yaml:"model_migration,omitempty"`
+
+	// Egress restricts, signs, and audits outbound requests
+	Egress EgressConfig `yaml:"egress,omitempty"`
+
+	// Ollama configures the local Ollama provider
+	Ollama OllamaConfig `yaml:"ollama,omitempty"`
+
+	// Server is an arc-ask serve URL that questions go through, sharing
+	// its cache and rate limit with the host's other users.
+	// $ARC_ASK_SERVER overrides it.
+	Server string `yaml:"server,omitempty"`
+
+	// Privacy limits the machine metadata in --env-context and tool traces
+	Privacy PrivacyConfig `yaml:"privacy,omitempty"`
+
+	// PromptRoles is keyed by provider, with "*" for any other provider
+	PromptRoles map[string]PromptRole `yaml:"prompt_roles,omitempty"`
+
+	// History remembers answered questions for completion and
+	// --pick-history; false turns it off
+	History *bool `yaml:"history,omitempty"`
+
+	// TitleModel titles saved sessions and history entries (default: the
+	// provider's cheapest small model)
+	TitleModel string `yaml:"title_model,omitempty"`
+}
+
+// Profile overrides the provider settings; empty fields keep the config's
+type Profile struct {
+	Provider string `yaml:"provider,omitempty"`
+	Model    string `yaml:"model,omitempty"`
+	APIKey   string `yaml:"api_key,omitempty"`
+}
+
+// providerKeyEnv is the environment variable pi reads each provider's key from
+var providerKeyEnv = map[string]string{
+	"anthropic":  "ANTHROPIC_API_KEY",
+	"openai":     "OPENAI_API_KEY",
+	"google":     "GEMINI_API_KEY",
+	"groq":       "GROQ_API_KEY",
+	"openrouter": "OPENROUTER_API_KEY",
+	"xai":        "XAI_API_KEY",
+}
+
+// loadConfig reads ask.yaml once per process; a missing file is an empty
+// config. Stateless runs read the environment instead.
+var loadConfig = sync.OnceValues(func() (*Config, error) {
+	if stateless {
+		return configFromEnv()
+	}
+	path := configPath()
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Config{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	var c Config
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, errors.NewCLIError("invalid config " + path).WithCause(err)
+	}
+	return &c, nil
+})
+
+func configExists() bool {
+	_, err := os.Stat(configPath())
+	return err == nil
+}
+
+// saveConfig writes ask.yaml privately since it may hold an API key
+func saveConfig(c *Config) error {
+	path := configPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// templateDir is the user template directory, from ask.yaml or the default
+func templateDir() string {
+	if c, err := loadConfig(); err == nil && c.TemplateDir != "" {
+		return c.TemplateDir
+	}
+	return defaultTemplateDir()
+}
+
+// apply configures a client from ask.yaml
+func (c *Config) apply(client *BridgeClient) {
+	if client.model == "" {
+		client.useModel(c.Model)
+	}
+	c.useProvider(client, c.Provider, c.APIKey)
+	client.server = serverURL()
+}
+
+// CacheConfig is the cache section of ask.yaml
+type CacheConfig struct {
+	// Responses reuses answers to identical prompts, as --cache does; off
+	// by default, as the cache keeps prompts and answers on disk
+	Responses bool `yaml:"responses,omitempty"`
+}
+
+// resolveModel maps a model alias from ask.yaml to its model ID, and a
+// retired ID to its successor. Other names are returned as is, with an
+// empty alias.
+func resolveModel(name string) (model, alias string) {
+	model = name
+	if c, err := loadConfig(); err == nil {
+		if m, ok := c.ModelAliases[name]; ok && m != "" {
+			model, alias = m, name
+		}
+	}
+	return migrateModel(model), alias
+}
+
+// useModel points the client at a model ID or alias
+func (c *BridgeClient) useModel(name string) {
+	c.model, c.modelAlias = resolveModel(name)
+}
+
+// useProvider points the client at provider, with the provider's rate
+// limit and, unless its environment variable is set, apiKey
+func (c *Config) useProvider(client *BridgeClient, provider, apiKey string) {
+	client.provider = provider
+	client.limiter = newRateLimiter(provider, c.RateLimits)
+	client.role = promptRoleFor(provider, c.PromptRoles)
+	if apiKey != "" {
+		if env, ok := providerKeyEnv[provider]; ok && os.Getenv(env) == "" {
+			client.env = append(client.env, env+"="+apiKey)
+		}
+	}
+}
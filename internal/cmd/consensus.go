@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// judgeInstructions asks a third query to reconcile two independent answers
+const judgeInstructions = `Two assistants answered the request below independently. Compare their answers and report:
+
+1. Agreements: points both answers make.
+2. Disagreements: where they differ, and which answer is more likely correct and why.
+3. Reconciled answer: the best combined answer.`
+
+// modelAnswer is one model's independent answer in consensus mode
+type modelAnswer struct {
+	Model    string `json:"model"`
+	Response string `json:"response"`
+	Verdict  string `json:"verdict,omitempty"`
+}
+
+// consensusResult is the outcome of a --consensus run
+type consensusResult struct {
+	Answers  []modelAnswer `json:"answers"`
+	Judgment string        `json:"judgment"`
+	Agree    *bool         `json:"verdicts_agree,omitempty"` // set with --check
+}
+
+// parseConsensusModels validates a "modelA,modelB" flag value
+func parseConsensusModels(spec string) ([]string, error) {
+	models := strings.Split(spec, ",")
+	for i := range models {
+		models[i] = strings.TrimSpace(models[i])
+	}
+	if len(models) != 2 || models[0] == "" || models[1] == "" {
+		return nil, errors.NewCLIError(fmt.Sprintf("invalid --consensus %q", spec)).
+			WithSuggestions("Name exactly two models: --consensus modelA,modelB")
+	}
+	return models, nil
+}
+
+// runConsensus asks each model independently, then has the default model
+// judge the answers. With check, every answer is expected to carry a
+// VERDICT line and the result records whether the models agree.
+func runConsensus(ctx context.Context, client *BridgeClient, models []string, prompt string, check bool) (*consensusResult, error) {
+	answers := make([]modelAnswer, len(models))
+	errs := make([]error, len(models))
+
+	var wg sync.WaitGroup
+	for i, m := range models {
+		wg.Add(1)
+		go func(i int, m string) {
+			defer wg.Done()
+			answer, err := client.WithModel(m).Ask(ctx, prompt)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s: %w", m, err)
+				return
+			}
+			verdict, _ := parseVerdict(answer)
+			answers[i] = modelAnswer{Model: m, Response: answer, Verdict: verdict}
+		}(i, m)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(judgeInstructions)
+	if check {
+		b.WriteString("\n\n" + ask.VerdictInstructions)
+	}
+	_, _ = fmt.Fprintf(&b, "\n\nRequest:\n%s", prompt)
+	for i, a := range answers {
+		_, _ = fmt.Fprintf(&b, "\n\nAnswer %c (%s):\n%s", 'A'+i, a.Model, a.Response)
+	}
+
+	judgment, err := client.Ask(ctx, b.String())
+	if err != nil {
+		return nil, fmt.Errorf("judge: %w", err)
+	}
+
+	result := &consensusResult{Answers: answers, Judgment: judgment}
+	if check {
+		agree := answers[0].Verdict != "" && answers[0].Verdict == answers[1].Verdict
+		result.Agree = &agree
+	}
+	return result, nil
+}
+
+// disagreement describes differing verdicts for the --check error
+func (r *consensusResult) disagreement() string {
+	parts := make([]string, len(r.Answers))
+	for i, a := range r.Answers {
+		verdict := a.Verdict
+		if verdict == "" {
+			verdict = "no verdict"
+		}
+		parts[i] = fmt.Sprintf("%s says %s", a.Model, verdict)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Context packing orders for --context-order
+const (
+	orderExplicit = "explicit" // command-line order
+	orderSmallest = "smallest" // smallest files first, fitting the most files
+	orderWeight   = "weight"   // highest --context-weight first
+)
+
+// contextOptions controls how --context files are packed into the prompt.
+// The zero value includes every file in command-line order.
+type contextOptions struct {
+	budget  int // max tokens for input plus context; 0 means unlimited
+	order   string
+	weights map[string]int
+	exclude []*regexp.Regexp // --exclude, applied inside context directories
+	numbers bool             // --line-numbers: number the lines of source files
+
+	// Untrusted sources are passed through fence before they are merged.
+	// A nil untrusted trusts every file.
+	untrusted      func(path string) bool
+	inputUntrusted string // names the input when it is untrusted
+	fence          func(name, text string) string
+}
+
+// contextFile is a context file read from disk
+type contextFile struct {
+	path   string
+	data   []byte
+	tokens int
+	index  int
+	weight int
+}
+
+// omittedContext is a file left out because it did not fit the budget
+type omittedContext struct {
+	Path   string `json:"path"`
+	Tokens int    `json:"tokens"`
+}
+
+// parseContextWeights converts path=N flag values into a map
+func parseContextWeights(pairs []string) (map[string]int, error) {
+	weights := make(map[string]int, len(pairs))
+	for _, p := range pairs {
+		path, value, ok := strings.Cut(p, "=")
+		n, err := strconv.Atoi(value)
+		if !ok || path == "" || err != nil {
+			return nil, errors.NewCLIError(fmt.Sprintf("invalid --context-weight %q", p)).
+				WithSuggestions("Format: --context-weight path=N (higher is packed first)")
+		}
+		weights[path] = n
+	}
+	return weights, nil
+}
+
+func validateContextOrder(order string) error {
+	switch order {
+	case "", orderExplicit, orderSmallest, orderWeight:
+		return nil
+	}
+	return errors.NewCLIError(fmt.Sprintf("invalid --context-order %q", order)).
+		WithSuggestions("Use one of: explicit, smallest, weight")
+}
+
+// readContextFiles reads all files concurrently, preserving their order
+func readContextFiles(paths []string, weights map[string]int) ([]contextFile, error) {
+	files := make([]contextFile, len(paths))
+	errs := make([]error, len(paths))
+
+	var wg sync.WaitGroup
+	for i, p := range paths {
+		wg.Add(1)
+		go func(i int, p string) {
+			defer wg.Done()
+			var (
+				data []byte
+				err  error
+			)
+			if isURL(p) {
+				data, err = fetchContextURL(p)
+			} else {
+				data, err = os.ReadFile(p)
+			}
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			files[i] = contextFile{
+				path:   p,
+				data:   data,
+				tokens: ask.EstimateTokens(string(data)),
+				index:  i,
+				weight: weights[p],
+			}
+		}(i, p)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, coded(codeInputUnavailable, errors.NewCLIError("failed to read context file").WithCause(err))
+		}
+	}
+	return files, nil
+}
+
+// duplicateContext is a context file whose content was already included
+type duplicateContext struct {
+	Path   string `json:"path"`
+	SameAs string `json:"same_as,omitempty"` // earlier context file, or "" for the piped/pane input
+}
+
+// includedContext is a context file that made it into the prompt
+type includedContext struct {
+	Path      string `json:"path"`
+	Bytes     int    `json:"bytes"`
+	Tokens    int    `json:"tokens"`
+	Untrusted bool   `json:"untrusted,omitempty"`
+}
+
+// contextResult describes what mergeContext did with each context file
+type contextResult struct {
+	Included   []includedContext
+	Omitted    []omittedContext
+	Duplicates []duplicateContext
+}
+
+// contentHash identifies content regardless of trailing whitespace and line endings
+func contentHash(text string) [sha256.Size]byte {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	return sha256.Sum256([]byte(strings.TrimSpace(text)))
+}
+
+// dedupeContext drops files whose content matches the input or an earlier
+// file, so the same text is never paid for twice
+func dedupeContext(input string, files []contextFile) ([]contextFile, []duplicateContext) {
+	seen := make(map[[sha256.Size]byte]string, len(files)+1)
+	if strings.TrimSpace(input) != "" {
+		seen[contentHash(input)] = ""
+	}
+
+	var (
+		unique []contextFile
+		dups   []duplicateContext
+	)
+	for _, f := range files {
+		h := contentHash(string(f.data))
+		if first, ok := seen[h]; ok {
+			dups = append(dups, duplicateContext{Path: f.path, SameAs: first})
+			continue
+		}
+		seen[h] = f.path
+		unique = append(unique, f)
+	}
+	return unique, dups
+}
+
+// packContext orders files by priority and keeps those that fit in the
+// token budget left after the input; the rest are reported as omitted
+func packContext(files []contextFile, inputTokens int, opts contextOptions) ([]contextFile, []omittedContext) {
+	ordered := append([]contextFile(nil), files...)
+	switch opts.order {
+	case orderSmallest:
+		sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].tokens < ordered[j].tokens })
+	case orderWeight:
+		sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].weight > ordered[j].weight })
+	}
+
+	if opts.budget <= 0 {
+		return ordered, nil
+	}
+
+	remaining := opts.budget - inputTokens
+	var (
+		packed  []contextFile
+		omitted []omittedContext
+	)
+	for _, f := range ordered {
+		if f.tokens > remaining {
+			omitted = append(omitted, omittedContext{Path: f.path, Tokens: f.tokens})
+			continue
+		}
+		remaining -= f.tokens
+		packed = append(packed, f)
+	}
+	return packed, omitted
+}
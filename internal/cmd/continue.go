@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourorg/arc-ask/pkg/ask/pi"
+)
+
+// stopLength is pi's stop reason for an answer cut off at the output limit
+const stopLength = pi.StopLength
+
+const (
+	defaultMaxContinuations = 3
+	// minOverlap keeps short coincidental matches, like a repeated word,
+	// from being dropped as overlap
+	minOverlap = 12
+	// maxOverlap bounds how far back the overlap search looks
+	maxOverlap = 2000
+)
+
+// continuationPrompt asks the model to pick up where a cut-off answer ended
+func continuationPrompt(prompt, partial string) string {
+	return fmt.Sprintf(`%s
+
+Your previous answer reached the output limit and was cut off. Here is everything it said so far:
+
+<previous-answer>
+%s
+</previous-answer>
+
+Continue exactly where it stopped, even mid-word or mid-code-block. Do not repeat any of it, do not summarize it, and do not add an introduction.`, prompt, partial)
+}
+
+// stitchContinuation appends a continuation to an answer, dropping any text
+// the model repeated from the end of the previous part
+func stitchContinuation(prev, next string) string {
+	trimmed := strings.TrimLeft(next, " \t\n")
+	for _, cont := range []string{next, trimmed} {
+		if k := overlap(prev, cont); k > 0 {
+			return prev + cont[k:]
+		}
+	}
+
+	// A fence reopened with a language would close the block the previous
+	// part left open instead of continuing it
+	if opener, rest, ok := strings.Cut(trimmed, "\n"); ok && openFence(prev) &&
+		strings.HasPrefix(opener, "```") && strings.TrimSpace(opener) != "```" {
+		if k := overlap(prev, rest); k > 0 {
+			return prev + rest[k:]
+		}
+		if !strings.HasSuffix(prev, "\n") {
+			prev += "\n"
+		}
+		return prev + rest
+	}
+	return prev + next
+}
+
+// overlap returns the length of the longest suffix of prev that starts
+// next, or 0 when it is shorter than minOverlap
+func overlap(prev, next string) int {
+	limit := min(len(prev), len(next), maxOverlap)
+	for k := limit; k >= minOverlap; k-- {
+		if strings.HasSuffix(prev, next[:k]) {
+			return k
+		}
+	}
+	return 0
+}
@@ -0,0 +1,100 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+const (
+	// defaultConfirmCost is the estimate in USD above which a request
+	// needs confirmation
+	defaultConfirmCost = 0.50
+	// assumedOutputTokens stands in for the answer length when
+	// --max-tokens is not set
+	assumedOutputTokens = 2000
+)
+
+// costEstimate is the expected price of one request to one model
+type costEstimate struct {
+	Model        ModelInfo
+	InputTokens  int
+	OutputTokens int
+}
+
+func (e costEstimate) USD() float64 {
+	return (float64(e.InputTokens)*e.Model.InputCost + float64(e.OutputTokens)*e.Model.OutputCost) / 1e6
+}
+
+// estimateCost prices a prompt for a model; ok is false when the model's
+// price is unknown
+func estimateCost(provider, model string, promptTokens, maxTokens int) (costEstimate, bool) {
+	m, ok := lookupModel(provider, model)
+	if !ok || (m.InputCost == 0 && m.OutputCost == 0) {
+		return costEstimate{}, false
+	}
+	out := maxTokens
+	if out <= 0 {
+		out = assumedOutputTokens
+		if m.MaxOutput > 0 {
+			out = min(out, m.MaxOutput)
+		}
+	}
+	return costEstimate{Model: m, InputTokens: promptTokens, OutputTokens: out}, true
+}
+
+// confirmCost asks before sending requests whose estimated total is above
+// the configured threshold. yes skips the question.
+func confirmCost(estimates []costEstimate, threshold float64, yes bool) error {
+	if threshold <= 0 || len(estimates) == 0 {
+		return nil
+	}
+	total := 0.0
+	for _, e := range estimates {
+		total += e.USD()
+	}
+	if total <= threshold || yes {
+		return nil
+	}
+
+	question := fmt.Sprintf("This request is estimated to cost %s", formatUSD(total))
+	for _, e := range estimates {
+		question += fmt.Sprintf("\n  %s: ~%s input tokens at %s/M, up to %s output at %s/M",
+			e.Model.Name, formatTokens(e.InputTokens), formatPrice(e.Model.InputCost),
+			formatTokens(e.OutputTokens), formatPrice(e.Model.OutputCost))
+	}
+	ok, err := confirm(question + "\nSend it?")
+	if err != nil {
+		return coded(codeDeclined, errors.NewCLIError(fmt.Sprintf("estimated cost %s is above confirm_cost %s", formatUSD(total), formatUSD(threshold))).
+			WithSuggestions("Confirm with --yes", "Shrink the input with --context-budget", "Raise confirm_cost in "+displayPath(configPath())))
+	}
+	if !ok {
+		return coded(codeDeclined, errors.NewCLIError("aborted, nothing sent"))
+	}
+	return nil
+}
+
+func formatUSD(v float64) string {
+	if v < 0.01 {
+		return fmt.Sprintf("$%.4f", v)
+	}
+	return fmt.Sprintf("$%.2f", v)
+}
+
+func formatPrice(perMillion float64) string {
+	if perMillion == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("$%.2f", perMillion)
+}
+
+// confirmCostThreshold is confirm_cost from the config, or the default
+func confirmCostThreshold(cfg *Config) float64 {
+	if cfg != nil && cfg.ConfirmCost != nil {
+		return *cfg.ConfirmCost
+	}
+	return defaultConfirmCost
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Racers under --deadline
+const (
+	racerDefault = "default"
+	racerFast    = "fast"
+)
+
+// deadlineRace reports which model answered under --deadline
+type deadlineRace struct {
+	Deadline  float64 `json:"deadline"` // seconds
+	Winner    string  `json:"winner"`   // default or fast
+	Model     string  `json:"model"`
+	FastModel string  `json:"fast_model"`
+	Elapsed   float64 `json:"elapsed"` // seconds until the winner answered
+}
+
+// fastModel is the model raced against the default under --deadline:
+// --fast-model, else the fast alias from ask.yaml, else the provider's
+// cheapest small model. Empty means there is none to race.
+func fastModel(client *BridgeClient, flag string) string {
+	if flag != "" {
+		return flag
+	}
+	if cfg, err := loadConfig(); err == nil && cfg.ModelAliases["fast"] != "" {
+		return "fast"
+	}
+	return cheapestSmallModel(client)
+}
+
+// raceDeadline asks the default and fast models at once and returns the
+// first usable answer within the deadline, canceling the other. When both
+// fail it returns the longer partial answer, if any, as a
+// *partialAnswerError.
+func raceDeadline(ctx context.Context, client *BridgeClient, fast, prompt string, deadline time.Duration) (string, *deadlineRace, error) {
+	ctx, cancel := context.WithTimeout(ctx, deadline)
+	defer cancel()
+
+	type finish struct {
+		racer, model, answer string
+		err                  error
+	}
+	racers := [][2]string{{racerDefault, client.model}}
+	if fast != "" {
+		if m, _ := resolveModel(fast); m != client.model {
+			racers = append(racers, [2]string{racerFast, m})
+		}
+	}
+	if len(racers) == 1 {
+		fmt.Fprintln(os.Stderr, "Note: no fast model to race (set --fast-model or a fast model alias); --deadline only bounds the default model")
+	}
+	results := make(chan finish, len(racers))
+	start := time.Now()
+	for _, r := range racers {
+		go func(racer, model string) {
+			c := client
+			if racer == racerFast {
+				c = client.WithModel(model)
+			}
+			answer, err := c.Ask(ctx, prompt)
+			results <- finish{racer, c.model, answer, err}
+		}(r[0], r[1])
+	}
+
+	var (
+		best *partialAnswerError
+		errs []string
+	)
+	for range racers {
+		f := <-results
+		if f.err == nil && strings.TrimSpace(f.answer) != "" {
+			race := &deadlineRace{
+				Deadline: deadline.Seconds(),
+				Winner:   f.racer,
+				Model:    f.model,
+				Elapsed:  time.Since(start).Round(time.Millisecond).Seconds(),
+			}
+			if len(racers) > 1 {
+				race.FastModel = racers[1][1]
+			}
+			return f.answer, race, nil
+		}
+		if p, ok := f.err.(*partialAnswerError); ok && (best == nil || len(p.Partial) > len(best.Partial)) {
+			best = p
+		}
+		if f.err == nil {
+			f.err = fmt.Errorf("empty answer")
+		}
+		errs = append(errs, fmt.Sprintf("%s: %v", f.model, f.err))
+	}
+	if best != nil && best.Partial != "" {
+		return "", nil, best
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return "", nil, fmt.Errorf("no answer within --deadline %s: %w", deadline, ctx.Err())
+	}
+	return "", nil, fmt.Errorf("%s", strings.Join(errs, "; "))
+}
+
+// noteDeadlineWinner says on stderr which model answered in time
+func noteDeadlineWinner(race *deadlineRace) {
+	fmt.Fprintf(os.Stderr, "Note: answered by the %s model (%s) in %.1fs, within --deadline %gs\n",
+		race.Winner, race.Model, race.Elapsed, race.Deadline)
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Kinds of degenerate answer that are retried once
+const (
+	degenerateEmpty     = "empty"
+	degenerateRefusal   = "refusal"
+	degenerateTruncated = "truncated"
+)
+
+const (
+	// maxRefusalLength keeps long answers that merely mention a limitation
+	// from counting as refusals
+	maxRefusalLength = 400
+	// retryMaxTokens is the output limit for retrying a truncated answer
+	// when neither --max-tokens nor the model's limit is known
+	retryMaxTokens = 8192
+)
+
+var refusalPattern = regexp.MustCompile(`(?i)^\W*((i'?m\s+)?sorry|i\s+apologi[sz]e|unfortunately)?[,.!\s]*(but\s+)?i\s+(can(no|')t|can\s+not|am\s+(not\s+able|unable)|'m\s+(not\s+able|unable)|won'?t|will\s+not)\s+(to\s+)?(help|assist|provide|comply|do\s+that|answer|support)`)
+
+// refusalClarification is added to the prompt when a technical question
+// was refused
+const refusalClarification = `Note: this is a routine technical request about the user's own software, logs, or systems. Answer it directly. If some part genuinely cannot be answered, say which part and answer the rest.`
+
+// degenerateAnswer reports why an answer is unusable, or "" when it is fine
+func degenerateAnswer(answer string) string {
+	text := strings.TrimSpace(answer)
+	switch {
+	case text == "":
+		return degenerateEmpty
+	case len(text) <= maxRefusalLength && refusalPattern.MatchString(text):
+		return degenerateRefusal
+	case openFence(text):
+		return degenerateTruncated
+	}
+	return ""
+}
+
+// openFence reports whether a code fence is left unclosed
+func openFence(text string) bool {
+	open := false
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			open = !open
+		}
+	}
+	return open
+}
+
+// retryDegenerate asks once more when an answer is empty, a refusal, or
+// cut off inside a code block. It returns the answer to use and, when it
+// retried, a note saying why.
+func retryDegenerate(ctx context.Context, client *BridgeClient, tools []string, prompt, answer string) (string, string) {
+	retryClient, retryPrompt := client, prompt
+	var note string
+	switch degenerateAnswer(answer) {
+	case "":
+		return answer, ""
+	case degenerateEmpty:
+		note = "Answer was empty; retried once"
+	case degenerateRefusal:
+		note = "Answer looked like a refusal; retried once with a clarified prompt"
+		retryPrompt = prompt + "\n\n" + refusalClarification
+	case degenerateTruncated:
+		limit := max(2*client.maxTokens, retryMaxTokens)
+		if m, ok := lookupModel(client.provider, client.model); ok && m.MaxOutput > 0 {
+			limit = min(limit, m.MaxOutput)
+		}
+		if limit <= client.maxTokens {
+			return answer, ""
+		}
+		cp := *client
+		cp.maxTokens = limit
+		retryClient = &cp
+		note = fmt.Sprintf("Answer ended inside a code block; retried once with --max-tokens %d", limit)
+	}
+
+	var (
+		retried string
+		err     error
+	)
+	if len(tools) > 0 {
+		retried, err = retryClient.AskWithTools(ctx, retryPrompt, tools)
+	} else {
+		retried, err = retryClient.Ask(ctx, retryPrompt)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: retry failed, keeping the first answer: %v\n", err)
+		return answer, note
+	}
+	if strings.TrimSpace(retried) == "" && strings.TrimSpace(answer) != "" {
+		return answer, note
+	}
+	return retried, note
+}
@@ -0,0 +1,159 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	diffGitPattern  = regexp.MustCompile(`(?m)^diff --git a/(\S+) b/(\S+)`)
+	diffPlusPattern = regexp.MustCompile(`(?m)^\+\+\+ b/(\S+)`)
+)
+
+// isDiff reports whether input looks like unified diff output
+func isDiff(input string) bool {
+	return diffGitPattern.MatchString(input) || diffPlusPattern.MatchString(input)
+}
+
+// diffFiles returns the files touched by a unified diff, in order
+func diffFiles(input string) []string {
+	seen := make(map[string]bool)
+	var files []string
+	add := func(f string) {
+		if f == "/dev/null" || seen[f] {
+			return
+		}
+		seen[f] = true
+		files = append(files, f)
+	}
+	for _, m := range diffGitPattern.FindAllStringSubmatch(input, -1) {
+		add(m[2])
+	}
+	for _, m := range diffPlusPattern.FindAllStringSubmatch(input, -1) {
+		add(strings.TrimSpace(m[1]))
+	}
+	return files
+}
+
+var diffHunkPattern = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// diffLine is a line of a hunk
+type diffLine struct {
+	Kind     byte // ' ', '+', or '-'
+	OldLine  int  // 0 for added lines
+	NewLine  int  // 0 for removed lines
+	Position int  // GitHub's diff position: lines since the file's first hunk header
+	Index    int  // line index in the diff text
+}
+
+// diffFile is one file's part of a unified diff
+type diffFile struct {
+	Path  string // the new path, or the old one for deleted files
+	Lines []diffLine
+}
+
+// parseDiff maps each hunk line of a unified diff to its line numbers in
+// the old and new file. The hunk headers' line counts tell hunk lines
+// apart from the next file's --- and +++ headers.
+func parseDiff(input string) []diffFile {
+	var (
+		files            []diffFile
+		cur              *diffFile
+		oldLine, newLine int
+		oldLeft, newLeft int
+		position         int
+		oldPath          string
+	)
+	for i, line := range strings.Split(input, "\n") {
+		inHunk := cur != nil && (oldLeft > 0 || newLeft > 0)
+		switch {
+		case inHunk && line != "" && strings.ContainsRune(" +-", rune(line[0])):
+			l := diffLine{Kind: line[0], Index: i}
+			switch line[0] {
+			case ' ':
+				l.OldLine, l.NewLine = oldLine, newLine
+				oldLine, oldLeft = oldLine+1, oldLeft-1
+				newLine, newLeft = newLine+1, newLeft-1
+			case '+':
+				l.NewLine = newLine
+				newLine, newLeft = newLine+1, newLeft-1
+			case '-':
+				l.OldLine = oldLine
+				oldLine, oldLeft = oldLine+1, oldLeft-1
+			}
+			position++
+			l.Position = position
+			cur.Lines = append(cur.Lines, l)
+		case inHunk && strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"
+		case strings.HasPrefix(line, "diff --git "):
+			cur, oldLeft, newLeft = nil, 0, 0
+		case strings.HasPrefix(line, "--- "):
+			oldPath = diffPath(line[4:])
+		case strings.HasPrefix(line, "+++ "):
+			path := diffPath(line[4:])
+			if path == "/dev/null" {
+				path = oldPath
+			}
+			files = append(files, diffFile{Path: path})
+			cur, position = &files[len(files)-1], -1
+		case cur != nil && diffHunkPattern.MatchString(line):
+			m := diffHunkPattern.FindStringSubmatch(line)
+			oldLine, _ = strconv.Atoi(m[1])
+			newLine, _ = strconv.Atoi(m[3])
+			oldLeft, newLeft = hunkCount(m[2]), hunkCount(m[4])
+			position++
+		}
+	}
+	return files
+}
+
+// hunkCount reads a hunk header's line count, which is 1 when omitted
+func hunkCount(s string) int {
+	if s == "" {
+		return 1
+	}
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// diffPath strips the a/ or b/ prefix and any trailing timestamp
+func diffPath(s string) string {
+	s, _, _ = strings.Cut(s, "\t")
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "a/") || strings.HasPrefix(s, "b/") {
+		return s[2:]
+	}
+	return s
+}
+
+// findDiffFile returns the file whose path matches path, allowing for the
+// a/, b/, or ./ prefixes and paths given relative to a subdirectory
+func findDiffFile(files []diffFile, path string) *diffFile {
+	path = strings.TrimPrefix(diffPath(path), "./")
+	for i := range files {
+		if files[i].Path == path {
+			return &files[i]
+		}
+	}
+	for i := range files {
+		if strings.HasSuffix(files[i].Path, "/"+path) {
+			return &files[i]
+		}
+	}
+	return nil
+}
+
+// line returns the added or unchanged line with the new-file number n
+func (f *diffFile) line(n int) *diffLine {
+	for i := range f.Lines {
+		if f.Lines[i].Kind != '-' && f.Lines[i].NewLine == n {
+			return &f.Lines[i]
+		}
+	}
+	return nil
+}
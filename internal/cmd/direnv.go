@@ -0,0 +1,323 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// dirEnvFile sets project defaults for the directory tree it is in
+const dirEnvFile = ".arc-ask.env"
+
+// dirEnvKeys are the settings a .arc-ask.env may make
+var dirEnvKeys = []string{"provider", "model", "profile"}
+
+// dirEnv is a parsed .arc-ask.env
+type dirEnv struct {
+	Path     string
+	Settings map[string]string
+	SHA256   string
+}
+
+// dirEnvApproval is the decision on one .arc-ask.env; a changed file is
+// asked about again
+type dirEnvApproval struct {
+	SHA256  string    `json:"sha256"`
+	Allowed bool      `json:"allowed"`
+	Time    time.Time `json:"time"`
+}
+
+func dirEnvAllowPath() string {
+	return filepath.Join(stateDir(), "env-allow.json")
+}
+
+// findDirEnv returns the nearest .arc-ask.env in dir or its parents, or nil
+func findDirEnv(dir string) (*dirEnv, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		path := filepath.Join(dir, dirEnvFile)
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return parseDirEnv(path, data)
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// parseDirEnv reads KEY=VALUE lines; blank lines and # comments are skipped
+func parseDirEnv(path string, data []byte) (*dirEnv, error) {
+	sum := sha256.Sum256(data)
+	env := &dirEnv{Path: path, Settings: make(map[string]string), SHA256: hex.EncodeToString(sum[:])}
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("%s:%d: expected KEY=VALUE", path, n)
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !isDirEnvKey(key) {
+			return nil, fmt.Errorf("%s:%d: unknown setting %q (use %s)", path, n, key, strings.Join(dirEnvKeys, ", "))
+		}
+		env.Settings[key] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return env, scanner.Err()
+}
+
+func isDirEnvKey(key string) bool {
+	for _, k := range dirEnvKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// String lists the settings one per line, in a fixed order
+func (e *dirEnv) String() string {
+	var b strings.Builder
+	for _, k := range dirEnvKeys {
+		if v, ok := e.Settings[k]; ok {
+			fmt.Fprintf(&b, "  %s=%s\n", k, v)
+		}
+	}
+	return b.String()
+}
+
+func loadDirEnvApprovals() (map[string]dirEnvApproval, error) {
+	approvals := make(map[string]dirEnvApproval)
+	data, err := os.ReadFile(dirEnvAllowPath())
+	if os.IsNotExist(err) {
+		return approvals, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &approvals); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", dirEnvAllowPath(), err)
+	}
+	return approvals, nil
+}
+
+// setDirEnvApproval records the decision on the file's current content
+func setDirEnvApproval(env *dirEnv, allowed bool) error {
+	approvals, err := loadDirEnvApprovals()
+	if err != nil {
+		return err
+	}
+	approvals[env.Path] = dirEnvApproval{SHA256: env.SHA256, Allowed: allowed, Time: time.Now().UTC()}
+	path := dirEnvAllowPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(approvals, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// dirEnvStatus is "allowed", "denied", or "new" (never decided, or
+// changed since)
+func dirEnvStatus(env *dirEnv) (string, error) {
+	approvals, err := loadDirEnvApprovals()
+	if err != nil {
+		return "", err
+	}
+	a, ok := approvals[env.Path]
+	switch {
+	case !ok || a.SHA256 != env.SHA256:
+		return "new", nil
+	case a.Allowed:
+		return "allowed", nil
+	default:
+		return "denied", nil
+	}
+}
+
+// applyDirEnv applies the nearest .arc-ask.env over ask.yaml. A file is
+// only applied once allowed: the first time it is seen, or after it
+// changes, arc-ask asks on the terminal, and without one it is ignored.
+func applyDirEnv(cfg *Config, client *BridgeClient) error {
+	env, err := findDirEnv(".")
+	if err != nil {
+		return errors.NewCLIError("invalid " + dirEnvFile).WithCause(err)
+	}
+	if env == nil {
+		return nil
+	}
+
+	status, err := dirEnvStatus(env)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring %s: %v\n", env.Path, err)
+		return nil
+	}
+	switch status {
+	case "denied":
+		return nil
+	case "new":
+		allowed, err := confirm(fmt.Sprintf("%s sets:\n%sApply it whenever arc-ask runs in %s?", env.Path, env, filepath.Dir(env.Path)))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Note: ignoring %s until it is allowed (arc-ask env allow)\n", env.Path)
+			return nil
+		}
+		if err := setDirEnvApproval(env, allowed); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not save the decision on %s: %v\n", env.Path, err)
+		}
+		if !allowed {
+			return nil
+		}
+	}
+	return env.apply(cfg, client)
+}
+
+// apply sets the profile first, so the file's own provider and model win
+func (e *dirEnv) apply(cfg *Config, client *BridgeClient) error {
+	if name := e.Settings["profile"]; name != "" {
+		p, ok := cfg.Profiles[name]
+		if !ok {
+			return errors.NewCLIError(fmt.Sprintf("%s selects unknown profile %q", e.Path, name)).
+				WithSuggestions(profileSuggestion(cfg))
+		}
+		if p.Provider != "" || p.APIKey != "" {
+			provider := p.Provider
+			if provider == "" {
+				provider = client.provider
+			}
+			cfg.useProvider(client, provider, p.APIKey)
+		}
+		if p.Model != "" {
+			client.useModel(p.Model)
+		}
+	}
+	if provider := e.Settings["provider"]; provider != "" {
+		cfg.useProvider(client, provider, "")
+	}
+	if model := e.Settings["model"]; model != "" {
+		client.useModel(model)
+	}
+	return nil
+}
+
+func profileSuggestion(cfg *Config) string {
+	if len(cfg.Profiles) == 0 {
+		return "Define profiles under profiles: in " + displayPath(configPath())
+	}
+	names := make([]string, 0, len(cfg.Profiles))
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "Profiles in " + displayPath(configPath()) + ": " + strings.Join(names, ", ")
+}
+
+func newEnvCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "Show or allow the directory's .arc-ask.env",
+		Long: `A .arc-ask.env file sets project defaults for arc-ask run anywhere in
+its directory tree, overriding ` + defaultConfigPath + `:
+
+  # .arc-ask.env
+  provider=anthropic
+  model=claude-sonnet-4-20250514
+  profile=work
+
+profile selects one of the profiles: in ` + defaultConfigPath + ` (provider, model,
+api_key); provider and model in the file override the profile's.
+
+A file is only applied after you allow it. arc-ask asks the first time it
+finds one, and again whenever its content changes; without a terminal the
+file is ignored until allowed here.`,
+		Example: `  arc-ask env
+  arc-ask env allow
+  arc-ask env deny ~/src/legacy`,
+		Args: cobra.NoArgs,
+		// Skip the root's config loading so the file is not applied or asked about
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			env, err := findDirEnv(".")
+			if err != nil {
+				return errors.NewCLIError("invalid " + dirEnvFile).WithCause(err)
+			}
+			out := cmd.OutOrStdout()
+			if env == nil {
+				_, _ = fmt.Fprintf(out, "No %s in this directory or its parents\n", dirEnvFile)
+				return nil
+			}
+			status, err := dirEnvStatus(env)
+			if err != nil {
+				return err
+			}
+			if status == "new" {
+				status = "not allowed yet"
+			}
+			_, _ = fmt.Fprintf(out, "%s (%s)\n%s", env.Path, status, env)
+			return nil
+		},
+	}
+
+	decide := func(use, short string, allowed bool) *cobra.Command {
+		return &cobra.Command{
+			Use:   use + " [DIR]",
+			Short: short,
+			Args:  cobra.MaximumNArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if err := refuseStateless("env " + use); err != nil {
+					return err
+				}
+				dir := "."
+				if len(args) > 0 {
+					dir = args[0]
+				}
+				env, err := findDirEnv(dir)
+				if err != nil {
+					return errors.NewCLIError("invalid " + dirEnvFile).WithCause(err)
+				}
+				if env == nil {
+					return errors.NewCLIError(fmt.Sprintf("no %s in %s or its parents", dirEnvFile, dir))
+				}
+				if err := setDirEnvApproval(env, allowed); err != nil {
+					return err
+				}
+				verb := "Allowed"
+				if !allowed {
+					verb = "Denied"
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s %s\n%s", verb, env.Path, env)
+				return nil
+			},
+		}
+	}
+	cmd.AddCommand(
+		decide("allow", "Apply the .arc-ask.env in its directory tree", true),
+		decide("deny", "Never apply the .arc-ask.env unless it changes", false),
+	)
+	return cmd
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+)
+
+// configDirFlag and stateDirFlag are --config-dir and --state-dir; empty
+// uses the XDG base directories, else the defaults
+var configDirFlag, stateDirFlag string
+
+// defaultConfigDir holds ask.yaml, templates, and recipes unless
+// $XDG_CONFIG_HOME or --config-dir says otherwise
+const defaultConfigDir = "~/.config/arc"
+
+// absDir expands ~/ and makes dir absolute, so a later chdir does not
+// move it
+func absDir(dir string) string {
+	dir = ask.ExpandHome(dir)
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	return dir
+}
+
+// xdgDir is sub under the XDG base directory in env, or def when env is
+// unset. The spec ignores relative values, and so does this.
+func xdgDir(env, sub, def string) string {
+	if base := os.Getenv(env); filepath.IsAbs(base) {
+		return filepath.Join(base, sub)
+	}
+	return absDir(def)
+}
+
+// configDir is --config-dir, else $XDG_CONFIG_HOME/arc, else ~/.config/arc
+func configDir() string {
+	if configDirFlag != "" {
+		return absDir(configDirFlag)
+	}
+	return xdgDir("XDG_CONFIG_HOME", "arc", defaultConfigDir)
+}
+
+// configPath is ask.yaml in the config directory
+func configPath() string {
+	return filepath.Join(configDir(), "ask.yaml")
+}
+
+// stateDir is --state-dir, else $XDG_STATE_HOME/arc/ask, else
+// ~/.local/state/arc/ask
+func stateDir() string {
+	if stateDirFlag != "" {
+		return absDir(stateDirFlag)
+	}
+	return xdgDir("XDG_STATE_HOME", "arc/ask", defaultStateDir)
+}
+
+// cacheDir holds the response and template caches: $XDG_CACHE_HOME/arc/ask
+// when set and --state-dir is not, else cache/ in the state directory,
+// where it has always been
+func cacheDir() string {
+	if base := os.Getenv("XDG_CACHE_HOME"); stateDirFlag == "" && filepath.IsAbs(base) {
+		return filepath.Join(base, "arc/ask")
+	}
+	return filepath.Join(stateDir(), "cache")
+}
+
+// sessionDir is sessions/ under --state-dir, else
+// $XDG_DATA_HOME/arc/ask/sessions, else ~/.local/share/arc/ask/sessions
+func sessionDir() string {
+	if stateDirFlag != "" {
+		return filepath.Join(stateDir(), "sessions")
+	}
+	return xdgDir("XDG_DATA_HOME", "arc/ask/sessions", defaultSessionDir)
+}
+
+// displayPath shortens a path under the home directory to ~/ for messages
+func displayPath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" || home == "/" {
+		return path
+	}
+	if rest, ok := strings.CutPrefix(path, home+string(filepath.Separator)); ok {
+		return "~/" + rest
+	}
+	return path
+}
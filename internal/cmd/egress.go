@@ -0,0 +1,240 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// providerHosts are the API hosts pi sends each provider's requests to
+var providerHosts = map[string]string{
+	"anthropic":  "api.anthropic.com",
+	"openai":     "api.openai.com",
+	"google":     "generativelanguage.googleapis.com",
+	"groq":       "api.groq.com",
+	"openrouter": "openrouter.ai",
+	"xai":        "api.x.ai",
+}
+
+// EgressConfig restricts and records where arc-ask sends data
+type EgressConfig struct {
+	// Allow lists the hosts requests may go to; "*.example.com" matches
+	// subdomains. Empty allows any host.
+	Allow []string `yaml:"allow,omitempty"`
+
+	// Audit appends every outbound request to egress.jsonl in the state dir
+	Audit bool `yaml:"audit,omitempty"`
+
+	// SigningKeyEnv names the variable holding a key that signs each
+	// request (an X-Arc-Ask-Signature header) and audit record
+	SigningKeyEnv string `yaml:"signing_key_env,omitempty"`
+}
+
+func (e EgressConfig) enabled() bool {
+	return len(e.Allow) > 0 || e.Audit || e.SigningKeyEnv != ""
+}
+
+// allowed reports whether host may be contacted
+func (e EgressConfig) allowed(host string) bool {
+	if len(e.Allow) == 0 {
+		return true
+	}
+	host = strings.ToLower(host)
+	for _, pattern := range e.Allow {
+		pattern = strings.ToLower(pattern)
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// signingKey returns the key from SigningKeyEnv, failing if the variable
+// is named but empty so requests are never silently unsigned
+func (e EgressConfig) signingKey() ([]byte, error) {
+	if e.SigningKeyEnv == "" {
+		return nil, nil
+	}
+	key := os.Getenv(e.SigningKeyEnv)
+	if key == "" {
+		return nil, errors.NewCLIError(fmt.Sprintf("egress signing is configured but $%s is empty", e.SigningKeyEnv)).
+			WithSuggestions("Export the signing key, or remove signing_key_env from " + displayPath(configPath()))
+	}
+	return []byte(key), nil
+}
+
+// egressRecord is one line of the egress audit log
+type egressRecord struct {
+	Time       time.Time `json:"time"`
+	ID         string    `json:"id"`
+	Host       string    `json:"host"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path,omitempty"`
+	Via        string    `json:"via"` // http, or pi for provider requests
+	BodySHA256 string    `json:"body_sha256"`
+	Bytes      int       `json:"bytes"`
+	Signature  string    `json:"signature,omitempty"`
+}
+
+// signed is the string a signature covers
+func (r egressRecord) signed() string {
+	return strings.Join([]string{r.Time.Format(time.RFC3339Nano), r.ID, r.Host, r.Method, r.Path, r.BodySHA256}, "\n")
+}
+
+// egressGuard enforces the egress policy for one run
+type egressGuard struct {
+	cfg EgressConfig
+	key []byte
+	mu  sync.Mutex // serializes audit log writes
+}
+
+// egress is the active guard; nil when no egress policy is configured
+var egress *egressGuard
+
+// useEgress enables the policy in ask.yaml for HTTP requests and pi runs
+func useEgress(cfg EgressConfig) error {
+	if !cfg.enabled() {
+		return nil
+	}
+	key, err := cfg.signingKey()
+	if err != nil {
+		return err
+	}
+	egress = &egressGuard{cfg: cfg, key: key}
+	httpClient = &http.Client{Transport: &egressTransport{guard: egress, base: http.DefaultTransport}}
+	return nil
+}
+
+// stamp records a request about to leave, returning its record with the
+// ID and signature set
+func (g *egressGuard) stamp(host, method, path, via string, body []byte) (egressRecord, error) {
+	sum := sha256.Sum256(body)
+	r := egressRecord{
+		Time:       time.Now().UTC(),
+		ID:         randomHex(8),
+		Host:       host,
+		Method:     method,
+		Path:       path,
+		Via:        via,
+		BodySHA256: hex.EncodeToString(sum[:]),
+		Bytes:      len(body),
+	}
+	if g.key != nil {
+		mac := hmac.New(sha256.New, g.key)
+		mac.Write([]byte(r.signed()))
+		r.Signature = hex.EncodeToString(mac.Sum(nil))
+	}
+	if !g.cfg.Audit {
+		return r, nil
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return r, err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if stateless {
+		// The audit trail goes to the container's log instead
+		_, err = fmt.Fprintf(os.Stderr, "egress: %s\n", data)
+		return r, err
+	}
+	path = egressLogPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return r, err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return r, err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return r, err
+}
+
+func egressLogPath() string {
+	return filepath.Join(stateDir(), "egress.jsonl")
+}
+
+// checkProvider allows a pi run only when its provider's host is allowed,
+// and stamps it. pi picks its own default provider, so the policy needs
+// one set explicitly.
+func (g *egressGuard) checkProvider(provider, request string) error {
+	if g == nil {
+		return nil
+	}
+	host, ok := providerHosts[provider]
+	if provider == providerOllama {
+		host, ok = ollamaHost(), true
+	}
+	if !ok {
+		if provider == "" {
+			return fmt.Errorf("egress policy: set provider in %s so its host can be checked", displayPath(configPath()))
+		}
+		return fmt.Errorf("egress policy: unknown host for provider %q", provider)
+	}
+	if !g.cfg.allowed(host) {
+		return fmt.Errorf("egress policy: %s (provider %s) is not in egress.allow", host, provider)
+	}
+	if _, err := g.stamp(host, http.MethodPost, "", "pi", []byte(request)); err != nil {
+		return fmt.Errorf("egress audit: %w", err)
+	}
+	return nil
+}
+
+// egressTransport refuses requests to hosts outside the allowlist and
+// stamps the rest
+type egressTransport struct {
+	guard *egressGuard
+	base  http.RoundTripper
+}
+
+func (t *egressTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	host := req.URL.Hostname()
+	if !t.guard.cfg.allowed(host) {
+		return nil, fmt.Errorf("egress policy: %s is not in egress.allow", host)
+	}
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+	}
+	r, err := t.guard.stamp(host, req.Method, req.URL.Path, "http", body)
+	if err != nil {
+		return nil, fmt.Errorf("egress audit: %w", err)
+	}
+
+	// RoundTrip must not modify the caller's request
+	out := req.Clone(req.Context())
+	if req.Body != nil {
+		out.Body = io.NopCloser(bytes.NewReader(body))
+		out.ContentLength = int64(len(body))
+	}
+	out.Header.Set("X-Arc-Ask-Request-Id", r.ID)
+	if r.Signature != "" {
+		out.Header.Set("X-Arc-Ask-Timestamp", r.Time.Format(time.RFC3339Nano))
+		out.Header.Set("X-Arc-Ask-Signature", r.Signature)
+	}
+	return t.base.RoundTrip(out)
+}
@@ -0,0 +1,189 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Sanitization profiles for machine metadata, from most to least private
+const (
+	envStrict   = "strict"   // no hostname or username; workspace paths relative
+	envStandard = "standard" // home directory shown as ~
+	envOff      = "off"      // as captured
+)
+
+var envProfiles = []string{envStrict, envStandard, envOff}
+
+// PrivacyConfig sets what machine metadata may leave the machine
+type PrivacyConfig struct {
+	// EnvProfile is the least private profile allowed: strict, standard,
+	// or off. --env-profile may only tighten it. Unset defaults to
+	// standard and allows any.
+	EnvProfile string `yaml:"env_profile,omitempty"`
+}
+
+// envRank orders profiles by privacy, strict first; -1 is unknown
+func envRank(profile string) int {
+	for i, p := range envProfiles {
+		if p == profile {
+			return i
+		}
+	}
+	return -1
+}
+
+// resolveEnvProfile picks the profile for a run: the flag when set, else
+// the config, else standard. The flag may not be less private than the
+// config.
+func resolveEnvProfile(configured, flag string) (string, error) {
+	if configured == "" {
+		if flag == "" {
+			return envStandard, nil
+		}
+		configured = envOff
+	}
+	if envRank(configured) < 0 {
+		return "", errors.NewCLIError(fmt.Sprintf("invalid privacy.env_profile %q in %s", configured, displayPath(configPath()))).
+			WithSuggestions("Use " + strings.Join(envProfiles, ", "))
+	}
+	if flag == "" {
+		return configured, nil
+	}
+	if envRank(flag) < 0 {
+		return "", errors.NewCLIError(fmt.Sprintf("invalid --env-profile %q", flag)).
+			WithSuggestions("Use " + strings.Join(envProfiles, ", "))
+	}
+	if envRank(flag) > envRank(configured) {
+		return "", errors.NewCLIError(fmt.Sprintf("--env-profile %s is less private than %s, set in %s", flag, configured, displayPath(configPath()))).
+			WithSuggestions("Use --env-profile " + configured + " or stricter")
+	}
+	return flag, nil
+}
+
+// envSanitizer scrubs machine metadata from text under a profile
+type envSanitizer struct {
+	profile string
+	home    string
+	root    string         // the workspace root, shown as . under strict
+	names   *regexp.Regexp // hostname and username under strict; nil otherwise
+}
+
+// sanitizer is the active sanitizer; nil leaves text as captured
+var sanitizer *envSanitizer
+
+// useEnvProfile enables a profile for --env-context and tool traces
+func useEnvProfile(profile string) {
+	if profile == envOff {
+		sanitizer = nil
+		return
+	}
+	s := &envSanitizer{profile: profile}
+	s.home, _ = os.UserHomeDir()
+	if profile == envStrict {
+		s.root, _ = os.Getwd()
+		if top, err := gitRoot(); err == nil && top != "" {
+			s.root = top
+		}
+		var names []string
+		if host, err := os.Hostname(); err == nil {
+			// Both the full name and its first label, as prompts show
+			short, _, _ := strings.Cut(host, ".")
+			names = append(names, host, short)
+		}
+		names = append(names, currentUsername())
+		var alts []string
+		for _, n := range names {
+			// Very short names would match ordinary words
+			if len(n) >= 2 {
+				alts = append(alts, regexp.QuoteMeta(n))
+			}
+		}
+		if len(alts) > 0 {
+			s.names = regexp.MustCompile(`\b(` + strings.Join(alts, "|") + `)\b`)
+		}
+	}
+	sanitizer = s
+}
+
+// clean scrubs text; a nil sanitizer returns it unchanged
+func (s *envSanitizer) clean(text string) string {
+	if s == nil || text == "" {
+		return text
+	}
+	if s.root != "" && s.root != "/" {
+		text = strings.ReplaceAll(text, s.root+string(filepath.Separator), "./")
+		text = strings.ReplaceAll(text, s.root, ".")
+	}
+	if s.home != "" && s.home != "/" {
+		text = strings.ReplaceAll(text, s.home, "~")
+	}
+	if s.names != nil {
+		text = s.names.ReplaceAllString(text, "[redacted]")
+	}
+	return text
+}
+
+// cleanTools scrubs tool call arguments and results
+func (s *envSanitizer) cleanTools(calls []toolCall) []toolCall {
+	if s == nil {
+		return calls
+	}
+	out := make([]toolCall, len(calls))
+	for i, c := range calls {
+		c.Result = s.clean(c.Result)
+		if len(c.Args) > 0 {
+			c.Args = []byte(s.clean(string(c.Args)))
+		}
+		out[i] = c
+	}
+	return out
+}
+
+func currentUsername() string {
+	if u := os.Getenv("USER"); u != "" {
+		return u
+	}
+	return os.Getenv("USERNAME")
+}
+
+// environmentContext describes the machine for --env-context, leaving out
+// what the active profile does not allow
+func environmentContext() string {
+	var b strings.Builder
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(&b, "  %s: %s\n", name, value)
+		}
+	}
+	b.WriteString("Environment:\n")
+	field("os", runtime.GOOS+"/"+runtime.GOARCH)
+	if shell := os.Getenv("SHELL"); shell != "" {
+		field("shell", filepath.Base(shell))
+	}
+	field("terminal", os.Getenv("TERM"))
+	if os.Getenv("TMUX") != "" {
+		field("multiplexer", "tmux")
+	}
+	if sanitizer == nil || sanitizer.profile != envStrict {
+		if host, err := os.Hostname(); err == nil {
+			field("hostname", host)
+		}
+		field("user", currentUsername())
+	}
+	if cwd, err := os.Getwd(); err == nil {
+		field("directory", cwd)
+	}
+	if out, err := execCommand("git", "rev-parse", "--abbrev-ref", "HEAD").Output(); err == nil {
+		field("git branch", strings.TrimSpace(string(out)))
+	}
+	return sanitizer.clean(strings.TrimRight(b.String(), "\n"))
+}
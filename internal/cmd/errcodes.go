@@ -0,0 +1,293 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// errorsSchemaVersion is bumped only if a field of errorReport changes meaning
+const errorsSchemaVersion = 1
+
+// Stable failure codes. Codes and their exit codes are part of the
+// interface: add new ones, never rename or reuse.
+const (
+	codeInternal         = "internal"
+	codeUsage            = "usage"
+	codeConfigInvalid    = "config_invalid"
+	codeTemplateNotFound = "template_not_found"
+	codeTemplateInvalid  = "template_invalid"
+	codeInputUnavailable = "input_unavailable"
+	codePromptTooLarge   = "prompt_too_large"
+	codePiNotFound       = "pi_not_found"
+	codeProviderAuth     = "provider_auth"
+	codeRateLimited      = "rate_limited"
+	codeProviderFailed   = "provider_failed"
+	codeTimeout          = "timeout"
+	codeIncompleteAnswer = "incomplete_answer"
+	codeToolFailed       = "tool_failed"
+	codeEgressDenied     = "egress_denied"
+	codeDeclined         = "declined"
+	codeCheckFailed      = "check_failed"
+	codeInterrupted      = "interrupted"
+)
+
+// errorClass is what a code means to a script: its category, its exit
+// code, and whether the same run may succeed later
+type errorClass struct {
+	Code      string `json:"code"`
+	Category  string `json:"category"`
+	Exit      int    `json:"exit_code"`
+	Retryable bool   `json:"retryable"`
+	Meaning   string `json:"meaning"`
+}
+
+// errorCodes is the exit code taxonomy, in exit code order
+var errorCodes = []errorClass{
+	{codeInternal, "internal", 1, false, "Any other failure"},
+	{codeUsage, "usage", 2, false, "Invalid command, flag, or argument"},
+	{codeConfigInvalid, "config", 3, false, "ask.yaml is invalid"},
+	{codeTemplateNotFound, "template", 4, false, "The template does not exist"},
+	{codeTemplateInvalid, "template", 5, false, "The template cannot be rendered, e.g. a missing or invalid variable"},
+	{codeInputUnavailable, "input", 6, false, "A pane, file, or other input cannot be read"},
+	{codePromptTooLarge, "input", 7, false, "The prompt does not fit the model's context window"},
+	{codePiNotFound, "setup", 8, false, "The pi harness is not installed"},
+	{codeProviderAuth, "provider", 9, false, "The provider rejected the credentials"},
+	{codeRateLimited, "provider", 10, true, "A rate limit was reached, locally or at the provider"},
+	{codeProviderFailed, "provider", 11, true, "pi or the model provider failed"},
+	{codeTimeout, "timeout", 12, true, "A capture, connect, or total timeout expired"},
+	{codeIncompleteAnswer, "timeout", 13, true, "The answer was cut short; arc-ask resume continues it"},
+	{codeToolFailed, "tool", 14, false, "A tool call failed the run, or tool changes could not be sandboxed or applied"},
+	{codeEgressDenied, "policy", 15, false, "The egress policy does not allow the provider"},
+	{codeDeclined, "policy", 16, false, "The cost or preview confirmation was declined"},
+	{codeCheckFailed, "check", 17, false, "A FAIL verdict, eval score below --min-score, or --check disagreement"},
+	{codeInterrupted, "interrupted", 130, false, "Interrupted with ctrl+c"},
+}
+
+// errorClassFor looks up a code; unknown codes are internal
+func errorClassFor(code string) errorClass {
+	for _, c := range errorCodes {
+		if c.Code == code {
+			return c
+		}
+	}
+	return errorCodes[0]
+}
+
+// codedError tags an error with a stable code
+type codedError struct {
+	code string
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+
+func (e *codedError) Unwrap() error { return e.err }
+
+// coded tags err with code; nil stays nil
+func coded(code string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// Provider failures as pi reports them on stderr
+var (
+	providerAuthFailure = regexp.MustCompile(`(?i)\b(401|403|unauthori[sz]ed|forbidden|invalid[ _-]?(x-)?api[ _-]?key|authentication)\b`)
+	providerRateFailure = regexp.MustCompile(`(?i)\b(429|rate[ _-]?limit(ed)?|too many requests|quota)\b`)
+)
+
+// piFailureCode classifies a failed pi run: rejected credentials, a
+// provider rate limit, a failed last tool call, or a provider failure
+func piFailureCode(err error, calls []toolCall) string {
+	msg := err.Error()
+	switch {
+	case providerAuthFailure.MatchString(msg):
+		return codeProviderAuth
+	case providerRateFailure.MatchString(msg):
+		return codeRateLimited
+	case len(calls) > 0 && calls[len(calls)-1].Error:
+		return codeToolFailed
+	}
+	return codeProviderFailed
+}
+
+// errorInfo is a failure as data
+type errorInfo struct {
+	Code      string `json:"code"`
+	Category  string `json:"category"`
+	Message   string `json:"message"`
+	Hint      string `json:"hint,omitempty"`
+	Retryable bool   `json:"retryable"`
+	ExitCode  int    `json:"exit_code"`
+}
+
+// errorReport is what --errors json prints on stdout when a command fails
+type errorReport struct {
+	Version int       `json:"version"`
+	Error   errorInfo `json:"error"`
+}
+
+// cobraUsagePrefixes start cobra's own argument errors
+var cobraUsagePrefixes = []string{"unknown command", "accepts ", "requires at least", "requires at most", "received ", "invalid argument", "required flag(s)", "if any flags in the group"}
+
+// classifyError finds the code for err: the outermost tag, else what the
+// error itself shows
+func classifyError(err error) errorInfo {
+	info := errorInfo{Code: codeInternal, Message: err.Error()}
+	found := false
+	for e := err; e != nil; e = unwrapError(e) {
+		switch e := e.(type) {
+		case *codedError:
+			if !found {
+				info.Code, found = e.code, true
+			}
+		case *errors.CLIError:
+			if info.Hint == "" && len(e.Suggestions) > 0 {
+				info.Hint = strings.Join(e.Suggestions, "; ")
+			}
+			if !found && strings.HasPrefix(e.Message, "invalid --") {
+				info.Code, found = codeUsage, true
+			}
+		case *partialAnswerError:
+			if !found {
+				info.Code, found = codeIncompleteAnswer, true
+			}
+		case *os.PathError:
+			if !found {
+				info.Code, found = codeInputUnavailable, true
+			}
+		case *serverError:
+			if !found {
+				info.Code, found = codeProviderFailed, true
+				if e.status == 429 {
+					info.Code = codeRateLimited
+				}
+			}
+		}
+		if !found && e == context.DeadlineExceeded {
+			info.Code, found = codeTimeout, true
+		}
+		if !found && e == context.Canceled {
+			info.Code, found = codeInterrupted, true
+		}
+	}
+	if !found {
+		for _, p := range cobraUsagePrefixes {
+			if strings.HasPrefix(info.Message, p) {
+				info.Code = codeUsage
+			}
+		}
+	}
+	class := errorClassFor(info.Code)
+	info.Category, info.ExitCode, info.Retryable = class.Category, class.Exit, class.Retryable
+	return info
+}
+
+func unwrapError(err error) error {
+	if u, ok := err.(interface{ Unwrap() error }); ok {
+		return u.Unwrap()
+	}
+	return nil
+}
+
+// errorsFormat is --errors: text (the default) or json
+var errorsFormat string
+
+// errorsAsJSON reports whether failures go to stdout as JSON. It also
+// looks at the raw arguments, since a bad flag stops parsing before
+// --errors is read.
+func errorsAsJSON() bool {
+	if errorsFormat != "" {
+		return errorsFormat == "json"
+	}
+	for i, a := range os.Args[1:] {
+		if a == "--" {
+			break
+		}
+		if a == "--errors=json" || a == "--errors" && i+2 < len(os.Args) && os.Args[i+2] == "json" {
+			return true
+		}
+	}
+	return os.Getenv("ARC_ASK_ERRORS") == "json"
+}
+
+// useErrorsFlag checks --errors, defaulting to $ARC_ASK_ERRORS
+func useErrorsFlag(cmd *cobra.Command) error {
+	if !cmd.Flags().Changed("errors") {
+		errorsFormat = os.Getenv("ARC_ASK_ERRORS")
+	}
+	switch errorsFormat {
+	case "", "text", "json":
+		return nil
+	}
+	bad := errorsFormat
+	errorsFormat = ""
+	return errors.NewCLIError(fmt.Sprintf("invalid --errors %q", bad)).
+		WithSuggestions("Use text or json")
+}
+
+// ReportError reports a failed command and returns the process exit code
+// for its failure code. With --errors json the failure is printed on
+// stdout as an errorReport, otherwise on stderr.
+func ReportError(err error) int {
+	info := classifyError(err)
+	if !errorsAsJSON() {
+		fmt.Fprintf(os.Stderr, "arc-ask: %v\n", err)
+		return info.ExitCode
+	}
+	data, _ := json.Marshal(errorReport{Version: errorsSchemaVersion, Error: info})
+	fmt.Printf("%s\n", data)
+	return info.ExitCode
+}
+
+func newExitCodesCmd() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "exit-codes",
+		Short: "List the exit codes and what each failure means",
+		Long: `List every exit code arc-ask can return, with its stable failure code,
+category, and whether retrying the same run may succeed. Exit code 0 is
+success. The same codes appear in --errors json reports.`,
+		Example: `  arc-ask exit-codes
+  arc-ask exit-codes --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch format {
+			case "json":
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(errorCodes)
+			case "text":
+			default:
+				return errors.NewCLIError(fmt.Sprintf("invalid --format %q", format)).
+					WithSuggestions("Use text or json")
+			}
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "EXIT\tCODE\tCATEGORY\tRETRY\tMEANING")
+			for _, c := range errorCodes {
+				retry := "no"
+				if c.Retryable {
+					retry = "yes"
+				}
+				_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n", c.Exit, c.Code, c.Category, retry, c.Meaning)
+			}
+			return tw.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json")
+	return cmd
+}
@@ -0,0 +1,385 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// graderInstructions ask a model to judge an answer against one criterion
+const graderInstructions = `You are grading an AI assistant's answer for a test suite. Decide whether the answer meets the criterion. Be strict: partial or vague answers fail.
+
+Reply with PASS or FAIL on the first line, then one sentence explaining why.
+
+Criterion:
+%s
+
+Answer:
+%s`
+
+// EvalSuite is a set of cases that check prompts and templates
+type EvalSuite struct {
+	Name  string     `yaml:"name"`
+	Cases []EvalCase `yaml:"cases"`
+}
+
+// EvalCase asks one question and checks the answer
+type EvalCase struct {
+	Name      string            `yaml:"name"`
+	Prompt    string            `yaml:"prompt"` // a question or @template
+	Input     string            `yaml:"input,omitempty"`
+	InputFile string            `yaml:"input_file,omitempty"` // relative to the suite file
+	Vars      map[string]string `yaml:"vars,omitempty"`
+	Assert    []EvalAssertion   `yaml:"assert"`
+}
+
+// EvalAssertion is one check; exactly one field is set
+type EvalAssertion struct {
+	Contains    string          `yaml:"contains,omitempty"`
+	NotContains string          `yaml:"not_contains,omitempty"`
+	Regex       string          `yaml:"regex,omitempty"`
+	JSON        bool            `yaml:"json,omitempty"`  // the answer is valid JSON
+	Field       *EvalFieldCheck `yaml:"field,omitempty"` // a top-level JSON field
+	LLM         string          `yaml:"llm,omitempty"`   // a criterion for a model grader
+}
+
+// EvalFieldCheck compares a top-level field of a JSON answer
+type EvalFieldCheck struct {
+	Key    string `yaml:"key"`
+	Equals any    `yaml:"equals"`
+}
+
+func (a EvalAssertion) String() string {
+	switch {
+	case a.Contains != "":
+		return fmt.Sprintf("contains %q", a.Contains)
+	case a.NotContains != "":
+		return fmt.Sprintf("not_contains %q", a.NotContains)
+	case a.Regex != "":
+		return fmt.Sprintf("regex %s", a.Regex)
+	case a.JSON:
+		return "json"
+	case a.Field != nil:
+		return fmt.Sprintf("field %s == %v", a.Field.Key, a.Field.Equals)
+	case a.LLM != "":
+		return fmt.Sprintf("llm %q", a.LLM)
+	}
+	return "empty assertion"
+}
+
+// evalResult is the outcome of one case
+type evalResult struct {
+	Name     string        `json:"name"`
+	Passed   bool          `json:"passed"`
+	Checks   []evalCheck   `json:"checks"`
+	Error    string        `json:"error,omitempty"`
+	Answer   string        `json:"answer,omitempty"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+type evalCheck struct {
+	Assertion string `json:"assertion"`
+	Passed    bool   `json:"passed"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// evalReport is the scored outcome of a suite
+type evalReport struct {
+	Suite  string       `json:"suite"`
+	Passed int          `json:"passed"`
+	Total  int          `json:"total"`
+	Score  float64      `json:"score"`
+	Cases  []evalResult `json:"cases"`
+}
+
+func loadEvalSuite(path string) (*EvalSuite, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.NewCLIError("failed to read eval suite").WithCause(err)
+	}
+	var s EvalSuite
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return nil, errors.NewCLIError("invalid eval suite " + path).WithCause(err)
+	}
+	if s.Name == "" {
+		s.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if len(s.Cases) == 0 {
+		return nil, errors.NewCLIError("eval suite " + path + " has no cases")
+	}
+
+	dir := filepath.Dir(path)
+	for i := range s.Cases {
+		c := &s.Cases[i]
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("case %d", i+1)
+		}
+		if c.Prompt == "" {
+			return nil, errors.NewCLIError(fmt.Sprintf("%s: %s has no prompt", path, c.Name))
+		}
+		if len(c.Assert) == 0 {
+			return nil, errors.NewCLIError(fmt.Sprintf("%s: %s has no assertions", path, c.Name))
+		}
+		for _, a := range c.Assert {
+			if a.Regex != "" {
+				if _, err := regexp.Compile(a.Regex); err != nil {
+					return nil, errors.NewCLIError(fmt.Sprintf("%s: %s: invalid regex", path, c.Name)).WithCause(err)
+				}
+			}
+		}
+		if c.InputFile != "" {
+			file := c.InputFile
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(dir, file)
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, errors.NewCLIError(fmt.Sprintf("%s: %s: failed to read input", path, c.Name)).WithCause(err)
+			}
+			c.Input += string(data)
+		}
+	}
+	return &s, nil
+}
+
+func newEvalCmd(client *BridgeClient) *cobra.Command {
+	var (
+		format   string
+		minScore float64
+		parallel int
+		filter   string
+		plan     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "eval SUITE.yaml",
+		Short: "Run a suite of prompt cases and score the answers",
+		Long: `Run each case in a suite (a question or @template with input), check the
+answer with its assertions, and report a score. The command fails when the
+score is below --min-score, so a suite can gate template changes in CI.
+
+Assertions: contains, not_contains, regex, json (the answer parses),
+field (a top-level JSON field equals a value), and llm (a criterion
+judged by the model). Combine with --replay-fixtures for deterministic runs.`,
+		Example: `  arc-ask eval prompts/review-suite.yaml
+  arc-ask eval suite.yaml --run 'sql' --format json
+  arc-ask --replay-fixtures testdata/fixtures eval suite.yaml
+  arc-ask eval suite.yaml --plan`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return errors.NewCLIError(fmt.Sprintf("invalid --format %q", format)).
+					WithSuggestions("Use text or json")
+			}
+			suite, err := loadEvalSuite(args[0])
+			if err != nil {
+				return err
+			}
+			cases := suite.Cases
+			if filter != "" {
+				re, err := regexp.Compile(filter)
+				if err != nil {
+					return errors.NewCLIError("invalid --run pattern").WithCause(err)
+				}
+				cases = nil
+				for _, c := range suite.Cases {
+					if re.MatchString(c.Name) {
+						cases = append(cases, c)
+					}
+				}
+				if len(cases) == 0 {
+					return errors.NewCLIError(fmt.Sprintf("no cases match --run %q", filter))
+				}
+			}
+
+			runner := &ask.Runner{Client: client, Templates: userTemplates()}
+			if plan {
+				p, err := planEval(runner, client, cases)
+				if err != nil {
+					return err
+				}
+				return writePlan(cmd.OutOrStdout(), p, format)
+			}
+			report := evalReport{Suite: suite.Name, Total: len(cases), Cases: make([]evalResult, len(cases))}
+
+			if parallel < 1 {
+				parallel = 1
+			}
+			sem := make(chan struct{}, parallel)
+			var wg sync.WaitGroup
+			for i, c := range cases {
+				wg.Add(1)
+				sem <- struct{}{}
+				go func(i int, c EvalCase) {
+					defer wg.Done()
+					defer func() { <-sem }()
+					report.Cases[i] = runEvalCase(runner, client, c)
+				}(i, c)
+			}
+			wg.Wait()
+
+			for _, r := range report.Cases {
+				if r.Passed {
+					report.Passed++
+				}
+			}
+			report.Score = float64(report.Passed) / float64(report.Total)
+
+			if format == "json" {
+				if err := json.NewEncoder(cmd.OutOrStdout()).Encode(report); err != nil {
+					return err
+				}
+			} else if err := writeEvalReport(cmd.OutOrStdout(), report); err != nil {
+				return err
+			}
+
+			if report.Score < minScore {
+				return coded(codeCheckFailed, errors.NewCLIError(fmt.Sprintf("score %.2f is below --min-score %.2f", report.Score, minScore)))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "text", "Report format: text, json")
+	cmd.Flags().Float64Var(&minScore, "min-score", 1, "Fail when the share of passing cases is below this (0-1)")
+	cmd.Flags().IntVar(&parallel, "parallel", 1, "Cases to run at once")
+	cmd.Flags().StringVar(&filter, "run", "", "Only run cases whose name matches this regular expression")
+	cmd.Flags().BoolVar(&plan, "plan", false, "List the requests with estimated tokens and cost, without sending them")
+	return cmd
+}
+
+// planEval prices each case's question and its llm assertions, whose
+// grader is sent the case's answer, assumed to use its whole output budget
+func planEval(runner *ask.Runner, client *BridgeClient, cases []EvalCase) (*runPlan, error) {
+	plan := newRunPlan(client)
+	for _, c := range cases {
+		prompt, err := runner.Prompt(ask.Request{Prompt: c.Prompt, Input: c.Input, Vars: c.Vars})
+		if err != nil {
+			return nil, errors.NewCLIError(fmt.Sprintf("%s: cannot build the prompt", c.Name)).WithCause(err)
+		}
+		plan.addPrompt(client, c.Name, prompt)
+		answerTokens := plan.Requests[len(plan.Requests)-1].OutputTokens
+		for _, a := range c.Assert {
+			if a.LLM == "" {
+				continue
+			}
+			grader := ask.EstimateTokens(fmt.Sprintf(graderInstructions, a.LLM, "")) + answerTokens
+			plan.add(client, c.Name+": "+a.String(), grader, "grader")
+		}
+	}
+	return plan, nil
+}
+
+// runEvalCase asks a case's question and applies its assertions
+func runEvalCase(runner *ask.Runner, client *BridgeClient, c EvalCase) evalResult {
+	start := time.Now()
+	r := evalResult{Name: c.Name}
+
+	ctx, cancel := context.WithTimeout(context.Background(), client.timeout)
+	defer cancel()
+	res, err := runner.Run(ctx, ask.Request{Prompt: c.Prompt, Input: c.Input, Vars: c.Vars})
+	if err != nil {
+		r.Error = err.Error()
+		r.Duration = time.Since(start)
+		return r
+	}
+	r.Answer = res.Response
+
+	r.Passed = true
+	for _, a := range c.Assert {
+		check := evalCheck{Assertion: a.String()}
+		check.Passed, check.Reason = checkAssertion(ctx, client, a, res.Response)
+		if check.Passed {
+			check.Reason = ""
+		}
+		r.Passed = r.Passed && check.Passed
+		r.Checks = append(r.Checks, check)
+	}
+	r.Duration = time.Since(start)
+	return r
+}
+
+// checkAssertion reports whether an answer satisfies an assertion, and
+// why not
+func checkAssertion(ctx context.Context, client ask.Client, a EvalAssertion, answer string) (bool, string) {
+	switch {
+	case a.Contains != "":
+		return strings.Contains(answer, a.Contains), "text not found"
+	case a.NotContains != "":
+		return !strings.Contains(answer, a.NotContains), "text found"
+	case a.Regex != "":
+		return regexp.MustCompile(a.Regex).MatchString(answer), "no match"
+	case a.JSON:
+		var v any
+		if err := json.Unmarshal([]byte(jsonAnswer(answer)), &v); err != nil {
+			return false, err.Error()
+		}
+		return true, ""
+	case a.Field != nil:
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(jsonAnswer(answer)), &obj); err != nil {
+			return false, "answer is not a JSON object"
+		}
+		got, ok := obj[a.Field.Key]
+		if !ok {
+			return false, "field missing"
+		}
+		if fmt.Sprint(got) != fmt.Sprint(a.Field.Equals) {
+			return false, fmt.Sprintf("got %v", got)
+		}
+		return true, ""
+	case a.LLM != "":
+		verdict, err := client.Ask(ctx, fmt.Sprintf(graderInstructions, a.LLM, answer))
+		if err != nil {
+			return false, "grader failed: " + err.Error()
+		}
+		first, reason, _ := strings.Cut(strings.TrimSpace(verdict), "\n")
+		return strings.HasPrefix(strings.ToUpper(strings.TrimSpace(first)), "PASS"), strings.TrimSpace(reason)
+	}
+	return false, "assertion has no check"
+}
+
+// jsonAnswer unwraps a JSON answer from a fenced block if needed
+func jsonAnswer(answer string) string {
+	if blocks := ask.CodeBlocks(answer); len(blocks) > 0 {
+		return blocks[0].Code
+	}
+	return strings.TrimSpace(answer)
+}
+
+func writeEvalReport(w io.Writer, r evalReport) error {
+	for _, c := range r.Cases {
+		status := "PASS"
+		if !c.Passed {
+			status = "FAIL"
+		}
+		_, _ = fmt.Fprintf(w, "%s  %s (%s)\n", status, c.Name, c.Duration.Round(time.Millisecond))
+		if c.Error != "" {
+			msg, _, _ := strings.Cut(c.Error, "\n")
+			_, _ = fmt.Fprintf(w, "      error: %s\n", msg)
+		}
+		for _, check := range c.Checks {
+			if !check.Passed {
+				reason, _, _ := strings.Cut(check.Reason, "\n")
+				_, _ = fmt.Fprintf(w, "      %s: %s\n", check.Assertion, reason)
+			}
+		}
+	}
+	_, err := fmt.Fprintf(w, "\n%s: %d/%d passed (score %.2f)\n", r.Suite, r.Passed, r.Total, r.Score)
+	return err
+}
@@ -0,0 +1,138 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// compileExcludes turns --exclude globs into matchers. Globs follow
+// CODEOWNERS rules: "vendor/**" is relative to the context directory,
+// "*.min.js" matches at any depth.
+func compileExcludes(globs []string) []*regexp.Regexp {
+	out := make([]*regexp.Regexp, 0, len(globs))
+	for _, g := range globs {
+		out = append(out, codeownersPattern(filepath.ToSlash(g)))
+	}
+	return out
+}
+
+// compileExcludeLines parses --exclude-lines regular expressions
+func compileExcludeLines(patterns []string) ([]*regexp.Regexp, error) {
+	out := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, errors.NewCLIError(fmt.Sprintf("invalid --exclude-lines %q", p)).WithCause(err)
+		}
+		out = append(out, re)
+	}
+	return out, nil
+}
+
+// excludeLines drops lines matching any pattern and returns how many went
+func excludeLines(text string, patterns []*regexp.Regexp) (string, int) {
+	if len(patterns) == 0 || text == "" {
+		return text, 0
+	}
+	var (
+		b       strings.Builder
+		dropped int
+	)
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if matchesAny(patterns, strings.TrimRight(line, "\r\n")) {
+			dropped++
+			continue
+		}
+		b.WriteString(line)
+	}
+	return b.String(), dropped
+}
+
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandContextDirs replaces directories in a --context list with the text
+// files under them, skipping hidden entries and anything excluded. Files
+// named explicitly are kept as given. Expanded files inherit the
+// directory's --context-weight.
+func expandContextDirs(paths []string, exclude []*regexp.Regexp, weights map[string]int) ([]string, error) {
+	var out []string
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err != nil || !info.IsDir() {
+			// Read errors are reported with the file
+			out = append(out, p)
+			continue
+		}
+
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if path == p {
+				return nil
+			}
+			rel, _ := filepath.Rel(p, path)
+			rel = filepath.ToSlash(rel)
+			if strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if d.IsDir() {
+				if excluded(exclude, rel+"/", path+"/") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() || excluded(exclude, rel, path) || isBinaryFile(path) {
+				return nil
+			}
+			out = append(out, path)
+			if w, ok := weights[p]; ok {
+				if _, set := weights[path]; !set {
+					weights[path] = w
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, errors.NewCLIError("failed to read context directory " + p).WithCause(err)
+		}
+	}
+	return out, nil
+}
+
+// excluded matches a path relative to its context directory and as given
+func excluded(exclude []*regexp.Regexp, rel, path string) bool {
+	return matchesAny(exclude, rel) || matchesAny(exclude, filepath.ToSlash(filepath.Clean(path)))
+}
+
+// isBinaryFile sniffs the start of a file for NUL bytes
+func isBinaryFile(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	buf := make([]byte, 8000)
+	n, _ := io.ReadFull(f, buf)
+	return strings.IndexByte(string(buf[:n]), 0) >= 0
+}
@@ -0,0 +1,302 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Experiment arms
+const (
+	armControl   = "control"
+	armCandidate = "candidate"
+)
+
+// ExperimentConfig sends a share of requests to a candidate model, so it
+// can be trialled on real traffic before it becomes the default
+type ExperimentConfig struct {
+	Name     string  `yaml:"name,omitempty"`
+	Model    string  `yaml:"model,omitempty"`    // the candidate
+	Provider string  `yaml:"provider,omitempty"` // the candidate's, if not the default's
+	Percent  float64 `yaml:"percent,omitempty"`  // share of requests, 0-100
+}
+
+func (e ExperimentConfig) active() bool {
+	return e.Model != "" && e.Percent > 0
+}
+
+// name identifies the experiment in the usage log
+func (e ExperimentConfig) name() string {
+	if e.Name != "" {
+		return e.Name
+	}
+	return e.Model
+}
+
+// experimentArm is the arm a request was assigned to
+type experimentArm struct {
+	Name string `json:"name"`
+	Arm  string `json:"arm"` // control or candidate
+}
+
+// assignExperiment picks an arm for this request and, for the candidate,
+// points the client at the candidate model. It returns nil when no
+// experiment is running.
+func assignExperiment(cfg *Config, client *BridgeClient) *experimentArm {
+	e := cfg.Experiment
+	if !e.active() {
+		return nil
+	}
+	arm := &experimentArm{Name: e.name(), Arm: armControl}
+	if rand.Float64()*100 < e.Percent {
+		arm.Arm = armCandidate
+		if e.Provider != "" && e.Provider != client.provider {
+			cfg.useProvider(client, e.Provider, "")
+		}
+		client.useModel(e.Model)
+	}
+	return arm
+}
+
+// usageRecord is one line of the usage log kept while an experiment runs
+type usageRecord struct {
+	Time         time.Time `json:"time"`
+	RequestID    string    `json:"request_id"`
+	Experiment   string    `json:"experiment"`
+	Arm          string    `json:"arm"`
+	Provider     string    `json:"provider,omitempty"`
+	Model        string    `json:"model,omitempty"`
+	Template     string    `json:"template,omitempty"`
+	InputTokens  int       `json:"input_tokens"`
+	OutputTokens int       `json:"output_tokens"`
+	Estimated    bool      `json:"estimated,omitempty"`
+	CostUSD      float64   `json:"cost_usd,omitempty"`
+	Latency      float64   `json:"latency"`
+	FinishReason string    `json:"finish_reason,omitempty"`
+	Retries      int       `json:"retries"`
+}
+
+func usageLogPath() string {
+	return filepath.Join(stateDir(), "usage.jsonl")
+}
+
+// newUsageRecord tags a finished request with its experiment arm
+func newUsageRecord(arm *experimentArm, arg string, result askResult) usageRecord {
+	r := usageRecord{
+		Time:         time.Now().UTC(),
+		RequestID:    result.RequestID,
+		Experiment:   arm.Name,
+		Arm:          arm.Arm,
+		Provider:     result.Provider,
+		Model:        result.Model,
+		Latency:      result.Latency,
+		FinishReason: result.FinishReason,
+		Retries:      result.Retries,
+	}
+	if ask.IsTemplateRef(arg) {
+		r.Template = strings.TrimPrefix(arg, "@")
+	}
+	if u := result.Usage; u != nil {
+		r.InputTokens, r.OutputTokens, r.Estimated = u.InputTokens, u.OutputTokens, u.Estimated
+		if m, ok := lookupModel(r.Provider, r.Model); ok {
+			r.CostUSD = costEstimate{Model: m, InputTokens: r.InputTokens, OutputTokens: r.OutputTokens}.USD()
+		}
+	}
+	return r
+}
+
+func appendUsage(r usageRecord) error {
+	if stateless {
+		return nil
+	}
+	path := usageLogPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// readUsage returns the logged requests for one experiment
+func readUsage(experiment string) ([]usageRecord, error) {
+	f, err := os.Open(usageLogPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []usageRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r usageRecord
+		if json.Unmarshal(scanner.Bytes(), &r) != nil || r.Experiment != experiment {
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
+
+// armSummary compares one arm's requests
+type armSummary struct {
+	Arm        string  `json:"arm"`
+	Models     string  `json:"models"`
+	Requests   int     `json:"requests"`
+	AvgLatency float64 `json:"avg_latency"`
+	AvgInput   int     `json:"avg_input_tokens"`
+	AvgOutput  int     `json:"avg_output_tokens"`
+	CostUSD    float64 `json:"cost_usd"`
+	Retries    int     `json:"retries"`
+	Incomplete int     `json:"incomplete"` // cut off or interrupted
+
+	costKnown bool
+}
+
+func summarizeArms(records []usageRecord) []armSummary {
+	byArm := make(map[string]*armSummary)
+	models := make(map[string]map[string]bool)
+	for _, r := range records {
+		s := byArm[r.Arm]
+		if s == nil {
+			s = &armSummary{Arm: r.Arm}
+			byArm[r.Arm] = s
+			models[r.Arm] = make(map[string]bool)
+		}
+		s.Requests++
+		s.AvgLatency += r.Latency
+		s.AvgInput += r.InputTokens
+		s.AvgOutput += r.OutputTokens
+		s.CostUSD += r.CostUSD
+		s.costKnown = s.costKnown || r.CostUSD > 0
+		s.Retries += r.Retries
+		if r.FinishReason != "" && r.FinishReason != "stop" {
+			s.Incomplete++
+		}
+		model := r.Model
+		if model == "" {
+			model = "(pi default)"
+		}
+		models[r.Arm][model] = true
+	}
+
+	var out []armSummary
+	for _, arm := range []string{armControl, armCandidate} {
+		s := byArm[arm]
+		if s == nil {
+			continue
+		}
+		s.AvgLatency /= float64(s.Requests)
+		s.AvgInput /= s.Requests
+		s.AvgOutput /= s.Requests
+		var names []string
+		for m := range models[arm] {
+			names = append(names, m)
+		}
+		sort.Strings(names)
+		s.Models = strings.Join(names, ", ")
+		out = append(out, *s)
+	}
+	return out
+}
+
+func writeArmTable(w io.Writer, arms []armSummary) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "ARM\tMODEL\tREQUESTS\tAVG LATENCY\tAVG IN\tAVG OUT\tCOST\tRETRIES\tINCOMPLETE")
+	for _, s := range arms {
+		cost := "-"
+		if s.costKnown {
+			cost = formatUSD(s.CostUSD)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\t%.2fs\t%s\t%s\t%s\t%d\t%d\n",
+			s.Arm, s.Models, s.Requests, s.AvgLatency, formatTokens(s.AvgInput), formatTokens(s.AvgOutput), cost, s.Retries, s.Incomplete)
+	}
+	return tw.Flush()
+}
+
+func newExperimentCmd() *cobra.Command {
+	var (
+		name   string
+		format string
+	)
+	cmd := &cobra.Command{
+		Use:   "experiment",
+		Short: "Compare a candidate model with the default on real traffic",
+		Long: `While experiment: is set in ` + defaultConfigPath + `, that share of
+requests goes to the candidate model and every request is logged with its
+arm. This command compares the arms:
+
+  experiment:
+    name: haiku-trial
+    model: claude-3-5-haiku-20241022
+    percent: 10
+
+--no-experiment keeps a request out of the trial. Consensus runs and
+fixture replays are never part of it.`,
+		Example: `  arc-ask experiment
+  arc-ask experiment --name haiku-trial --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return errors.NewCLIError(fmt.Sprintf("invalid --format %q", format)).
+					WithSuggestions("Use text or json")
+			}
+			if name == "" {
+				cfg, err := loadConfig()
+				if err != nil {
+					return err
+				}
+				if !cfg.Experiment.active() {
+					return errors.NewCLIError("no experiment is configured").
+						WithSuggestions("Set experiment: (model, percent) in "+displayPath(configPath()), "Or name a past one with --name")
+				}
+				name = cfg.Experiment.name()
+			}
+			records, err := readUsage(name)
+			if err != nil {
+				return errors.NewCLIError("cannot read the usage log").WithCause(err)
+			}
+			arms := summarizeArms(records)
+			if format == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(arms)
+			}
+			if len(arms) == 0 {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "No requests logged for experiment %q yet\n", name)
+				return nil
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Experiment %s\n\n", name)
+			return writeArmTable(cmd.OutOrStdout(), arms)
+		},
+	}
+	cmd.Flags().StringVar(&name, "name", "", "Experiment to report (default: the configured one)")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json")
+	return cmd
+}
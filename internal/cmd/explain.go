@@ -0,0 +1,148 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+)
+
+// runExplanation is the --explain-run account of what an invocation did
+type runExplanation struct {
+	Sources      []runSource        `json:"sources,omitempty"`
+	Truncation   []string           `json:"truncation,omitempty"`
+	Duplicates   []duplicateContext `json:"duplicates,omitempty"`
+	Untrusted    []string           `json:"untrusted,omitempty"`
+	Injection    []injectionHit     `json:"injection,omitempty"`
+	Template     *runTemplate       `json:"template,omitempty"`
+	Routing      runRouting         `json:"routing"`
+	PromptTokens int                `json:"prompt_tokens"`
+	Retries      int                `json:"retries"`
+	RetryReasons []string           `json:"retry_reasons,omitempty"`
+	Cache        runCache           `json:"cache"`
+}
+
+// runSource is one piece of gathered input
+type runSource struct {
+	Kind   string `json:"kind"` // stdin, pane, or context
+	Name   string `json:"name,omitempty"`
+	Bytes  int    `json:"bytes"`
+	Tokens int    `json:"tokens"`
+}
+
+type runTemplate struct {
+	Name    string `json:"name"`
+	Path    string `json:"path,omitempty"`
+	Version string `json:"version"` // content hash, or "builtin"
+}
+
+// runRouting records which provider and model answered, and why
+type runRouting struct {
+	Provider string   `json:"provider,omitempty"`
+	Model    string   `json:"model,omitempty"`
+	Models   []string `json:"models,omitempty"` // --consensus
+	Reason   string   `json:"reason"`
+
+	// Aliases maps each model alias used to the model ID it resolved to
+	Aliases map[string]string `json:"aliases,omitempty"`
+}
+
+type runCache struct {
+	TemplateHits   int    `json:"template_hits"`
+	TemplateMisses int    `json:"template_misses"`
+	Response       string `json:"response"` // hit, miss, or off
+}
+
+// addInput records the piped or captured input
+func (e *runExplanation) addInput(pane, input string, sinceLast bool, capture captureFilter, lines int) {
+	if input == "" {
+		return
+	}
+	src := runSource{Kind: "stdin", Bytes: len(input), Tokens: ask.EstimateTokens(input)}
+	if pane != "" {
+		src.Kind, src.Name = "pane", pane
+		note := fmt.Sprintf("pane %s: %s filter, %d lines", pane, capture.mode, lines)
+		if sinceLast {
+			note += ", only output since the last --since-last run"
+		}
+		e.Truncation = append(e.Truncation, note)
+	}
+	e.Sources = append(e.Sources, src)
+}
+
+// addShellOutputs records commands included with --last-output
+func (e *runExplanation) addShellOutputs(outputs []shellOutput) {
+	for _, o := range outputs {
+		e.Sources = append(e.Sources, runSource{Kind: "shell", Name: o.Command, Bytes: len(o.Output), Tokens: ask.EstimateTokens(o.Output)})
+	}
+}
+
+// addContext records what happened to each context file
+func (e *runExplanation) addContext(res contextResult, budget int) {
+	for _, c := range res.Included {
+		e.Sources = append(e.Sources, runSource{Kind: "context", Name: c.Path, Bytes: c.Bytes, Tokens: c.Tokens})
+	}
+	for _, o := range res.Omitted {
+		e.Truncation = append(e.Truncation, fmt.Sprintf("context %s omitted: ~%d tokens over --context-budget %d", o.Path, o.Tokens, budget))
+	}
+	e.Duplicates = res.Duplicates
+}
+
+// setTemplate records the template and a version identifying its content
+func (e *runExplanation) setTemplate(arg string) {
+	if !ask.IsTemplateRef(arg) {
+		return
+	}
+	t, err := loadTemplate(arg)
+	if err != nil {
+		return
+	}
+	info := &runTemplate{Name: t.Name, Path: t.Path, Version: "builtin"}
+	if t.Path != "" {
+		if data, err := os.ReadFile(t.Path); err == nil {
+			sum := sha256.Sum256(data)
+			info.Version = hex.EncodeToString(sum[:6])
+		}
+	}
+	e.Template = info
+}
+
+// setRouting explains which provider and model were chosen
+func (e *runExplanation) setRouting(client *BridgeClient, models []string, tools []string) {
+	e.Routing = runRouting{Provider: client.provider, Model: client.model}
+	aliases := make(map[string]string)
+	if client.modelAlias != "" {
+		aliases[client.modelAlias] = client.model
+	}
+	for _, m := range models {
+		model, alias := resolveModel(m)
+		if alias != "" {
+			aliases[alias] = model
+		}
+		e.Routing.Models = append(e.Routing.Models, model)
+	}
+	if len(aliases) > 0 {
+		e.Routing.Aliases = aliases
+	}
+	cfg, _ := loadConfig()
+	switch {
+	case len(models) > 0:
+		e.Routing.Reason = "--consensus: each model answered, then the default model judged"
+	case len(tools) > 0:
+		e.Routing.Reason = "--tools requested; fallback mode runs pi without tools"
+	case cfg != nil && (cfg.Model != "" || cfg.Provider != ""):
+		e.Routing.Reason = "default from " + displayPath(configPath())
+	default:
+		e.Routing.Reason = "pi default"
+	}
+}
+
+func (e *runExplanation) setCache(response string) {
+	hits, misses := userTemplates().CacheStats()
+	e.Cache = runCache{TemplateHits: hits, TemplateMisses: misses, Response: response}
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+const explainErrorInstructions = `Explain this error to the engineer who hit it. Use the stack frames and source shown to find where it actually goes wrong, which is often not the innermost frame.
+
+Answer with:
+1. What happened, in one or two sentences.
+2. The root cause, pointing at file:line in the source shown.
+3. A concrete fix, with a code change when one is clear.
+
+If the cause is outside the code shown (configuration, input data, the environment), say so and say what to check.`
+
+// maxTraceLines bounds how much of the raw trace is sent with the frames
+const maxTraceLines = 150
+
+func newExplainErrorCmd(client *BridgeClient) *cobra.Command {
+	var (
+		pane     string
+		lines    int
+		frames   int
+		around   int
+		showOnly bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "explain-error [focus]",
+		Short: "Explain a stack trace or panic using the local source",
+		Long: `Parse a Go panic, Python traceback, JavaScript (Node) stack, or Java
+exception from the input, pick the frames in your code (skipping the
+standard library and dependencies), and send the error with the source
+around those frames. Paths from other machines, such as CI, are matched
+to files under the working directory by their longest existing suffix.
+
+Input without a recognizable trace is explained as is.`,
+		Example: `  go test ./... 2>&1 | arc-ask explain-error
+  arc-ask explain-error --pane dev:0.1 --lines 500
+  kubectl logs pod/api-7d9 | arc-ask explain-error "why only in production?"
+  pytest 2>&1 | arc-ask explain-error --show`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			input, err := gatherInput(cmd, pane, lines, captureFilter{mode: captureSmart})
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(input) == "" {
+				return errors.NewCLIError("no error to explain").
+					WithSuggestions("Pipe the output: go test ./... 2>&1 | arc-ask explain-error", "Or capture a pane: --pane session:0.0")
+			}
+
+			trace := parseStackTrace(input)
+			if showOnly {
+				if trace == nil {
+					return errors.NewCLIError("no stack trace recognized in the input")
+				}
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(trace)
+			}
+			if trace == nil {
+				fmt.Fprintln(os.Stderr, "Note: no stack trace recognized; explaining the raw input")
+			}
+
+			prompt := explainErrorPrompt(trace, input, frames, around)
+			if len(args) > 0 {
+				prompt += "\n\nFocus: " + args[0]
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), client.timeout)
+			defer cancel()
+			answer, err := client.Ask(ctx, prompt)
+			if err != nil {
+				return errors.NewCLIError("AI query failed").WithCause(err)
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), answer)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&pane, "pane", "", "Capture from tmux pane (e.g., session:0.0)")
+	cmd.Flags().IntVar(&lines, "lines", 300, "Lines to capture from pane")
+	cmd.Flags().IntVar(&frames, "frames", 5, "Frames in your code to include source for")
+	cmd.Flags().IntVar(&around, "source-lines", 8, "Lines of source to include around each frame")
+	cmd.Flags().BoolVar(&showOnly, "show", false, "Print the parsed trace as JSON without asking")
+	return cmd
+}
+
+// explainErrorPrompt assembles the error, its frames, the source around
+// the frames found locally, and the raw trace
+func explainErrorPrompt(trace *stackTrace, input string, maxFrames, around int) string {
+	var b strings.Builder
+	b.WriteString(explainErrorInstructions)
+
+	if trace != nil {
+		fmt.Fprintf(&b, "\n\nLanguage: %s\n", trace.Lang)
+		if len(trace.Messages) > 0 {
+			fmt.Fprintf(&b, "Error: %s\n", trace.Messages[0])
+			for _, cause := range trace.Messages[1:] {
+				fmt.Fprintf(&b, "Also raised: %s\n", cause)
+			}
+		}
+
+		b.WriteString("\nFrames, innermost first:\n")
+		for _, f := range trace.Frames {
+			note := ""
+			switch {
+			case f.Library:
+				note = " [library]"
+			case f.Path == "":
+				note = " [source not found]"
+			}
+			fmt.Fprintf(&b, "- %s%s\n", f, note)
+		}
+
+		shown := 0
+		for _, f := range trace.Frames {
+			if shown == maxFrames {
+				break
+			}
+			if f.Path == "" {
+				continue
+			}
+			snippet, err := sourceSnippet(f.Path, f.Line, around)
+			if err != nil {
+				continue
+			}
+			if shown == 0 {
+				b.WriteString("\nSource:\n")
+			}
+			fmt.Fprintf(&b, "\n%s:%d", f.Path, f.Line)
+			if f.Function != "" {
+				fmt.Fprintf(&b, " in %s", f.Function)
+			}
+			fmt.Fprintf(&b, "\n```%s\n%s```\n", trace.Lang, snippet)
+			shown++
+		}
+	}
+
+	// Keep both ends: Go prints the panic first, most others print it last
+	raw := strings.Split(strings.TrimSpace(input), "\n")
+	if len(raw) > maxTraceLines {
+		head, tail := maxTraceLines/3, maxTraceLines-maxTraceLines/3
+		omitted := fmt.Sprintf("[%d lines omitted]", len(raw)-maxTraceLines)
+		raw = append(append(raw[:head:head], omitted), raw[len(raw)-tail:]...)
+	}
+	b.WriteString("\nOutput:\n")
+	b.WriteString(strings.Join(raw, "\n"))
+	return b.String()
+}
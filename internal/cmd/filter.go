@@ -0,0 +1,82 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// filterInstructions constrain the model to emit code only
+const filterInstructions = `You are a text filter inside an editor. Apply the instruction to the code below.
+Reply with ONLY the complete resulting code: no explanations, no markdown fences, no commentary.
+Preserve indentation and any code the instruction does not ask you to change.`
+
+// runFilter implements --filter: code in on stdin, transformed code out on
+// stdout. On any failure the original input is written back unchanged so
+// editor buffers piped through arc-ask (:%!arc-ask --filter ...) survive.
+func runFilter(ctx context.Context, client ask.Client, w io.Writer, input, arg string, vars map[string]string) error {
+	if input == "" {
+		return errors.NewCLIError("--filter requires code on stdin").
+			WithSuggestions("Vim: :%!arc-ask --filter @refactor")
+	}
+	if arg == "" {
+		_, _ = io.WriteString(w, input)
+		return errors.NewCLIError("--filter requires a prompt or @template")
+	}
+
+	system, user, err := buildFilterPrompt(arg, input, vars)
+	if err == nil {
+		var answer string
+		asker, prompt := shapePrompt(client, system, user)
+		answer, err = asker.Ask(ctx, prompt)
+		if err == nil {
+			code := ask.ExtractCode(answer)
+			if strings.TrimSpace(code) == "" {
+				err = fmt.Errorf("model returned no code")
+			} else {
+				_, err = io.WriteString(w, matchTrailingNewline(code, input))
+				return err
+			}
+		}
+	}
+
+	_, _ = io.WriteString(w, input)
+	return errors.NewCLIError("filter failed, input left unchanged").WithCause(err)
+}
+
+func buildFilterPrompt(arg, input string, vars map[string]string) (string, string, error) {
+	instruction := arg
+	system := filterInstructions
+	if ask.IsTemplateRef(arg) {
+		t, err := loadTemplate(arg)
+		if err != nil {
+			return "", "", err
+		}
+		tsys, tuser, err := t.Render(ask.TemplateData{Vars: vars})
+		if err != nil {
+			return "", "", err
+		}
+		instruction = tuser
+		if tsys != "" {
+			system = tsys + "\n\n" + filterInstructions
+		}
+	}
+	user := fmt.Sprintf("Instruction: %s\n\nCode:\n%s", strings.TrimSpace(instruction), input)
+	return system, user, nil
+}
+
+// matchTrailingNewline makes out end with a newline exactly when ref does
+func matchTrailingNewline(out, ref string) string {
+	out = strings.TrimRight(out, "\n")
+	if strings.HasSuffix(ref, "\n") {
+		out += "\n"
+	}
+	return out
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+const findTermsInstructions = `A developer asks where something is in their codebase. List up to 10 words or identifier fragments likely to appear in the relevant source lines: names of functions, types, config keys, and flags, in the naming styles code would use. Reply with one term per line and nothing else.
+
+Question: %s`
+
+const findRankInstructions = `A developer asks: %s
+
+A text search of their repository found these candidate locations:
+
+%s
+Pick the candidates that answer the question, best first, at most %d. Prefer where the behavior is implemented or configured over where it is merely mentioned, tested, or logged.
+
+Reply only with JSON:
+{"answer": "one or two sentences answering the question directly", "matches": [{"id": 1, "why": "one sentence on what is there"}]}
+
+If no candidate answers the question, return an empty matches list and say so in answer.`
+
+// findResult is one ranked location
+type findResult struct {
+	Path    string `json:"path"`
+	Line    int    `json:"line"`
+	Why     string `json:"why,omitempty"`
+	Snippet string `json:"snippet"`
+}
+
+// findReport is the answer to a find question
+type findReport struct {
+	Question string       `json:"question"`
+	Answer   string       `json:"answer,omitempty"`
+	Results  []findResult `json:"results"`
+}
+
+func newFindCmd(client *BridgeClient) *cobra.Command {
+	var (
+		dir        string
+		format     string
+		candidates int
+		limit      int
+		excludes   []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "find QUESTION",
+		Short: "Find where something is implemented in the repository",
+		Long: `Answer "where is X" questions with file:line locations. The question is
+turned into search terms (its own words plus identifiers the model
+suggests), the repository is searched locally, and the model ranks the
+best candidates and explains each one. Only the candidate snippets are
+sent, not the repository.
+
+In a git repository, ignored files are skipped. Hidden files, binaries,
+node_modules, vendor, and build output are always skipped.`,
+		Example: `  arc-ask find "where is rate limiting configured?"
+  arc-ask find "what parses the config file" --dir internal/
+  arc-ask find "retry logic for HTTP calls" --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return errors.NewCLIError(fmt.Sprintf("invalid --format %q", format)).
+					WithSuggestions("Use text or json")
+			}
+			question := args[0]
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*client.timeout)
+			defer cancel()
+
+			terms := searchTerms(question)
+			terms = append(terms, suggestSearchTerms(ctx, client, question, terms)...)
+			if len(terms) == 0 {
+				return errors.NewCLIError("no search terms in the question").
+					WithSuggestions("Name the feature or behavior, e.g. arc-ask find \"where is rate limiting configured?\"")
+			}
+
+			files, err := searchFiles(dir, compileExcludes(append(defaultSearchExcludes, excludes...)))
+			if err != nil {
+				return err
+			}
+			hits := searchCode(files, terms, candidates)
+			if len(hits) == 0 {
+				return errors.NewCLIError("nothing in " + dir + " matches the question").
+					WithSuggestions("Searched for: " + strings.Join(terms, ", "))
+			}
+
+			report := findReport{Question: question}
+			report.Answer, report.Results, err = rankFindHits(ctx, client, question, hits, limit)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not rank matches (%v); showing the search results\n", err)
+				for _, h := range hits[:min(limit, len(hits))] {
+					report.Results = append(report.Results, findResult{Path: h.Path, Line: h.Line, Snippet: findSnippet(h.Path, h.Line)})
+				}
+			}
+
+			if format == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(report)
+			}
+			writeFindReport(cmd.OutOrStdout(), report)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", ".", "Directory to search")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json")
+	cmd.Flags().IntVar(&candidates, "candidates", 40, "Search hits the model chooses from")
+	cmd.Flags().IntVar(&limit, "limit", 5, "Most locations to show")
+	cmd.Flags().StringArrayVar(&excludes, "exclude", nil, "Skip matching paths (glob, e.g. 'testdata/**')")
+	return cmd
+}
+
+// suggestSearchTerms asks the model for identifiers the question's own
+// words would miss; failures only narrow the search
+func suggestSearchTerms(ctx context.Context, client ask.Client, question string, have []string) []string {
+	answer, err := client.Ask(ctx, fmt.Sprintf(findTermsInstructions, question))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not expand search terms: %v\n", err)
+		return nil
+	}
+	seen := make(map[string]bool)
+	for _, t := range have {
+		seen[t] = true
+	}
+	var terms []string
+	for _, line := range strings.Split(answer, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			continue
+		}
+		t := normalizeTerm(strings.Trim(strings.TrimSpace(line), "-*`'\"., "))
+		if len(t) < 3 || strings.ContainsAny(t, " \t") || seen[t] {
+			continue
+		}
+		seen[t] = true
+		terms = append(terms, t)
+		if len(terms) == 10 {
+			break
+		}
+	}
+	return terms
+}
+
+// rankFindHits has the model choose and explain the best candidates
+func rankFindHits(ctx context.Context, client ask.Client, question string, hits []searchHit, limit int) (string, []findResult, error) {
+	snippets := make([]string, len(hits))
+	var b strings.Builder
+	for i, h := range hits {
+		snippets[i] = findSnippet(h.Path, h.Line)
+		fmt.Fprintf(&b, "[%d] %s:%d\n```\n%s```\n\n", i+1, h.Path, h.Line, snippets[i])
+	}
+	answer, err := client.Ask(ctx, fmt.Sprintf(findRankInstructions, question, b.String(), limit))
+	if err != nil {
+		return "", nil, err
+	}
+
+	var ranked struct {
+		Answer  string `json:"answer"`
+		Matches []struct {
+			ID  int    `json:"id"`
+			Why string `json:"why"`
+		} `json:"matches"`
+	}
+	if err := json.Unmarshal([]byte(jsonAnswer(answer)), &ranked); err != nil {
+		return "", nil, fmt.Errorf("answer is not the requested JSON: %w", err)
+	}
+	var results []findResult
+	for _, m := range ranked.Matches {
+		if m.ID < 1 || m.ID > len(hits) || len(results) == limit {
+			continue
+		}
+		h := hits[m.ID-1]
+		results = append(results, findResult{Path: h.Path, Line: h.Line, Why: m.Why, Snippet: snippets[m.ID-1]})
+	}
+	return ranked.Answer, results, nil
+}
+
+func findSnippet(path string, line int) string {
+	snippet, err := sourceSnippet(path, line, 2)
+	if err != nil {
+		return ""
+	}
+	return snippet
+}
+
+func writeFindReport(w io.Writer, r findReport) {
+	if r.Answer != "" {
+		_, _ = fmt.Fprintf(w, "%s\n\n", r.Answer)
+	}
+	for _, res := range r.Results {
+		_, _ = fmt.Fprintf(w, "%s:%d", res.Path, res.Line)
+		if res.Why != "" {
+			_, _ = fmt.Fprintf(w, "  %s", res.Why)
+		}
+		_, _ = fmt.Fprintf(w, "\n%s\n", res.Snippet)
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+)
+
+// Finding severities, aligned with GitHub annotation levels
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+	SeverityNotice  = "notice"
+)
+
+// findingInstructions asks the model to put one finding per line in a
+// file:line form that extractFindings can parse
+const findingInstructions = `Report each finding on its own line as "path/to/file:LINE: SEVERITY: message", where SEVERITY is error, warning, or notice. Omit the location if none applies.`
+
+// structuredFindingInstructions asks for findings as a JSON document
+const structuredFindingInstructions = `After your analysis, output the findings as a single JSON code block of the form:
+` + "```json" + `
+{"findings": [{"rule": "short-kebab-id", "title": "Short rule title", "severity": "error|warning|notice", "file": "path/to/file", "line": 1, "message": "What is wrong and how to fix it"}]}
+` + "```" + `
+Use an empty list when there are no findings.`
+
+// Finding is a single issue extracted from a model answer
+type Finding struct {
+	Rule     string `json:"rule,omitempty"`
+	Title    string `json:"title,omitempty"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+var (
+	locationPattern = regexp.MustCompile(`([A-Za-z0-9_./\\-]+\.[A-Za-z0-9]+):(\d+)(?::\d+)?`)
+	listItemPattern = regexp.MustCompile(`^\s*(?:[-*•]|\d+[.)])\s+`)
+)
+
+// parseFindings prefers a structured JSON findings block and falls back to
+// scanning free-form text for file:line findings
+func parseFindings(answer string) []Finding {
+	if findings, ok := parseStructuredFindings(answer); ok {
+		return findings
+	}
+	return extractFindings(answer)
+}
+
+// parseStructuredFindings decodes a {"findings": [...]} JSON block
+func parseStructuredFindings(answer string) ([]Finding, bool) {
+	candidates := []string{answer}
+	for _, b := range ask.CodeBlocks(answer) {
+		if b.Lang == "json" || b.Lang == "" {
+			candidates = append(candidates, b.Code)
+		}
+	}
+	for _, c := range candidates {
+		var doc struct {
+			Findings *[]Finding `json:"findings"`
+		}
+		if err := json.Unmarshal([]byte(strings.TrimSpace(c)), &doc); err != nil || doc.Findings == nil {
+			continue
+		}
+		findings := *doc.Findings
+		for i := range findings {
+			findings[i].Severity = normalizeSeverity(findings[i].Severity)
+		}
+		return findings, true
+	}
+	return nil, false
+}
+
+func normalizeSeverity(s string) string {
+	if sev, ok := severityKeywords[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return sev
+	}
+	return SeverityWarning
+}
+
+// extractFindings pulls file:line findings out of free-form model output.
+// Lines without a location are only kept when they are list items that
+// carry an explicit severity keyword.
+func extractFindings(answer string) []Finding {
+	var findings []Finding
+	for _, raw := range strings.Split(answer, "\n") {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "```") {
+			continue
+		}
+		if _, ok := parseVerdict(line); ok {
+			continue
+		}
+
+		f := Finding{Severity: severityOf(line)}
+		if m := locationPattern.FindStringSubmatchIndex(line); m != nil {
+			f.File = line[m[2]:m[3]]
+			f.Line, _ = strconv.Atoi(line[m[4]:m[5]])
+			f.Message = cleanFindingMessage(line[:m[0]] + line[m[1]:])
+		} else {
+			if !listItemPattern.MatchString(line) || !hasSeverityKeyword(line) {
+				continue
+			}
+			f.Message = cleanFindingMessage(line)
+		}
+		if f.Message == "" {
+			continue
+		}
+		findings = append(findings, f)
+	}
+	return findings
+}
+
+var severityKeywords = map[string]string{
+	"critical": SeverityError,
+	"high":     SeverityError,
+	"error":    SeverityError,
+	"medium":   SeverityWarning,
+	"warning":  SeverityWarning,
+	"warn":     SeverityWarning,
+	"low":      SeverityNotice,
+	"info":     SeverityNotice,
+	"notice":   SeverityNotice,
+}
+
+var wordPattern = regexp.MustCompile(`[a-z]+`)
+
+func severityOf(line string) string {
+	for _, w := range wordPattern.FindAllString(strings.ToLower(line), -1) {
+		if sev, ok := severityKeywords[w]; ok {
+			return sev
+		}
+	}
+	return SeverityWarning
+}
+
+func hasSeverityKeyword(line string) bool {
+	for _, w := range wordPattern.FindAllString(strings.ToLower(line), -1) {
+		if _, ok := severityKeywords[w]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanFindingMessage strips list markers, separators, and a leading
+// severity label from a finding line
+func cleanFindingMessage(s string) string {
+	s = listItemPattern.ReplaceAllString(s, "")
+	s = strings.Trim(s, " \t:-—`*")
+	if label, rest, ok := strings.Cut(s, ":"); ok {
+		if _, isSev := severityKeywords[strings.ToLower(strings.Trim(label, " *[]"))]; isSev {
+			s = strings.TrimSpace(rest)
+		}
+	}
+	return s
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+// followUpBar lists the one-key actions offered after an answer
+const followUpBar = "[r]efine  [s]horter  [e]xpand  [c]opy  [q]uit "
+
+// followUpMessages are the follow-ups sent for s and e
+var followUpMessages = map[byte]string{
+	's': "Make that answer shorter. Keep only what matters most.",
+	'e': "Expand on that answer, with more detail and examples.",
+}
+
+// stdoutIsTerminal reports whether answers are printed to a terminal
+func stdoutIsTerminal() bool {
+	stat, err := os.Stdout.Stat()
+	return err == nil && stat.Mode()&os.ModeCharDevice != 0
+}
+
+// followUp offers the follow-up bar on the terminal after an answer, until
+// q. Each follow-up continues the conversation in sess, which ends with
+// the answer, and once there is one the conversation is saved, so arc-ask
+// chat can take it further. saved is whether sess is already stored.
+func followUp(client *BridgeClient, out io.Writer, sess *Session, saved bool) error {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return nil
+	}
+	defer tty.Close()
+
+	store := NewSessionStore()
+
+	for {
+		key, err := readKey(tty, followUpBar)
+		if err != nil {
+			return nil
+		}
+		if key >= 'A' && key <= 'Z' {
+			key += 'a' - 'A'
+		}
+
+		message := followUpMessages[key]
+		switch key {
+		case 'r':
+			if message, err = promptLine("Refine", ""); err != nil || message == "" {
+				continue
+			}
+		case 's', 'e':
+		case 'c':
+			if err := copyToClipboard(tty, sess.Turns[len(sess.Turns)-1].Content); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+			continue
+		case 'q', '\r', '\n', 0x1b, 0x03, 0x04: // q, Enter, Esc, Ctrl-C, Ctrl-D
+			if saved && !stateless {
+				fmt.Fprintf(os.Stderr, "Continue with: arc-ask chat --session %s\n", sess.ID)
+			}
+			return nil
+		default:
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), client.timeout)
+		reply, err := client.Ask(ctx, sess.Prompt(message))
+		cancel()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: follow-up failed: %v\n", err)
+			continue
+		}
+		sess.Append(RoleUser, message)
+		sess.Append(RoleAssistant, reply)
+		_, _ = fmt.Fprintf(out, "\n%s\n", reply)
+		titleSession(client, sess)
+		if err := store.Save(sess); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not save the conversation: %v\n", err)
+		} else {
+			saved = true
+		}
+	}
+}
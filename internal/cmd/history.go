@@ -0,0 +1,448 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// historyKeep is how many distinct invocations the history keeps
+const historyKeep = 1000
+
+// historySkipFlags are not restored from history: they pick from it,
+// stand in for the question, or say where history itself is kept
+var historySkipFlags = map[string]bool{
+	"pick-history": true, "mic": true, "mic-max": true,
+	"config-dir": true, "state-dir": true,
+}
+
+// historyEntry is one answered question and the flags it was asked with
+type historyEntry struct {
+	Question string    `json:"question"`
+	Args     []string  `json:"args"` // the full invocation, question included
+	Time     time.Time `json:"time"`
+	Title    string    `json:"title,omitempty"` // empty when the question is short enough
+	Tags     []string  `json:"tags,omitempty"`
+	Count    int       `json:"-"` // times asked, when read back
+}
+
+func historyPath() string {
+	return filepath.Join(stateDir(), "history.jsonl")
+}
+
+// historyEnabled is false when ask.yaml sets history: false
+func historyEnabled() bool {
+	if stateless {
+		return false
+	}
+	cfg, err := loadConfig()
+	return err != nil || cfg.History == nil || *cfg.History
+}
+
+// historyArgs rebuilds the invocation from the question and the flags set
+// on the command line
+func historyArgs(cmd *cobra.Command, question string) []string {
+	return append([]string{question}, flagArgs(cmd, historySkipFlags)...)
+}
+
+// flagArgs are the flags set on the command line, as --name=value, except
+// those in skip
+func flagArgs(cmd *cobra.Command, skip map[string]bool) []string {
+	var args []string
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		if skip[f.Name] {
+			return
+		}
+		if s, ok := f.Value.(pflag.SliceValue); ok {
+			for _, v := range s.GetSlice() {
+				args = append(args, "--"+f.Name+"="+v)
+			}
+			return
+		}
+		args = append(args, "--"+f.Name+"="+f.Value.String())
+	})
+	return args
+}
+
+// recordHistory appends an answered question to the history, trimming it
+// to the newest historyKeep invocations now and then
+func recordHistory(e historyEntry) error {
+	if !historyEnabled() {
+		return nil
+	}
+	path := historyPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(f, "%s\n", data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	lines, err := readHistoryLines()
+	if err != nil || len(lines) <= 2*historyKeep {
+		return err
+	}
+	entries := dedupeHistory(lines)
+	if len(entries) > historyKeep {
+		entries = entries[:historyKeep]
+	}
+	var b strings.Builder
+	for i := len(entries) - 1; i >= 0; i-- {
+		data, _ := json.Marshal(entries[i])
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func readHistoryLines() ([]historyEntry, error) {
+	f, err := os.Open(historyPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []historyEntry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for sc.Scan() {
+		var e historyEntry
+		if json.Unmarshal(sc.Bytes(), &e) == nil && len(e.Args) > 0 {
+			entries = append(entries, e)
+		}
+	}
+	return entries, sc.Err()
+}
+
+// dedupeHistory merges repeats of an invocation into its latest entry,
+// counting them, most recent first
+func dedupeHistory(lines []historyEntry) []historyEntry {
+	index := make(map[string]int)
+	var out []historyEntry
+	for i := len(lines) - 1; i >= 0; i-- {
+		key := strings.Join(lines[i].Args, "\x00")
+		if j, ok := index[key]; ok {
+			out[j].Count++
+			continue
+		}
+		e := lines[i]
+		e.Count = 1
+		index[key] = len(out)
+		out = append(out, e)
+	}
+	return out
+}
+
+// readHistory returns distinct past invocations, most recent first
+func readHistory() ([]historyEntry, error) {
+	lines, err := readHistoryLines()
+	if err != nil {
+		return nil, err
+	}
+	return dedupeHistory(lines), nil
+}
+
+func newHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Search past questions",
+	}
+	cmd.AddCommand(newHistorySearchCmd())
+	return cmd
+}
+
+func newHistorySearchCmd() *cobra.Command {
+	var tag string
+
+	cmd := &cobra.Command{
+		Use:   "search [TERM...]",
+		Short: "Find past questions by their words, title, or tags",
+		Long: `Find past questions whose question, title, or tags contain every term,
+ignoring case, most recent first. Each is shown with the command that asks it
+again.
+
+Questions are titled and tagged when they are recorded; --no-title records
+them without.`,
+		Example: `  arc-ask history search docker
+  arc-ask history search --tag git rebase`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := readHistory()
+			if err != nil {
+				return errors.NewCLIError("cannot read question history").WithCause(err)
+			}
+			out := cmd.OutOrStdout()
+			found := 0
+			for _, e := range entries {
+				if (tag != "" && !hasTag(e.Tags, tag)) || !historyMatches(e, args) {
+					continue
+				}
+				found++
+				title := orDefault(e.Title, heuristicTitle(e.Question))
+				if e.Count > 1 {
+					title += fmt.Sprintf("  ×%d", e.Count)
+				}
+				if len(e.Tags) > 0 {
+					title += "  [" + strings.Join(e.Tags, ", ") + "]"
+				}
+				_, _ = fmt.Fprintf(out, "%s  %s\n    arc-ask %s\n", e.Time.Local().Format("2006-01-02 15:04"), title, shellJoin(e.Args))
+			}
+			if found == 0 {
+				_, _ = fmt.Fprintln(out, "No matching questions")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&tag, "tag", "", "Only questions tagged `TAG`")
+	return cmd
+}
+
+// historyMatches reports whether every term is in the entry's question,
+// title, or tags, ignoring case
+func historyMatches(e historyEntry, terms []string) bool {
+	text := strings.ToLower(e.Question + "\n" + e.Title + "\n" + strings.Join(e.Tags, " "))
+	for _, t := range terms {
+		if !strings.Contains(text, strings.ToLower(t)) {
+			return false
+		}
+	}
+	return true
+}
+
+// completeQuestion completes the question argument: @templates, then past
+// questions, most often asked first
+func completeQuestion(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var out []string
+	if strings.HasPrefix(toComplete, "@") {
+		if templates, err := userTemplates().List(); err == nil {
+			for _, t := range templates {
+				out = append(out, "@"+t.Name+"\t"+t.Description)
+				for _, v := range t.VariantNames() {
+					out = append(out, "@"+t.Name+":"+v+"\t"+orDefault(t.Variants[v].Description, t.Description))
+				}
+			}
+		}
+		return out, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	entries, _ := readHistory()
+	counts := make(map[string]int)
+	var questions []string
+	for _, e := range entries {
+		if !strings.HasPrefix(strings.ToLower(e.Question), strings.ToLower(toComplete)) {
+			continue
+		}
+		if counts[e.Question] == 0 {
+			questions = append(questions, e.Question)
+		}
+		counts[e.Question] += e.Count
+	}
+	sort.SliceStable(questions, func(i, j int) bool { return counts[questions[i]] > counts[questions[j]] })
+	return append(out, questions...), cobra.ShellCompDirectiveNoFileComp
+}
+
+// fuzzyScore matches query's characters in order within s, ignoring case.
+// Higher is better: runs of adjacent characters and matches at word
+// starts count more.
+func fuzzyScore(query, s string) (int, bool) {
+	if query == "" {
+		return 0, true
+	}
+	q := []rune(strings.ToLower(query))
+	score, qi, run := 0, 0, 0
+	prev := ' '
+	for _, r := range strings.ToLower(s) {
+		if qi < len(q) && r == q[qi] {
+			run++
+			score += run
+			if !unicode.IsLetter(prev) && !unicode.IsDigit(prev) {
+				score += 3
+			}
+			qi++
+		} else {
+			run = 0
+		}
+		prev = r
+	}
+	return score, qi == len(q)
+}
+
+var (
+	pickSelected = lipgloss.NewStyle().Reverse(true)
+	pickDim      = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+)
+
+// historyPicker is the --pick-history model
+type historyPicker struct {
+	entries  []historyEntry
+	matches  []historyEntry
+	query    textinput.Model
+	cursor   int
+	height   int
+	width    int
+	chosen   *historyEntry
+	canceled bool
+}
+
+func newHistoryPicker(entries []historyEntry) *historyPicker {
+	q := textinput.New()
+	q.Prompt = "> "
+	q.Placeholder = "type to filter past questions"
+	q.Focus()
+	p := &historyPicker{entries: entries, query: q, height: 20, width: 80}
+	p.filter()
+	return p
+}
+
+// filter ranks entries by fuzzy score, then recency
+func (p *historyPicker) filter() {
+	type scored struct {
+		e     historyEntry
+		score int
+	}
+	var found []scored
+	for _, e := range p.entries {
+		if s, ok := fuzzyScore(p.query.Value(), e.Question); ok {
+			found = append(found, scored{e, s})
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].score > found[j].score })
+	p.matches = p.matches[:0]
+	for _, f := range found {
+		p.matches = append(p.matches, f.e)
+	}
+	p.cursor = min(p.cursor, max(len(p.matches)-1, 0))
+}
+
+func (p *historyPicker) Init() tea.Cmd { return textinput.Blink }
+
+func (p *historyPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.width, p.height = msg.Width, msg.Height
+		return p, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "esc":
+			p.canceled = true
+			return p, tea.Quit
+		case "enter":
+			if len(p.matches) > 0 {
+				p.chosen = &p.matches[p.cursor]
+			}
+			return p, tea.Quit
+		case "up", "ctrl+p", "ctrl+k":
+			if p.cursor > 0 {
+				p.cursor--
+			}
+			return p, nil
+		case "down", "ctrl+n", "ctrl+j":
+			if p.cursor < len(p.matches)-1 {
+				p.cursor++
+			}
+			return p, nil
+		}
+	}
+	var cmd tea.Cmd
+	before := p.query.Value()
+	p.query, cmd = p.query.Update(msg)
+	if p.query.Value() != before {
+		p.cursor = 0
+		p.filter()
+	}
+	return p, cmd
+}
+
+func (p *historyPicker) View() string {
+	var b strings.Builder
+	b.WriteString(p.query.View() + "\n")
+	rows := max(p.height-3, 1)
+	start := 0
+	if p.cursor >= rows {
+		start = p.cursor - rows + 1
+	}
+	for i := start; i < len(p.matches) && i < start+rows; i++ {
+		e := p.matches[i]
+		flags := shellJoin(e.Args[1:])
+		line := e.Question
+		if flags != "" {
+			line += "  " + pickDim.Render(fitColumn(flags, max(p.width/2, 20)))
+		}
+		if e.Count > 1 {
+			line += pickDim.Render(fmt.Sprintf("  ×%d", e.Count))
+		}
+		line = fitColumn(line, max(p.width-2, 10))
+		if i == p.cursor {
+			line = pickSelected.Render(line)
+		}
+		b.WriteString("  " + line + "\n")
+	}
+	b.WriteString(pickDim.Render(fmt.Sprintf("%d/%d  enter run · esc cancel", len(p.matches), len(p.entries))))
+	return b.String()
+}
+
+// pickHistory lets the user choose a past invocation on the terminal
+func pickHistory() (*historyEntry, error) {
+	entries, err := readHistory()
+	if err != nil {
+		return nil, errors.NewCLIError("cannot read question history").WithCause(err)
+	}
+	if len(entries) == 0 {
+		return nil, errors.NewCLIError("no past questions yet").
+			WithSuggestions("Questions are remembered once answered; history: false in " + displayPath(configPath()) + " turns this off")
+	}
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return nil, coded(codeUsage, errors.NewCLIError("--pick-history needs a terminal"))
+	}
+	defer tty.Close()
+
+	// Drawn on stderr, so stdout carries only the answer
+	_ = lipgloss.HasDarkBackground()
+	m := newHistoryPicker(entries)
+	if _, err := tea.NewProgram(m, tea.WithInput(tty), tea.WithOutput(os.Stderr)).Run(); err != nil {
+		return nil, errors.NewCLIError("history picker failed").WithCause(err)
+	}
+	if m.canceled || m.chosen == nil {
+		return nil, coded(codeDeclined, errors.NewCLIError("nothing picked"))
+	}
+	return m.chosen, nil
+}
@@ -0,0 +1,276 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// hookBypassEnv skips all arc-ask git hooks when set
+const hookBypassEnv = "ARC_ASK_SKIP_HOOKS"
+
+// hookMarker identifies hook scripts written by arc-ask
+const hookMarker = "# arc-ask:managed"
+
+// hookTemplates maps supported git hooks to the template they run
+var hookTemplates = map[string]string{
+	"commit-msg": "@commit-lint",
+	"pre-push":   "@diff-risk",
+}
+
+// zeroSHA is what git passes for a missing ref in pre-push
+const zeroSHA = "0000000000000000000000000000000000000000"
+
+func newHooksCmd(client *BridgeClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "Manage git hooks that run arc-ask templates",
+	}
+	cmd.AddCommand(newHooksInstallCmd(), newHooksRunCmd(client))
+	return cmd
+}
+
+func newHooksInstallCmd() *cobra.Command {
+	var (
+		blocking bool
+		template string
+		force    bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "install commit-msg|pre-push",
+		Short: "Install a git hook",
+		Long: `Install a git hook that runs an arc-ask template.
+
+commit-msg runs @commit-lint on the commit message; pre-push runs
+@diff-risk on the commits being pushed. Hooks are non-blocking by
+default: findings are printed but never stop git. With --blocking a
+FAIL verdict aborts the commit or push.
+
+Set ` + hookBypassEnv + `=1 to skip the hooks for a single command.`,
+		Example: `  arc-ask hooks install commit-msg
+  arc-ask hooks install pre-push --blocking
+  ` + hookBypassEnv + `=1 git push`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hook := args[0]
+			if _, ok := hookTemplates[hook]; !ok {
+				return errors.NewCLIError(fmt.Sprintf("unsupported hook %q", hook)).
+					WithSuggestions("Supported hooks: commit-msg, pre-push")
+			}
+
+			dir, err := gitHooksDir()
+			if err != nil {
+				return err
+			}
+			path := filepath.Join(dir, hook)
+
+			if existing, err := os.ReadFile(path); err == nil && !bytes.Contains(existing, []byte(hookMarker)) && !force {
+				return errors.NewCLIError(fmt.Sprintf("%s already exists and was not installed by arc-ask", path)).
+					WithSuggestions("Overwrite it with --force", "Or call arc-ask from your existing hook")
+			}
+
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return fmt.Errorf("create hooks dir: %w", err)
+			}
+			script := hookScript(hook, template, blocking)
+			if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+				return fmt.Errorf("write hook: %w", err)
+			}
+			// WriteFile keeps the mode of a hook it overwrites
+			if err := os.Chmod(path, 0o755); err != nil {
+				return fmt.Errorf("make hook executable: %w", err)
+			}
+
+			mode := "non-blocking"
+			if blocking {
+				mode = "blocking"
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Installed %s hook (%s): %s\n", hook, mode, path)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&blocking, "blocking", false, "Abort git on a FAIL verdict")
+	cmd.Flags().StringVar(&template, "template", "", "Template to run instead of the default")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing hook")
+	return cmd
+}
+
+func hookScript(hook, template string, blocking bool) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString(hookMarker + " " + hook + " hook\n")
+	b.WriteString("# Set " + hookBypassEnv + "=1 to bypass.\n")
+	b.WriteString("[ -n \"$" + hookBypassEnv + "\" ] && exit 0\n")
+	b.WriteString("exec arc-ask hooks run " + hook)
+	if blocking {
+		b.WriteString(" --blocking")
+	}
+	if template != "" {
+		b.WriteString(" --template " + shellQuote(template))
+	}
+	b.WriteString(" -- \"$@\"\n")
+	return b.String()
+}
+
+func newHooksRunCmd(client *BridgeClient) *cobra.Command {
+	var (
+		blocking bool
+		template string
+	)
+
+	cmd := &cobra.Command{
+		Use:    "run HOOK [args...]",
+		Short:  "Run a hook (invoked by installed git hooks)",
+		Hidden: true,
+		Args:   cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if os.Getenv(hookBypassEnv) != "" {
+				return nil
+			}
+
+			hook := args[0]
+			if template == "" {
+				template = hookTemplates[hook]
+			}
+			stderr := cmd.ErrOrStderr()
+			// skip reports a failure to review; only --blocking lets it abort git
+			skip := func(err error) error {
+				if blocking {
+					return err
+				}
+				_, _ = fmt.Fprintf(stderr, "arc-ask %s: skipped (%v)\n", hook, err)
+				return nil
+			}
+
+			var (
+				input string
+				err   error
+			)
+			switch hook {
+			case "commit-msg":
+				if len(args) < 2 {
+					return errors.NewCLIError("commit-msg hook requires the message file")
+				}
+				input, err = readCommitMessage(args[1])
+			case "pre-push":
+				input, err = prePushDiff(cmd.InOrStdin())
+			default:
+				return errors.NewCLIError(fmt.Sprintf("unsupported hook %q", hook))
+			}
+			if err != nil {
+				return skip(err)
+			}
+			if strings.TrimSpace(input) == "" {
+				return nil
+			}
+
+			system, user, err := buildPrompt(template, input, nil)
+			if err != nil {
+				return skip(err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), client.timeout)
+			defer cancel()
+			asker, prompt := client.shapePrompt(system, user)
+			answer, err := asker.Ask(ctx, prompt)
+			if err != nil {
+				// Never block git because the model is unreachable
+				_, _ = fmt.Fprintf(stderr, "arc-ask %s: skipped (%v)\n", hook, err)
+				return nil
+			}
+
+			_, _ = fmt.Fprintf(stderr, "arc-ask %s (%s):\n%s\n", hook, template, answer)
+
+			verdict, _ := parseVerdict(answer)
+			if verdict == VerdictFail && blocking {
+				return coded(codeCheckFailed, errors.NewCLIError(fmt.Sprintf("%s blocked by %s", hook, template)).
+					WithSuggestions(fmt.Sprintf("Bypass once with: %s=1 git ...", hookBypassEnv)))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&blocking, "blocking", false, "Fail on a FAIL verdict")
+	cmd.Flags().StringVar(&template, "template", "", "Template to run")
+	return cmd
+}
+
+// gitHooksDir returns the hooks directory, honoring core.hooksPath
+func gitHooksDir() (string, error) {
+	out, err := execCommand("git", "rev-parse", "--git-path", "hooks").Output()
+	if err != nil {
+		return "", errors.NewCLIError("not inside a git repository").WithCause(err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// readCommitMessage reads a commit message file without git comment lines
+func readCommitMessage(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("read commit message: %w", err)
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// prePushDiff builds the diff being pushed from pre-push's stdin
+// ("<local ref> <local sha> <remote ref> <remote sha>" per line)
+func prePushDiff(r io.Reader) (string, error) {
+	var b strings.Builder
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 4 {
+			continue
+		}
+		local, remote := fields[1], fields[3]
+		if local == zeroSHA {
+			continue // branch deletion
+		}
+
+		rangeSpec := remote + ".." + local
+		if remote == zeroSHA {
+			base, err := execCommand("git", "merge-base", local, "origin/HEAD").Output()
+			if err != nil {
+				continue // new branch without a known base
+			}
+			rangeSpec = strings.TrimSpace(string(base)) + ".." + local
+		}
+
+		out, err := execCommand("git", "diff", rangeSpec).Output()
+		if err != nil {
+			return "", fmt.Errorf("git diff %s: %w", rangeSpec, err)
+		}
+		b.Write(out)
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// shellQuote single-quotes s for POSIX sh
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// initHintMarker records that the first-run hint was shown
+const initHintMarker = "init-hint-shown"
+
+// starterTemplates is the optional template pack installed by arc-ask init
+var starterTemplates = map[string]string{
+	"pr-description": `name: pr-description
+description: Write a pull request description from a diff
+prompt: |
+  Write a pull request description for this diff: a one-paragraph summary of
+  what changes and why, then a short bullet list of notable details and how
+  to test them.
+
+  {{.Input}}
+`,
+	"test-ideas": `name: test-ideas
+description: Suggest test cases for code
+prompt: |
+  List the test cases this code needs, most important first. Cover edge
+  cases and failure paths; give each a one-line name and what it asserts.
+
+  {{.Input}}
+`,
+	"standup": `name: standup
+description: Turn notes or git log into a standup update
+prompt: |
+  Turn the following into a brief standup update with Yesterday, Today, and
+  Blockers sections.
+
+  {{.Input}}
+`,
+}
+
+func newInitCmd() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:   "init",
+		Short: "Set up provider, model, and templates",
+		Long: `Interactively create ` + defaultConfigPath + `: the provider and its API
+key, the default model, and the template directory. Optionally installs a
+starter pack of templates.
+
+The API key may be left empty to keep using the provider's environment
+variable (e.g. ANTHROPIC_API_KEY).`,
+		Args: cobra.NoArgs,
+		// Skip the root's config loading so a broken config can be replaced
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := refuseStateless("init"); err != nil {
+				return err
+			}
+			if configExists() && !force {
+				return errors.NewCLIError(displayPath(configPath()) + " already exists").
+					WithSuggestions("Re-run setup with: arc-ask init --force")
+			}
+
+			// With --force, an unreadable config is replaced rather than edited
+			cfg, err := loadConfig()
+			if err != nil {
+				cfg = &Config{}
+			}
+
+			providers := make([]string, 0, len(providerKeyEnv))
+			for p := range providerKeyEnv {
+				providers = append(providers, p)
+			}
+			sort.Strings(providers)
+
+			provider, err := promptLine("Provider ("+strings.Join(providers, ", ")+")", orDefault(cfg.Provider, "anthropic"))
+			if err != nil {
+				return err
+			}
+			env, ok := providerKeyEnv[provider]
+			if !ok {
+				return errors.NewCLIError(fmt.Sprintf("unknown provider %q", provider)).
+					WithSuggestions("Use one of: " + strings.Join(providers, ", "))
+			}
+			cfg.Provider = provider
+
+			keyQuestion := "API key (empty to use $" + env + ")"
+			if os.Getenv(env) != "" {
+				keyQuestion = "API key (empty to keep using $" + env + ", which is set)"
+			}
+			key, err := promptSecret(keyQuestion)
+			if err != nil {
+				return err
+			}
+			if key != "" {
+				cfg.APIKey = key
+			}
+
+			if cfg.Model, err = promptLine("Default model (empty for the provider default)", cfg.Model); err != nil {
+				return err
+			}
+			templates := displayPath(defaultTemplateDir())
+			if cfg.TemplateDir, err = promptLine("Template directory", orDefault(cfg.TemplateDir, templates)); err != nil {
+				return err
+			}
+			if cfg.TemplateDir == templates {
+				cfg.TemplateDir = ""
+			}
+
+			if err := saveConfig(cfg); err != nil {
+				return errors.NewCLIError("failed to write config").WithCause(err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s\n", displayPath(configPath()))
+
+			install, err := confirm("Install the starter template pack?")
+			if err != nil {
+				return err
+			}
+			if install {
+				dir := ask.ExpandHome(orDefault(cfg.TemplateDir, defaultTemplateDir()))
+				installed, err := installStarterTemplates(dir)
+				if err != nil {
+					return errors.NewCLIError("failed to install templates").WithCause(err)
+				}
+				for _, name := range installed {
+					_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Installed @%s\n", name)
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing config")
+	return cmd
+}
+
+// installStarterTemplates writes the starter pack, never overwriting a
+// template the user already has
+func installStarterTemplates(dir string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(starterTemplates))
+	for name := range starterTemplates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var installed []string
+	for _, name := range names {
+		path := filepath.Join(dir, name+".yaml")
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(starterTemplates[name]), 0o644); err != nil {
+			return installed, err
+		}
+		installed = append(installed, name)
+	}
+	return installed, nil
+}
+
+// suggestInit points first-time users at arc-ask init, once
+func suggestInit() {
+	if stateless || configExists() {
+		return
+	}
+	marker := filepath.Join(stateDir(), initHintMarker)
+	if _, err := os.Stat(marker); err == nil {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "Tip: run 'arc-ask init' to set up a provider, default model, and templates.")
+	if err := os.MkdirAll(filepath.Dir(marker), 0o700); err == nil {
+		_ = os.WriteFile(marker, nil, 0o600)
+	}
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
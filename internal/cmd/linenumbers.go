@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// outputLocations lists cited file:line locations for editors
+const outputLocations = "locations"
+
+// citationInstructions asks the model to cite code by the numbers shown
+const citationInstructions = `The context files are shown with line numbers ("12 | code"). When you refer to code, cite it as path/to/file:LINE or path/to/file:START-END, using those numbers and the paths as shown.`
+
+// unnumberedExtensions are prose and data files, which --line-numbers
+// leaves as they are
+var unnumberedExtensions = map[string]bool{
+	".md": true, ".markdown": true, ".txt": true, ".rst": true, ".adoc": true,
+	".log": true, ".csv": true, ".tsv": true,
+}
+
+// numbersLines reports whether --line-numbers numbers a context file
+func numbersLines(path string) bool {
+	return !isURL(path) && !unnumberedExtensions[strings.ToLower(filepath.Ext(path))]
+}
+
+// numberLines prefixes each line with its number, right-aligned
+func numberLines(data []byte) []byte {
+	lines := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+	width := len(strconv.Itoa(len(lines)))
+	var b bytes.Buffer
+	for i, line := range lines {
+		fmt.Fprintf(&b, "%*d | %s\n", width, i+1, line)
+	}
+	return b.Bytes()
+}
+
+// Citation is a file:line location an answer refers to
+type Citation struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	EndLine int    `json:"end_line,omitempty"`
+	Text    string `json:"text,omitempty"` // the answer's line that cites it
+}
+
+// citationPattern matches path:LINE and path:START-END
+var citationPattern = regexp.MustCompile(`([A-Za-z0-9_./\\-]+\.[A-Za-z0-9]+):(\d+)(?:[-–](\d+))?`)
+
+// parseCitations finds the locations an answer cites. Only files that
+// exist, and lines within them, are kept, so version numbers and the like
+// are not mistaken for locations.
+func parseCitations(answer string) []Citation {
+	lineCounts := make(map[string]int)
+	countLines := func(path string) int {
+		if n, ok := lineCounts[path]; ok {
+			return n
+		}
+		n := 0
+		if data, err := os.ReadFile(path); err == nil {
+			n = bytes.Count(data, []byte("\n"))
+			if len(data) > 0 && data[len(data)-1] != '\n' {
+				n++
+			}
+		}
+		lineCounts[path] = n
+		return n
+	}
+
+	seen := make(map[Citation]bool)
+	var out []Citation
+	for _, line := range strings.Split(answer, "\n") {
+		text := strings.TrimSpace(listItemPattern.ReplaceAllString(line, ""))
+		for _, m := range citationPattern.FindAllStringSubmatch(line, -1) {
+			c := Citation{File: m[1]}
+			c.Line, _ = strconv.Atoi(m[2])
+			if m[3] != "" {
+				c.EndLine, _ = strconv.Atoi(m[3])
+			}
+			n := countLines(c.File)
+			if c.Line < 1 || c.Line > n || c.EndLine != 0 && (c.EndLine < c.Line || c.EndLine > n) {
+				continue
+			}
+			if seen[c] {
+				continue
+			}
+			seen[c] = true
+			c.Text = text
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// writeLocations emits one file:line:column: text line per citation, the
+// form vim's quickfix list, emacs' grep mode, and editor problem matchers
+// read
+func writeLocations(w io.Writer, r report) error {
+	for _, c := range parseCitations(r.Answer) {
+		if _, err := fmt.Fprintf(w, "%s:%d:1: %s\n", c.File, c.Line, c.Text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
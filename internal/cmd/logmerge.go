@@ -0,0 +1,149 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// logSource is one --log: a file, a tmux pane, or a docker container
+type logSource struct {
+	kind   string // file, pane, or docker
+	target string
+}
+
+// parseLogSource reads a --log value: pane:TARGET, docker:CONTAINER, or a
+// file path
+func parseLogSource(spec string) (logSource, error) {
+	kind, target, ok := strings.Cut(spec, ":")
+	switch {
+	case ok && (kind == "pane" || kind == "docker"):
+	case ok && kind == "file":
+		spec = target
+		fallthrough
+	default:
+		kind, target = "file", spec
+	}
+	if target == "" {
+		return logSource{}, errors.NewCLIError(fmt.Sprintf("invalid --log %q", spec)).
+			WithSuggestions("Use a file path, pane:session:0.1, or docker:CONTAINER")
+	}
+	return logSource{kind: kind, target: target}, nil
+}
+
+// label names the source in merged output
+func (s logSource) label() string {
+	if s.kind == "file" {
+		return filepath.Base(s.target)
+	}
+	return s.kind + ":" + s.target
+}
+
+// read fetches the source: a pane's or container's last lines, or a file
+// whole
+func (s logSource) read(lines int, capture captureFilter) (string, error) {
+	switch s.kind {
+	case "pane":
+		content, err := capturePane(s.target, capture.scrollback(lines))
+		if err != nil {
+			return "", err
+		}
+		return capture.apply(content, lines), nil
+	case "docker":
+		out, err := execCommand("docker", "logs", "--timestamps", "--tail", strconv.Itoa(lines), s.target).CombinedOutput()
+		if err != nil {
+			return "", coded(codeInputUnavailable, errors.NewCLIError("cannot read docker logs of "+s.target).
+				WithCause(fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))).
+				WithSuggestions("Check the container name: docker ps"))
+		}
+		return string(out), nil
+	}
+	data, err := os.ReadFile(s.target)
+	if err != nil {
+		return "", errors.NewCLIError("cannot read log " + s.target).WithCause(err)
+	}
+	return string(data), nil
+}
+
+// logStream is a source's text and its label
+type logStream struct {
+	label string
+	text  string
+}
+
+// appendLogs adds each stream after the input as its own block
+func appendLogs(input string, streams []logStream) string {
+	var b strings.Builder
+	b.WriteString(input)
+	for _, s := range streams {
+		if b.Len() > 0 {
+			b.WriteString("\n\n")
+		}
+		fmt.Fprintf(&b, "Log (%s):\n%s", s.label, strings.TrimRight(s.text, "\n"))
+	}
+	return b.String()
+}
+
+// logEntry is a timestamped line with the untimestamped lines after it,
+// such as a stack trace
+type logEntry struct {
+	t      time.Time
+	source int
+	lines  []string
+}
+
+// mergeLogsByTime interleaves the streams' entries chronologically, each line
+// labelled with its source, and returns how many entries had a timestamp.
+// Lines before a stream's first timestamp come first; entries with equal
+// times keep the streams' order.
+func mergeLogsByTime(streams []logStream, norm timestampNorm) (string, int) {
+	var all strings.Builder
+	for _, s := range streams {
+		all.WriteString(s.text)
+		all.WriteByte('\n')
+	}
+	norm.dateFrom(all.String())
+
+	var entries []logEntry
+	timed := 0
+	for i, s := range streams {
+		cur := -1 // the stream's latest entry
+		for _, line := range strings.Split(strings.TrimRight(s.text, "\n"), "\n") {
+			t, ok := norm.firstTimestamp(line)
+			if ok {
+				timed++
+			}
+			if ok || cur < 0 {
+				entries = append(entries, logEntry{t: t, source: i, lines: []string{line}})
+				cur = len(entries) - 1
+				continue
+			}
+			entries[cur].lines = append(entries[cur].lines, line)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].t.Before(entries[j].t) })
+
+	width := 0
+	labels := make([]string, len(streams))
+	for i, s := range streams {
+		labels[i] = s.label
+		width = max(width, len(s.label))
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%d logs merged by time, each line prefixed with its source: %s]\n", len(streams), strings.Join(labels, ", "))
+	for _, e := range entries {
+		for _, line := range e.lines {
+			fmt.Fprintf(&b, "%-*s | %s\n", width, labels[e.source], line)
+		}
+	}
+	return b.String(), timed
+}
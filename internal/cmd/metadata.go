@@ -0,0 +1,190 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// callUsage is the token usage of the provider calls behind an answer
+type callUsage struct {
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	// Estimated is set when pi did not report usage and the counts were
+	// estimated from the text
+	Estimated bool `json:"estimated,omitempty"`
+	// ThinkingTokens is the estimated share of OutputTokens spent on the
+	// reasoning trace
+	ThinkingTokens int `json:"thinking_tokens,omitempty"`
+}
+
+// callStats accumulates usage across the calls one invocation makes,
+// including continuations, retries, and consensus models. It is shared by
+// copies of the client.
+type callStats struct {
+	mu           sync.Mutex
+	calls        int
+	usage        callUsage
+	finishReason string // of the last call
+	thinking     []string
+	tools        []toolCall
+}
+
+// record adds one completed call; a zero usage is replaced by an estimate
+func (s *callStats) record(usage callUsage, stop string, promptTokens, answerTokens int) {
+	if s == nil {
+		return
+	}
+	if usage.InputTokens == 0 && usage.OutputTokens == 0 {
+		usage = callUsage{InputTokens: promptTokens, OutputTokens: answerTokens, Estimated: true}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	s.usage.InputTokens += usage.InputTokens
+	s.usage.OutputTokens += usage.OutputTokens
+	s.usage.Estimated = s.usage.Estimated || usage.Estimated
+	s.finishReason = stop
+}
+
+// addThinking records the reasoning trace of a completed call
+func (s *callStats) addThinking(thinking string) {
+	if s == nil || thinking == "" {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.thinking = append(s.thinking, thinking)
+	s.usage.ThinkingTokens += ask.EstimateTokens(thinking)
+}
+
+// thinkingText returns the reasoning traces of every call so far
+func (s *callStats) thinkingText() string {
+	if s == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return strings.Join(s.thinking, "\n\n")
+}
+
+// addTools records the tool calls of a call, finished or not
+func (s *callStats) addTools(calls []toolCall) {
+	if s == nil || len(calls) == 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tools = append(s.tools, sanitizer.cleanTools(calls)...)
+}
+
+// toolCalls returns the tool calls made so far
+func (s *callStats) toolCalls() []toolCall {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]toolCall(nil), s.tools...)
+}
+
+// snapshot returns the usage so far, or nil when no call was made (a
+// cached or replayed answer)
+func (s *callStats) snapshot() (*callUsage, string) {
+	if s == nil {
+		return nil, ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.calls == 0 {
+		return nil, ""
+	}
+	u := s.usage
+	return &u, s.finishReason
+}
+
+// parseFields splits --fields and checks each name is an --output json
+// field
+func parseFields(spec string) ([]string, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	known := askResultFields()
+	var fields []string
+	for _, f := range strings.Split(spec, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		if !containsString(known, f) {
+			return nil, errors.NewCLIError(fmt.Sprintf("unknown --fields name %q", f)).
+				WithSuggestions("Fields: " + strings.Join(known, ", "))
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// askResultFields lists the JSON names of askResult's fields in order
+func askResultFields() []string {
+	t := reflect.TypeOf(askResult{})
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		names = append(names, name)
+	}
+	return names
+}
+
+// writeResultFields writes result as one JSON object holding only the
+// given fields, in that order; a field with no value is null
+func writeResultFields(w io.Writer, result askResult, fields []string) error {
+	if len(fields) == 0 {
+		return json.NewEncoder(w).Encode(result)
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+	var b bytes.Buffer
+	b.WriteByte('{')
+	for i, f := range fields {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		name, _ := json.Marshal(f)
+		b.Write(name)
+		b.WriteByte(':')
+		if v, ok := m[f]; ok {
+			b.Write(v)
+		} else {
+			b.WriteString("null")
+		}
+	}
+	b.WriteString("}\n")
+	_, err = w.Write(b.Bytes())
+	return err
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
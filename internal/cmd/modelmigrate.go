@@ -0,0 +1,152 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Policies for a request that names a retired model
+const (
+	migrateSubstitute = "substitute" // use the successor, with a warning
+	migrateFail       = "fail"       // refuse the request
+	migrateOff        = "off"        // send the model ID as is
+)
+
+// retiredModels maps model IDs providers have retired to their successors
+var retiredModels = map[string]string{
+	"claude-instant-1.2":         "claude-3-5-haiku-20241022",
+	"claude-2.0":                 "claude-sonnet-4-20250514",
+	"claude-2.1":                 "claude-sonnet-4-20250514",
+	"claude-3-sonnet-20240229":   "claude-sonnet-4-20250514",
+	"claude-3-opus-20240229":     "claude-opus-4-20250514",
+	"claude-3-5-sonnet-20240620": "claude-sonnet-4-20250514",
+	"claude-3-5-sonnet-20241022": "claude-sonnet-4-20250514",
+	"gpt-4-32k":                  "gpt-4o",
+	"gpt-4-vision-preview":       "gpt-4o",
+	"gpt-3.5-turbo-0613":         "gpt-4o-mini",
+	"gpt-3.5-turbo-16k":          "gpt-4o-mini",
+	"gemini-pro":                 "gemini-2.5-flash",
+	"gemini-1.0-pro":             "gemini-2.5-flash",
+}
+
+// ModelMigrationConfig sets what happens when a template, script, or
+// config pins a retired model
+type ModelMigrationConfig struct {
+	Policy  string            `yaml:"policy,omitempty"`  // substitute (default), fail, off
+	Retired map[string]string `yaml:"retired,omitempty"` // more retired IDs, or built-in overrides
+}
+
+func (m ModelMigrationConfig) policy() string {
+	if m.Policy == "" {
+		return migrateSubstitute
+	}
+	return m.Policy
+}
+
+// retired merges the built-in table with the config's; an empty successor
+// in the config unlists a built-in entry
+func (m ModelMigrationConfig) retired() map[string]string {
+	all := make(map[string]string, len(retiredModels)+len(m.Retired))
+	for old, next := range retiredModels {
+		all[old] = next
+	}
+	for old, next := range m.Retired {
+		if next == "" {
+			delete(all, old)
+			continue
+		}
+		all[old] = next
+	}
+	return all
+}
+
+func (m ModelMigrationConfig) check() error {
+	switch m.policy() {
+	case migrateSubstitute, migrateFail, migrateOff:
+		return nil
+	}
+	return errors.NewCLIError(fmt.Sprintf("invalid model_migration policy %q", m.Policy)).
+		WithSuggestions("Use substitute, fail, or off")
+}
+
+// warnedRetired holds the retired models already warned about, so a run
+// warns once per model
+var warnedRetired sync.Map
+
+// migrateModel returns the successor of a retired model under the
+// substitute policy, warning once. Any other model, or policy, is
+// returned unchanged.
+func migrateModel(model string) string {
+	cfg, err := loadConfig()
+	if err != nil || model == "" || cfg.ModelMigration.policy() != migrateSubstitute {
+		return model
+	}
+	next, ok := cfg.ModelMigration.retired()[model]
+	if !ok {
+		return model
+	}
+	if _, warned := warnedRetired.LoadOrStore(model, true); !warned {
+		fmt.Fprintf(os.Stderr, "Warning: %s is retired; using %s instead (model_migration in %s)\n", model, next, displayPath(configPath()))
+	}
+	return next
+}
+
+// checkRetired refuses a retired model under the fail policy
+func checkRetired(model string) error {
+	cfg, err := loadConfig()
+	if err != nil || model == "" || cfg.ModelMigration.policy() != migrateFail {
+		return nil
+	}
+	if next, ok := cfg.ModelMigration.retired()[model]; ok {
+		return fmt.Errorf("model %s is retired; use %s, or set model_migration.policy: substitute in %s", model, next, displayPath(configPath()))
+	}
+	return nil
+}
+
+func newModelsRetiredCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "retired",
+		Short: "List retired model IDs and their successors",
+		Long: `List the retired model IDs arc-ask knows, and the successor each is
+replaced with. model_migration in ` + defaultConfigPath + ` sets the policy
+(substitute, fail, or off) and adds entries:
+
+  model_migration:
+    policy: fail
+    retired:
+      my-old-finetune: my-new-finetune`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			retired := cfg.ModelMigration.retired()
+			names := make([]string, 0, len(retired))
+			for name := range retired {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "RETIRED\tSUCCESSOR")
+			for _, name := range names {
+				_, _ = fmt.Fprintf(tw, "%s\t%s\n", name, retired[name])
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "\nPolicy: %s\n", cfg.ModelMigration.policy())
+			return nil
+		},
+	}
+}
@@ -0,0 +1,343 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// defaultModelsURL is the catalog models refresh downloads
+const defaultModelsURL = "https://models.dev/api.json"
+
+// ModelInfo is what arc-ask knows about a model's limits, in tokens, its
+// price in USD per million tokens, and its tier and capabilities
+type ModelInfo struct {
+	Provider     string   `json:"provider"`
+	Name         string   `json:"name"`
+	Context      int      `json:"context"`
+	MaxOutput    int      `json:"max_output"`
+	InputCost    float64  `json:"input_cost,omitempty"`
+	OutputCost   float64  `json:"output_cost,omitempty"`
+	Tier         string   `json:"tier,omitempty"`
+	Capabilities []string `json:"capabilities"` // null when unknown
+}
+
+// builtinModels covers common models until models refresh is run. Names
+// match by prefix, so dated snapshots resolve to their family.
+var builtinModels = []ModelInfo{
+	{"anthropic", "claude-opus-4", 200000, 32000, 15, 75, "large", capsVRT},
+	{"anthropic", "claude-sonnet-4", 200000, 64000, 3, 15, "medium", capsVRT},
+	{"anthropic", "claude-3-7-sonnet", 200000, 64000, 3, 15, "medium", capsVRT},
+	{"anthropic", "claude-3-5-sonnet", 200000, 8192, 3, 15, "medium", capsVT},
+	{"anthropic", "claude-3-5-haiku", 200000, 8192, 0.8, 4, "small", capsT},
+	{"openai", "gpt-4.1", 1047576, 32768, 2, 8, "medium", capsVT},
+	{"openai", "gpt-4o", 128000, 16384, 2.5, 10, "medium", capsVT},
+	{"openai", "o3", 200000, 100000, 2, 8, "large", capsVRT},
+	{"openai", "o4-mini", 200000, 100000, 1.1, 4.4, "small", capsVRT},
+	{"google", "gemini-2.5-pro", 1048576, 65536, 1.25, 10, "large", capsVRT},
+	{"google", "gemini-2.5-flash", 1048576, 65536, 0.3, 2.5, "small", capsVRT},
+}
+
+// Capability sets of the built-in models
+var (
+	capsT   = []string{ask.CapabilityTools}
+	capsVT  = []string{ask.CapabilityVision, ask.CapabilityTools}
+	capsVRT = []string{ask.CapabilityVision, ask.CapabilityReasoning, ask.CapabilityTools}
+)
+
+// modelCatalog is the refreshed catalog in the state dir
+type modelCatalog struct {
+	Source  string      `json:"source"`
+	Updated time.Time   `json:"updated"`
+	Models  []ModelInfo `json:"models"`
+}
+
+func modelCatalogPath() string {
+	return filepath.Join(stateDir(), "models.json")
+}
+
+// knownModels returns the refreshed catalog, or the built-in table if
+// models refresh has never run
+func knownModels() ([]ModelInfo, string) {
+	data, err := os.ReadFile(modelCatalogPath())
+	if err == nil {
+		var c modelCatalog
+		if json.Unmarshal(data, &c) == nil && len(c.Models) > 0 {
+			return c.Models, c.Source
+		}
+	}
+	return builtinModels, "built-in"
+}
+
+// lookupModel finds a model's limits by exact name, then longest prefix.
+// The provider narrows the search when set.
+func lookupModel(provider, model string) (ModelInfo, bool) {
+	if model == "" {
+		return ModelInfo{}, false
+	}
+	models, _ := knownModels()
+	var (
+		best ModelInfo
+		ok   bool
+	)
+	for _, m := range models {
+		if provider != "" && m.Provider != provider {
+			continue
+		}
+		if m.Name == model {
+			return m, true
+		}
+		if strings.HasPrefix(model, m.Name) && len(m.Name) > len(best.Name) {
+			best, ok = m, true
+		}
+	}
+	return best, ok
+}
+
+// cheapestSmallModel is the cheapest small-tier model of the client's
+// provider, or empty when the provider or its models are unknown
+func cheapestSmallModel(client *BridgeClient) string {
+	provider := client.provider
+	if provider == "" {
+		m, ok := lookupModel("", client.model)
+		if !ok {
+			return ""
+		}
+		provider = m.Provider
+	}
+	models, _ := knownModels()
+	var best *ModelInfo
+	for i, m := range models {
+		if m.Provider == provider && m.Tier == "small" && (best == nil || m.InputCost < best.InputCost) {
+			best = &models[i]
+		}
+	}
+	if best == nil {
+		return ""
+	}
+	return best.Name
+}
+
+// checkContextWindow fails when a prompt cannot fit a model's context
+// window with room for the requested output. Unknown models pass.
+func checkContextWindow(provider, model string, promptTokens, maxTokens int) error {
+	m, ok := lookupModel(provider, model)
+	if !ok || m.Context <= 0 {
+		return nil
+	}
+	suggestions := []string{
+		"Cap the input with --context-budget N",
+		"Send fewer pane lines with --lines, or fewer -c files",
+		"Use a model with a larger window (arc-ask models)",
+	}
+	if promptTokens > m.Context {
+		return coded(codePromptTooLarge, errors.NewCLIError(fmt.Sprintf("prompt is %s tokens, %s limit %s",
+			formatTokens(promptTokens), m.Name, formatTokens(m.Context))).
+			WithSuggestions(suggestions...))
+	}
+	if maxTokens > 0 && promptTokens+maxTokens > m.Context {
+		return coded(codePromptTooLarge, errors.NewCLIError(fmt.Sprintf("prompt is %s tokens plus --max-tokens %s, %s limit %s",
+			formatTokens(promptTokens), formatTokens(maxTokens), m.Name, formatTokens(m.Context))).
+			WithSuggestions(append([]string{"Lower --max-tokens"}, suggestions...)...))
+	}
+	if m.MaxOutput > 0 && maxTokens > m.MaxOutput {
+		return coded(codeUsage, errors.NewCLIError(fmt.Sprintf("--max-tokens %d is above %s's output limit of %d", maxTokens, m.Name, m.MaxOutput)))
+	}
+	return nil
+}
+
+// formatTokens abbreviates large counts: 231k, 1.0M
+func formatTokens(n int) string {
+	switch {
+	case n >= 1000000:
+		return fmt.Sprintf("%.1fM", float64(n)/1e6)
+	case n >= 10000:
+		return fmt.Sprintf("%dk", n/1000)
+	}
+	return fmt.Sprint(n)
+}
+
+func newModelsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "models",
+		Short: "List known model context windows, output limits, and prices",
+		Long: `List the context window, maximum output, and price of known models. Before
+a query, arc-ask checks the prompt against the selected model's window and
+fails with the exact sizes instead of an opaque provider error, and asks
+for confirmation when the estimated cost is above confirm_cost. Tiers and
+capabilities are what templates' min_tier and requires are checked against.
+
+The built-in table covers common models; models refresh downloads a
+current catalog into the state directory. Aliases from model_aliases in
+ask.yaml are listed after the table.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			models, source := knownModels()
+			sorted := append([]ModelInfo(nil), models...)
+			sort.Slice(sorted, func(i, j int) bool {
+				if sorted[i].Provider != sorted[j].Provider {
+					return sorted[i].Provider < sorted[j].Provider
+				}
+				return sorted[i].Name < sorted[j].Name
+			})
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "PROVIDER\tMODEL\tCONTEXT\tMAX OUTPUT\tINPUT $/M\tOUTPUT $/M\tTIER\tCAPABILITIES")
+			for _, m := range sorted {
+				tier, caps := m.Tier, strings.Join(m.Capabilities, ",")
+				if tier == "" {
+					tier = "-"
+				}
+				if caps == "" {
+					caps = "-"
+				}
+				_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", m.Provider, m.Name, formatTokens(m.Context), formatTokens(m.MaxOutput), formatPrice(m.InputCost), formatPrice(m.OutputCost), tier, caps)
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "\nSource: %s\n", source)
+			if cfg, err := loadConfig(); err == nil && len(cfg.ModelAliases) > 0 {
+				names := make([]string, 0, len(cfg.ModelAliases))
+				for name := range cfg.ModelAliases {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "Aliases:")
+				for _, name := range names {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "  %s = %s\n", name, cfg.ModelAliases[name])
+				}
+			}
+			return nil
+		},
+	}
+	cmd.AddCommand(newModelsRefreshCmd(), newModelsRetiredCmd(), newModelsPullCmd())
+	return cmd
+}
+
+func newModelsRefreshCmd() *cobra.Command {
+	var url string
+	cmd := &cobra.Command{
+		Use:   "refresh",
+		Short: "Download the current model catalog",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := refuseStateless("models refresh"); err != nil {
+				return err
+			}
+			ctx, cancel := context.WithTimeout(cmd.Context(), 30*time.Second)
+			defer cancel()
+			models, err := fetchModelCatalog(ctx, url)
+			if err != nil {
+				return errors.NewCLIError("failed to refresh models").WithCause(err).
+					WithSuggestions("The built-in table is used until a refresh succeeds")
+			}
+
+			data, err := json.MarshalIndent(modelCatalog{Source: url, Updated: time.Now().UTC(), Models: models}, "", "  ")
+			if err != nil {
+				return err
+			}
+			path := modelCatalogPath()
+			if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+				return err
+			}
+			if err := os.WriteFile(path, data, 0o600); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Saved %d models to %s\n", len(models), path)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&url, "url", defaultModelsURL, "Catalog in the models.dev api.json format")
+	return cmd
+}
+
+// fetchModelCatalog downloads a models.dev style catalog:
+// {"<provider>": {"models": {"<id>": {"limit": {"context": N, "output": N},
+// "cost": {"input": USD, "output": USD}, "reasoning": bool, "tool_call": bool,
+// "modalities": {"input": ["text", "image"]}}}}}. Tiers come from the
+// built-in family a model belongs to.
+func fetchModelCatalog(ctx context.Context, url string) ([]ModelInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 32<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	var catalog map[string]struct {
+		Models map[string]struct {
+			Limit struct {
+				Context int `json:"context"`
+				Output  int `json:"output"`
+			} `json:"limit"`
+			Cost struct {
+				Input  float64 `json:"input"`
+				Output float64 `json:"output"`
+			} `json:"cost"`
+			Reasoning  bool `json:"reasoning"`
+			ToolCall   bool `json:"tool_call"`
+			Modalities struct {
+				Input []string `json:"input"`
+			} `json:"modalities"`
+		} `json:"models"`
+	}
+	if err := json.Unmarshal(body, &catalog); err != nil {
+		return nil, fmt.Errorf("unexpected catalog format: %w", err)
+	}
+
+	var models []ModelInfo
+	for provider, p := range catalog {
+		for name, m := range p.Models {
+			if m.Limit.Context <= 0 {
+				continue
+			}
+			caps := []string{}
+			for _, in := range m.Modalities.Input {
+				if in == "image" {
+					caps = append(caps, ask.CapabilityVision)
+				}
+			}
+			if m.Reasoning {
+				caps = append(caps, ask.CapabilityReasoning)
+			}
+			if m.ToolCall {
+				caps = append(caps, ask.CapabilityTools)
+			}
+			models = append(models, ModelInfo{
+				Provider: provider, Name: name, Context: m.Limit.Context, MaxOutput: m.Limit.Output,
+				InputCost: m.Cost.Input, OutputCost: m.Cost.Output,
+				Tier: builtinTier(provider, name), Capabilities: caps,
+			})
+		}
+	}
+	if len(models) == 0 {
+		return nil, fmt.Errorf("catalog lists no models with limits")
+	}
+	return models, nil
+}
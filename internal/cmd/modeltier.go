@@ -0,0 +1,172 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// smallModelWords mark the small member of a family, as in gpt-4o-mini
+var smallModelWords = map[string]bool{"mini": true, "nano": true, "haiku": true, "flash": true, "lite": true}
+
+// builtinTier is the tier of the built-in family a model belongs to, or
+// empty when it belongs to none
+func builtinTier(provider, name string) string {
+	var family ModelInfo
+	for _, m := range builtinModels {
+		if m.Provider == provider && strings.HasPrefix(name, m.Name) && len(m.Name) > len(family.Name) {
+			family = m
+		}
+	}
+	if family.Name == "" {
+		return ""
+	}
+	for _, word := range strings.Split(strings.TrimPrefix(name, family.Name), "-") {
+		if smallModelWords[word] {
+			return ask.ModelTiers[0]
+		}
+	}
+	return family.Tier
+}
+
+// modelShortfall lists what m lacks of the template's requires and
+// min_tier. What arc-ask does not know about m is not held against it.
+func modelShortfall(m ModelInfo, t *ask.Template) []string {
+	var missing []string
+	if m.Capabilities != nil {
+		for _, want := range t.Requires {
+			has := false
+			for _, c := range m.Capabilities {
+				has = has || c == want
+			}
+			if !has {
+				missing = append(missing, want)
+			}
+		}
+	}
+	need, _ := ask.TierRank(t.MinTier)
+	if have, ok := ask.TierRank(m.Tier); ok && have < need {
+		missing = append(missing, fmt.Sprintf("tier %s or above (it is %s)", t.MinTier, m.Tier))
+	}
+	return missing
+}
+
+// fitsTemplate is true when m is known to meet everything t asks for
+func fitsTemplate(m ModelInfo, t *ask.Template) bool {
+	if len(t.Requires) > 0 && m.Capabilities == nil {
+		return false
+	}
+	if _, ok := ask.TierRank(m.Tier); t.MinTier != "" && !ok {
+		return false
+	}
+	return len(modelShortfall(m, t)) == 0
+}
+
+// fittingModels are provider's known models that fit t: lowest tier,
+// then cheapest, first
+func fittingModels(provider string, t *ask.Template) []ModelInfo {
+	models, _ := knownModels()
+	var fits []ModelInfo
+	for _, m := range models {
+		if m.Provider == provider && fitsTemplate(m, t) {
+			fits = append(fits, m)
+		}
+	}
+	sort.SliceStable(fits, func(i, j int) bool {
+		ri, _ := ask.TierRank(fits[i].Tier)
+		rj, _ := ask.TierRank(fits[j].Tier)
+		if ri != rj {
+			return ri < rj
+		}
+		if fits[i].InputCost != fits[j].InputCost {
+			return fits[i].InputCost < fits[j].InputCost
+		}
+		return len(fits[i].Name) < len(fits[j].Name)
+	})
+	return fits
+}
+
+// fitTemplateModel checks the client's model against the template's
+// requires and min_tier. A model from --model (modelFlag) or from the
+// template's own defaults that falls short fails; one from ask.yaml is
+// upgraded to the first model that fits. Unknown models are not checked.
+func fitTemplateModel(client *BridgeClient, ref string, modelFlag bool) error {
+	t, err := loadTemplate(ref)
+	if err != nil || len(t.Requires) == 0 && t.MinTier == "" || client.model == "" {
+		return nil
+	}
+	m, ok := lookupModel(client.provider, client.model)
+	if !ok {
+		return nil
+	}
+	missing := modelShortfall(m, t)
+	if len(missing) == 0 {
+		return nil
+	}
+
+	provider := client.provider
+	if provider == "" {
+		provider = m.Provider
+	}
+	fits := fittingModels(provider, t)
+	need := strings.Join(missing, ", ")
+	var suggestions []string
+	if len(fits) > 0 {
+		suggestions = append(suggestions, "Use a model that fits, such as --model "+fits[0].Name)
+	}
+
+	switch {
+	case modelFlag:
+		return coded(codeUsage, errors.NewCLIError(fmt.Sprintf("@%s needs %s, which --model %s lacks", t.Name, need, client.model)).
+			WithSuggestions(append(suggestions, "Or leave out --model to let arc-ask pick one")...))
+	case t.Defaults != nil && t.Defaults.Model != "":
+		return coded(codeTemplateInvalid, errors.NewCLIError(fmt.Sprintf("@%s needs %s, which its default model %s lacks", t.Name, need, client.model)).
+			WithSuggestions(append(suggestions, "Fix defaults.model in "+templateSource(t))...))
+	case len(fits) == 0:
+		return coded(codeConfigInvalid, errors.NewCLIError(fmt.Sprintf("@%s needs %s, and no known %s model has it", t.Name, need, provider)).
+			WithSuggestions("Pass --model with a model that fits", "See tiers and capabilities with arc-ask models"))
+	}
+	fmt.Fprintf(os.Stderr, "Note: @%s needs %s; using %s instead of %s\n", t.Name, need, fits[0].Name, client.model)
+	client.useModel(fits[0].Name)
+	return nil
+}
+
+// templateSource names where a template came from, for messages
+func templateSource(t *ask.Template) string {
+	if t.Path == "" {
+		return "the built-in @" + t.Name
+	}
+	return t.Path
+}
+
+// templateAccepts reports whether model meets the template's requires and
+// min_tier; unknown models and plain questions are accepted
+func templateAccepts(ref, provider, model string) bool {
+	if !ask.IsTemplateRef(ref) {
+		return true
+	}
+	t, err := loadTemplate(ref)
+	if err != nil {
+		return true
+	}
+	model, _ = resolveModel(model)
+	m, ok := lookupModel(provider, model)
+	return !ok || len(modelShortfall(m, t)) == 0
+}
+
+// experimentFits keeps templates out of an experiment whose candidate
+// model they would refuse
+func experimentFits(cfg *Config, client *BridgeClient, ref string) bool {
+	provider := cfg.Experiment.Provider
+	if provider == "" {
+		provider = client.provider
+	}
+	return templateAccepts(ref, provider, cfg.Experiment.Model)
+}
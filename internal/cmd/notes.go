@@ -0,0 +1,161 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// appleNotesPrefix selects Apple Notes instead of a folder: apple-notes:Folder
+const appleNotesPrefix = "apple-notes:"
+
+// maxNoteInput bounds how much of the input is kept in a note
+const maxNoteInput = 4000
+
+// noteNameChars are unsafe in file names or break Obsidian links
+var noteNameChars = regexp.MustCompile(`[\\/:*?"<>|#^\[\]\x00-\x1f]+`)
+
+// note is one question and answer saved for later
+type note struct {
+	Question string
+	Input    string
+	Answer   string
+	Provider string
+	Model    string
+	Template string
+	Time     time.Time
+}
+
+// title is the note's heading and file name: the question's first line
+func (n note) title() string {
+	t, _, _ := strings.Cut(strings.TrimSpace(n.Question), "\n")
+	if len(t) > 80 {
+		t = strings.TrimSpace(strings.ToValidUTF8(t[:80], "")) + "…"
+	}
+	if t == "" {
+		t = "arc-ask answer"
+	}
+	return t
+}
+
+func (n note) tags() []string {
+	tags := []string{"arc-ask"}
+	if n.Template != "" {
+		tags = append(tags, "arc-ask/"+n.Template)
+	}
+	return tags
+}
+
+// markdown renders the note with frontmatter Obsidian indexes
+func (n note) markdown() string {
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "created: %s\n", n.Time.Format(time.RFC3339))
+	if n.Model != "" {
+		fmt.Fprintf(&b, "model: %q\n", n.Model)
+	}
+	if n.Provider != "" {
+		fmt.Fprintf(&b, "provider: %s\n", n.Provider)
+	}
+	if n.Template != "" {
+		fmt.Fprintf(&b, "template: %s\n", n.Template)
+	}
+	b.WriteString("tags:\n")
+	for _, t := range n.tags() {
+		fmt.Fprintf(&b, "  - %s\n", t)
+	}
+	b.WriteString("---\n\n")
+
+	fmt.Fprintf(&b, "# %s\n\n", n.title())
+	b.WriteString("## Question\n\n")
+	b.WriteString(strings.TrimSpace(n.Question))
+	b.WriteString("\n\n")
+	if input := strings.TrimSpace(n.Input); input != "" {
+		if len(input) > maxNoteInput {
+			input = strings.ToValidUTF8(input[:maxNoteInput], "") + "\n… (truncated)"
+		}
+		b.WriteString("## Input\n\n````text\n")
+		b.WriteString(input)
+		b.WriteString("\n````\n\n")
+	}
+	b.WriteString("## Answer\n\n")
+	b.WriteString(strings.TrimSpace(n.Answer))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// saveNote writes a note to a folder (relative paths resolve against
+// notes_dir in ask.yaml) or, with the apple-notes: prefix, to Apple Notes.
+// It returns where the note went.
+func saveNote(target, notesDir string, n note) (string, error) {
+	if folder, ok := strings.CutPrefix(target, appleNotesPrefix); ok {
+		return saveAppleNote(folder, n)
+	}
+
+	dir := ask.ExpandHome(target)
+	if !filepath.IsAbs(dir) && notesDir != "" {
+		dir = filepath.Join(ask.ExpandHome(notesDir), dir)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("create note folder: %w", err)
+	}
+
+	name := strings.Trim(strings.Join(strings.Fields(noteNameChars.ReplaceAllString(n.title(), " ")), " "), ".")
+	base := n.Time.Format("2006-01-02 1504") + " " + name
+	path := filepath.Join(dir, base+".md")
+	for i := 2; ; i++ {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if os.IsExist(err) {
+			path = filepath.Join(dir, fmt.Sprintf("%s %d.md", base, i))
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		_, err = f.WriteString(n.markdown())
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return path, err
+	}
+}
+
+// saveAppleNote creates a note through AppleScript; Notes bodies are HTML
+func saveAppleNote(folder string, n note) (string, error) {
+	if runtime.GOOS != "darwin" {
+		return "", errors.NewCLIError("Apple Notes is only available on macOS").
+			WithSuggestions("Save to a folder instead: --save-note ~/Notes/AI")
+	}
+	if folder == "" {
+		folder = "Notes"
+	}
+
+	var body strings.Builder
+	for _, line := range strings.Split(n.markdown(), "\n") {
+		fmt.Fprintf(&body, "<div>%s</div>", html.EscapeString(line))
+	}
+
+	// Arguments are passed through argv so nothing needs AppleScript quoting
+	script := `on run argv
+	tell application "Notes"
+		if not (exists folder (item 1 of argv)) then make new folder with properties {name:(item 1 of argv)}
+		make new note at folder (item 1 of argv) with properties {name:(item 2 of argv), body:(item 3 of argv)}
+	end tell
+end run`
+	out, err := execCommand("osascript", "-e", script, folder, n.title(), body.String()).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("osascript: %s: %w", strings.TrimSpace(string(out)), err)
+	}
+	return "Apple Notes folder " + folder, nil
+}
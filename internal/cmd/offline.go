@@ -0,0 +1,281 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// queuedQuestion is a question saved with the input captured when it was
+// asked, to be answered later by queue flush
+type queuedQuestion struct {
+	ID     string            `json:"id"`
+	Prompt string            `json:"prompt"`
+	Input  string            `json:"input,omitempty"`
+	Vars   map[string]string `json:"vars,omitempty"`
+	Source string            `json:"source,omitempty"` // where the input came from
+	Queued time.Time         `json:"queued"`
+}
+
+func offlineQueueDir() string {
+	return filepath.Join(stateDir(), "queue")
+}
+
+func (q *queuedQuestion) path() string {
+	return filepath.Join(offlineQueueDir(), q.ID+".json")
+}
+
+func (q *queuedQuestion) save() error {
+	if err := os.MkdirAll(offlineQueueDir(), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return err
+	}
+	// O_EXCL keeps two questions queued in the same instant apart
+	f, err := os.OpenFile(q.path(), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// queuedQuestions returns the queue oldest first
+func queuedQuestions() ([]*queuedQuestion, error) {
+	entries, err := os.ReadDir(offlineQueueDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []*queuedQuestion
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(offlineQueueDir(), e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		var q queuedQuestion
+		if err := json.Unmarshal(data, &q); err != nil {
+			return nil, fmt.Errorf("invalid queued question %s: %w", e.Name(), err)
+		}
+		out = append(out, &q)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].Queued.Equal(out[j].Queued) {
+			return out[i].Queued.Before(out[j].Queued)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out, nil
+}
+
+func newQueueCmd(client *BridgeClient) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "queue",
+		Short: "Queue questions while offline and answer them later",
+		Long: `Queue questions while the provider is unreachable. Input is captured
+when the question is queued, so a pane snapshot or piped log reflects the
+moment you asked, not the moment the queue is flushed.`,
+		Example: `  make 2>&1 | arc-ask queue add "Why does this fail?"
+  arc-ask queue add --pane dev:0.1 @debug
+  arc-ask queue list
+  arc-ask queue flush`,
+	}
+	cmd.AddCommand(newQueueAddCmd(), newQueueListCmd(), newQueueFlushCmd(client))
+	return cmd
+}
+
+func newQueueAddCmd() *cobra.Command {
+	var (
+		pane         string
+		lines        int
+		contextFiles []string
+		vars         []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "add QUESTION",
+		Short: "Queue a question with its input captured now",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := refuseStateless("queue add"); err != nil {
+				return err
+			}
+			templateVars, err := parseVars(vars)
+			if err != nil {
+				return err
+			}
+			if ask.IsTemplateRef(args[0]) {
+				if _, err := loadTemplate(args[0]); err != nil {
+					return err
+				}
+			}
+
+			input, err := gatherInput(cmd, pane, lines, captureFilter{mode: captureSmart})
+			if err != nil {
+				return err
+			}
+			source := "stdin"
+			if pane != "" {
+				source = "pane " + pane
+			}
+			if input == "" {
+				source = ""
+			}
+			if len(contextFiles) > 0 {
+				if input, _, err = mergeContext(input, contextFiles, contextOptions{}); err != nil {
+					return err
+				}
+			}
+
+			now := time.Now()
+			q := &queuedQuestion{
+				ID:     now.UTC().Format("20060102-150405.000000000"),
+				Prompt: args[0],
+				Input:  input,
+				Vars:   templateVars,
+				Source: source,
+				Queued: now,
+			}
+			if err := q.save(); err != nil {
+				return errors.NewCLIError("failed to queue question").WithCause(err)
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Queued %s\n", q.ID)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&pane, "pane", "", "Capture from tmux pane now (e.g., session:0.0)")
+	cmd.Flags().IntVar(&lines, "lines", 200, "Lines to capture from pane")
+	cmd.Flags().StringArrayVarP(&contextFiles, "context", "c", nil, "Add context file(s), read now")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Template variable (key=value)")
+	return cmd
+}
+
+func newQueueListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List queued questions",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			queue, err := queuedQuestions()
+			if err != nil {
+				return err
+			}
+			if len(queue) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Queue is empty")
+				return nil
+			}
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "ID\tQUEUED\tINPUT\tQUESTION")
+			for _, q := range queue {
+				input := "-"
+				if q.Input != "" {
+					input = fmt.Sprintf("%s, %s tokens", q.Source, formatTokens(ask.EstimateTokens(q.Input)))
+				}
+				question, _, _ := strings.Cut(q.Prompt, "\n")
+				_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", q.ID, q.Queued.Format("2006-01-02 15:04"), input, question)
+			}
+			return tw.Flush()
+		},
+	}
+}
+
+func newQueueFlushCmd(client *BridgeClient) *cobra.Command {
+	var plan bool
+
+	cmd := &cobra.Command{
+		Use:   "flush",
+		Short: "Answer queued questions, oldest first",
+		Long: `Answer every queued question, oldest first, and print each question
+with its answer. Answered questions leave the queue; failed ones stay for
+the next flush. --plan lists what a flush would send and its estimated
+cost without sending anything.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !plan {
+				if err := refuseStateless("queue flush"); err != nil {
+					return err
+				}
+			}
+			queue, err := queuedQuestions()
+			if err != nil {
+				return err
+			}
+			if len(queue) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Queue is empty")
+				return nil
+			}
+
+			runner := &ask.Runner{Client: client, Templates: userTemplates()}
+			if plan {
+				p := newRunPlan(client)
+				for _, q := range queue {
+					prompt, err := runner.Prompt(ask.Request{Prompt: q.Prompt, Input: q.Input, Vars: q.Vars})
+					if err != nil {
+						p.skip(q.ID, err.Error())
+						continue
+					}
+					question, _, _ := strings.Cut(q.Prompt, "\n")
+					p.addPrompt(client, q.ID+" "+question, prompt)
+				}
+				return writePlan(cmd.OutOrStdout(), p, "text")
+			}
+			out := cmd.OutOrStdout()
+			failed := 0
+			for i, q := range queue {
+				ctx, cancel := interruptibleContext(client.timeout)
+				res, err := runner.Run(ctx, ask.Request{Prompt: q.Prompt, Input: q.Input, Vars: q.Vars})
+				interrupted := ctx.Err() != nil
+				cancel()
+
+				if i > 0 {
+					_, _ = fmt.Fprintln(out)
+				}
+				_, _ = fmt.Fprintf(out, "## %s (queued %s)\n\n", q.Prompt, q.Queued.Format("2006-01-02 15:04"))
+				if err != nil {
+					failed++
+					fmt.Fprintf(os.Stderr, "Failed %s: %v\n", q.ID, err)
+					if interrupted {
+						break
+					}
+					continue
+				}
+				_, _ = fmt.Fprintln(out, res.Response)
+				if err := os.Remove(q.path()); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+				}
+			}
+
+			if failed > 0 {
+				return errors.NewCLIError(fmt.Sprintf("%d queued question(s) failed and remain queued", failed)).
+					WithSuggestions("Retry with: arc-ask queue flush")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&plan, "plan", false, "List the requests with estimated tokens and cost, without sending them")
+	return cmd
+}
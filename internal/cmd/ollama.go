@@ -0,0 +1,255 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+const (
+	// providerOllama is a local Ollama server
+	providerOllama = "ollama"
+	// defaultOllamaHost is where Ollama listens unless OLLAMA_HOST says otherwise
+	defaultOllamaHost = "http://127.0.0.1:11434"
+)
+
+// Auto-pull settings for a model the Ollama server does not have
+const (
+	pullAsk    = "ask"    // confirm on the terminal (default)
+	pullAlways = "always" // pull without asking
+	pullNever  = "never"  // fail with the models pull command to run
+)
+
+// OllamaConfig configures the local Ollama provider
+type OllamaConfig struct {
+	Host     string `yaml:"host,omitempty"`      // default $OLLAMA_HOST or 127.0.0.1:11434
+	AutoPull string `yaml:"auto_pull,omitempty"` // ask (default), always, never
+}
+
+func (o OllamaConfig) autoPull() string {
+	if o.AutoPull == "" {
+		return pullAsk
+	}
+	return o.AutoPull
+}
+
+func (o OllamaConfig) check() error {
+	switch o.autoPull() {
+	case pullAsk, pullAlways, pullNever:
+		return nil
+	}
+	return errors.NewCLIError(fmt.Sprintf("invalid ollama auto_pull %q", o.AutoPull)).
+		WithSuggestions("Use ask, always, or never")
+}
+
+// ollamaURL is the Ollama server's base URL, from ask.yaml, OLLAMA_HOST,
+// or the default; a bare host:port gets http://
+func ollamaURL() string {
+	host := os.Getenv("OLLAMA_HOST")
+	if cfg, err := loadConfig(); err == nil && cfg.Ollama.Host != "" {
+		host = cfg.Ollama.Host
+	}
+	if host == "" {
+		return defaultOllamaHost
+	}
+	if !strings.Contains(host, "://") {
+		host = "http://" + host
+	}
+	return strings.TrimRight(host, "/")
+}
+
+// ollamaHost is the host egress rules see for Ollama requests
+func ollamaHost() string {
+	u, err := url.Parse(ollamaURL())
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
+
+// ollamaHasModel reports whether the server has pulled model; a name
+// without a tag matches :latest
+func ollamaHasModel(ctx context.Context, model string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ollamaURL()+"/api/tags", nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("reach Ollama at %s: %w", ollamaURL(), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("ollama returned %s", resp.Status)
+	}
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return false, fmt.Errorf("decode Ollama models: %w", err)
+	}
+	if !strings.Contains(model, ":") {
+		model += ":latest"
+	}
+	for _, m := range tags.Models {
+		if m.Name == model {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ollamaProgress is one line of a streamed pull
+type ollamaProgress struct {
+	Status    string `json:"status"`
+	Total     int64  `json:"total"`
+	Completed int64  `json:"completed"`
+	Error     string `json:"error"`
+}
+
+// ollamaPull downloads model, reporting progress to w: each new status,
+// and download progress in steps of 10%
+func ollamaPull(ctx context.Context, model string, w io.Writer) error {
+	body, _ := json.Marshal(map[string]any{"model": model, "stream": true})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ollamaURL()+"/api/pull", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("reach Ollama at %s: %w", ollamaURL(), err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("ollama returned %s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+
+	var (
+		lastStatus string
+		lastStep   int64 = -1
+	)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var p ollamaProgress
+		if json.Unmarshal(scanner.Bytes(), &p) != nil {
+			continue
+		}
+		if p.Error != "" {
+			return fmt.Errorf("pull %s: %s", model, p.Error)
+		}
+		if p.Status != lastStatus {
+			lastStatus, lastStep = p.Status, -1
+			if p.Total == 0 {
+				_, _ = fmt.Fprintf(w, "%s\n", p.Status)
+			}
+		}
+		if p.Total > 0 {
+			if step := p.Completed * 10 / p.Total; step != lastStep {
+				lastStep = step
+				_, _ = fmt.Fprintf(w, "%s: %d%% of %s\n", p.Status, step*10, formatBytes(p.Total))
+			}
+		}
+		if p.Status == "success" {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("pull %s: %w", model, err)
+	}
+	return fmt.Errorf("pull %s: the stream ended before it succeeded", model)
+}
+
+// ensuredOllama holds the models already checked this run
+var ensuredOllama sync.Map
+
+// ensureOllamaModel pulls the model on first use when the Ollama server
+// lacks it, confirming first unless auto_pull says otherwise
+func ensureOllamaModel(ctx context.Context, model string) error {
+	if model == "" {
+		return nil
+	}
+	if _, done := ensuredOllama.Load(model); done {
+		return nil
+	}
+	has, err := ollamaHasModel(ctx, model)
+	if err != nil {
+		return err
+	}
+	if !has {
+		cfg, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		switch cfg.Ollama.autoPull() {
+		case pullNever:
+			return fmt.Errorf("ollama does not have %s; run: arc-ask models pull %s", model, model)
+		case pullAsk:
+			ok, err := confirm(fmt.Sprintf("Ollama does not have %s. Pull it now?", model))
+			if err != nil {
+				return fmt.Errorf("ollama does not have %s; run: arc-ask models pull %s", model, model)
+			}
+			if !ok {
+				return fmt.Errorf("not pulled: %s", model)
+			}
+		}
+		if err := ollamaPull(ctx, model, os.Stderr); err != nil {
+			return err
+		}
+	}
+	ensuredOllama.Store(model, true)
+	return nil
+}
+
+func formatBytes(n int64) string {
+	switch {
+	case n >= 1<<30:
+		return fmt.Sprintf("%.1f GB", float64(n)/(1<<30))
+	case n >= 1<<20:
+		return fmt.Sprintf("%.0f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%d KB", n>>10)
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
+
+func newModelsPullCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "pull NAME",
+		Short: "Download a model into the local Ollama server",
+		Long: `Pull a model into the Ollama server at $OLLAMA_HOST (or ollama.host in
+` + defaultConfigPath + `), showing progress. With provider: ollama, a model
+the server lacks is also pulled on first use, after confirmation; set
+ollama.auto_pull to always or never to change that.`,
+		Example: `  arc-ask models pull llama3.1
+  arc-ask models pull qwen2.5-coder:7b`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			model, _ := resolveModel(args[0])
+			if err := ollamaPull(cmd.Context(), model, cmd.ErrOrStderr()); err != nil {
+				return errors.NewCLIError("failed to pull " + model).WithCause(err).
+					WithSuggestions("Check that Ollama is running: ollama serve")
+			}
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Pulled %s\n", model)
+			return nil
+		},
+	}
+}
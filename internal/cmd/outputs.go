@@ -0,0 +1,186 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// templateOutputs returns the template arg names when it declares named
+// outputs, or nil
+func templateOutputs(arg string) *ask.Template {
+	if !ask.IsTemplateRef(arg) {
+		return nil
+	}
+	t, err := loadTemplate(arg)
+	if err != nil || len(t.Outputs) == 0 {
+		return nil
+	}
+	return t
+}
+
+// parseRoutes reads --route name=dest values, which override where a
+// template sends its named outputs
+func parseRoutes(specs []string, t *ask.Template) (map[string]string, error) {
+	if len(specs) == 0 {
+		return nil, nil
+	}
+	if t == nil {
+		return nil, errors.NewCLIError("--route needs a template that declares outputs:")
+	}
+	routes := make(map[string]string, len(specs))
+	for _, spec := range specs {
+		name, dest, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, errors.NewCLIError(fmt.Sprintf("invalid --route %q", spec)).
+				WithSuggestions("Use --route name=dest, e.g. --route patch=file:fix.patch")
+		}
+		if !declaresOutput(t, name) {
+			return nil, errors.NewCLIError(fmt.Sprintf("@%s has no output %q", t.Name, name)).
+				WithSuggestions("Outputs: " + strings.Join(outputNames(t), ", "))
+		}
+		if _, _, err := ask.ParseDest(dest); err != nil {
+			return nil, errors.NewCLIError(fmt.Sprintf("invalid --route %q", spec)).WithCause(err)
+		}
+		routes[name] = dest
+	}
+	return routes, nil
+}
+
+func declaresOutput(t *ask.Template, name string) bool {
+	for _, o := range t.Outputs {
+		if o.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func outputNames(t *ask.Template) []string {
+	names := make([]string, len(t.Outputs))
+	for i, o := range t.Outputs {
+		names[i] = o.Name
+	}
+	return names
+}
+
+// enforceOutputs checks the answer holds every required output and, if
+// not, retries once with the problem appended to the prompt. It returns
+// the accepted answer and whether it retried.
+func enforceOutputs(ctx context.Context, client *BridgeClient, tools []string, t *ask.Template, prompt, answer string) (string, bool, error) {
+	_, err := t.SplitOutputs(answer)
+	if err == nil {
+		return answer, false, nil
+	}
+
+	retry := fmt.Sprintf("%s\n\nYour previous answer was rejected: %v\n\nPrevious answer:\n%s\n\n%s", prompt, err, answer, t.OutputsInstructions())
+	var askErr error
+	if len(tools) > 0 {
+		answer, askErr = client.AskWithTools(ctx, retry, tools)
+	} else {
+		answer, askErr = client.Ask(ctx, retry)
+	}
+	if askErr != nil {
+		return "", true, errors.NewCLIError("AI query failed").WithCause(askErr)
+	}
+	if _, err := t.SplitOutputs(answer); err != nil {
+		return "", true, errors.NewCLIError(fmt.Sprintf("answer does not hold the outputs @%s declares", t.Name)).
+			WithCause(err)
+	}
+	return answer, true, nil
+}
+
+// outputRouter sends each named output to its destination
+type outputRouter struct {
+	template *ask.Template
+	routes   map[string]string // --route overrides
+	stdout   io.Writer
+	notesDir string
+	note     note // question, input, and model for note: destinations
+}
+
+// dest is where the named output goes
+func (r *outputRouter) dest(o ask.NamedOutput) string {
+	if to, ok := r.routes[o.Name]; ok {
+		return to
+	}
+	return o.To
+}
+
+// route writes every output that is not for stdout, then the stdout ones
+// in declared order unless skipStdout is set (JSON output carries them)
+func (r *outputRouter) route(parts map[string]string, skipStdout bool) error {
+	var stdout []string
+	for _, o := range r.template.Outputs {
+		text := strings.TrimSpace(parts[o.Name])
+		if text == "" {
+			continue
+		}
+		kind, arg, err := ask.ParseDest(r.dest(o))
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case ask.DestStdout:
+			stdout = append(stdout, text)
+		case ask.DestStderr:
+			fmt.Fprintln(os.Stderr, text)
+		case ask.DestFile:
+			if err := writeOutputFile(arg, text); err != nil {
+				return errors.NewCLIError(fmt.Sprintf("cannot write the %s output", o.Name)).WithCause(err)
+			}
+			fmt.Fprintf(os.Stderr, "Wrote %s to %s\n", o.Name, arg)
+		case ask.DestNote:
+			n := r.note
+			n.Question, n.Answer = strings.TrimSpace(n.Question+" "+o.Name), text
+			where, err := saveNote(arg, r.notesDir, n)
+			if err != nil {
+				return errors.NewCLIError(fmt.Sprintf("cannot save the %s output as a note", o.Name)).WithCause(err)
+			}
+			fmt.Fprintf(os.Stderr, "Saved %s to %s\n", o.Name, where)
+		}
+	}
+	if skipStdout || len(stdout) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintln(r.stdout, strings.Join(stdout, "\n\n"))
+	return err
+}
+
+// writeOutputFile writes one output, unwrapping it when it is a single
+// fenced code block so a patch or script is usable as is
+func writeOutputFile(path, text string) error {
+	if blocks := ask.CodeBlocks(text); len(blocks) == 1 && strings.TrimSpace(ask.StripCodeBlocks(text)) == "" {
+		text = blocks[0].Code
+	}
+	path = ask.ExpandHome(path)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	return os.WriteFile(path, []byte(text), 0o644)
+}
+
+// outputsNote is the note metadata for an answer's note: outputs
+func outputsNote(arg, input string, client *BridgeClient) note {
+	return note{
+		Question: arg,
+		Input:    input,
+		Provider: client.provider,
+		Model:    client.model,
+		Template: strings.TrimPrefix(arg, "@"),
+		Time:     time.Now(),
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// defaultStateDir holds per-machine state such as pane positions
+const defaultStateDir = "~/.local/state/arc/ask"
+
+// paneAnchorLines is how many trailing lines identify the last position
+const paneAnchorLines = 5
+
+// paneSinceScrollback is how much history --since-last searches for the anchor
+const paneSinceScrollback = 5000
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// paneMark remembers where the previous --since-last capture of a pane ended
+type paneMark struct {
+	Pane   string    `json:"pane"`
+	Anchor []string  `json:"anchor"`
+	Time   time.Time `json:"time"`
+}
+
+func paneMarkPath(pane string) string {
+	return filepath.Join(stateDir(), "panes", unsafeFileChars.ReplaceAllString(pane, "_")+".json")
+}
+
+func loadPaneMark(pane string) (*paneMark, error) {
+	data, err := os.ReadFile(paneMarkPath(pane))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m paneMark
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse pane state: %w", err)
+	}
+	return &m, nil
+}
+
+func (m *paneMark) save() error {
+	path := paneMarkPath(m.Pane)
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// capturePaneSince captures only the pane output added since the previous
+// --since-last run. The returned mark must be saved once the output has
+// been handled so a failed query can be retried with the same output.
+func capturePaneSince(pane string, lines int, capture captureFilter) (string, *paneMark, error) {
+	content, err := capturePane(pane, paneSinceScrollback)
+	if err != nil {
+		return "", nil, err
+	}
+	all := strings.Split(strings.TrimRight(content, "\n"), "\n")
+
+	prev, err := loadPaneMark(pane)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring saved pane position: %v\n", err)
+	}
+
+	fresh := all
+	if prev != nil {
+		if i, ok := findAnchor(all, prev.Anchor); ok {
+			fresh = all[i:]
+		} else {
+			fmt.Fprintf(os.Stderr, "Note: previous position in %s scrolled out of history; sending recent output.\n", pane)
+		}
+	}
+
+	mark := &paneMark{Pane: pane, Anchor: lastNonEmpty(all, paneAnchorLines), Time: time.Now()}
+	if strings.TrimSpace(strings.Join(fresh, "\n")) == "" {
+		return "", mark, nil
+	}
+	return capture.apply(strings.Join(fresh, "\n"), lines), mark, nil
+}
+
+// findAnchor returns the index just after the last occurrence of anchor
+func findAnchor(lines, anchor []string) (int, bool) {
+	if len(anchor) == 0 {
+		return 0, false
+	}
+	trimmed := make([]string, len(lines))
+	for i, l := range lines {
+		trimmed[i] = strings.TrimRight(l, " ")
+	}
+	for end := len(trimmed); end >= len(anchor); end-- {
+		match := true
+		for k := range anchor {
+			if trimmed[end-len(anchor)+k] != anchor[k] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return end, true
+		}
+	}
+	return 0, false
+}
+
+// lastNonEmpty returns up to n trailing lines, skipping trailing blank lines
+func lastNonEmpty(lines []string, n int) []string {
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	start := max(end-n, 0)
+	out := make([]string, 0, end-start)
+	for _, l := range lines[start:end] {
+		out = append(out, strings.TrimRight(l, " "))
+	}
+	return out
+}
@@ -0,0 +1,370 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// noPins is --no-pins: pinned context is left out of this invocation
+var noPins bool
+
+// Kinds of pinned context
+const (
+	pinFile = "file"
+	pinPane = "pane"
+)
+
+// contextPin is context added to every invocation in a directory tree
+type contextPin struct {
+	Kind   string    `json:"kind"`   // file or pane
+	Target string    `json:"target"` // an absolute path, or a tmux target
+	Added  time.Time `json:"added"`
+}
+
+// String is the pin as given to --context or --log
+func (p contextPin) String() string {
+	if p.Kind == pinPane {
+		return "pane:" + p.Target
+	}
+	return p.Target
+}
+
+func pinsPath() string {
+	return filepath.Join(stateDir(), "pins.json")
+}
+
+// loadPins reads the pins of every directory, keyed by absolute path
+func loadPins() (map[string][]contextPin, error) {
+	pins := make(map[string][]contextPin)
+	data, err := os.ReadFile(pinsPath())
+	if os.IsNotExist(err) {
+		return pins, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &pins); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", pinsPath(), err)
+	}
+	return pins, nil
+}
+
+func savePins(pins map[string][]contextPin) error {
+	for dir, set := range pins {
+		if len(set) == 0 {
+			delete(pins, dir)
+		}
+	}
+	path := pinsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(pins, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// findPins returns the pins of dir or its nearest pinned parent, and that
+// directory; a directory's own pins replace its parents'
+func findPins(pins map[string][]contextPin, dir string) ([]contextPin, string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, "", err
+	}
+	for {
+		if set := pins[dir]; len(set) > 0 {
+			return set, dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, "", nil
+		}
+		dir = parent
+	}
+}
+
+// applyPins adds the pinned files to the context files and the pinned
+// panes to the logs, skipping what the command line already names and
+// files that are gone. It returns the number of pins added.
+func applyPins(pane string, contextFiles, logSpecs *[]string) int {
+	pins, err := loadPins()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring pinned context: %v\n", err)
+		return 0
+	}
+	set, dir, err := findPins(pins, ".")
+	if err != nil || len(set) == 0 {
+		return 0
+	}
+
+	named := map[string]bool{"pane:" + pane: pane != ""}
+	for _, f := range *contextFiles {
+		if abs, err := filepath.Abs(f); err == nil {
+			named[abs] = true
+		}
+	}
+	for _, spec := range *logSpecs {
+		named[spec] = true
+	}
+
+	var files []string
+	n := 0
+	for _, p := range set {
+		switch {
+		case named[p.String()]:
+		case p.Kind == pinPane:
+			*logSpecs = append(*logSpecs, p.String())
+			n++
+		default:
+			if _, err := os.Stat(p.Target); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: pinned %s is gone (arc-ask pin rm %s)\n", p.Target, shellJoin([]string{p.Target}))
+				continue
+			}
+			files = append(files, p.Target)
+			n++
+		}
+	}
+	// Pinned files come before the ones given now
+	*contextFiles = append(files, *contextFiles...)
+	if n > 0 {
+		fmt.Fprintf(os.Stderr, "Note: including %d pinned context item(s) from %s (--no-pins to skip)\n", n, displayPath(dir))
+	}
+	return n
+}
+
+func newPinCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pin",
+		Short: "Pin context to every question asked in a directory",
+		Long: `Pinned files and tmux panes are included in every question asked in the
+directory they were pinned in, and below it, until unpinned: files as
+--context, panes as --log pane:TARGET. A directory's own pins replace
+its parent's. --no-pins leaves them out of one question.`,
+		Example: `  arc-ask pin add internal/auth/*.go
+  arc-ask pin add --pane dev:0.1
+  arc-ask pin list
+  arc-ask pin rm 2`,
+	}
+	cmd.AddCommand(newPinAddCmd(), newPinListCmd(), newPinRmCmd())
+	return cmd
+}
+
+func newPinAddCmd() *cobra.Command {
+	var panes []string
+
+	cmd := &cobra.Command{
+		Use:   "add [FILE...]",
+		Short: "Pin files or panes in this directory",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := refuseStateless("pin add"); err != nil {
+				return err
+			}
+			if len(args) == 0 && len(panes) == 0 {
+				return coded(codeUsage, errors.NewCLIError("nothing to pin").
+					WithSuggestions("Pin files: arc-ask pin add FILE...", "Pin a pane: arc-ask pin add --pane session:0.1"))
+			}
+
+			var add []contextPin
+			now := time.Now().UTC()
+			for _, f := range args {
+				abs, err := filepath.Abs(f)
+				if err != nil {
+					return err
+				}
+				info, err := os.Stat(abs)
+				if err != nil {
+					return coded(codeInputUnavailable, errors.NewCLIError("cannot pin "+f).WithCause(err))
+				}
+				if info.IsDir() {
+					return coded(codeUsage, errors.NewCLIError(f+" is a directory").
+						WithSuggestions("Pin its files: arc-ask pin add "+filepath.Join(f, "*")))
+				}
+				add = append(add, contextPin{Kind: pinFile, Target: abs, Added: now})
+			}
+			for _, p := range panes {
+				add = append(add, contextPin{Kind: pinPane, Target: p, Added: now})
+			}
+
+			pins, err := loadPins()
+			if err != nil {
+				return err
+			}
+			dir, err := filepath.Abs(".")
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			for _, p := range add {
+				if pinIndex(pins[dir], p) >= 0 {
+					_, _ = fmt.Fprintf(out, "Already pinned %s\n", p)
+					continue
+				}
+				pins[dir] = append(pins[dir], p)
+				_, _ = fmt.Fprintf(out, "Pinned %s\n", p)
+			}
+			return savePins(pins)
+		},
+	}
+
+	cmd.Flags().StringArrayVar(&panes, "pane", nil, "Pin a tmux pane (e.g., session:0.1); its last --lines are included")
+	return cmd
+}
+
+func newPinListCmd() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the pins that apply here",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			pins, err := loadPins()
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			if all {
+				dirs := make([]string, 0, len(pins))
+				for dir := range pins {
+					dirs = append(dirs, dir)
+				}
+				sort.Strings(dirs)
+				if len(dirs) == 0 {
+					_, _ = fmt.Fprintln(out, "Nothing pinned")
+				}
+				for i, dir := range dirs {
+					if i > 0 {
+						_, _ = fmt.Fprintln(out)
+					}
+					writePins(out, dir, pins[dir])
+				}
+				return nil
+			}
+
+			set, dir, err := findPins(pins, ".")
+			if err != nil {
+				return err
+			}
+			if len(set) == 0 {
+				_, _ = fmt.Fprintln(out, "Nothing pinned in this directory or its parents")
+				return nil
+			}
+			writePins(out, dir, set)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "List the pins of every directory")
+	return cmd
+}
+
+// writePins lists a directory's pins, numbered for pin rm
+func writePins(w io.Writer, dir string, set []contextPin) {
+	_, _ = fmt.Fprintf(w, "%s:\n", displayPath(dir))
+	for i, p := range set {
+		target := p.Target
+		if p.Kind == pinFile {
+			if rel, err := filepath.Rel(dir, p.Target); err == nil && !strings.HasPrefix(rel, "..") {
+				target = rel
+			}
+		}
+		_, _ = fmt.Fprintf(w, "  %d. %-4s %s\n", i+1, p.Kind, target)
+	}
+}
+
+func newPinRmCmd() *cobra.Command {
+	var all bool
+
+	cmd := &cobra.Command{
+		Use:   "rm [N|FILE|pane:TARGET...]",
+		Short: "Unpin context",
+		Long: `Unpin context from the pins that apply here, by number from pin list,
+file, or pane:TARGET. --all unpins everything there.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := refuseStateless("pin rm"); err != nil {
+				return err
+			}
+			if len(args) == 0 && !all {
+				return coded(codeUsage, errors.NewCLIError("nothing to unpin").
+					WithSuggestions("Name pins by number (arc-ask pin list), file, or pane:TARGET, or use --all"))
+			}
+			pins, err := loadPins()
+			if err != nil {
+				return err
+			}
+			set, dir, err := findPins(pins, ".")
+			if err != nil {
+				return err
+			}
+			if len(set) == 0 {
+				return errors.NewCLIError("nothing pinned in this directory or its parents")
+			}
+
+			out := cmd.OutOrStdout()
+			if all {
+				delete(pins, dir)
+				_, _ = fmt.Fprintf(out, "Unpinned %d item(s) in %s\n", len(set), displayPath(dir))
+				return savePins(pins)
+			}
+
+			remove := make(map[int]bool)
+			for _, arg := range args {
+				i := -1
+				if n, err := strconv.Atoi(arg); err == nil {
+					i = n - 1
+				} else if kind, target, ok := strings.Cut(arg, ":"); ok && kind == pinPane {
+					i = pinIndex(set, contextPin{Kind: pinPane, Target: target})
+				} else if abs, err := filepath.Abs(arg); err == nil {
+					i = pinIndex(set, contextPin{Kind: pinFile, Target: abs})
+				}
+				if i < 0 || i >= len(set) {
+					return errors.NewCLIError(fmt.Sprintf("%s is not pinned in %s", arg, displayPath(dir))).
+						WithSuggestions("See the pins: arc-ask pin list")
+				}
+				remove[i] = true
+			}
+			var kept []contextPin
+			for i, p := range set {
+				if remove[i] {
+					_, _ = fmt.Fprintf(out, "Unpinned %s\n", p)
+					continue
+				}
+				kept = append(kept, p)
+			}
+			pins[dir] = kept
+			return savePins(pins)
+		},
+	}
+
+	cmd.Flags().BoolVar(&all, "all", false, "Unpin everything that applies here")
+	return cmd
+}
+
+// pinIndex finds a pin by kind and target
+func pinIndex(set []contextPin, p contextPin) int {
+	for i, q := range set {
+		if q.Kind == p.Kind && q.Target == p.Target {
+			return i
+		}
+	}
+	return -1
+}
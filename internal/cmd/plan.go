@@ -0,0 +1,114 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+)
+
+// plannedRequest is one request a run would make, priced without sending it
+type plannedRequest struct {
+	Name         string  `json:"name"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"` // the most the answer may use
+	USD          float64 `json:"usd"`
+	Priced       bool    `json:"priced"` // false when the model's price is unknown
+	Note         string  `json:"note,omitempty"`
+}
+
+// runPlan is what --plan reports for a batch run
+type runPlan struct {
+	Model        string           `json:"model,omitempty"`
+	Provider     string           `json:"provider,omitempty"`
+	Requests     []plannedRequest `json:"requests"`
+	Skipped      []plannedRequest `json:"skipped,omitempty"` // items that would make no request
+	InputTokens  int              `json:"input_tokens"`
+	OutputTokens int              `json:"output_tokens"`
+	USD          float64          `json:"usd"`
+	Unpriced     int              `json:"unpriced,omitempty"` // requests left out of usd
+}
+
+func newRunPlan(client *BridgeClient) *runPlan {
+	return &runPlan{Model: client.model, Provider: client.provider, Requests: []plannedRequest{}}
+}
+
+// add prices a request of inputTokens to the client's model
+func (p *runPlan) add(client *BridgeClient, name string, inputTokens int, note string) {
+	r := plannedRequest{Name: name, InputTokens: inputTokens, Note: note}
+	if e, ok := estimateCost(client.provider, client.model, inputTokens, client.maxTokens); ok {
+		r.OutputTokens, r.USD, r.Priced = e.OutputTokens, e.USD(), true
+	} else {
+		r.OutputTokens = client.maxTokens
+		if r.OutputTokens <= 0 {
+			r.OutputTokens = assumedOutputTokens
+		}
+		p.Unpriced++
+	}
+	p.Requests = append(p.Requests, r)
+	p.InputTokens += r.InputTokens
+	p.OutputTokens += r.OutputTokens
+	p.USD += r.USD
+}
+
+// addPrompt prices a request that sends prompt
+func (p *runPlan) addPrompt(client *BridgeClient, name, prompt string) {
+	p.add(client, name, ask.EstimateTokens(prompt), "")
+}
+
+// skip records an item that would make no request, and why
+func (p *runPlan) skip(name, reason string) {
+	p.Skipped = append(p.Skipped, plannedRequest{Name: name, Note: reason})
+}
+
+func writePlan(w io.Writer, p *runPlan, format string) error {
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(p)
+	}
+
+	route := "pi's default model"
+	if p.Model != "" {
+		route = p.Model
+	}
+	if p.Provider != "" {
+		route += " via " + p.Provider
+	}
+	_, _ = fmt.Fprintf(w, "Plan: %d request(s) to %s, nothing sent\n\n", len(p.Requests), route)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "REQUEST\tINPUT\tOUTPUT\tCOST\tNOTE")
+	for _, r := range p.Requests {
+		cost := "-"
+		if r.Priced {
+			cost = formatUSD(r.USD)
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", fitColumn(r.Name, 48),
+			formatTokens(r.InputTokens), formatTokens(r.OutputTokens), cost, r.Note)
+	}
+	for _, r := range p.Skipped {
+		_, _ = fmt.Fprintf(tw, "%s\t-\t-\t-\tskipped: %s\n", fitColumn(r.Name, 48), r.Note)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	total := fmt.Sprintf("\nTotal: ~%s input tokens, up to %s output", formatTokens(p.InputTokens), formatTokens(p.OutputTokens))
+	switch {
+	case len(p.Requests) == 0:
+	case p.Unpriced == len(p.Requests):
+		total += ", price unknown (see arc-ask models)"
+	case p.Unpriced > 0:
+		total += fmt.Sprintf(", estimated %s (%d request(s) with unknown price not counted)", formatUSD(p.USD), p.Unpriced)
+	default:
+		total += ", estimated " + formatUSD(p.USD)
+	}
+	_, err := fmt.Fprintln(w, total)
+	return err
+}
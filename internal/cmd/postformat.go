@@ -0,0 +1,177 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// postFormat turns an answer into a file format other tools consume
+type postFormat struct {
+	// instructions are appended to the prompt
+	instructions string
+	// normalize validates the answer and returns it cleaned up; the error
+	// explains what is wrong so the model can fix it on a retry
+	normalize func(answer string) (string, error)
+}
+
+// postFormats maps --to values to their handlers
+var postFormats = map[string]postFormat{
+	"patch": {
+		instructions: "Respond only with a unified diff (as produced by git diff) that can be applied with git apply from the repository root. Include full ---/+++ headers and correct @@ hunk ranges. No explanation.",
+		normalize:    normalizePatch,
+	},
+	"csv": {
+		instructions: "Respond only with CSV data (RFC 4180): a header row first, the same number of fields in every row, fields containing commas, quotes, or newlines quoted with double quotes. No explanation.",
+		normalize:    normalizeCSV,
+	},
+	"html": {
+		instructions: "Respond only with a complete, standalone HTML5 document, starting with <!DOCTYPE html>, with inline CSS if styling is needed and no external resources. No explanation.",
+		normalize:    normalizeHTML,
+	},
+	"man": {
+		instructions: "Respond only with a man page in roff format using the man macros: start with .TH, then .SH NAME, .SH SYNOPSIS, .SH DESCRIPTION, and any further sections. No explanation.",
+		normalize:    normalizeMan,
+	},
+}
+
+func postFormatNames() []string {
+	names := make([]string, 0, len(postFormats))
+	for name := range postFormats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func validatePostFormat(name string) error {
+	if _, ok := postFormats[name]; name == "" || ok {
+		return nil
+	}
+	return errors.NewCLIError(fmt.Sprintf("invalid --to %q", name)).
+		WithSuggestions("Use one of: " + strings.Join(postFormatNames(), ", "))
+}
+
+// unfence returns the content of the answer's only code block, or the
+// answer itself
+func unfence(answer string) string {
+	if blocks := ask.CodeBlocks(answer); len(blocks) == 1 {
+		return blocks[0].Code
+	}
+	return strings.TrimSpace(answer)
+}
+
+var hunkHeader = regexp.MustCompile(`(?m)^@@ -\d+(,\d+)? \+\d+(,\d+)? @@`)
+
+// normalizePatch checks the diff is well formed and, when git is
+// available, that it applies to the working tree
+func normalizePatch(answer string) (string, error) {
+	patch := strings.TrimRight(unfence(answer), "\n") + "\n"
+	hasHeader := strings.HasPrefix(patch, "+++ ") || strings.Contains(patch, "\n+++ ")
+	if !hasHeader || !hunkHeader.MatchString(patch) {
+		return "", fmt.Errorf("answer is not a unified diff (missing +++ header or @@ hunk)")
+	}
+	if _, err := exec.LookPath("git"); err != nil {
+		return patch, nil
+	}
+	check := execCommand("git", "apply", "--check", "--recount", "-")
+	check.Stdin = strings.NewReader(patch)
+	if out, err := check.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("patch does not apply: %s", strings.TrimSpace(string(out)))
+	}
+	return patch, nil
+}
+
+// normalizeCSV parses the answer and rewrites it with consistent quoting
+func normalizeCSV(answer string) (string, error) {
+	r := csv.NewReader(strings.NewReader(unfence(answer)))
+	records, err := r.ReadAll()
+	if err != nil {
+		return "", err
+	}
+	if len(records) == 0 {
+		return "", fmt.Errorf("answer has no CSV rows")
+	}
+	var b bytes.Buffer
+	w := csv.NewWriter(&b)
+	if err := w.WriteAll(records); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+var (
+	htmlTag     = regexp.MustCompile(`(?i)<[a-z][a-z0-9-]*[\s>/]`)
+	htmlRoot    = regexp.MustCompile(`(?i)<html[\s>]`)
+	htmlDoctype = regexp.MustCompile(`(?i)^\s*<!doctype html`)
+)
+
+// normalizeHTML accepts a document or a fragment; fragments are wrapped
+// in a minimal document
+func normalizeHTML(answer string) (string, error) {
+	doc := unfence(answer)
+	if !htmlTag.MatchString(doc) {
+		return "", fmt.Errorf("answer contains no HTML elements")
+	}
+	if !htmlRoot.MatchString(doc) {
+		doc = "<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"></head>\n<body>\n" + doc + "\n</body>\n</html>"
+	} else if !htmlDoctype.MatchString(doc) {
+		doc = "<!DOCTYPE html>\n" + doc
+	}
+	return doc + "\n", nil
+}
+
+// normalizeMan checks for the man macros a page needs
+func normalizeMan(answer string) (string, error) {
+	page := unfence(answer)
+	var th, name bool
+	for _, line := range strings.Split(page, "\n") {
+		switch {
+		case strings.HasPrefix(line, `.\"`) || strings.HasPrefix(line, `'\"`):
+		case strings.HasPrefix(line, ".TH "):
+			th = true
+		case strings.HasPrefix(line, ".SH") && strings.Contains(strings.ToUpper(line), "NAME"):
+			name = true
+		case !th && strings.TrimSpace(line) != "":
+			return "", fmt.Errorf("man page must start with a .TH line, found %q", line)
+		}
+	}
+	if !th {
+		return "", fmt.Errorf("man page has no .TH line")
+	}
+	if !name {
+		return "", fmt.Errorf("man page has no .SH NAME section")
+	}
+	return strings.TrimRight(page, "\n") + "\n", nil
+}
+
+// applyPostFormat normalizes an answer and, if it fails validation,
+// retries once with the problem described. It reports whether it retried.
+func applyPostFormat(ctx context.Context, client ask.Client, name, prompt, answer string) (string, bool, error) {
+	f := postFormats[name]
+	fixed, err := f.normalize(answer)
+	if err == nil {
+		return fixed, false, nil
+	}
+
+	retry := fmt.Sprintf("%s\n\nYour previous answer was rejected: %v\n\nPrevious answer:\n%s\n\n%s", prompt, err, answer, f.instructions)
+	answer, askErr := client.Ask(ctx, retry)
+	if askErr != nil {
+		return "", true, errors.NewCLIError("AI query failed").WithCause(askErr)
+	}
+	if fixed, err = f.normalize(answer); err != nil {
+		return "", true, errors.NewCLIError("answer is not valid " + name).WithCause(err)
+	}
+	return fixed, true, nil
+}
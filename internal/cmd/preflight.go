@@ -0,0 +1,101 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+)
+
+var (
+	leftoverPlaceholder = regexp.MustCompile(`\{\{[^{}\n]{0,80}\}\}`)
+	templateVarRef      = regexp.MustCompile(`\.Vars\.([A-Za-z_][A-Za-z0-9_]*)`)
+	shellVarRef         = regexp.MustCompile(`\$(\{[A-Za-z_][A-Za-z0-9_]*\}|[A-Z_][A-Z0-9_]{2,})`)
+	emptyExpansion      = regexp.MustCompile(`\S {2,}\S|\s[,.](\s|$)`)
+)
+
+// preflight looks for common template and shell mistakes in a prompt
+// before tokens are spent on it. The input is excluded from the checks,
+// since code and logs legitimately contain braces and $VARs.
+func preflight(arg, input, system, user string, vars map[string]string) []string {
+	var warnings []string
+
+	rendered := system + "\n" + user
+	if input != "" {
+		rendered = strings.ReplaceAll(rendered, input, "")
+	}
+	for _, m := range uniqueMatches(leftoverPlaceholder, rendered) {
+		warnings = append(warnings, fmt.Sprintf("prompt contains an unrendered placeholder %s", m))
+	}
+	if strings.Contains(rendered, "<no value>") {
+		warnings = append(warnings, "prompt contains <no value>: a template field was missing")
+	}
+
+	if ask.IsTemplateRef(arg) {
+		if t, err := loadTemplate(arg); err == nil {
+			warnings = append(warnings, templateWarnings(t, input, vars)...)
+		}
+		return warnings
+	}
+
+	for _, m := range uniqueMatches(shellVarRef, arg) {
+		warnings = append(warnings, fmt.Sprintf("question contains %s literally; if you meant its value, use double quotes in the shell", m))
+	}
+	if emptyExpansion.MatchString(arg) {
+		warnings = append(warnings, "question has a gap like \"  \" or \" .\": a shell variable may have expanded to nothing")
+	}
+	return warnings
+}
+
+func templateWarnings(t *ask.Template, input string, given map[string]string) []string {
+	var warnings []string
+	text := t.System + "\n" + t.Prompt
+
+	if strings.TrimSpace(input) == "" && strings.Contains(text, ".Input") {
+		warnings = append(warnings, fmt.Sprintf("template @%s uses {{.Input}} but the input is empty (pipe something in or use --pane)", t.Name))
+	}
+
+	vars, err := t.ResolveVars(given)
+	if err != nil {
+		return warnings
+	}
+	for _, name := range uniqueSubmatches(templateVarRef, text) {
+		if vars[name] == "" {
+			warnings = append(warnings, fmt.Sprintf("template @%s variable %q is empty (set it with --var %s=VALUE)", t.Name, name, name))
+		}
+	}
+	for _, m := range uniqueMatches(shellVarRef, text) {
+		warnings = append(warnings, fmt.Sprintf("template @%s contains %s, which is sent literally; use a template variable instead", t.Name, m))
+	}
+	return warnings
+}
+
+func uniqueMatches(re *regexp.Regexp, text string) []string {
+	seen := make(map[string]bool)
+	for _, m := range re.FindAllString(text, -1) {
+		seen[m] = true
+	}
+	return sortedKeys(seen)
+}
+
+func uniqueSubmatches(re *regexp.Regexp, text string) []string {
+	seen := make(map[string]bool)
+	for _, m := range re.FindAllStringSubmatch(text, -1) {
+		seen[m[1]] = true
+	}
+	return sortedKeys(seen)
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
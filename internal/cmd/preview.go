@@ -0,0 +1,154 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// previewEdgeLines is how many lines --preview shows from each end of a block
+const previewEdgeLines = 4
+
+// previewBlockStart marks where gathered input puts a new source in the prompt
+var previewBlockStart = regexp.MustCompile(`(?m)^(Context \(([^)\n]+)\):|Recent command output:|Environment:)$`)
+
+// previewBlock is one part of the prompt as sent
+type previewBlock struct {
+	Name string
+	Text string
+}
+
+// splitPromptBlocks cuts the prompt at each context file, shell output,
+// and environment section
+func splitPromptBlocks(prompt string) []previewBlock {
+	var blocks []previewBlock
+	name, start := "prompt", 0
+	for _, m := range previewBlockStart.FindAllStringSubmatchIndex(prompt, -1) {
+		blocks = append(blocks, previewBlock{name, prompt[start:m[0]]})
+		switch {
+		case m[4] >= 0:
+			name = "context " + prompt[m[4]:m[5]]
+		case strings.HasPrefix(prompt[m[0]:], "Recent"):
+			name = "shell output"
+		default:
+			name = "environment"
+		}
+		start = m[1]
+	}
+	blocks = append(blocks, previewBlock{name, prompt[start:]})
+
+	out := blocks[:0]
+	for _, b := range blocks {
+		if b.Text = strings.Trim(b.Text, "\n"); b.Text != "" {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// writePreview shows what a request will send: the route, the sources
+// gathered, and the first and last lines of each block of the prompt
+func writePreview(w io.Writer, client *BridgeClient, sources []runSource, prompt string, estimates []costEstimate) {
+	route := "pi's default model"
+	if client.model != "" {
+		route = client.model
+	}
+	if client.provider != "" {
+		route += " via " + client.provider
+	}
+	_, _ = fmt.Fprintf(w, "Request to %s\n\n", route)
+
+	if len(sources) > 0 {
+		_, _ = fmt.Fprintln(w, "Sources:")
+		for _, s := range sources {
+			name := s.Kind
+			if s.Name != "" {
+				name += " " + s.Name
+			}
+			_, _ = fmt.Fprintf(w, "  %-44s %9s  ~%s tokens\n", fitColumn(name, 44), formatBytes(int64(s.Bytes)), formatTokens(s.Tokens))
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+
+	for _, b := range splitPromptBlocks(prompt) {
+		lines := strings.Split(b.Text, "\n")
+		_, _ = fmt.Fprintf(w, "=== %s: %d lines, %s, ~%s tokens ===\n",
+			b.Name, len(lines), formatBytes(int64(len(b.Text))), formatTokens(ask.EstimateTokens(b.Text)))
+		if len(lines) > 2*previewEdgeLines+1 {
+			omitted := len(lines) - 2*previewEdgeLines
+			lines = append(append(lines[:previewEdgeLines:previewEdgeLines],
+				fmt.Sprintf("… %d more lines …", omitted)), lines[len(lines)-previewEdgeLines:]...)
+		}
+		for _, l := range lines {
+			_, _ = fmt.Fprintln(w, "  "+fitColumn(l, 116))
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+
+	total := fmt.Sprintf("Total: ~%s tokens", formatTokens(ask.EstimateTokens(prompt)))
+	if len(estimates) > 0 {
+		usd := 0.0
+		for _, e := range estimates {
+			usd += e.USD()
+		}
+		total += ", estimated " + formatUSD(usd)
+	}
+	_, _ = fmt.Fprintln(w, total)
+}
+
+// previewRequest pages the preview on the terminal and asks whether to
+// send the request
+func previewRequest(client *BridgeClient, sources []runSource, prompt string, estimates []costEstimate) error {
+	var b strings.Builder
+	writePreview(&b, client, sources, prompt, estimates)
+
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return coded(codeUsage, errors.NewCLIError("--preview needs a terminal to show the request and confirm").
+			WithSuggestions("See what would be sent without a terminal: arc-ask compose"))
+	}
+	defer tty.Close()
+	if err := page(tty, b.String()); err != nil {
+		return err
+	}
+
+	ok, err := confirm("Send it?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return coded(codeDeclined, errors.NewCLIError("aborted, nothing sent"))
+	}
+	return nil
+}
+
+// page shows text in $PAGER (default less), or writes it straight to the
+// terminal when there is no pager
+func page(tty *os.File, text string) error {
+	pager := os.Getenv("PAGER")
+	if pager == "" {
+		pager = "less -FRX"
+	}
+	fields := strings.Fields(pager)
+	if len(fields) == 0 || pager == "cat" {
+		_, err := io.WriteString(tty, text)
+		return err
+	}
+	if _, err := exec.LookPath(fields[0]); err != nil {
+		_, err := io.WriteString(tty, text)
+		return err
+	}
+	cmd := execCommand(fields[0], fields[1:]...)
+	cmd.Stdin = strings.NewReader(text)
+	cmd.Stdout, cmd.Stderr = tty, tty
+	return cmd.Run()
+}
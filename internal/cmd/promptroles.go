@@ -0,0 +1,104 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Where a template's system prompt goes
+const (
+	roleFold      = "fold"      // prepended to the user prompt
+	roleSystem    = "system"    // pi's system prompt, appended to its own
+	roleDeveloper = "developer" // pi's whole system prompt, in place of its own
+)
+
+// PromptRole is how one provider takes system prompts
+type PromptRole struct {
+	System    string `yaml:"system,omitempty"`    // fold (default), system, or developer
+	Delimiter string `yaml:"delimiter,omitempty"` // between a folded system prompt and the user prompt; default a blank line
+}
+
+// delimiter is what joins the system prompt to the user prompt
+func (r PromptRole) delimiter() string {
+	if r.Delimiter == "" {
+		return "\n\n"
+	}
+	return r.Delimiter
+}
+
+// promptRoleFor picks the rule for provider, falling back to "*"
+func promptRoleFor(provider string, roles map[string]PromptRole) PromptRole {
+	if r, ok := roles[provider]; ok {
+		return r
+	}
+	return roles["*"]
+}
+
+// checkPromptRoles rejects unknown system roles in ask.yaml
+func checkPromptRoles(roles map[string]PromptRole) error {
+	names := make([]string, 0, len(roles))
+	for name := range roles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		switch roles[name].System {
+		case "", roleFold, roleSystem, roleDeveloper:
+		default:
+			return errors.NewCLIError(fmt.Sprintf("invalid prompt_roles.%s.system %q in %s", name, roles[name].System, displayPath(configPath()))).
+				WithSuggestions("Use fold, system, or developer")
+		}
+	}
+	return nil
+}
+
+// JoinPrompt folds a system prompt into the user prompt with the
+// provider's delimiter. This is the prompt as cached, estimated, and
+// shown, whatever role the system prompt is sent in.
+func (c *BridgeClient) JoinPrompt(system, user string) string {
+	if system == "" {
+		return user
+	}
+	return system + c.role.delimiter() + user
+}
+
+// folded is prompt with the client's own system prompt folded in
+func (c *BridgeClient) folded(prompt string) string {
+	return c.JoinPrompt(c.system, prompt)
+}
+
+// shapePrompt applies the provider's prompt_roles rule to a system and
+// user prompt and returns the client to ask and the prompt to send it.
+// Under fold the system prompt is folded into the prompt; under system
+// and developer the client returned, a copy of c, sends it in that role
+// with each request.
+func (c *BridgeClient) shapePrompt(system, user string) (*BridgeClient, string) {
+	cp := *c
+	cp.system = ""
+	switch c.role.System {
+	case roleSystem, roleDeveloper:
+		cp.system = system
+		return &cp, user
+	}
+	return &cp, c.JoinPrompt(system, user)
+}
+
+// ShapePrompt is shapePrompt for ask.PromptShaper
+func (c *BridgeClient) ShapePrompt(system, user string) (ask.Client, string) {
+	return c.shapePrompt(system, user)
+}
+
+// shapePrompt shapes system and user by client's rule when it has one,
+// and folds them by the default rule otherwise
+func shapePrompt(client ask.Client, system, user string) (ask.Client, string) {
+	if s, ok := client.(ask.PromptShaper); ok {
+		return s.ShapePrompt(system, user)
+	}
+	return client, ask.JoinPrompt(system, user)
+}
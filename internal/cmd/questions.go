@@ -0,0 +1,193 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// questionAnswer is one entry in a --questions report
+type questionAnswer struct {
+	Question string `json:"question"`
+	Response string `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// questionsReport is the --questions --output json document
+type questionsReport struct {
+	Answers []questionAnswer `json:"answers"`
+}
+
+// runQuestionsFile answers every question in path about the same input
+// and prints one labeled report; it fails if any question failed
+func runQuestionsFile(cmd *cobra.Command, client *BridgeClient, path, input string, vars map[string]string, guard *sourceGuard, tools []string, costThreshold float64, yes, jsonOut bool) error {
+	questions, err := loadQuestions(path)
+	if err != nil {
+		return err
+	}
+	instructions := ""
+	if len(guard.sources) > 0 {
+		instructions = untrustedInstructions
+		if len(tools) > 0 {
+			fmt.Fprintf(os.Stderr, "Tools disabled: the prompt includes untrusted content (%s)\n", strings.Join(guard.sources, ", "))
+			tools = nil
+		}
+	}
+	askers, prompts, err := questionPrompts(client, questions, input, vars, instructions)
+	if err != nil {
+		return err
+	}
+
+	var estimates []costEstimate
+	for i, p := range prompts {
+		tokens := ask.EstimateTokens(askers[i].folded(p))
+		if err := checkContextWindow(client.provider, client.model, tokens, client.maxTokens); err != nil {
+			return err
+		}
+		if e, ok := estimateCost(client.provider, client.model, tokens, client.maxTokens); ok {
+			estimates = append(estimates, e)
+		}
+	}
+	if err := confirmCost(estimates, costThreshold, yes); err != nil {
+		return err
+	}
+	if !client.IsDaemonRunning() {
+		fmt.Fprintln(os.Stderr, "Note: arc-ai daemon not running. Using fallback mode.")
+		fmt.Fprintln(os.Stderr, "For better performance, run: arc-ai start")
+	}
+
+	ctx, cancel := interruptibleContext(client.timeout)
+	defer cancel()
+	answers := askQuestions(ctx, askers, tools, questions, prompts)
+
+	if jsonOut {
+		if err := json.NewEncoder(cmd.OutOrStdout()).Encode(questionsReport{Answers: answers}); err != nil {
+			return err
+		}
+	} else {
+		writeQuestionsReport(cmd.OutOrStdout(), answers)
+	}
+	if n := failedQuestions(answers); n > 0 {
+		return errors.NewCLIError(fmt.Sprintf("%d of %d questions failed", n, len(answers)))
+	}
+	return nil
+}
+
+// loadQuestions reads one question or @template per line; blank lines
+// and # comments are skipped
+func loadQuestions(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.NewCLIError("cannot read --questions file").WithCause(err)
+	}
+	defer f.Close()
+
+	var questions []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		q := strings.TrimSpace(scanner.Text())
+		if q == "" || strings.HasPrefix(q, "#") {
+			continue
+		}
+		questions = append(questions, q)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.NewCLIError("cannot read --questions file").WithCause(err)
+	}
+	if len(questions) == 0 {
+		return nil, errors.NewCLIError(path + " has no questions").
+			WithSuggestions("Write one question or @template per line")
+	}
+	return questions, nil
+}
+
+// questionPrompts builds the full prompt for each question over the same
+// input, prefixing system with the given instructions when set, and the
+// client to send each with
+func questionPrompts(client *BridgeClient, questions []string, input string, vars map[string]string, instructions string) ([]*BridgeClient, []string, error) {
+	askers := make([]*BridgeClient, len(questions))
+	prompts := make([]string, len(questions))
+	for i, q := range questions {
+		system, user, err := buildPrompt(q, input, vars)
+		if err != nil {
+			return nil, nil, err
+		}
+		if instructions != "" {
+			system = strings.TrimSpace(instructions + "\n\n" + system)
+		}
+		askers[i], prompts[i] = client.shapePrompt(system, user)
+	}
+	return askers, prompts, nil
+}
+
+// askQuestions sends every prompt at once; a failed question is reported
+// in its entry rather than stopping the others
+func askQuestions(ctx context.Context, askers []*BridgeClient, tools []string, questions, prompts []string) []questionAnswer {
+	answers := make([]questionAnswer, len(questions))
+	var wg sync.WaitGroup
+	for i := range questions {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var (
+				answer string
+				err    error
+			)
+			if len(tools) > 0 {
+				answer, err = askers[i].AskWithTools(ctx, prompts[i], tools)
+			} else {
+				answer, err = askers[i].Ask(ctx, prompts[i])
+			}
+			if partial, ok := err.(*partialAnswerError); ok {
+				answer, err = partial.Partial, fmt.Errorf("incomplete: %w", partial.Cause)
+			}
+			answers[i] = questionAnswer{Question: questions[i], Response: strings.TrimSpace(answer)}
+			if err != nil {
+				answers[i].Error = err.Error()
+			}
+		}(i)
+	}
+	wg.Wait()
+	return answers
+}
+
+func writeQuestionsReport(w io.Writer, answers []questionAnswer) {
+	for i, a := range answers {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintf(w, "## %d. %s\n\n", i+1, a.Question)
+		if a.Response != "" {
+			_, _ = fmt.Fprintln(w, a.Response)
+		}
+		if a.Error != "" {
+			if a.Response != "" {
+				_, _ = fmt.Fprintln(w)
+			}
+			_, _ = fmt.Fprintf(w, "(failed: %s)\n", a.Error)
+		}
+	}
+}
+
+// failedQuestions counts the entries that ended in an error
+func failedQuestions(answers []questionAnswer) int {
+	n := 0
+	for _, a := range answers {
+		if a.Error != "" {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// Request priorities; lower values are served first
+type priority int
+
+const (
+	priorityInteractive priority = iota
+	priorityBatch
+	numPriorities
+)
+
+var priorityNames = [numPriorities]string{"interactive", "batch"}
+
+func parsePriority(s string) (priority, error) {
+	switch s {
+	case "", "interactive":
+		return priorityInteractive, nil
+	case "batch":
+		return priorityBatch, nil
+	}
+	return 0, fmt.Errorf("invalid priority %q (use interactive or batch)", s)
+}
+
+// errQueueFull is returned when the queue is at its maximum depth
+var errQueueFull = fmt.Errorf("queue is full")
+
+// queuedJob is one request waiting for a worker
+type queuedJob struct {
+	client   string
+	pri      priority
+	run      func()
+	enqueued time.Time
+}
+
+// requestQueue runs jobs on a fixed pool of workers, always preferring
+// interactive over batch work and capping how many jobs one client may
+// have running at once
+type requestQueue struct {
+	mu        sync.Mutex
+	cond      *sync.Cond
+	pending   [numPriorities][]*queuedJob
+	running   map[string]int
+	perClient int // 0 means no per-client cap
+	maxDepth  int // 0 means unbounded
+
+	served   [numPriorities]int
+	waitTime [numPriorities]time.Duration
+}
+
+func newRequestQueue(workers, perClient, maxDepth int) *requestQueue {
+	q := &requestQueue{running: make(map[string]int), perClient: perClient, maxDepth: maxDepth}
+	q.cond = sync.NewCond(&q.mu)
+	for i := 0; i < workers; i++ {
+		go q.work()
+	}
+	return q
+}
+
+func (q *requestQueue) submit(j *queuedJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.maxDepth > 0 && q.depthLocked() >= q.maxDepth {
+		return errQueueFull
+	}
+	j.enqueued = time.Now()
+	q.pending[j.pri] = append(q.pending[j.pri], j)
+	q.cond.Signal()
+	return nil
+}
+
+// cancel removes a job that has not started; it reports whether it did
+func (q *requestQueue) cancel(j *queuedJob) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for i, p := range q.pending[j.pri] {
+		if p == j {
+			q.pending[j.pri] = append(q.pending[j.pri][:i], q.pending[j.pri][i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (q *requestQueue) work() {
+	for {
+		j := q.next()
+		j.run()
+
+		q.mu.Lock()
+		q.running[j.client]--
+		if q.running[j.client] == 0 {
+			delete(q.running, j.client)
+		}
+		q.mu.Unlock()
+		// A finished job may unblock a client at its cap
+		q.cond.Broadcast()
+	}
+}
+
+// next blocks until a job is runnable and claims it
+func (q *requestQueue) next() *queuedJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for {
+		for pri := range q.pending {
+			for i, j := range q.pending[pri] {
+				if q.perClient > 0 && q.running[j.client] >= q.perClient {
+					continue
+				}
+				q.pending[pri] = append(q.pending[pri][:i], q.pending[pri][i+1:]...)
+				q.running[j.client]++
+				q.served[pri]++
+				q.waitTime[pri] += time.Since(j.enqueued)
+				return j
+			}
+		}
+		q.cond.Wait()
+	}
+}
+
+func (q *requestQueue) depthLocked() int {
+	n := 0
+	for _, p := range q.pending {
+		n += len(p)
+	}
+	return n
+}
+
+// writeMetrics writes queue metrics in the Prometheus text format
+func (q *requestQueue) writeMetrics(w io.Writer) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	inFlight := 0
+	for _, n := range q.running {
+		inFlight += n
+	}
+
+	_, _ = fmt.Fprintln(w, "# TYPE arc_ask_queue_depth gauge")
+	for pri, name := range priorityNames {
+		_, _ = fmt.Fprintf(w, "arc_ask_queue_depth{priority=%q} %d\n", name, len(q.pending[pri]))
+	}
+	_, _ = fmt.Fprintln(w, "# TYPE arc_ask_in_flight gauge")
+	_, _ = fmt.Fprintf(w, "arc_ask_in_flight %d\n", inFlight)
+	_, _ = fmt.Fprintln(w, "# TYPE arc_ask_requests_started_total counter")
+	for pri, name := range priorityNames {
+		_, _ = fmt.Fprintf(w, "arc_ask_requests_started_total{priority=%q} %d\n", name, q.served[pri])
+	}
+	_, _ = fmt.Fprintln(w, "# TYPE arc_ask_queue_wait_seconds_sum counter")
+	for pri, name := range priorityNames {
+		_, _ = fmt.Fprintf(w, "arc_ask_queue_wait_seconds_sum{priority=%q} %.3f\n", name, q.waitTime[pri].Seconds())
+	}
+}
@@ -0,0 +1,220 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// RateLimit caps how hard arc-ask invocations may hit a provider, shared
+// across processes. Zero fields are unlimited.
+type RateLimit struct {
+	RequestsPerMinute int `yaml:"requests_per_minute"`
+	TokensPerHour     int `yaml:"tokens_per_hour"`
+}
+
+// rateLimiter is a pair of token buckets persisted in the state dir
+type rateLimiter struct {
+	name  string // bucket key, usually the provider
+	limit RateLimit
+}
+
+// bucketState is the persisted fill level of both buckets
+type bucketState struct {
+	Requests float64   `json:"requests"`
+	Tokens   float64   `json:"tokens"`
+	Updated  time.Time `json:"updated"`
+}
+
+// staleLock is how old a lock file must be before it is assumed abandoned
+const staleLock = 10 * time.Second
+
+// newRateLimiter picks the limits for a provider: its own entry, else "*".
+// It returns nil when no limit applies.
+func newRateLimiter(provider string, limits map[string]RateLimit) *rateLimiter {
+	name := provider
+	if name == "" {
+		name = "default"
+	}
+	limit, ok := limits[provider]
+	if !ok {
+		limit, ok = limits["*"]
+	}
+	if !ok || (limit.RequestsPerMinute <= 0 && limit.TokensPerHour <= 0) {
+		return nil
+	}
+	return &rateLimiter{name: name, limit: limit}
+}
+
+func (l *rateLimiter) path() string {
+	return filepath.Join(stateDir(), "ratelimit", unsafeFileChars.ReplaceAllString(l.name, "_")+".json")
+}
+
+// acquire takes one request and the estimated tokens from the buckets.
+// When they are empty it waits if wait is set and fails otherwise. A nil
+// limiter always allows.
+func (l *rateLimiter) acquire(ctx context.Context, tokens int, wait bool) error {
+	if l == nil {
+		return nil
+	}
+	for {
+		var delay time.Duration
+		err := l.update(func(s *bucketState) {
+			delay = l.shortfall(s, tokens)
+			if delay == 0 {
+				l.take(s, 1, tokens)
+			}
+		})
+		if err != nil || delay == 0 {
+			return err
+		}
+
+		if !wait {
+			return coded(codeRateLimited, errors.NewCLIError(fmt.Sprintf("rate limit for %s reached; next request allowed in %s", l.name, delay.Round(time.Second))).
+				WithSuggestions("Wait for it with --wait", "Adjust rate_limits in "+displayPath(configPath())))
+		}
+		fmt.Fprintf(os.Stderr, "Rate limit for %s reached; waiting %s\n", l.name, delay.Round(time.Second))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// charge records tokens used beyond the estimate, such as the answer
+func (l *rateLimiter) charge(tokens int) {
+	if l == nil || tokens <= 0 {
+		return
+	}
+	_ = l.update(func(s *bucketState) { l.take(s, 0, tokens) })
+}
+
+// take removes from the buckets that are limited; tokens may go into debt
+func (l *rateLimiter) take(s *bucketState, requests, tokens int) {
+	if l.limit.RequestsPerMinute > 0 {
+		s.Requests -= float64(requests)
+	}
+	if l.limit.TokensPerHour > 0 {
+		s.Tokens -= float64(tokens)
+	}
+}
+
+// shortfall returns how long until the request can go ahead, 0 if now.
+// A request larger than the whole token bucket waits for a full bucket.
+func (l *rateLimiter) shortfall(s *bucketState, tokens int) time.Duration {
+	var wait float64 // seconds
+	if rpm := float64(l.limit.RequestsPerMinute); rpm > 0 && s.Requests < 1 {
+		wait = math.Max(wait, (1-s.Requests)/(rpm/60))
+	}
+	if tph := float64(l.limit.TokensPerHour); tph > 0 {
+		need := math.Min(float64(tokens), tph)
+		if s.Tokens < need {
+			wait = math.Max(wait, (need-s.Tokens)/(tph/3600))
+		}
+	}
+	if wait == 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(wait)) * time.Second
+}
+
+// memoryBuckets hold the buckets of --stateless runs, which do not
+// persist them, keyed by limiter name
+var (
+	memoryBucketsMu sync.Mutex
+	memoryBuckets   = make(map[string]bucketState)
+)
+
+// refill is the buckets' state now: full, or saved topped up for the
+// time since it was saved
+func (l *rateLimiter) refill(saved *bucketState, now time.Time) bucketState {
+	s := bucketState{
+		Requests: float64(l.limit.RequestsPerMinute),
+		Tokens:   float64(l.limit.TokensPerHour),
+		Updated:  now,
+	}
+	if saved != nil {
+		elapsed := now.Sub(saved.Updated).Seconds()
+		s.Requests = math.Min(s.Requests, saved.Requests+elapsed*float64(l.limit.RequestsPerMinute)/60)
+		s.Tokens = math.Min(s.Tokens, saved.Tokens+elapsed*float64(l.limit.TokensPerHour)/3600)
+	}
+	return s
+}
+
+// update refills the buckets and applies fn under a cross-process lock;
+// stateless runs keep them in memory instead
+func (l *rateLimiter) update(fn func(*bucketState)) error {
+	if stateless {
+		memoryBucketsMu.Lock()
+		defer memoryBucketsMu.Unlock()
+		var saved *bucketState
+		if b, ok := memoryBuckets[l.name]; ok {
+			saved = &b
+		}
+		s := l.refill(saved, time.Now())
+		fn(&s)
+		memoryBuckets[l.name] = s
+		return nil
+	}
+
+	path := l.path()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var saved *bucketState
+	if data, err := os.ReadFile(path); err == nil {
+		var b bucketState
+		if json.Unmarshal(data, &b) == nil {
+			saved = &b
+		}
+	}
+	s := l.refill(saved, time.Now())
+	fn(&s)
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// lockFile takes an exclusive lock by creating path, breaking locks left
+// behind by crashed processes
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(2 * staleLock)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > staleLock {
+			_ = os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock %s", path)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,288 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// recipeDir is where saved invocations are stored: ask/recipes/ in the
+// config directory
+func recipeDir() string {
+	return filepath.Join(configDir(), "ask", "recipes")
+}
+
+// recipeParamPattern matches {{name}} placeholders in recipe arguments
+var recipeParamPattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_-]*)\s*\}\}`)
+
+// Recipe is a saved arc-ask invocation: template or question, flags, and
+// input sources, with {{name}} placeholders filled in at run time
+type Recipe struct {
+	Name        string        `yaml:"name"`
+	Description string        `yaml:"description,omitempty"`
+	Args        []string      `yaml:"args"`
+	Params      []RecipeParam `yaml:"params,omitempty"`
+}
+
+// RecipeParam is a placeholder prompted for when not passed with --var
+type RecipeParam struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Default     string `yaml:"default,omitempty"`
+}
+
+func recipePath(name string) string {
+	return filepath.Join(recipeDir(), name+".yaml")
+}
+
+func loadRecipe(name string) (*Recipe, error) {
+	data, err := os.ReadFile(recipePath(name))
+	if os.IsNotExist(err) {
+		return nil, errors.NewCLIError(fmt.Sprintf("recipe %q not found", name)).
+			WithSuggestions("List recipes: arc-ask recipe list")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read recipe: %w", err)
+	}
+	var r Recipe
+	if err := yaml.Unmarshal(data, &r); err != nil {
+		return nil, errors.NewCLIError(fmt.Sprintf("invalid recipe %s", recipePath(name))).WithCause(err)
+	}
+	if r.Name == "" {
+		r.Name = name
+	}
+	return &r, nil
+}
+
+func saveRecipe(r *Recipe) error {
+	if err := os.MkdirAll(recipeDir(), 0o755); err != nil {
+		return fmt.Errorf("create recipe dir: %w", err)
+	}
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(recipePath(r.Name), data, 0o644)
+}
+
+// recipeParams returns the distinct placeholders used in args, in order
+func recipeParams(args []string) []RecipeParam {
+	seen := make(map[string]bool)
+	var params []RecipeParam
+	for _, a := range args {
+		for _, m := range recipeParamPattern.FindAllStringSubmatch(a, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				params = append(params, RecipeParam{Name: m[1]})
+			}
+		}
+	}
+	return params
+}
+
+// expand fills placeholders from vars, prompting for missing values
+func (r *Recipe) expand(vars map[string]string) ([]string, error) {
+	values := make(map[string]string, len(r.Params))
+	for _, p := range r.Params {
+		if v, ok := vars[p.Name]; ok {
+			values[p.Name] = v
+			continue
+		}
+		question := p.Name
+		if p.Description != "" {
+			question = fmt.Sprintf("%s (%s)", p.Name, p.Description)
+		}
+		v, err := promptLine(question, p.Default)
+		if err != nil {
+			return nil, errors.NewCLIError(fmt.Sprintf("recipe %s needs %q", r.Name, p.Name)).
+				WithCause(err).
+				WithSuggestions(fmt.Sprintf("Pass it with: --var %s=VALUE", p.Name))
+		}
+		values[p.Name] = v
+	}
+
+	args := make([]string, len(r.Args))
+	for i, a := range r.Args {
+		args[i] = recipeParamPattern.ReplaceAllStringFunc(a, func(m string) string {
+			return values[recipeParamPattern.FindStringSubmatch(m)[1]]
+		})
+	}
+	return args, nil
+}
+
+func newRecipeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "recipe",
+		Short: "Save and re-run complete invocations",
+		Long: `Recipes capture a full arc-ask invocation (template or question, flags,
+and input sources such as --pane and --context) so it can be re-run by
+name. Arguments may contain {{name}} placeholders that are filled from
+--var or prompted for at run time.`,
+	}
+	cmd.AddCommand(newRecipeSaveCmd(), newRecipeRunCmd(), newRecipeListCmd(), newRecipeEditCmd())
+	return cmd
+}
+
+func newRecipeSaveCmd() *cobra.Command {
+	var (
+		description string
+		force       bool
+	)
+	cmd := &cobra.Command{
+		Use:   "save NAME -- [arc-ask args...]",
+		Short: "Save an invocation as a recipe",
+		Example: `  arc-ask recipe save triage -- @triage --pane '{{pane}}' --var service='{{service}}' --lines 500
+  arc-ask recipe save review -d "Review staged diff" -- @code-review --context CONTRIBUTING.md`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := refuseStateless("recipe save"); err != nil {
+				return err
+			}
+			name := args[0]
+			if err := validateSessionID(name); err != nil {
+				return err
+			}
+			if cmd.ArgsLenAtDash() != 1 {
+				return errors.NewCLIError("separate the recipe name from the invocation with --").
+					WithSuggestions("arc-ask recipe save triage -- @triage --pane dev:1.0")
+			}
+			if _, err := os.Stat(recipePath(name)); err == nil && !force {
+				return errors.NewCLIError(fmt.Sprintf("recipe %q already exists", name)).
+					WithSuggestions("Overwrite with --force", "Edit with: arc-ask recipe edit "+name)
+			}
+
+			r := &Recipe{Name: name, Description: description, Args: args[1:], Params: recipeParams(args[1:])}
+			if err := saveRecipe(r); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Saved recipe %s: %s\n", name, recipePath(name))
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&description, "description", "d", "", "Recipe description")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite an existing recipe")
+	return cmd
+}
+
+func newRecipeRunCmd() *cobra.Command {
+	var vars []string
+	cmd := &cobra.Command{
+		Use:   "run NAME",
+		Short: "Run a saved recipe",
+		Example: `  arc-ask recipe run triage --var service=api --var pane=prod:logs.0
+  git diff --staged | arc-ask recipe run review`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			r, err := loadRecipe(args[0])
+			if err != nil {
+				return err
+			}
+			given, err := parseVars(vars)
+			if err != nil {
+				return err
+			}
+			expanded, err := r.expand(given)
+			if err != nil {
+				return err
+			}
+
+			root := NewRootCmd()
+			root.SetArgs(expanded)
+			root.SetIn(cmd.InOrStdin())
+			root.SetOut(cmd.OutOrStdout())
+			root.SetErr(cmd.ErrOrStderr())
+			return root.Execute()
+		},
+	}
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Recipe parameter (key=value)")
+	return cmd
+}
+
+func newRecipeListCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List saved recipes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			entries, err := os.ReadDir(recipeDir())
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("read recipe dir: %w", err)
+			}
+			var names []string
+			for _, e := range entries {
+				if !e.IsDir() && filepath.Ext(e.Name()) == ".yaml" {
+					names = append(names, strings.TrimSuffix(e.Name(), ".yaml"))
+				}
+			}
+			sort.Strings(names)
+
+			w := cmd.OutOrStdout()
+			if len(names) == 0 {
+				_, _ = fmt.Fprintln(w, "No recipes. Save one with: arc-ask recipe save NAME -- ARGS...")
+				return nil
+			}
+			for _, name := range names {
+				r, err := loadRecipe(name)
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintf(w, "  %-16s %s\n", r.Name, r.Description)
+				_, _ = fmt.Fprintf(w, "  %-16s arc-ask %s\n", "", shellJoin(r.Args))
+			}
+			return nil
+		},
+	}
+}
+
+func newRecipeEditCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "edit NAME",
+		Short: "Edit a recipe in $EDITOR",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := refuseStateless("recipe edit"); err != nil {
+				return err
+			}
+			if _, err := loadRecipe(args[0]); err != nil {
+				return err
+			}
+			editor := os.Getenv("VISUAL")
+			if editor == "" {
+				editor = os.Getenv("EDITOR")
+			}
+			if editor == "" {
+				editor = "vi"
+			}
+			c := execCommand("sh", "-c", editor+" "+shellQuote(recipePath(args[0])))
+			c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+			if err := c.Run(); err != nil {
+				return fmt.Errorf("run editor: %w", err)
+			}
+			// Validate the edited file
+			_, err := loadRecipe(args[0])
+			return err
+		},
+	}
+}
+
+// shellJoin renders args as a copy-pasteable command line
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		if a == "" || strings.ContainsAny(a, " \t\n'\"$`\\*?;&|<>(){}") {
+			a = shellQuote(a)
+		}
+		quoted[i] = a
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Redaction kinds
+const (
+	kindSecret = "secret"
+	kindPII    = "pii"
+)
+
+// Output scan modes for --scan-output
+const (
+	scanOff    = "off"
+	scanWarn   = "warn"
+	scanRedact = "redact"
+)
+
+// redactRule detects one kind of sensitive value
+type redactRule struct {
+	name  string
+	kind  string
+	re    *regexp.Regexp
+	valid func(match string) bool // optional extra check
+}
+
+// redactRules is the shared engine for input redaction and output scanning
+var redactRules = []redactRule{
+	{name: "private-key", kind: kindSecret, re: regexp.MustCompile(`-----BEGIN [A-Z ]*PRIVATE KEY-----[\s\S]*?-----END [A-Z ]*PRIVATE KEY-----`)},
+	{name: "aws-access-key", kind: kindSecret, re: regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{name: "github-token", kind: kindSecret, re: regexp.MustCompile(`\b(gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{40,})\b`)},
+	{name: "slack-token", kind: kindSecret, re: regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}\b`)},
+	{name: "api-key", kind: kindSecret, re: regexp.MustCompile(`\b(sk-[A-Za-z0-9_-]{20,}|AIza[0-9A-Za-z_-]{35})\b`)},
+	{name: "jwt", kind: kindSecret, re: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,}\b`)},
+	{name: "credential-assignment", kind: kindSecret, re: regexp.MustCompile(`(?i)\b(password|passwd|secret|api[_-]?key|access[_-]?token|auth[_-]?token)\s*[:=]\s*["']?[^\s"']{8,}`)},
+	{name: "connection-string", kind: kindSecret, re: regexp.MustCompile(`\b[a-z][a-z0-9+]*://[^\s:/@]+:[^\s@/]+@[^\s]+`)},
+	{name: "email", kind: kindPII, re: regexp.MustCompile(`\b[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}\b`)},
+	{name: "credit-card", kind: kindPII, re: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), valid: luhnValid},
+	{name: "us-ssn", kind: kindPII, re: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+}
+
+// redactionHit counts matches of one rule
+type redactionHit struct {
+	Rule  string `json:"rule"`
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
+}
+
+// redact replaces sensitive values with [REDACTED:rule] markers and
+// reports what it found
+func redact(text string) (string, []redactionHit) {
+	counts := make(map[string]*redactionHit)
+	for _, rule := range redactRules {
+		text = rule.re.ReplaceAllStringFunc(text, func(m string) string {
+			if rule.valid != nil && !rule.valid(m) {
+				return m
+			}
+			if counts[rule.name] == nil {
+				counts[rule.name] = &redactionHit{Rule: rule.name, Kind: rule.kind}
+			}
+			counts[rule.name].Count++
+			return "[REDACTED:" + rule.name + "]"
+		})
+	}
+
+	hits := make([]redactionHit, 0, len(counts))
+	for _, h := range counts {
+		hits = append(hits, *h)
+	}
+	sort.Slice(hits, func(i, j int) bool { return hits[i].Rule < hits[j].Rule })
+	return text, hits
+}
+
+// scanOutput applies --scan-output to an answer before it leaves arc-ask:
+// warn reports hits on stderr, redact also masks them
+func scanOutput(mode, answer string, warn func(string)) (string, []redactionHit) {
+	if mode == "" || mode == scanOff {
+		return answer, nil
+	}
+	redacted, hits := redact(answer)
+	if len(hits) == 0 {
+		return answer, nil
+	}
+	action := "contains"
+	if mode == scanRedact {
+		action = "redacted"
+		answer = redacted
+	}
+	warn(fmt.Sprintf("Warning: answer %s possible sensitive data: %s", action, describeHits(hits)))
+	return answer, hits
+}
+
+func validateScanMode(mode string) error {
+	switch mode {
+	case scanOff, scanWarn, scanRedact:
+		return nil
+	}
+	return errors.NewCLIError(fmt.Sprintf("invalid --scan-output %q", mode)).
+		WithSuggestions("Use one of: off, warn, redact")
+}
+
+func describeHits(hits []redactionHit) string {
+	parts := make([]string, len(hits))
+	for i, h := range hits {
+		parts[i] = fmt.Sprintf("%s x%d", h.Rule, h.Count)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// luhnValid filters credit-card candidates to those with a valid checksum
+func luhnValid(s string) bool {
+	var digits []int
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, int(r-'0'))
+		}
+	}
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
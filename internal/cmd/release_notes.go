@@ -0,0 +1,210 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// releaseNoteStyles are the supported --format values
+var releaseNoteStyles = map[string]string{
+	"keep-a-changelog": "Use Keep a Changelog sections (### Added, ### Changed, ### Deprecated, ### Removed, ### Fixed, ### Security), omitting empty sections.",
+	"markdown":         "Use markdown sections for Features, Fixes, Performance, Documentation, and Internal, omitting empty sections.",
+	"plain":            "Use plain text with a short heading line per category and dash bullets, no markdown.",
+}
+
+const releaseNotesInstructions = `Write release notes from these commits and merged pull requests.
+Group related changes, describe user-visible impact in plain language, keep PR numbers like (#123),
+and drop noise such as merge commits, typo fixes, and CI tweaks unless they matter to users.
+Do not add a version heading; output only the categorized notes.`
+
+var (
+	mergePRPattern  = regexp.MustCompile(`^Merge pull request #(\d+)`)
+	squashPRPattern = regexp.MustCompile(`\(#\d+\)\s*$`)
+)
+
+// commitsPerChunk bounds how many log entries go into one model request
+const commitsPerChunk = 150
+
+func newReleaseNotesCmd(client *BridgeClient) *cobra.Command {
+	var (
+		style     string
+		apply     bool
+		changelog string
+		version   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "release-notes FROM..TO",
+		Short: "Generate categorized release notes for a git range",
+		Long: `Gather commit messages and merged PR titles in a git range and generate
+categorized release notes. Large ranges are summarized in chunks and then
+merged. With --apply the notes are prepended to the changelog file, which
+needs a markdown format.`,
+		Example: `  arc-ask release-notes v1.2.0..v1.3.0
+  arc-ask release-notes v1.2.0..HEAD --version v1.3.0 --apply
+  arc-ask release-notes v1.2.0..v1.3.0 --format plain`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			styleHint, ok := releaseNoteStyles[style]
+			if !ok {
+				return errors.NewCLIError(fmt.Sprintf("unknown format %q", style)).
+					WithSuggestions("Formats: keep-a-changelog, markdown, plain")
+			}
+			// prependChangelog finds the newest release by its "## " heading
+			if apply && style == "plain" {
+				return errors.NewCLIError("--apply needs a markdown format; plain notes have no release headings to insert above").
+					WithSuggestions("Use --format keep-a-changelog or --format markdown", "Or print plain notes without --apply")
+			}
+
+			rangeSpec := args[0]
+			from, to, ok := strings.Cut(rangeSpec, "..")
+			if !ok || from == "" {
+				return errors.NewCLIError(fmt.Sprintf("invalid range %q", rangeSpec)).
+					WithSuggestions("Format: FROM..TO (e.g., v1.2.0..v1.3.0)")
+			}
+			if to == "" {
+				to = "HEAD"
+			}
+			if version == "" {
+				version = to
+				if to == "HEAD" {
+					version = "Unreleased"
+				}
+			}
+
+			entries, err := releaseLogEntries(from + ".." + to)
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				return errors.NewCLIError(fmt.Sprintf("no commits in %s", rangeSpec))
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), client.timeout*time.Duration(1+len(entries)/commitsPerChunk))
+			defer cancel()
+
+			notes, err := summarizeReleaseEntries(ctx, client, entries, styleHint)
+			if err != nil {
+				return err
+			}
+
+			heading := fmt.Sprintf("## %s - %s", version, time.Now().Format("2006-01-02"))
+			if style == "plain" {
+				heading = fmt.Sprintf("%s (%s)", version, time.Now().Format("2006-01-02"))
+			}
+			section := heading + "\n\n" + strings.TrimSpace(notes) + "\n"
+
+			if !apply {
+				_, _ = fmt.Fprint(cmd.OutOrStdout(), section)
+				return nil
+			}
+			if err := prependChangelog(changelog, section); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Updated %s with %s\n", changelog, version)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&style, "format", "keep-a-changelog", "Notes format (keep-a-changelog, markdown, plain)")
+	cmd.Flags().BoolVar(&apply, "apply", false, "Prepend the notes to the changelog file (markdown formats only)")
+	cmd.Flags().StringVar(&changelog, "changelog", "CHANGELOG.md", "Changelog file for --apply")
+	cmd.Flags().StringVar(&version, "version", "", "Version heading (default: range end)")
+	return cmd
+}
+
+// releaseLogEntries returns one line per commit, preferring PR titles for
+// merge commits ("Merge pull request #12 from x" -> "PR title (#12)")
+func releaseLogEntries(rangeSpec string) ([]string, error) {
+	out, err := execCommand("git", "log", "--no-color", "--format=%s%x1f%b%x1e", rangeSpec).Output()
+	if err != nil {
+		return nil, errors.NewCLIError(fmt.Sprintf("git log %s failed", rangeSpec)).
+			WithCause(err).
+			WithSuggestions("Check that both refs exist: git tag --list")
+	}
+
+	var entries []string
+	for _, record := range strings.Split(string(out), "\x1e") {
+		subject, body, _ := strings.Cut(strings.TrimSpace(record), "\x1f")
+		subject = strings.TrimSpace(subject)
+		if subject == "" {
+			continue
+		}
+		if m := mergePRPattern.FindStringSubmatch(subject); m != nil {
+			title := strings.TrimSpace(strings.SplitN(strings.TrimSpace(body), "\n", 2)[0])
+			if title == "" {
+				continue
+			}
+			entries = append(entries, fmt.Sprintf("PR: %s (#%s)", title, m[1]))
+			continue
+		}
+		if strings.HasPrefix(subject, "Merge ") {
+			continue
+		}
+		if squashPRPattern.MatchString(subject) {
+			entries = append(entries, "PR: "+subject)
+			continue
+		}
+		entries = append(entries, "Commit: "+subject)
+	}
+	return entries, nil
+}
+
+// summarizeReleaseEntries drafts notes per chunk and merges the drafts
+func summarizeReleaseEntries(ctx context.Context, client ask.Client, entries []string, styleHint string) (string, error) {
+	var drafts []string
+	for start := 0; start < len(entries); start += commitsPerChunk {
+		end := min(start+commitsPerChunk, len(entries))
+		prompt := releaseNotesInstructions + "\n" + styleHint + "\n\nChanges:\n" + strings.Join(entries[start:end], "\n")
+		draft, err := client.Ask(ctx, prompt)
+		if err != nil {
+			return "", errors.NewCLIError("AI query failed").WithCause(err)
+		}
+		drafts = append(drafts, draft)
+	}
+	if len(drafts) == 1 {
+		return drafts[0], nil
+	}
+
+	prompt := "Merge these partial release notes into one set, combining duplicate categories and entries.\n" +
+		styleHint + "\n\n" + strings.Join(drafts, "\n\n---\n\n")
+	merged, err := client.Ask(ctx, prompt)
+	if err != nil {
+		return "", errors.NewCLIError("AI query failed").WithCause(err)
+	}
+	return merged, nil
+}
+
+// prependChangelog inserts a section after the changelog's title block,
+// creating the file when it does not exist
+func prependChangelog(path, section string) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("read changelog: %w", err)
+	}
+	existing := string(data)
+	if existing == "" {
+		existing = "# Changelog\n"
+	}
+
+	// Keep the title and intro paragraph above the newest release
+	insertAt := len(existing)
+	if i := strings.Index(existing, "\n## "); i >= 0 {
+		insertAt = i + 1
+	}
+	head := strings.TrimRight(existing[:insertAt], "\n") + "\n\n"
+	updated := head + section + "\n" + existing[insertAt:]
+
+	return os.WriteFile(path, []byte(strings.TrimRight(updated, "\n")+"\n"), 0o644)
+}
@@ -0,0 +1,148 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// continueInstructions asks the model to pick up an interrupted answer
+const continueInstructions = `Your previous answer to this request was interrupted. It ended with the text below. Continue exactly where it stopped: do not repeat or summarize what was already written.
+
+Interrupted answer:
+`
+
+// interruptedRequest is the state saved when a generation is cut short
+type interruptedRequest struct {
+	Prompt  string    `json:"prompt"`
+	Model   string    `json:"model,omitempty"`
+	Partial string    `json:"partial"`
+	Reason  string    `json:"reason"`
+	Time    time.Time `json:"time"`
+}
+
+func interruptedPath() string {
+	return filepath.Join(stateDir(), "interrupted.json")
+}
+
+func saveInterrupted(r interruptedRequest) error {
+	if stateless {
+		return nil
+	}
+	path := interruptedPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func loadInterrupted() (*interruptedRequest, error) {
+	data, err := os.ReadFile(interruptedPath())
+	if os.IsNotExist(err) {
+		return nil, errors.NewCLIError("nothing to resume").
+			WithSuggestions("Interrupted answers are saved when a generation is cut short")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var r interruptedRequest
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("parse interrupted request: %w", err)
+	}
+	return &r, nil
+}
+
+// interruptibleContext ends on Ctrl-C or SIGTERM as well as after timeout,
+// so a partial answer can be saved instead of lost
+func interruptibleContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+func newResumeCmd(client *BridgeClient) *cobra.Command {
+	var restart bool
+
+	cmd := &cobra.Command{
+		Use:   "resume",
+		Short: "Continue the last interrupted answer",
+		Long: `Continue the last answer that was cut short by Ctrl-C, --total-timeout,
+or a provider failure. The saved partial answer is printed, then the model
+is asked to continue from where it stopped.
+
+pi has no native continuation, so resuming sends the original request with
+the partial answer attached; --restart re-runs the request from scratch.`,
+		Example: `  arc-ask resume
+  arc-ask resume --restart --total-timeout 5m`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			saved, err := loadInterrupted()
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+
+			prompt := saved.Prompt
+			if restart {
+				fmt.Fprintf(os.Stderr, "Previous partial answer (%s):\n%s\n\n", saved.Reason, saved.Partial)
+			} else {
+				prompt = ask.JoinPrompt(prompt, continueInstructions+saved.Partial)
+				_, _ = fmt.Fprintln(out, saved.Partial)
+			}
+
+			ctx, cancel := interruptibleContext(client.timeout)
+			defer cancel()
+
+			if saved.Model != "" {
+				client = client.WithModel(saved.Model)
+			}
+			answer, err := client.Ask(ctx, prompt)
+			if partial, ok := err.(*partialAnswerError); ok {
+				next := *saved
+				next.Partial = partial.Partial
+				if !restart {
+					next.Partial = saved.Partial + "\n" + partial.Partial
+				}
+				next.Reason, next.Time = partial.Cause.Error(), time.Now()
+				_, _ = fmt.Fprintln(out, partial.Partial)
+				if err := saveInterrupted(next); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: could not save interrupted answer: %v\n", err)
+				}
+				return errors.NewCLIError("answer is incomplete").
+					WithCause(partial.Cause).
+					WithSuggestions("Continue with: arc-ask resume")
+			}
+			if err != nil {
+				return errors.NewCLIError("AI query failed").WithCause(err)
+			}
+
+			_, _ = fmt.Fprintln(out, answer)
+			if err := os.Remove(interruptedPath()); err != nil && !os.IsNotExist(err) {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&restart, "restart", false, "Re-run the request from scratch instead of continuing")
+	return cmd
+}
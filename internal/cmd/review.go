@@ -0,0 +1,150 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// reviewInstructions asks for findings located on lines the diff shows
+const reviewInstructions = `After your review, output the findings as a single JSON code block of the form:
+` + "```json" + `
+{"findings": [{"file": "path/as/in/the/diff", "line": 12, "severity": "error|warning|notice", "message": "What is wrong and how to fix it"}]}
+` + "```" + `
+line is the line number in the new version of the file and must be an added (+) or unchanged line shown in the diff; for problems in removed code, use the nearest line shown. Use an empty list when there are no findings.`
+
+// reviewComment is a finding placed on the diff
+type reviewComment struct {
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Severity string `json:"severity"`
+	Comment  string `json:"comment"`
+	// Position is GitHub's diff position for review comments; 0 when the
+	// line is not in the diff
+	Position int  `json:"position,omitempty"`
+	InDiff   bool `json:"in_diff"`
+
+	index int // diff text line the comment follows
+}
+
+// reviewJSON is the review-json document
+type reviewJSON struct {
+	Verdict  string          `json:"verdict,omitempty"`
+	Comments []reviewComment `json:"comments"`
+}
+
+// reviewComments places each finding on the diff line it refers to
+func reviewComments(diff []diffFile, findings []Finding) []reviewComment {
+	comments := make([]reviewComment, 0, len(findings))
+	for _, f := range findings {
+		c := reviewComment{File: f.File, Line: f.Line, Severity: f.Severity, Comment: f.Message, index: -1}
+		if df := findDiffFile(diff, f.File); df != nil {
+			c.File = df.Path
+			if l := df.line(f.Line); l != nil {
+				c.Position, c.InDiff, c.index = l.Position, true, l.Index
+			}
+		}
+		comments = append(comments, c)
+	}
+	return comments
+}
+
+// writeReviewDiff prints the diff with each comment under its line, then
+// the comments that could not be placed
+func writeReviewDiff(w io.Writer, r report) error {
+	comments := reviewComments(parseDiff(r.Diff), r.Findings)
+	at := make(map[int][]reviewComment)
+	var outside []reviewComment
+	for _, c := range comments {
+		if c.InDiff {
+			at[c.index] = append(at[c.index], c)
+		} else {
+			outside = append(outside, c)
+		}
+	}
+
+	if len(at) > 0 {
+		for i, line := range strings.Split(strings.TrimRight(r.Diff, "\n"), "\n") {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+			for _, c := range at[i] {
+				if err := writeReviewNote(w, "    ^ ", c); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if len(outside) > 0 {
+		if len(at) > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		_, _ = fmt.Fprintln(w, "Comments outside the diff:")
+		for _, c := range outside {
+			prefix := "  "
+			if c.File != "" {
+				prefix += c.File
+				if c.Line > 0 {
+					prefix += fmt.Sprintf(":%d", c.Line)
+				}
+				prefix += ": "
+			}
+			if err := writeReviewNote(w, prefix, c); err != nil {
+				return err
+			}
+		}
+	}
+
+	if len(comments) == 0 {
+		_, err := fmt.Fprintln(w, strings.TrimSpace(r.Answer))
+		return err
+	}
+	_, err := fmt.Fprintf(w, "\n%s\n", reviewSummary(comments))
+	return err
+}
+
+// writeReviewNote writes a comment, indenting continuation lines under
+// its first line
+func writeReviewNote(w io.Writer, prefix string, c reviewComment) error {
+	lines := strings.Split(strings.TrimSpace(c.Comment), "\n")
+	indent := strings.Repeat(" ", len(prefix)+len(c.Severity)+2)
+	if _, err := fmt.Fprintf(w, "%s%s: %s\n", prefix, c.Severity, lines[0]); err != nil {
+		return err
+	}
+	for _, l := range lines[1:] {
+		if _, err := fmt.Fprintf(w, "%s%s\n", indent, l); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reviewSummary counts the comments by severity
+func reviewSummary(comments []reviewComment) string {
+	counts := make(map[string]int)
+	for _, c := range comments {
+		counts[c.Severity]++
+	}
+	var parts []string
+	for _, sev := range []string{SeverityError, SeverityWarning, SeverityNotice} {
+		if n := counts[sev]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, sev))
+		}
+	}
+	noun := "comments"
+	if len(comments) == 1 {
+		noun = "comment"
+	}
+	return fmt.Sprintf("%d review %s: %s", len(comments), noun, strings.Join(parts, ", "))
+}
+
+func writeReviewJSON(w io.Writer, r report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(reviewJSON{Verdict: r.Verdict, Comments: reviewComments(parseDiff(r.Diff), r.Findings)})
+}
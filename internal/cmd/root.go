@@ -5,6 +5,7 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -13,23 +14,38 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-ask/pkg/ask/pi"
+	"github.com/yourorg/arc-ask/pkg/fixture"
 	"github.com/yourorg/arc-sdk/errors"
 	"github.com/yourorg/arc-sdk/output"
 	"github.com/yourorg/arc-tmux/pkg/tmux"
 )
 
-// Client interface for arc-ai bridge
-type AIClient interface {
-	Ask(ctx context.Context, prompt string) (string, error)
-	AskWithContext(ctx context.Context, prompt, context string) (string, error)
-	AskWithTools(ctx context.Context, prompt string, tools []string) (string, error)
-	IsDaemonRunning() bool
-}
-
-// BridgeClient implements AIClient using arc-ai daemon
+// BridgeClient implements ask.Client using arc-ai daemon
 type BridgeClient struct {
-	socketPath string
-	timeout    time.Duration
+	socketPath     string
+	timeout        time.Duration  // total generation time
+	connectTimeout time.Duration  // time to the first response byte
+	model          string         // empty uses pi's default
+	modelAlias     string         // the alias model was resolved from, if any
+	provider       string         // empty uses pi's default
+	role           PromptRole     // how the provider takes system prompts
+	system         string         // sent in the system or developer role; see shapePrompt
+	env            []string       // extra environment for pi, e.g. API keys
+	maxTokens      int            // 0 uses the provider's default
+	temperature    *float64       // nil uses the provider's default
+	thinking       string         // reasoning level; empty uses the model's default
+	dir            string         // pi's working directory; empty is the current one
+	tools          []string       // pi tools for this request; see AskWithTools
+	server         string         // arc-ask serve URL to ask through; empty runs pi here
+	onText         func(string)   // sees answer text as it streams in; nil ignores it
+	fixtures       *fixture.Store // --record-fixtures or --replay-fixtures
+	replay         bool           // answer from fixtures instead of the provider
+	limiter        *rateLimiter   // nil when no rate limit is configured
+	waitForLimit   bool           // wait out the rate limit instead of failing
+	continuations  int            // continuation requests when an answer hits maxTokens
+	stats          *callStats     // usage and finish reason, shared by copies
 }
 
 // NewBridgeClient creates a client for arc-ai daemon
@@ -39,15 +55,25 @@ func NewBridgeClient() *BridgeClient {
 		socketPath = "~/.config/arc/ai/daemon.sock"
 	}
 	return &BridgeClient{
-		socketPath: socketPath,
-		timeout:    60 * time.Second,
+		socketPath:     socketPath,
+		timeout:        defaultTotalTimeout,
+		connectTimeout: defaultConnectTimeout,
+		stats:          &callStats{},
 	}
 }
 
+// WithModel returns a copy of the client that queries the given model or
+// alias
+func (c *BridgeClient) WithModel(model string) *BridgeClient {
+	cp := *c
+	cp.useModel(model)
+	return &cp
+}
+
 // IsDaemonRunning checks if arc-ai is available
 func (c *BridgeClient) IsDaemonRunning() bool {
 	// Check for socket file
-	path := expandHome(c.socketPath)
+	path := ask.ExpandHome(c.socketPath)
 	_, err := os.Stat(path)
 	return err == nil
 }
@@ -66,49 +92,182 @@ func (c *BridgeClient) AskWithContext(ctx context.Context, prompt, context strin
 
 // AskWithTools enables specific Pi tools
 func (c *BridgeClient) AskWithTools(ctx context.Context, prompt string, tools []string) (string, error) {
-	// In full implementation, tell daemon which extensions to load
-	// For fallback, tools are not supported (daemon required)
-	return c.fallbackAsk(ctx, prompt, "")
+	cp := *c
+	cp.tools = tools
+	return cp.fallbackAsk(ctx, prompt)
 }
 
-// fallbackAsk runs pi directly (temporary until full RPC)
+// fallbackAsk runs pi directly (temporary until full RPC), recording or
+// replaying the exchange when fixtures are enabled
 func (c *BridgeClient) fallbackAsk(ctx context.Context, prompt string, input ...string) (string, error) {
-	// Check if pi is installed
-	piPath := "pi"
-	if _, err := exec.LookPath(piPath); err != nil {
-		return "", fmt.Errorf("pi not found. Install: npm install -g @mariozechner/pi-coding-agent")
+	if c.fixtures == nil {
+		return c.runFallback(ctx, prompt, input...)
+	}
+	// Fixtures match on the folded prompt, whatever role the system is sent in
+	request := c.folded(prompt)
+	if len(input) > 0 {
+		request = fixture.RequestPrompt(request, input[0])
 	}
+	if c.replay {
+		return c.fixtures.Replay(c.provider, c.model, request)
+	}
+	answer, err := c.runFallback(ctx, prompt, input...)
+	if recErr := c.fixtures.Record(c.provider, c.model, request, answer, err); recErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not record fixture: %v\n", recErr)
+	}
+	return answer, err
+}
 
-	args := []string{"--mode", "json", "--print", prompt}
-	if len(input) > 0 && input[0] != "" {
-		// Use heredoc for input
-		args = []string{"-c", fmt.Sprintf("echo %q | pi --mode json --print %q", input[0], prompt)}
-		piPath = "bash"
+func (c *BridgeClient) runFallback(ctx context.Context, prompt string, input ...string) (string, error) {
+	if err := checkRetired(c.model); err != nil {
+		return "", err
+	}
+	// The server answers without tools, so tool runs stay here
+	if c.server != "" && c.dir == "" && len(c.tools) == 0 {
+		stdin := ""
+		if len(input) > 0 {
+			stdin = input[0]
+		}
+		answer, err := c.askServer(ctx, prompt, stdin)
+		if _, answered := err.(*serverError); err == nil || answered {
+			return answer, err
+		}
+		serverUnreachable.Do(func() {
+			fmt.Fprintf(os.Stderr, "Warning: arc-ask server %s unreachable (%v); asking directly\n", c.server, err)
+		})
+	}
+	if c.provider == providerOllama {
+		if err := ensureOllamaModel(ctx, c.model); err != nil {
+			return "", err
+		}
+	}
+	text, stop, err := c.runPiOnce(ctx, prompt, input...)
+	for n := 1; err == nil && stop == stopLength && n <= c.continuations; n++ {
+		fmt.Fprintf(os.Stderr, "Answer reached the output limit; continuing (%d/%d)\n", n, c.continuations)
+		var more string
+		more, stop, err = c.runPiOnce(ctx, continuationPrompt(prompt, text), input...)
+		if partial, ok := err.(*partialAnswerError); ok {
+			more = partial.Partial
+		}
+		text = stitchContinuation(text, more)
+		if err != nil {
+			text = strings.TrimSpace(text)
+			return text, &partialAnswerError{Partial: text, Cause: err}
+		}
 	}
+	if err == nil && stop == stopLength && c.continuations > 0 {
+		fmt.Fprintf(os.Stderr, "Warning: answer still incomplete after --max-continuations %d\n", c.continuations)
+	}
+	return strings.TrimSpace(text), err
+}
 
-	cmd := execCommand(piPath, args...)
-	cmd.Env = os.Environ()
+// runPiOnce runs one pi request and returns the answer and why the model
+// stopped
+func (c *BridgeClient) runPiOnce(ctx context.Context, prompt string, input ...string) (string, string, error) {
+	if err := pi.Installed(); err != nil {
+		return "", "", coded(codePiNotFound, err)
+	}
+	stdin := ""
+	if len(input) > 0 {
+		stdin = input[0]
+	}
+	if err := egress.checkProvider(c.provider, c.system+prompt+stdin); err != nil {
+		return "", "", coded(codeEgressDenied, err)
+	}
+
+	p := c.piClient()
+	if len(prompt)+len(stdin) > pi.SpillThreshold {
+		// Too big for argv: pi gets private temp files instead
+		dir, err := privateTempDir()
+		if err != nil {
+			return "", "", err
+		}
+		p.TempDir = dir
+	}
 
-	out, err := cmd.Output()
+	promptTokens := ask.EstimateTokens(c.folded(prompt))
+	if len(input) > 0 {
+		promptTokens += ask.EstimateTokens(input[0])
+	}
+	if err := c.limiter.acquire(ctx, promptTokens, c.waitForLimit); err != nil {
+		return "", "", err
+	}
+
+	tools := newToolRecorder()
+	p.Observe = tools
+	if c.onText != nil {
+		p.Observe = io.MultiWriter(tools, &textStream{fn: c.onText})
+	}
+	res, err := p.Run(ctx, pi.Request{Prompt: prompt, Input: stdin, Tools: c.tools})
+	c.stats.addTools(tools.snapshot())
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			return "", fmt.Errorf("pi failed: %s", exitErr.Stderr)
+		if timeout, ok := err.(*pi.ConnectTimeoutError); ok {
+			return "", "", coded(codeTimeout, fmt.Errorf("%v (raise --connect-timeout)", timeout))
 		}
-		return "", fmt.Errorf("failed to run pi: %w", err)
+		if partial := strings.TrimSpace(res.Text); partial != "" {
+			return partial, "", &partialAnswerError{Partial: partial, Cause: err}
+		}
+		if ctx.Err() != nil {
+			return "", "", fmt.Errorf("no answer before the generation ended: %w", ctx.Err())
+		}
+		return "", "", coded(piFailureCode(err, tools.snapshot()), err)
 	}
 
-	return strings.TrimSpace(string(out)), nil
+	// Keep surrounding whitespace so continuations can be stitched exactly
+	answer := res.Text
+	c.limiter.charge(ask.EstimateTokens(answer))
+	usage := callUsage{InputTokens: res.Usage.InputTokens, OutputTokens: res.Usage.OutputTokens}
+	c.stats.record(usage, res.StopReason, promptTokens, ask.EstimateTokens(answer))
+	c.stats.addThinking(res.Thinking)
+	return answer, res.StopReason, nil
+}
+
+// piClient is the pi run for c's settings
+func (c *BridgeClient) piClient() *pi.Client {
+	return &pi.Client{
+		Provider:       c.provider,
+		Model:          c.model,
+		MaxTokens:      c.maxTokens,
+		Temperature:    c.temperature,
+		Thinking:       c.thinking,
+		System:         c.system,
+		ReplaceSystem:  c.role.System == roleDeveloper,
+		Dir:            c.dir,
+		Env:            c.env,
+		ConnectTimeout: c.connectTimeout,
+		Command:        execCommand,
+	}
 }
 
 // execCommand is an abstraction for testing
 var execCommand = exec.Command
 
-func expandHome(path string) string {
-	if strings.HasPrefix(path, "~/") {
-		home, _ := os.UserHomeDir()
-		return home + path[1:]
-	}
-	return path
+// askResult is the --output json document; --fields selects among its
+// fields
+type askResult struct {
+	Response     string               `json:"response"`
+	Provider     string               `json:"provider,omitempty"`
+	Model        string               `json:"model,omitempty"`
+	Thinking     string               `json:"thinking,omitempty"` // the model's reasoning trace
+	Tools        []toolCall           `json:"tools,omitempty"`    // tool calls made while answering
+	Usage        *callUsage           `json:"usage,omitempty"`    // nil for cached answers
+	Latency      float64              `json:"latency,omitempty"`  // seconds spent generating
+	FinishReason string               `json:"finish_reason,omitempty"`
+	RequestID    string               `json:"request_id"`
+	Retries      int                  `json:"retries"`
+	Cached       bool                 `json:"cached,omitempty"`
+	Outputs      map[string]string    `json:"outputs,omitempty"` // a template's named outputs
+	Experiment   *experimentArm       `json:"experiment,omitempty"`
+	Deadline     *deadlineRace        `json:"deadline,omitempty"`
+	ByOwner      map[string][]Finding `json:"by_owner,omitempty"`
+	Redactions   []redactionHit       `json:"redactions,omitempty"`
+	Confidence   *Confidence          `json:"confidence,omitempty"`
+	Actions      []Action             `json:"actions,omitempty"`
+	Consensus    *consensusResult     `json:"consensus,omitempty"`
+	Sandbox      *sandboxResult       `json:"sandbox,omitempty"`
+	Run          *runExplanation      `json:"run,omitempty"`
+	Untrusted    []string             `json:"untrusted_sources,omitempty"`
+	Citations    []Citation           `json:"citations,omitempty"` // with --line-numbers
 }
 
 // NewRootCmd creates the root command
@@ -116,12 +275,75 @@ func NewRootCmd() *cobra.Command {
 	client := NewBridgeClient()
 
 	var (
-		pane          string
-		lines         int
-		contextFiles  []string
-		tools         []string
-		listTemplates bool
-		outputOpts    output.OutputOptions
+		pane                string
+		lines               int
+		captureSpec         string
+		sinceLast           bool
+		contextFiles        []string
+		contextBudget       int
+		contextOrder        string
+		contextWeights      []string
+		tools               []string
+		vars                []string
+		listTemplates       bool
+		pickFromHistory     bool
+		filter              bool
+		byOwner             bool
+		redactInput         bool
+		scanMode            string
+		confidence          bool
+		withActions         bool
+		consensusSpec       string
+		captureTimeout      time.Duration
+		timing              bool
+		explainRun          bool
+		excludes            []string
+		lastOutput          int
+		recordFixtures      string
+		autoContinue        bool
+		maxContinuations    int
+		modelName           string
+		thinkingSpec        string
+		showThinking        bool
+		envProfile          string
+		envContext          bool
+		preview             bool
+		replayFixtures      string
+		excludeLinePatterns []string
+		sampleMode          string
+		timestampSpec       string
+		logSpecs            []string
+		mergeByTime         bool
+		lineNumbers         bool
+		sampleSize          int
+		extract             string
+		temperature         float64
+		noCache             bool
+		useCache            bool
+		noRetry             bool
+		yes                 bool
+		saveNoteTo          string
+		mic                 bool
+		noFollowUp          bool
+		sessionName         string
+		deadline            time.Duration
+		fastModelName       string
+		resumeSession       bool
+		speakAnswer         bool
+		hardenMode          string
+		untrustedContext    []string
+		toFormat            string
+		micMax              time.Duration
+		check               bool
+		questionsFile       string
+		snapshotDir         string
+		fromSnapshot        string
+		fieldsSpec          string
+		routeSpecs          []string
+		noExperiment        bool
+		sandboxTools        bool
+		noSandbox           bool
+		outputOpts          output.OutputOptions
 	)
 
 	cmd := &cobra.Command{
@@ -143,34 +365,458 @@ If arc-ai is not running, falls back to direct Pi execution.`,
   arc-ask "What's wrong?" --pane dev:1.0
 
   # With tools
-  cat errors.log | arc-ask "Analyze" --tools security,tmux`,
+  cat errors.log | arc-ask "Analyze" --tools security,tmux
+
+  # Using a template
+  git diff | arc-ask @code-review
+
+  # Annotate a pull request in GitHub Actions
+  git diff origin/main | arc-ask @code-review --output github-annotations
+
+  # As an editor filter (Vim/Neovim)
+  :%!arc-ask --filter @refactor`,
 		Args: cobra.MaximumNArgs(1),
-		RunE: func(cmd *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			timer := newPhaseTimer(timing)
+			// Runs that are traced use the trace ID, so the two can be matched
+			requestID := randomHex(8)
+			if timer != nil && timer.tracer != nil {
+				requestID = timer.tracer.traceID
+			}
+			defer func() {
+				attrs := map[string]string{}
+				if len(args) > 0 && ask.IsTemplateRef(args[0]) {
+					attrs["arc_ask.template"] = args[0]
+				}
+				if pane != "" {
+					attrs["arc_ask.pane"] = pane
+				}
+				timer.finish(attrs, err)
+			}()
+
 			if listTemplates {
 				return listTemplatesCmd(cmd.OutOrStdout())
 			}
+			if pickFromHistory {
+				if len(args) > 0 {
+					return coded(codeUsage, errors.NewCLIError("--pick-history takes no question"))
+				}
+				picked, err := pickHistory()
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "arc-ask %s\n", shellJoin(picked.Args))
+				// A new root resets every flag, so the ones given with
+				// --pick-history, such as --stateless, go after the picked
+				// ones to win
+				rerun := append(append([]string(nil), picked.Args...), flagArgs(cmd, map[string]bool{"pick-history": true})...)
+				root := NewRootCmd()
+				root.SetArgs(rerun)
+				root.SetIn(cmd.InOrStdin())
+				root.SetOut(cmd.OutOrStdout())
+				root.SetErr(cmd.ErrOrStderr())
+				return root.Execute()
+			}
 
-			if err := outputOpts.Resolve(); err != nil {
+			// Questions typed on the command line are remembered with their
+			// flags, as given before a template's defaults apply
+			var (
+				asked    []string
+				answered string // what the question led to, for its title
+			)
+			if len(args) > 0 && !mic {
+				asked = historyArgs(cmd, args[0])
+			}
+			defer func() {
+				if err != nil || asked == nil {
+					return
+				}
+				entry := historyEntry{Question: asked[0], Args: asked, Time: time.Now()}
+				titleHistory(client, &entry, answered)
+				if herr := recordHistory(entry); herr != nil {
+					fmt.Fprintf(os.Stderr, "Warning: could not record the question in history: %v\n", herr)
+				}
+			}()
+
+			suggestInit()
+
+			templateVars, err := parseVars(vars)
+			if err != nil {
 				return err
 			}
 
-			// Check daemon status
-			if !client.IsDaemonRunning() {
-				fmt.Fprintln(os.Stderr, "Note: arc-ai daemon not running. Using fallback mode.")
-				fmt.Fprintln(os.Stderr, "For better performance, run: arc-ai start")
+			// A session carries the conversation across invocations
+			sess, err := openSession(sessionName, resumeSession)
+			if err != nil {
+				return err
+			}
+
+			// A snapshot brings its input, question, and variables
+			var (
+				snapshot      *snapshotManifest
+				snapshotInput string
+			)
+			if fromSnapshot != "" {
+				if pane != "" || lastOutput > 0 || len(contextFiles) > 0 || len(untrustedContext) > 0 || snapshotDir != "" {
+					return errors.NewCLIError("--from-snapshot replaces --pane, --last-output, --context, --untrusted-context, and --snapshot-input")
+				}
+				if snapshotInput, snapshot, err = loadInputSnapshot(fromSnapshot); err != nil {
+					return err
+				}
+				if len(args) == 0 && snapshot.Question != "" {
+					args = []string{snapshot.Question}
+				}
+				for k, v := range snapshot.Vars {
+					if _, ok := templateVars[k]; !ok {
+						templateVars[k] = v
+					}
+				}
+			}
+
+			// A snapshot carries all of its context already
+			if !noPins && snapshot == nil {
+				applyPins(pane, &contextFiles, &logSpecs)
 			}
 
-			// Gather input
-			input, err := gatherInput(cmd, pane, lines)
+			modelFlag := cmd.Flags().Changed("model")
+			if len(args) > 0 {
+				if err := applyTemplateDefaults(cmd, args[0]); err != nil {
+					return err
+				}
+			}
+			if modelName != "" {
+				// Again, as a template's default model is only known now
+				client.useModel(modelName)
+			}
+			if len(args) > 0 && ask.IsTemplateRef(args[0]) {
+				if err := fitTemplateModel(client, args[0], modelFlag); err != nil {
+					return err
+				}
+			}
+			if thinkingSpec != "" {
+				if client.thinking, err = parseThinking(thinkingSpec); err != nil {
+					return err
+				}
+			}
+			if err := ask.ValidateExtract(extract); err != nil {
+				return errors.NewCLIError(err.Error())
+			}
+			if cmd.Flags().Changed("temperature") {
+				client.temperature = &temperature
+			}
+
+			if filter {
+				input, err := gatherInput(cmd, "", 0, captureFilter{})
+				if err != nil {
+					return err
+				}
+				arg := ""
+				if len(args) > 0 {
+					arg = args[0]
+				}
+				ctx, cancel := context.WithTimeout(context.Background(), client.timeout)
+				defer cancel()
+				return runFilter(ctx, client, cmd.OutOrStdout(), input, arg, templateVars)
+			}
+
+			if sandboxTools && noSandbox {
+				return errors.NewCLIError("--sandbox and --no-sandbox cannot be combined")
+			}
+			if err := validateHardenMode(hardenMode); err != nil {
+				return err
+			}
+			if err := validatePostFormat(toFormat); err != nil {
+				return err
+			}
+			if err := validateScanMode(scanMode); err != nil {
+				return err
+			}
+
+			var models []string
+			if consensusSpec != "" {
+				if models, err = parseConsensusModels(consensusSpec); err != nil {
+					return err
+				}
+			} else if check {
+				return errors.NewCLIError("--check requires --consensus")
+			}
+			if useCache && noCache {
+				return coded(codeUsage, errors.NewCLIError("--cache and --no-cache cannot be combined"))
+			}
+			if deadline < 0 {
+				return coded(codeUsage, errors.NewCLIError("--deadline cannot be negative"))
+			}
+			if deadline > 0 && (len(models) > 0 || len(tools) > 0) {
+				return coded(codeUsage, errors.NewCLIError("--deadline cannot be combined with --consensus or --tools"))
+			}
+
+			reportFormat := requestedReportFormat(cmd)
+			if reportFormat == outputLocations {
+				// Locations are cited by the numbers shown
+				lineNumbers = true
+			}
+			fields, err := parseFields(fieldsSpec)
+			if err != nil {
+				return err
+			}
+			if len(fields) > 0 && (!outputOpts.Is(output.OutputJSON) || reportFormat != "") {
+				return errors.NewCLIError("--fields selects fields of --output json").
+					WithSuggestions("Add --output json; text output is only the answer")
+			}
+			if toFormat != "" && (reportFormat != "" || byOwner || extract == ask.ExtractModeCode) {
+				return errors.NewCLIError("--to cannot be combined with report --output formats, --by-owner, or --extract code")
+			}
+			if reportFormat == "" {
+				if err := outputOpts.Resolve(); err != nil {
+					return err
+				}
+			}
+
+			capture, err := parseCaptureFilter(captureSpec)
+			if err != nil {
+				return err
+			}
+
+			// Gather input; stdin is never timed out since it may be a slow producer
+			if sinceLast && pane == "" {
+				return errors.NewCLIError("--since-last requires --pane")
+			}
+			if sinceLast {
+				if err := refuseStateless("--since-last"); err != nil {
+					return err
+				}
+			}
+
+			sample, err := parseSample(sampleMode, sampleSize)
+			if err != nil {
+				return err
+			}
+			if sample.mode != "" && (pane != "" || snapshot != nil) {
+				return errors.NewCLIError("--sample applies to stdin").
+					WithSuggestions("For a pane, use --lines and --capture-filter")
+			}
+
+			inputTimeout := captureTimeout
+			if pane == "" {
+				inputTimeout = 0
+			}
+			var mark *paneMark
+			input, err := withPhaseTimeout("pane capture", "--capture-timeout", inputTimeout, func() (string, error) {
+				if snapshot != nil {
+					return snapshotInput, nil
+				}
+				if sinceLast {
+					text, m, err := capturePaneSince(pane, lines, capture)
+					mark = m
+					return text, err
+				}
+				if sample.mode != "" {
+					return sampleStdin(sample)
+				}
+				return gatherInput(cmd, pane, lines, capture)
+			})
+			if err != nil {
+				return err
+			}
+			if sinceLast && input == "" {
+				fmt.Fprintf(os.Stderr, "No new output in %s since the last check.\n", pane)
+				return nil
+			}
+			lineExcludes, err := compileExcludeLines(excludeLinePatterns)
 			if err != nil {
 				return err
 			}
+			if text, n := excludeLines(input, lineExcludes); n > 0 {
+				input = text
+				fmt.Fprintf(os.Stderr, "Excluded %d input lines matching --exclude-lines\n", n)
+			}
+			norm := timestampNorm{zone: time.Local}
+			if timestampSpec != "" {
+				if norm, err = parseTimestampNorm(timestampSpec); err != nil {
+					return err
+				}
+			}
+			if len(logSpecs) > 0 || mergeByTime {
+				logs, err := withPhaseTimeout("reading logs", "--capture-timeout", captureTimeout, func() ([]logStream, error) {
+					var logs []logStream
+					for _, spec := range logSpecs {
+						src, err := parseLogSource(spec)
+						if err != nil {
+							return nil, err
+						}
+						text, err := src.read(lines, capture)
+						if err != nil {
+							return nil, err
+						}
+						logs = append(logs, logStream{src.label(), text})
+					}
+					return logs, nil
+				})
+				if err != nil {
+					return err
+				}
+				if mergeByTime {
+					if input != "" {
+						label := "stdin"
+						switch {
+						case snapshot != nil:
+							label = "snapshot"
+						case pane != "":
+							label = "pane:" + pane
+						}
+						logs = append([]logStream{{label, input}}, logs...)
+					}
+					if len(logs) < 2 {
+						return coded(codeUsage, errors.NewCLIError("--merge-by-time needs at least two logs").
+							WithSuggestions("Add logs with --log FILE, --log pane:TARGET, or --log docker:CONTAINER"))
+					}
+					var timed int
+					input, timed = mergeLogsByTime(logs, norm)
+					fmt.Fprintf(os.Stderr, "Merged %d logs by time (%d timestamped entries)\n", len(logs), timed)
+				} else {
+					input = appendLogs(input, logs)
+				}
+			}
+			if timestampSpec != "" {
+				if text, n := norm.apply(input); n > 0 {
+					input = text
+					fmt.Fprintf(os.Stderr, "Normalized %d input timestamps (%s)\n", n, norm.mode)
+				}
+			}
+			timer.mark("input")
+			explain := &runExplanation{}
+			explain.addInput(pane, input, sinceLast, capture, lines)
+
+			if lastOutput > 0 {
+				outputs, err := lastShellOutputs(lastOutput)
+				if err != nil {
+					return err
+				}
+				if len(outputs) == 0 {
+					fmt.Fprintln(os.Stderr, "Warning: no finished commands recorded in this shell yet")
+				} else {
+					if input != "" {
+						input += "\n\n"
+					}
+					input += "Recent command output:\n\n" + formatShellOutputs(outputs)
+					explain.addShellOutputs(outputs)
+				}
+			}
+
+			// The review formats annotate the diff itself, without context files
+			diffInput := input
+
+			if envContext {
+				if input != "" {
+					input += "\n\n"
+				}
+				input += environmentContext()
+			}
 
 			// Merge context files
-			input, err = mergeContext(input, contextFiles)
+			if err := validateContextOrder(contextOrder); err != nil {
+				return err
+			}
+			weights, err := parseContextWeights(contextWeights)
+			if err != nil {
+				return err
+			}
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			trust, err := newTrustPolicy(cfg.Trust, hardenMode, untrustedContext)
 			if err != nil {
 				return err
 			}
+			guard := &sourceGuard{strip: hardenMode == hardenStrip}
+			inputName := ""
+			if trust.input && snapshot == nil {
+				switch {
+				case lastOutput > 0:
+					inputName = "input"
+				case pane != "":
+					inputName = "pane " + pane
+				default:
+					inputName = "stdin"
+				}
+			}
+			var ctxResult contextResult
+			input, err = withPhaseTimeout("reading context files", "--capture-timeout", captureTimeout, func() (string, error) {
+				merged, res, err := mergeContext(input, append(contextFiles, untrustedContext...), contextOptions{
+					budget:         contextBudget,
+					order:          contextOrder,
+					weights:        weights,
+					exclude:        compileExcludes(excludes),
+					numbers:        lineNumbers,
+					untrusted:      trust.untrusted,
+					inputUntrusted: inputName,
+					fence:          guard.fence,
+				})
+				ctxResult = res
+				return merged, err
+			})
+			if err != nil {
+				return err
+			}
+			explain.addContext(ctxResult, contextBudget)
+			if snapshot != nil {
+				// The snapshot's input is already fenced where it was untrusted
+				guard.sources = snapshot.Untrusted
+			}
+			explain.Untrusted, explain.Injection = guard.sources, guard.hits
+			for _, o := range ctxResult.Omitted {
+				fmt.Fprintf(os.Stderr, "Omitted context %s (~%d tokens): over --context-budget %d\n", o.Path, o.Tokens, contextBudget)
+			}
+			timer.mark("context")
+
+			if redactInput {
+				var hits []redactionHit
+				input, hits = redact(input)
+				if len(hits) > 0 {
+					fmt.Fprintf(os.Stderr, "Redacted from input: %s\n", describeHits(hits))
+				}
+			}
+
+			if questionsFile != "" {
+				if len(args) > 0 || mic || len(models) > 0 || toFormat != "" || reportFormat != "" || byOwner || confidence {
+					return errors.NewCLIError("--questions cannot be combined with a question argument, --mic, --consensus, --check, --to, --confidence, --by-owner, or report --output formats")
+				}
+				return runQuestionsFile(cmd, client, questionsFile, input, templateVars, guard, tools, confirmCostThreshold(cfg), yes, outputOpts.Is(output.OutputJSON))
+			}
+
+			if mic {
+				if len(args) > 0 {
+					return errors.NewCLIError("--mic replaces the question argument").
+						WithSuggestions("Drop the question and speak it instead")
+				}
+				question, err := recordQuestion(context.Background(), cfg.Transcription, micMax)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "Heard: %s\n", question)
+				args = []string{question}
+				timer.mark("transcription")
+			}
+
+			if snapshotDir != "" {
+				m := snapshotManifest{
+					Vars:       templateVars,
+					Provider:   client.provider,
+					Model:      client.model,
+					Sources:    explain.Sources,
+					Truncation: explain.Truncation,
+					Untrusted:  guard.sources,
+					Redacted:   redactInput,
+				}
+				if len(args) > 0 {
+					m.Question = args[0]
+				}
+				if err := saveInputSnapshot(snapshotDir, input, m); err != nil {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "Saved input snapshot to %s (re-run with --from-snapshot %s)\n", snapshotDir, snapshotDir)
+			}
 
 			// Validate prompt
 			if len(args) == 0 && input == "" {
@@ -182,117 +828,803 @@ If arc-ai is not running, falls back to direct Pi execution.`,
 					)
 			}
 
-			prompt := ""
+			arg := ""
 			if len(args) > 0 {
-				prompt = args[0]
+				arg = args[0]
 			}
 
 			// Build full prompt
-			if input != "" {
-				prompt = fmt.Sprintf("%s\n\nInput:\n%s", prompt, input)
+			system, user, err := buildPrompt(arg, input, templateVars)
+			if err != nil {
+				return err
+			}
+			if len(guard.sources) > 0 {
+				if system == "" {
+					system = untrustedInstructions
+				} else {
+					system = untrustedInstructions + "\n\n" + system
+				}
+				if len(tools) > 0 {
+					fmt.Fprintf(os.Stderr, "Tools disabled: the prompt includes untrusted content (%s)\n", strings.Join(guard.sources, ", "))
+					tools = nil
+				}
+			}
+			for _, w := range preflight(arg, input, system, user, templateVars) {
+				fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+			}
+
+			if (reportFormat == outputReview || reportFormat == outputReviewJSON) && !isDiff(diffInput) {
+				fmt.Fprintln(os.Stderr, "Warning: input is not a unified diff; review comments are listed without it")
+			}
+
+			// Ownership info for diff reviews
+			var owners *Codeowners
+			if isDiff(input) || byOwner {
+				owners = repoCodeowners()
+			}
+			if isDiff(input) {
+				if info := ownershipContext(owners, diffFiles(input)); info != "" {
+					user += "\n\n" + info
+				}
+			}
+
+			if lineNumbers && reportFormat != outputLocations {
+				user += "\n\n" + citationInstructions
+			}
+			switch {
+			case reportFormat != "":
+				user += "\n\n" + reportFormats[reportFormat].instructions
+			case toFormat != "":
+				user += "\n\n" + postFormats[toFormat].instructions
+			case byOwner:
+				user += "\n\n" + findingInstructions
+			}
+			if check {
+				user += "\n\n" + ask.VerdictInstructions
+			}
+			contract := templateContract(arg)
+			if contract != nil {
+				user += "\n\n" + contract.Output.Instructions()
+			}
+			outputs := templateOutputs(arg)
+			routes, err := parseRoutes(routeSpecs, outputs)
+			if err != nil {
+				return err
+			}
+			if outputs != nil {
+				if reportFormat != "" || toFormat != "" || byOwner || extract == ask.ExtractModeCode {
+					return errors.NewCLIError(fmt.Sprintf("@%s declares outputs, which cannot be combined with --to, --by-owner, --extract code, or report --output formats", outputs.Name))
+				}
+				user += "\n\n" + outputs.OutputsInstructions()
+			}
+			if withActions {
+				user += "\n\n" + actionsInstructions
+			}
+			if confidence {
+				user += "\n\n" + confidenceInstructions
+			}
+			body := user
+			if sess != nil {
+				budget := newSessionBudget(client, cfg.Chat, sessionBudget{})
+				if !budget.fits(sess, user) {
+					ctx, cancel := context.WithTimeout(context.Background(), client.timeout)
+					n, err := budget.compact(ctx, client, sess)
+					cancel()
+					if err != nil {
+						return errors.NewCLIError(fmt.Sprintf("session %s is over its token budget", sess.ID)).WithCause(err)
+					}
+					if n > 0 {
+						fmt.Fprintf(os.Stderr, "Summarized %d earlier turns of session %s to stay within %s tokens\n", n, sess.ID, formatTokens(budget.tokens))
+					}
+				}
+				body = sess.Prompt(user)
+			}
+			// The prompt as cached, estimated, and shown; the one sent is
+			// shaped by prompt_roles once the client is final
+			prompt := client.JoinPrompt(system, body)
+			var experiment *experimentArm
+			if !noExperiment && len(models) == 0 && deadline == 0 && client.fixtures == nil && experimentFits(cfg, client, arg) {
+				experiment = assignExperiment(cfg, client)
+			}
+			explain.setTemplate(arg)
+			explain.setRouting(client, models, tools)
+			if experiment != nil {
+				explain.Routing.Reason = fmt.Sprintf("experiment %s: %s arm (%g%% of requests go to %s)",
+					experiment.Name, experiment.Arm, cfg.Experiment.Percent, cfg.Experiment.Model)
+			}
+			explain.PromptTokens = ask.EstimateTokens(prompt)
+			for _, model := range append([]string{client.model}, models...) {
+				model, _ = resolveModel(model)
+				if err := checkContextWindow(client.provider, model, explain.PromptTokens, client.maxTokens); err != nil {
+					return err
+				}
+			}
+
+			timer.mark("prompt")
+
+			// With --cache, plain questions are answered from the response
+			// cache when possible
+			var (
+				responses = newResponseCache()
+				cacheKey  string
+				answer    string
+				cached    bool
+			)
+			if (useCache || cfg.Cache.Responses) && !noCache && client.fixtures == nil && len(models) == 0 && len(tools) == 0 && templateCacheable(arg) {
+				cacheKey = responseKey(client, prompt)
+				if answer, cached = responses.get(cacheKey); cached {
+					fmt.Fprintln(os.Stderr, "Using cached answer (--no-cache to ask again)")
+				}
+			}
+
+			if !cached {
+				var estimates []costEstimate
+				for _, model := range append([]string{client.model}, models...) {
+					model, _ = resolveModel(model)
+					if e, ok := estimateCost(client.provider, model, explain.PromptTokens, client.maxTokens); ok {
+						estimates = append(estimates, e)
+					}
+				}
+				if preview {
+					// Confirming the preview, which shows the cost, confirms the cost too
+					if err := previewRequest(client, explain.Sources, prompt, estimates); err != nil {
+						return err
+					}
+				} else if err := confirmCost(estimates, confirmCostThreshold(cfg), yes); err != nil {
+					return err
+				}
+			}
+
+			// Check daemon status only once a query is certain
+			if !cached && client.server == "" && !client.IsDaemonRunning() {
+				fmt.Fprintln(os.Stderr, "Note: arc-ai daemon not running. Using fallback mode.")
+				fmt.Fprintln(os.Stderr, "For better performance, run: arc-ai start")
+			}
+
+			// Tool writes land in a copy of the workspace, applied on confirmation
+			var sb *sandbox
+			if (sandboxTools || len(tools) > 0 && !noSandbox) && !cached {
+				if sb, err = newSandbox(); err != nil {
+					return coded(codeToolFailed, errors.NewCLIError("cannot create the tool sandbox").WithCause(err).
+						WithSuggestions("Run without it: --no-sandbox"))
+				}
+				defer sb.remove()
+				client.dir = sb.dir
 			}
 
 			// Query AI
-			ctx, cancel := context.WithTimeout(context.Background(), client.timeout)
+			asker, sent := client.shapePrompt(system, body)
+			ctx, cancel := interruptibleContext(client.timeout)
 			defer cancel()
+			generationStart := time.Now()
 
-			var answer string
-			if len(tools) > 0 {
-				answer, err = client.AskWithTools(ctx, prompt, tools)
-			} else {
-				answer, err = client.Ask(ctx, prompt)
+			var (
+				consensus *consensusResult
+				race      *deadlineRace
+			)
+			switch {
+			case cached:
+			case deadline > 0:
+				answer, race, err = raceDeadline(ctx, asker, fastModel(asker, fastModelName), sent, deadline)
+				if race != nil {
+					noteDeadlineWinner(race)
+				}
+			case len(models) > 0:
+				consensus, err = runConsensus(ctx, asker, models, sent, check)
+				if err == nil {
+					answer = consensus.Judgment
+				}
+			case len(tools) > 0:
+				answer, err = asker.AskWithTools(ctx, sent, tools)
+			default:
+				answer, err = asker.Ask(ctx, sent)
 			}
 
+			partial, isPartial := err.(*partialAnswerError)
+			if isPartial {
+				// Show what arrived; the error is reported after the output
+				answer, err = partial.Partial, nil
+				saveErr := saveInterrupted(interruptedRequest{
+					Prompt:  prompt,
+					Partial: partial.Partial,
+					Reason:  partial.Cause.Error(),
+					Time:    time.Now(),
+				})
+				if saveErr != nil {
+					fmt.Fprintf(os.Stderr, "Warning: could not save interrupted answer: %v\n", saveErr)
+				}
+			}
 			if err != nil {
 				return errors.NewCLIError("AI query failed").WithCause(err)
 			}
+			if !noRetry && !cached && consensus == nil && race == nil && !isPartial {
+				var note string
+				if answer, note = retryDegenerate(ctx, asker, tools, sent, answer); note != "" {
+					fmt.Fprintln(os.Stderr, note)
+					explain.Retries++
+					explain.RetryReasons = append(explain.RetryReasons, note)
+				}
+			}
+			// Answers that ran tools depend on the state they saw, so are not reused
+			if cacheKey != "" && !cached && !isPartial && len(client.stats.toolCalls()) == 0 {
+				name := ""
+				if ask.IsTemplateRef(arg) {
+					name = strings.TrimPrefix(arg, "@")
+				}
+				responses.put(cacheKey, name, answer)
+			}
+			latency := time.Since(generationStart)
+			timer.mark("generation")
+
+			if mark != nil {
+				if err := mark.save(); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: could not save pane position: %v\n", err)
+				}
+			}
+
+			var conf *Confidence
+			if confidence {
+				body, c, ok := parseConfidence(answer)
+				if ok {
+					answer, conf = body, &c
+				} else {
+					fmt.Fprintln(os.Stderr, "Warning: answer had no CONFIDENCE trailer")
+				}
+			}
+			var actions []Action
+			if withActions && !isPartial {
+				body, a, ok := parseActions(answer)
+				if ok {
+					answer, actions = body, a
+				} else {
+					fmt.Fprintln(os.Stderr, "Warning: answer had no actions block")
+				}
+			}
+
+			if contract != nil && !isPartial {
+				var retried bool
+				answer, retried, err = ask.EnforceContract(ctx, asker, contract, sent, answer)
+				if retried {
+					note := fmt.Sprintf("Answer broke @%s output contract; retried once", contract.Name)
+					fmt.Fprintln(os.Stderr, note)
+					explain.Retries++
+					explain.RetryReasons = append(explain.RetryReasons, note)
+				}
+				if err != nil {
+					return err
+				}
+			}
+
+			if outputs != nil && !isPartial {
+				var retried bool
+				answer, retried, err = enforceOutputs(ctx, asker, tools, outputs, sent, answer)
+				if retried {
+					note := fmt.Sprintf("Answer was missing @%s outputs; retried once", outputs.Name)
+					fmt.Fprintln(os.Stderr, note)
+					explain.Retries++
+					explain.RetryReasons = append(explain.RetryReasons, note)
+				}
+				if err != nil {
+					return err
+				}
+			}
+
+			if extract == ask.ExtractModeCode && !isPartial {
+				answer = ask.ExtractCode(answer)
+			}
+
+			if toFormat != "" && !isPartial {
+				var retried bool
+				answer, retried, err = applyPostFormat(ctx, asker, toFormat, sent, answer)
+				if retried {
+					note := fmt.Sprintf("Answer was not valid %s; retried once", toFormat)
+					fmt.Fprintln(os.Stderr, note)
+					explain.Retries++
+					explain.RetryReasons = append(explain.RetryReasons, note)
+				}
+				if err != nil {
+					return err
+				}
+			}
+
+			answer, hits := scanOutput(scanMode, answer, func(msg string) {
+				fmt.Fprintln(os.Stderr, msg)
+			})
+			answered = answer
+
+			var sandboxed *sandboxResult
+			if sb != nil {
+				if sandboxed, err = sb.review(requestID); err != nil {
+					return err
+				}
+			}
+
+			result := askResult{
+				Response:   answer,
+				Provider:   client.provider,
+				Model:      client.model,
+				RequestID:  requestID,
+				Retries:    explain.Retries,
+				Cached:     cached,
+				Redactions: hits,
+				Confidence: conf,
+				Actions:    actions,
+				Consensus:  consensus,
+				Untrusted:  guard.sources,
+				Experiment: experiment,
+				Sandbox:    sandboxed,
+				Deadline:   race,
+			}
+			if race != nil {
+				result.Model = race.Model
+			}
+			if !cached {
+				result.Latency = latency.Round(time.Millisecond).Seconds()
+				result.Usage, result.FinishReason = client.stats.snapshot()
+				result.Thinking = client.stats.thinkingText()
+				result.Tools = client.stats.toolCalls()
+			}
+			if isPartial {
+				result.FinishReason = "incomplete"
+			}
+			if lineNumbers {
+				result.Citations = parseCitations(answer)
+			}
+			if explainRun {
+				switch {
+				case cached:
+					explain.setCache("hit")
+				case cacheKey != "":
+					explain.setCache("miss")
+				default:
+					explain.setCache("off")
+				}
+				result.Run = explain
+				if !outputOpts.Is(output.OutputJSON) || reportFormat != "" {
+					enc := json.NewEncoder(os.Stderr)
+					enc.SetIndent("", "  ")
+					_ = enc.Encode(explain)
+				}
+			}
+			if experiment != nil && !cached {
+				if err := appendUsage(newUsageRecord(experiment, arg, result)); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: could not log experiment usage: %v\n", err)
+				}
+			}
+			if byOwner {
+				result.ByOwner = groupFindingsByOwner(owners, parseFindings(answer))
+			}
+			if outputs != nil && !isPartial {
+				if result.Outputs, err = outputs.SplitOutputs(answer); err != nil {
+					return errors.NewCLIError("cannot split the answer into outputs").WithCause(err)
+				}
+				router := &outputRouter{
+					template: outputs,
+					routes:   routes,
+					stdout:   cmd.OutOrStdout(),
+					notesDir: cfg.NotesDir,
+					note:     outputsNote(arg, input, client),
+				}
+				if err := router.route(result.Outputs, !outputOpts.Is(output.OutputTable)); err != nil {
+					return err
+				}
+			}
 
 			// Output
+			offerFollowUp := false
 			switch {
+			case reportFormat != "":
+				name := "arc-ask"
+				if ask.IsTemplateRef(arg) {
+					name = arg
+				}
+				r := newReport(name, answer)
+				r.Diff = diffInput
+				if err := reportFormats[reportFormat].write(cmd.OutOrStdout(), r); err != nil {
+					return err
+				}
 			case outputOpts.Is(output.OutputJSON):
-				fmt.Printf(`{"response": %q}%s`, answer, "\n")
+				if err := writeResultFields(cmd.OutOrStdout(), result, fields); err != nil {
+					return err
+				}
 			case outputOpts.Is(output.OutputQuiet):
 				// No output
+			case result.Outputs != nil:
+				// Routed above
+			case len(result.ByOwner) > 0:
+				writeFindingsByOwner(cmd.OutOrStdout(), result.ByOwner)
+			case toFormat != "":
+				fmt.Print(answer)
 			default:
+				if showThinking {
+					writeThinking(os.Stderr, result.Thinking)
+				}
 				fmt.Println(answer)
+				writeActions(cmd.OutOrStdout(), actions)
+				if conf != nil {
+					writeConfidenceFooter(cmd.OutOrStdout(), *conf)
+				}
+				offerFollowUp = !noFollowUp && !isPartial && stdoutIsTerminal()
+			}
+			timer.mark("output")
+			if len(guard.sources) > 0 && !outputOpts.Is(output.OutputJSON) {
+				fmt.Fprintf(os.Stderr, "Note: this answer draws on untrusted sources (%s); verify before acting on it\n", strings.Join(guard.sources, ", "))
+			}
+
+			if speakAnswer && !isPartial {
+				if err := speak(context.Background(), cfg.Speech, answer); err != nil {
+					return err
+				}
+			}
+
+			if sess != nil && !isPartial {
+				sess.Append(RoleUser, user)
+				sess.Append(RoleAssistant, answer)
+				titleSession(client, sess)
+				if err := NewSessionStore().Save(sess); err != nil {
+					return errors.NewCLIError("failed to save the session").WithCause(err)
+				}
+			}
+
+			if saveNoteTo != "" && !isPartial {
+				n := note{Question: arg, Input: input, Answer: answer, Provider: client.provider, Model: client.model, Time: time.Now()}
+				if ask.IsTemplateRef(arg) {
+					n.Template = strings.TrimPrefix(arg, "@")
+				}
+				where, err := saveNote(saveNoteTo, cfg.NotesDir, n)
+				if err != nil {
+					return errors.NewCLIError("failed to save note").WithCause(err)
+				}
+				fmt.Fprintf(os.Stderr, "Saved note to %s\n", where)
+			}
+
+			if offerFollowUp {
+				saved := sess != nil
+				if !saved {
+					if sess, err = newSession(""); err != nil {
+						return err
+					}
+					sess.Append(RoleUser, prompt)
+					sess.Append(RoleAssistant, answer)
+				}
+				if err := followUp(client, os.Stdout, sess, saved); err != nil {
+					return err
+				}
 			}
 
+			if isPartial {
+				return coded(codeIncompleteAnswer, errors.NewCLIError("answer is incomplete").
+					WithCause(partial.Cause).
+					WithSuggestions("Continue with: arc-ask resume", "Raise the limit with --total-timeout"))
+			}
+			if consensus != nil && consensus.Agree != nil && !*consensus.Agree {
+				return coded(codeCheckFailed, errors.NewCLIError("models disagree: "+consensus.disagreement()))
+			}
 			return nil
 		},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			beginStats(cmd, args, client)
+			if err := useErrorsFlag(cmd); err != nil {
+				return err
+			}
+			cfg, err := loadConfig()
+			if err != nil {
+				return coded(codeConfigInvalid, err)
+			}
+			if err := cfg.ModelMigration.check(); err != nil {
+				return coded(codeConfigInvalid, err)
+			}
+			if err := cfg.Ollama.check(); err != nil {
+				return coded(codeConfigInvalid, err)
+			}
+			if err := checkPromptRoles(cfg.PromptRoles); err != nil {
+				return coded(codeConfigInvalid, err)
+			}
+			if err := useEgress(cfg.Egress); err != nil {
+				return coded(codeConfigInvalid, err)
+			}
+			profile, err := resolveEnvProfile(cfg.Privacy.EnvProfile, envProfile)
+			if err != nil {
+				return err
+			}
+			useEnvProfile(profile)
+			cfg.apply(client)
+			if err := applyDirEnv(cfg, client); err != nil {
+				return err
+			}
+			if modelName != "" {
+				client.useModel(modelName)
+			}
+			if thinkingSpec != "" {
+				if client.thinking, err = parseThinking(thinkingSpec); err != nil {
+					return err
+				}
+			}
+			if maxContinuations < 1 {
+				return errors.NewCLIError("--max-continuations must be at least 1")
+			}
+			if autoContinue {
+				client.continuations = maxContinuations
+			}
+			return client.useFixtures(recordFixtures, replayFixtures)
+		},
 		SilenceUsage:  true,
 		SilenceErrors: true,
 	}
+	cmd.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return coded(codeUsage, err)
+	})
 
 	cmd.Flags().StringVar(&pane, "pane", "", "Capture from tmux pane (e.g., session:0.0)")
 	cmd.Flags().IntVar(&lines, "lines", 200, "Lines to capture from pane")
+	cmd.Flags().DurationVar(&captureTimeout, "capture-timeout", defaultCaptureTimeout, "Limit for pane capture and reading context files (0 = none)")
+	cmd.PersistentFlags().StringVar(&errorsFormat, "errors", "", "How failures are reported: text on stderr, or json on stdout with stable codes and exit codes (default $ARC_ASK_ERRORS, else text)")
+	cmd.PersistentFlags().StringVar(&configDirFlag, "config-dir", "", "Directory for ask.yaml, templates, and recipes (default $XDG_CONFIG_HOME/arc, else ~/.config/arc)")
+	cmd.PersistentFlags().StringVar(&stateDirFlag, "state-dir", "", "Directory for history, sessions, caches, and other state (default $XDG_STATE_HOME/arc/ask, else ~/.local/state/arc/ask)")
+	cmd.PersistentFlags().BoolVar(&stateless, "stateless", statelessDefault(), "Write nothing to disk and read the config from $ARC_ASK_CONFIG and $ARC_ASK_* instead of ask.yaml (default $ARC_ASK_STATELESS)")
+	cmd.PersistentFlags().BoolVar(&noTitle, "no-title", false, "Save sessions and history without a title and tags, skipping the title model")
+	cmd.PersistentFlags().DurationVar(&client.connectTimeout, "connect-timeout", defaultConnectTimeout, "Limit for the provider's first response (0 = none)")
+	cmd.PersistentFlags().StringVar(&modelName, "model", "", "Model ID or alias from model_aliases (default from ask.yaml or pi)")
+	cmd.PersistentFlags().StringVar(&thinkingSpec, "thinking", "", "Reasoning for thinking models: off, minimal, low, medium, high, or a token budget")
+	cmd.Flags().BoolVar(&showThinking, "show-thinking", false, "Print the model's reasoning trace to stderr before the answer")
+	cmd.Flags().BoolVar(&preview, "preview", false, "Show the sources, sizes, and each block of the prompt in a pager, and confirm before sending")
+	cmd.Flags().BoolVar(&envContext, "env-context", false, "Add the OS, shell, terminal, directory, and git branch to the input")
+	cmd.PersistentFlags().StringVar(&envProfile, "env-profile", "", "Machine metadata allowed in --env-context and tool traces: strict, standard, off (default from ask.yaml, else standard)")
+	cmd.PersistentFlags().BoolVar(&client.waitForLimit, "wait", false, "Wait when the configured rate limit is reached instead of failing")
+	cmd.PersistentFlags().StringVar(&recordFixtures, "record-fixtures", "", "Record provider requests and answers (sanitized) into `DIR`")
+	cmd.PersistentFlags().StringVar(&replayFixtures, "replay-fixtures", "", "Answer from fixtures in `DIR` instead of calling the provider")
+	cmd.PersistentFlags().BoolVar(&autoContinue, "auto-continue", false, "When an answer hits the output limit, ask the model to continue and stitch the parts")
+	cmd.PersistentFlags().IntVar(&maxContinuations, "max-continuations", defaultMaxContinuations, "Continuation requests allowed per answer with --auto-continue")
+	cmd.PersistentFlags().DurationVar(&client.timeout, "total-timeout", defaultTotalTimeout, "Limit for the whole generation; partial output is shown")
+	cmd.Flags().IntVar(&lastOutput, "last-output", 0, "Include the output of the last N shell commands (needs arc-ask shell-init; --last-output=N)")
+	cmd.Flags().Lookup("last-output").NoOptDefVal = "1"
+	cmd.Flags().StringVar(&captureSpec, "capture-filter", captureSmart, "Pane line selection: smart, tail, errors (tune with ,keep=RE,drop=RE)")
+	cmd.Flags().BoolVar(&sinceLast, "since-last", false, "With --pane, only send output new since the previous --since-last run")
 	cmd.Flags().StringArrayVarP(&contextFiles, "context", "c", nil, "Add context file(s)")
-	cmd.Flags().StringSliceVar(&tools, "tools", nil, "Enable tools (security,tmux,deps)")
+	cmd.Flags().IntVar(&contextBudget, "context-budget", 0, "Max tokens for input plus context (0 = unlimited)")
+	cmd.Flags().StringVar(&contextOrder, "context-order", orderExplicit, "Context packing priority: explicit, smallest, weight")
+	cmd.Flags().StringArrayVar(&untrustedContext, "context-untrusted", nil, "Add context file(s), directories, or URLs treated as untrusted data")
+	cmd.Flags().StringArrayVar(&contextWeights, "context-weight", nil, "Context priority for --context-order weight (path=N)")
+	cmd.Flags().StringArrayVar(&excludes, "exclude", nil, "Skip matching paths inside context directories (glob, e.g. 'vendor/**')")
+	cmd.Flags().StringArrayVar(&excludeLinePatterns, "exclude-lines", nil, "Drop pane/stdin lines matching a regular expression")
+	cmd.Flags().BoolVar(&lineNumbers, "line-numbers", false, "Number the lines of source context files and have the answer cite file:line (in JSON output as citations)")
+	cmd.Flags().BoolVar(&noPins, "no-pins", false, "Leave out the context pinned with arc-ask pin")
+	cmd.Flags().StringArrayVar(&logSpecs, "log", nil, "Add a log: a file, pane:TARGET, or docker:CONTAINER (its last --lines)")
+	cmd.Flags().BoolVar(&mergeByTime, "merge-by-time", false, "Interleave stdin or --pane and every --log chronologically, with source labels")
+	cmd.Flags().StringVar(&timestampSpec, "timestamps", "", "Rewrite pane/stdin timestamps in mixed formats and zones: utc, local, relative (tune with ,tz=ZONE,order=dmy)")
+	cmd.Flags().StringVar(&sampleMode, "sample", "", "Send a sample of large stdin with its line count and most repeated lines: head, tail, random, stratified")
+	cmd.Flags().IntVar(&sampleSize, "sample-size", defaultSampleSize, "Lines in a --sample")
+	cmd.Flags().StringSliceVar(&tools, "tools", nil, "Enable tools; passed to pi's --tools (e.g. read,bash,edit)")
+	cmd.Flags().BoolVar(&sandboxTools, "sandbox", false, "Run in a copy of the workspace and apply its changes on confirmation (default with --tools)")
+	cmd.Flags().BoolVar(&noSandbox, "no-sandbox", false, "Let --tools write to the workspace directly")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Template variable (key=value)")
+	_ = cmd.RegisterFlagCompletionFunc("var", completeVars)
 	cmd.Flags().BoolVar(&listTemplates, "list-templates", false, "List available templates")
+	cmd.Flags().BoolVar(&pickFromHistory, "pick-history", false, "Pick a past question, with its flags, to ask again")
+	cmd.ValidArgsFunction = completeQuestion
+	cmd.Flags().BoolVar(&filter, "filter", false, "Editor filter mode: code on stdin, only code on stdout")
+	cmd.Flags().BoolVar(&byOwner, "by-owner", false, "Group findings by CODEOWNERS team")
+	cmd.Flags().BoolVar(&redactInput, "redact", false, "Redact secrets and PII from input before sending")
+	cmd.Flags().BoolVar(&withActions, "actions", false, "Ask for recommended next actions (command, description, risk), numbered below the answer and under actions in JSON output")
+	cmd.Flags().BoolVar(&confidence, "confidence", false, "Ask for a confidence score and assumptions (in JSON output and as a footer)")
+	cmd.Flags().StringVar(&consensusSpec, "consensus", "", "Ask two models independently and reconcile (modelA,modelB)")
+	cmd.Flags().BoolVar(&check, "check", false, "With --consensus, exit non-zero if the models' verdicts disagree")
+	cmd.Flags().StringVar(&hardenMode, "harden", hardenOff, "Treat every source as untrusted: off, warn (fence, warn on injection phrases), strip (also remove them)")
+	cmd.Flags().Lookup("harden").NoOptDefVal = hardenWarn
+	cmd.Flags().StringVar(&scanMode, "scan-output", scanOff, "Scan the answer for secrets/PII: off, warn, redact")
+	cmd.Flags().IntVar(&client.maxTokens, "max-tokens", 0, "Cap the answer length in tokens (0 = provider default)")
+	cmd.Flags().Float64Var(&temperature, "temperature", 0, "Sampling temperature (default: provider's)")
+	cmd.Flags().StringVar(&toFormat, "to", "", "Produce a validated file format: "+strings.Join(postFormatNames(), ", "))
+	cmd.Flags().StringVar(&extract, "extract", ask.ExtractModeNone, "Post-process the answer: none, code (first fenced block)")
+	cmd.Flags().StringVar(&saveNoteTo, "save-note", "", "Save the question and answer as a markdown note in `FOLDER` (or apple-notes:Folder on macOS)")
+	cmd.Flags().DurationVar(&deadline, "deadline", 0, "Race a fast model against the default and take whichever answers first within `DURATION` (e.g. 15s)")
+	cmd.Flags().StringVar(&fastModelName, "fast-model", "", "Model raced under --deadline (default: the fast alias, else the provider's cheapest small model)")
+	cmd.Flags().StringVar(&sessionName, "session", "", "Continue the saved session `NAME`, creating it if new, and save this answer to it")
+	cmd.Flags().BoolVar(&resumeSession, "resume", false, "Continue the most recently updated session")
+	_ = cmd.RegisterFlagCompletionFunc("session", completeSessionIDs)
+	cmd.Flags().BoolVar(&noFollowUp, "no-follow-up", false, "Skip the refine/shorter/expand/copy bar offered after an answer on a terminal")
+	cmd.Flags().BoolVar(&mic, "mic", false, "Speak the question: record from the microphone and transcribe it")
+	cmd.Flags().DurationVar(&micMax, "mic-max", defaultMicMax, "Longest --mic recording; Enter stops sooner")
+	cmd.Flags().BoolVar(&speakAnswer, "speak", false, "Read the answer aloud (prose only; code blocks are skipped)")
+	cmd.Flags().BoolVar(&useCache, "cache", false, "Reuse the answer to an identical earlier prompt, and keep this one for reuse (or cache: responses: true in ask.yaml)")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Always ask the model, skipping the response cache")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Send without confirming when the estimated cost is above confirm_cost")
+	cmd.Flags().StringVar(&snapshotDir, "snapshot-input", "", "Save the assembled input and a manifest to `DIR` for re-running later")
+	cmd.Flags().StringVar(&fromSnapshot, "from-snapshot", "", "Use the input saved in `DIR` by --snapshot-input instead of gathering it")
+	cmd.Flags().StringVar(&questionsFile, "questions", "", "Ask every question in `FILE` (one per line) about the same input, concurrently")
+	cmd.Flags().BoolVar(&noRetry, "no-retry", false, "Keep empty, refused, or truncated answers instead of retrying once")
+	cmd.Flags().BoolVar(&explainRun, "explain-run", false, "Report sources, truncation, template, routing, retries, and cache use (in JSON output, else on stderr)")
+	cmd.Flags().BoolVar(&timing, "timing", false, "Print per-phase timings to stderr")
+	cmd.Flags().BoolVar(&noExperiment, "no-experiment", false, "Keep this request out of the configured model experiment")
+	cmd.Flags().StringArrayVar(&routeSpecs, "route", nil, "Send a template's named output elsewhere: name=stdout|stderr|file:PATH|note:FOLDER")
+	cmd.Flags().StringVar(&fieldsSpec, "fields", "", "With --output json, only these comma-separated fields (e.g. response,model,usage,latency,finish_reason)")
 	outputOpts.AddOutputFlags(cmd, output.OutputTable)
 
+	cmd.AddCommand(
+		newHooksCmd(client),
+		newTicketCmd(client),
+		newReleaseNotesCmd(client),
+		newChatCmd(client),
+		newTUICmd(client),
+		newSessionsCmd(),
+		newHistoryCmd(),
+		newPinCmd(),
+		newRecipeCmd(),
+		newResumeCmd(client),
+		newInitCmd(),
+		newServeCmd(client),
+		newCacheCmd(),
+		newShellInitCmd(),
+		newModelsCmd(),
+		newQueueCmd(client),
+		newEvalCmd(client),
+		newTemplateCmd(client),
+		newSummarizeCmd(client),
+		newExplainErrorCmd(client),
+		newFindCmd(client),
+		newEnvCmd(),
+		newSweepCmd(client),
+		newExperimentCmd(),
+		newComposeCmd(),
+		newExitCodesCmd(),
+		newStatsCmd(),
+		newUsageCmd(),
+	)
+
 	return cmd
 }
 
-func gatherInput(cmd *cobra.Command, pane string, lines int) (string, error) {
+func gatherInput(cmd *cobra.Command, pane string, lines int, capture captureFilter) (string, error) {
 	if pane != "" {
-		if err := tmux.ValidateTarget(pane); err != nil {
-			return "", errors.NewCLIError("invalid pane target").
-				WithCause(err).
-				WithSuggestions("Format: session:window.pane (e.g., dev:0.0)")
-		}
-		content, err := tmux.Capture(pane, lines)
-		if err != nil {
-			return "", errors.NewCLIError("failed to capture pane").
-				WithCause(err).
-				WithSuggestions("Check that the pane exists: tmux list-panes")
-		}
-		return content, nil
-	}
-
-	// Check stdin
-	stat, _ := os.Stdin.Stat()
-	if (stat.Mode() & os.ModeCharDevice) == 0 {
-		data, err := io.ReadAll(os.Stdin)
+		content, err := capturePane(pane, capture.scrollback(lines))
 		if err != nil {
 			return "", err
 		}
-		return string(data), nil
+		return capture.apply(content, lines), nil
 	}
 
-	return "", nil
+	return ask.ReadStdin()
+}
+
+// capturePane validates a pane target and captures its last lines
+func capturePane(pane string, lines int) (string, error) {
+	if err := tmux.ValidateTarget(pane); err != nil {
+		return "", errors.NewCLIError("invalid pane target").
+			WithCause(err).
+			WithSuggestions("Format: session:window.pane (e.g., dev:0.0)")
+	}
+	content, err := ask.CapturePane(pane, lines)
+	if err != nil {
+		return "", coded(codeInputUnavailable, errors.NewCLIError("failed to capture pane").
+			WithCause(err).
+			WithSuggestions("Check that the pane exists: tmux list-panes"))
+	}
+	return content, nil
 }
 
-func mergeContext(input string, files []string) (string, error) {
+// mergeContext appends context files to the input, packed per opts.
+// Directories are expanded to the files under them. Files whose content
+// is already present are included once; files that do not fit the budget
+// are reported as omitted.
+func mergeContext(input string, files []string, opts contextOptions) (string, contextResult, error) {
+	var res contextResult
 	if len(files) == 0 {
-		return input, nil
+		return guardInput(input, opts), res, nil
 	}
 
+	files, err := expandContextDirs(files, opts.exclude, opts.weights)
+	if err != nil {
+		return "", res, err
+	}
+	read, err := readContextFiles(files, opts.weights)
+	if err != nil {
+		return "", res, err
+	}
+	read, res.Duplicates = dedupeContext(input, read)
+	if opts.numbers {
+		for i, f := range read {
+			if numbersLines(f.path) {
+				read[i].data = numberLines(f.data)
+				read[i].tokens = ask.EstimateTokens(string(read[i].data))
+			}
+		}
+	}
+	packed, omitted := packContext(read, ask.EstimateTokens(input), opts)
+	res.Omitted = omitted
+
 	var b strings.Builder
-	b.WriteString(input)
+	b.WriteString(guardInput(input, opts))
 
-	for _, f := range files {
-		data, err := os.ReadFile(f)
-		if err != nil {
-			return "", errors.NewCLIError("failed to read context file").
-				WithCause(err)
+	for _, d := range res.Duplicates {
+		same := "the input"
+		if d.SameAs != "" {
+			same = d.SameAs
 		}
+		fmt.Fprintf(os.Stderr, "Skipped duplicate context %s: same content as %s\n", d.Path, same)
+		_, _ = fmt.Fprintf(&b, "\n\nContext (%s): identical to %s, included once.", d.Path, same)
+	}
+
+	for _, f := range packed {
 		b.WriteString("\n\nContext (")
-		b.WriteString(f)
+		b.WriteString(f.path)
 		b.WriteString("):\n")
-		b.Write(data)
+		included := includedContext{Path: f.path, Bytes: len(f.data), Tokens: f.tokens}
+		if opts.untrusted != nil && opts.untrusted(f.path) {
+			b.WriteString(opts.fence(f.path, string(f.data)))
+			included.Untrusted = true
+		} else {
+			b.Write(f.data)
+		}
+		res.Included = append(res.Included, included)
+	}
+
+	return b.String(), res, nil
+}
+
+// guardInput fences the input when it is untrusted
+func guardInput(input string, opts contextOptions) string {
+	if opts.inputUntrusted == "" || input == "" {
+		return input
 	}
+	return opts.fence(opts.inputUntrusted, input)
+}
 
-	return b.String(), nil
+// templateContract returns the template named by arg if it declares an
+// output contract
+func templateContract(arg string) *ask.Template {
+	if !ask.IsTemplateRef(arg) {
+		return nil
+	}
+	t, err := loadTemplate(arg)
+	if err != nil || t.Output == nil {
+		return nil
+	}
+	return t
+}
+
+// templateCacheable reports whether answers for arg may be cached; templates
+// opt out with cache: false
+func templateCacheable(arg string) bool {
+	if !ask.IsTemplateRef(arg) {
+		return true
+	}
+	t, err := loadTemplate(arg)
+	return err != nil || t.Cache == nil || *t.Cache
 }
 
 func listTemplatesCmd(w io.Writer) error {
+	templates, err := userTemplates().List()
+	if err != nil {
+		return err
+	}
+
 	_, _ = fmt.Fprintln(w, "Available templates:")
 	_, _ = fmt.Fprintln(w)
-	_, _ = fmt.Fprintln(w, "  @code-review     Review code changes")
-	_, _ = fmt.Fprintln(w, "  @explain         Explain complex code")
-	_, _ = fmt.Fprintln(w, "  @summarize       Summarize text/logs")
-	_, _ = fmt.Fprintln(w, "  @security-check  Check for vulnerabilities")
+	for _, t := range templates {
+		_, _ = fmt.Fprintf(w, "  %-16s %s\n", "@"+t.Name, t.Description)
+		for _, v := range t.VariantNames() {
+			_, _ = fmt.Fprintln(w, strings.TrimRight(fmt.Sprintf("    %-14s %s", ":"+v, t.Variants[v].Description), " "))
+		}
+	}
 	_, _ = fmt.Fprintln(w)
-	_, _ = fmt.Fprintln(w, "Create templates in: ~/.config/arc/prompts/")
+	_, _ = fmt.Fprintln(w, "Create templates in: "+displayPath(ask.ExpandHome(templateDir()))+"/")
+	return nil
+}
+
+// useFixtures enables --record-fixtures or --replay-fixtures. Recorded
+// prompts and answers are redacted like --redact input.
+func (c *BridgeClient) useFixtures(record, replay string) error {
+	switch {
+	case record != "" && replay != "":
+		return errors.NewCLIError("--record-fixtures and --replay-fixtures cannot be combined")
+	case record != "":
+		c.fixtures = &fixture.Store{Dir: record, Sanitize: func(s string) string {
+			clean, _ := redact(s)
+			return clean
+		}}
+	case replay != "":
+		c.fixtures = &fixture.Store{Dir: replay}
+		c.replay = true
+	}
 	return nil
 }
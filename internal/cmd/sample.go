@@ -0,0 +1,238 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Sampling strategies for large stdin
+const (
+	sampleHead       = "head"       // the first lines
+	sampleTail       = "tail"       // the last lines
+	sampleRandom     = "random"     // a uniform random sample, in input order
+	sampleStratified = "stratified" // one random line from each equal slice of the input
+)
+
+const (
+	defaultSampleSize = 2000
+
+	// sampleSeed makes samples repeatable, so the same input gives the
+	// same prompt and can be answered from the cache
+	sampleSeed = 1
+
+	// sampleTopLines is how many of the most repeated lines are reported
+	sampleTopLines = 10
+
+	// sampleMaxDistinct caps the distinct lines counted for repeats;
+	// lines first seen after that are not counted
+	sampleMaxDistinct = 100000
+)
+
+// inputSample is --sample and --sample-size; the zero value reads stdin whole
+type inputSample struct {
+	mode string
+	size int
+}
+
+func parseSample(mode string, size int) (inputSample, error) {
+	switch mode {
+	case "", sampleHead, sampleTail, sampleRandom, sampleStratified:
+	default:
+		return inputSample{}, errors.NewCLIError(fmt.Sprintf("invalid --sample %q", mode)).
+			WithSuggestions("Use head, tail, random, or stratified")
+	}
+	if size < 1 {
+		return inputSample{}, errors.NewCLIError("invalid --sample-size: must be at least 1")
+	}
+	return inputSample{mode: mode, size: size}, nil
+}
+
+// sampledLine is a kept line and its 1-based number in the input
+type sampledLine struct {
+	n    int
+	text string
+}
+
+// sampleStats is what sampling learns about the whole input
+type sampleStats struct {
+	lines   int
+	bytes   int64
+	sampled int // lines kept
+	top     []repeatedLine
+}
+
+type repeatedLine struct {
+	text  string
+	count int
+}
+
+// readSample reads r to the end, keeping only the sample and the counts
+// behind sampleStats
+func readSample(r io.Reader, s inputSample) ([]sampledLine, sampleStats, error) {
+	var (
+		st     sampleStats
+		kept   []sampledLine
+		rng    = rand.New(rand.NewSource(sampleSeed))
+		counts = make(map[string]int)
+		first  = make(map[string]string) // normalized → first line as written
+
+		// stratified: strata of width lines each hold one line chosen
+		// uniformly from their slice
+		width   = 1
+		stratum = -1
+		seen    int // lines of the current stratum so far
+	)
+	br := bufio.NewReaderSize(r, 64*1024)
+	for {
+		line, err := br.ReadString('\n')
+		if line != "" {
+			st.lines++
+			st.bytes += int64(len(line))
+			text := strings.TrimRight(line, "\r\n")
+			n := st.lines
+
+			key := digitsPattern.ReplaceAllString(strings.TrimSpace(text), "N")
+			if _, ok := counts[key]; ok || len(counts) < sampleMaxDistinct {
+				counts[key]++
+				if _, ok := first[key]; !ok {
+					first[key] = text
+				}
+			}
+
+			switch s.mode {
+			case sampleHead:
+				if len(kept) < s.size {
+					kept = append(kept, sampledLine{n, text})
+				}
+			case sampleTail:
+				if len(kept) < s.size {
+					kept = append(kept, sampledLine{n, text})
+				} else {
+					kept[(n-1)%s.size] = sampledLine{n, text}
+				}
+			case sampleRandom:
+				// Reservoir sampling
+				if len(kept) < s.size {
+					kept = append(kept, sampledLine{n, text})
+				} else if j := rng.Intn(n); j < s.size {
+					kept[j] = sampledLine{n, text}
+				}
+			case sampleStratified:
+				if (n-1)/width != stratum {
+					stratum, seen = (n-1)/width, 0
+					kept = append(kept, sampledLine{})
+				}
+				seen++
+				if rng.Intn(seen) == 0 {
+					kept[len(kept)-1] = sampledLine{n, text}
+				}
+				// Too many strata: merge neighbours, keeping either line
+				// with equal chance, and double the width
+				if len(kept) > s.size && seen == width {
+					merged := kept[:0]
+					for i := 0; i+1 < len(kept); i += 2 {
+						merged = append(merged, kept[i+rng.Intn(2)])
+					}
+					if len(kept)%2 == 1 {
+						merged = append(merged, kept[len(kept)-1])
+					}
+					kept = merged
+					width *= 2
+					stratum = (n - 1) / width
+					seen = n - stratum*width
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, st, err
+		}
+	}
+
+	sort.Slice(kept, func(i, j int) bool { return kept[i].n < kept[j].n })
+	st.sampled = len(kept)
+	for key, c := range counts {
+		if c > 1 && key != "" {
+			st.top = append(st.top, repeatedLine{first[key], c})
+		}
+	}
+	sort.Slice(st.top, func(i, j int) bool {
+		if st.top[i].count != st.top[j].count {
+			return st.top[i].count > st.top[j].count
+		}
+		return st.top[i].text < st.top[j].text
+	})
+	if len(st.top) > sampleTopLines {
+		st.top = st.top[:sampleTopLines]
+	}
+	return kept, st, nil
+}
+
+// formatSample writes the input's stats and the sample as the prompt
+// input. Lines of random and stratified samples carry their line numbers,
+// as they are not contiguous.
+func formatSample(kept []sampledLine, st sampleStats, s inputSample) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[Input sampled: %d of %d lines (%s), %s]\n", len(kept), st.lines, s.mode, formatBytes(st.bytes))
+	if len(st.top) > 0 {
+		b.WriteString("\nMost repeated lines (digits ignored):\n")
+		for _, r := range st.top {
+			fmt.Fprintf(&b, "  %d× %s\n", r.count, fitColumn(r.text, 160))
+		}
+	}
+	b.WriteString("\nSample:\n")
+	numbered := s.mode == sampleRandom || s.mode == sampleStratified
+	for _, l := range kept {
+		if numbered {
+			fmt.Fprintf(&b, "%d: ", l.n)
+		}
+		b.WriteString(l.text)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// sampleReader reads r whole when it fits the sample size, and otherwise
+// returns its sample with stats
+func sampleReader(r io.Reader, s inputSample) (string, sampleStats, error) {
+	kept, st, err := readSample(r, s)
+	if err != nil {
+		return "", st, err
+	}
+	if st.lines <= s.size {
+		lines := make([]string, len(kept))
+		for i, l := range kept {
+			lines[i] = l.text
+		}
+		return strings.Join(lines, "\n"), st, nil
+	}
+	return formatSample(kept, st, s), st, nil
+}
+
+// sampleStdin samples stdin when it is piped; a terminal gives no input
+func sampleStdin(s inputSample) (string, error) {
+	stat, _ := os.Stdin.Stat()
+	if stat.Mode()&os.ModeCharDevice != 0 {
+		return "", nil
+	}
+	text, st, err := sampleReader(os.Stdin, s)
+	if err != nil {
+		return "", err
+	}
+	if st.lines > s.size {
+		fmt.Fprintf(os.Stderr, "Sampled %d of %d stdin lines (%s)\n", st.sampled, st.lines, s.mode)
+	}
+	return text, nil
+}
@@ -0,0 +1,386 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// maxSandboxBytes caps the tree copied into a sandbox
+const maxSandboxBytes = 1 << 30
+
+// Kinds of sandbox change
+const (
+	changeAdded    = "added"
+	changeModified = "modified"
+	changeDeleted  = "deleted"
+)
+
+// sandboxChange is a file the run changed in its sandbox
+type sandboxChange struct {
+	Path   string `json:"path"` // relative to the workspace root
+	Change string `json:"change"`
+}
+
+// sandboxResult is what a sandboxed run changed, for --output json
+type sandboxResult struct {
+	Root    string          `json:"root"`
+	Changes []sandboxChange `json:"changes"`
+	Applied bool            `json:"applied"`
+	Patch   string          `json:"patch,omitempty"` // saved when not applied
+}
+
+// sandboxPrefix names sandbox temp dirs, which removeStaleTemp keeps
+// while their owner runs
+const sandboxPrefix = tempPrefix + "sandbox-"
+
+// sandbox is an ephemeral copy of the workspace where tool writes land
+type sandbox struct {
+	tmp   string          // holds the copy and the pid of the run using it
+	root  string          // the real workspace: the git root, else the current dir
+	copy  string          // its copy
+	dir   string          // the current directory inside the copy
+	files map[string]bool // the regular files copied, by relative path
+}
+
+// newSandbox copies the workspace into the private temp dir: in a git
+// repo its tracked and unignored files, else everything but .git
+func newSandbox() (*sandbox, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, err
+	}
+	root, inRepo := cwd, false
+	if top, err := gitRoot(); err == nil && top != "" {
+		root, inRepo = top, true
+	}
+	paths, err := workspaceFiles(root, inRepo)
+	if err != nil {
+		return nil, fmt.Errorf("list workspace files: %w", err)
+	}
+	tmp, err := privateTempDir()
+	if err != nil {
+		return nil, err
+	}
+	sbTmp, err := os.MkdirTemp(tmp, sandboxPrefix+"*")
+	if err != nil {
+		return nil, err
+	}
+	sb := &sandbox{tmp: sbTmp, root: root, copy: filepath.Join(sbTmp, "work")}
+	// Marks the sandbox in use, however long the run takes
+	if err := os.WriteFile(filepath.Join(sbTmp, "pid"), []byte(strconv.Itoa(os.Getpid())), 0o600); err != nil {
+		sb.remove()
+		return nil, err
+	}
+	if sb.files, err = copyFiles(root, sb.copy, paths); err != nil {
+		sb.remove()
+		return nil, fmt.Errorf("copy workspace into sandbox: %w", err)
+	}
+	rel, err := filepath.Rel(root, cwd)
+	if err != nil {
+		rel = "."
+	}
+	sb.dir = filepath.Join(sb.copy, rel)
+	if err := os.MkdirAll(sb.dir, 0o700); err != nil {
+		sb.remove()
+		return nil, err
+	}
+	return sb, nil
+}
+
+func (s *sandbox) remove() {
+	_ = os.RemoveAll(s.tmp)
+}
+
+// sandboxInUse reports whether the run that made a sandbox temp dir is
+// still running
+func sandboxInUse(tmp string) bool {
+	data, err := os.ReadFile(filepath.Join(tmp, "pid"))
+	if err != nil {
+		return false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return false
+	}
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	return p.Signal(syscall.Signal(0)) == nil
+}
+
+// workspaceFiles lists the files to copy, relative to root. In a git repo
+// these are the ones git ls-files shows, so build output and other
+// ignored paths stay out.
+func workspaceFiles(root string, inRepo bool) ([]string, error) {
+	if inRepo {
+		cmd := execCommand("git", "ls-files", "-z", "--cached", "--others", "--exclude-standard")
+		cmd.Dir = root
+		out, err := cmd.Output()
+		if err == nil {
+			var paths []string
+			for _, p := range strings.Split(string(out), "\x00") {
+				if p != "" {
+					paths = append(paths, filepath.FromSlash(p))
+				}
+			}
+			return paths, nil
+		}
+	}
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		paths = append(paths, rel)
+		return nil
+	})
+	return paths, err
+}
+
+// copyFiles copies regular files and symlinks from src to dst and returns
+// the regular files copied. Symlinks are kept only when they resolve
+// inside src, rewritten relative so they resolve inside dst; the rest
+// would let writes escape the sandbox and are left out.
+func copyFiles(src, dst string, paths []string) (map[string]bool, error) {
+	realSrc, err := filepath.EvalSymlinks(src)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]bool)
+	var total int64
+	var skipped []string
+	for _, rel := range paths {
+		path := filepath.Join(src, rel)
+		target := filepath.Join(dst, rel)
+		info, err := os.Lstat(path)
+		if os.IsNotExist(err) {
+			continue // tracked but deleted
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, ok := sandboxLink(realSrc, rel, path)
+			if !ok {
+				skipped = append(skipped, rel)
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+				return nil, err
+			}
+			if err := os.Symlink(link, target); err != nil {
+				return nil, err
+			}
+		case info.Mode().IsRegular():
+			if total += info.Size(); total > maxSandboxBytes {
+				return nil, fmt.Errorf("workspace is over %s", formatBytes(maxSandboxBytes))
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+				return nil, err
+			}
+			if err := copyFile(path, target, info.Mode().Perm()); err != nil {
+				return nil, err
+			}
+			files[rel] = true
+		}
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintf(os.Stderr, "Note: left %d symlink(s) that do not resolve inside the workspace out of the sandbox (%s)\n",
+			len(skipped), strings.Join(skipped, ", "))
+	}
+	return files, nil
+}
+
+// sandboxLink returns the symlink at path, rel under realSrc, rewritten
+// relative to its directory, and false if it resolves outside realSrc or
+// not at all
+func sandboxLink(realSrc, rel, path string) (string, bool) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", false
+	}
+	inside, err := filepath.Rel(realSrc, resolved)
+	if err != nil || inside == ".." || strings.HasPrefix(inside, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	link, err := filepath.Rel(filepath.Dir(filepath.Join(realSrc, rel)), resolved)
+	if err != nil {
+		return "", false
+	}
+	return link, true
+}
+
+func copyFile(src, dst string, perm fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// regularFiles lists the regular files under dir by relative path,
+// skipping .git
+func regularFiles(dir string) (map[string]bool, error) {
+	files := make(map[string]bool)
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.Type().IsRegular() {
+			rel, _ := filepath.Rel(dir, path)
+			files[rel] = true
+		}
+		return nil
+	})
+	return files, err
+}
+
+// changes compares the sandbox with the files copied into it
+func (s *sandbox) changes() ([]sandboxChange, error) {
+	before := s.files
+	after, err := regularFiles(s.copy)
+	if err != nil {
+		return nil, err
+	}
+	var changes []sandboxChange
+	for path := range after {
+		if !before[path] {
+			changes = append(changes, sandboxChange{Path: path, Change: changeAdded})
+			continue
+		}
+		a, errA := os.ReadFile(filepath.Join(s.root, path))
+		b, errB := os.ReadFile(filepath.Join(s.copy, path))
+		if errA != nil || errB != nil || !bytes.Equal(a, b) {
+			changes = append(changes, sandboxChange{Path: path, Change: changeModified})
+		}
+	}
+	for path := range before {
+		if !after[path] {
+			changes = append(changes, sandboxChange{Path: path, Change: changeDeleted})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// patch is a unified diff of the changes that git apply accepts
+func (s *sandbox) patch(changes []sandboxChange) string {
+	var b strings.Builder
+	for _, c := range changes {
+		oldPath, newPath := filepath.Join(s.root, c.Path), filepath.Join(s.copy, c.Path)
+		oldLabel, newLabel := "a/"+c.Path, "b/"+c.Path
+		switch c.Change {
+		case changeAdded:
+			oldPath, oldLabel = os.DevNull, os.DevNull
+		case changeDeleted:
+			newPath, newLabel = os.DevNull, os.DevNull
+		}
+		// diff exits 1 when the files differ
+		out, _ := execCommand("diff", "-u", "--label", oldLabel, "--label", newLabel, oldPath, newPath).Output()
+		if len(out) == 0 {
+			fmt.Fprintf(&b, "Binary or unreadable file %s %s\n", c.Path, c.Change)
+			continue
+		}
+		fmt.Fprintf(&b, "diff -u %s %s\n", oldLabel, newLabel)
+		b.Write(out)
+	}
+	return b.String()
+}
+
+// apply copies the changes into the real workspace
+func (s *sandbox) apply(changes []sandboxChange) error {
+	for _, c := range changes {
+		dst := filepath.Join(s.root, c.Path)
+		if c.Change == changeDeleted {
+			if err := os.Remove(dst); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		src := filepath.Join(s.copy, c.Path)
+		info, err := os.Stat(src)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return err
+		}
+		if err := copyFile(src, dst, info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// review shows what the run changed and applies it on confirmation.
+// Without a terminal, the changes are saved as a patch instead.
+func (s *sandbox) review(requestID string) (*sandboxResult, error) {
+	changes, err := s.changes()
+	if err != nil {
+		return nil, errors.NewCLIError("cannot compare the sandbox with the workspace").WithCause(err)
+	}
+	result := &sandboxResult{Root: s.root, Changes: changes}
+	if len(changes) == 0 {
+		return result, nil
+	}
+	patch := s.patch(changes)
+	fmt.Fprintf(os.Stderr, "\nThe run changed %d file(s) in its sandbox:\n\n%s\n", len(changes), patch)
+	ok, confirmErr := confirm(fmt.Sprintf("Apply these changes to %s?", s.root))
+	if ok {
+		if err := s.apply(changes); err != nil {
+			return nil, coded(codeToolFailed, errors.NewCLIError("failed to apply sandbox changes").WithCause(err))
+		}
+		result.Applied = true
+		fmt.Fprintln(os.Stderr, "Applied.")
+		return result, nil
+	}
+
+	path := filepath.Join(stateDir(), "sandbox", requestID+".patch")
+	if !stateless && os.MkdirAll(filepath.Dir(path), 0o700) == nil && os.WriteFile(path, []byte(patch), 0o600) == nil {
+		result.Patch = path
+	}
+	reason := "Not applied"
+	if confirmErr != nil {
+		reason = "Not applied (no terminal to confirm)"
+	}
+	if result.Patch != "" {
+		fmt.Fprintf(os.Stderr, "%s; the patch is in %s (git apply it from %s)\n", reason, result.Patch, s.root)
+	} else {
+		fmt.Fprintln(os.Stderr, reason+".")
+	}
+	return result, nil
+}
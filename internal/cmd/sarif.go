@@ -0,0 +1,165 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolURI = "https://github.com/mtreilly/arc-ask"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+var ruleIDPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// writeSARIF emits a SARIF 2.1.0 log with one rule per distinct finding rule
+func writeSARIF(w io.Writer, r report) error {
+	driver := sarifDriver{
+		Name:           "arc-ask",
+		InformationURI: sarifToolURI,
+		Rules:          []sarifRule{},
+	}
+	ruleIndex := make(map[string]int)
+	results := make([]sarifResult, 0, len(r.Findings))
+
+	for i, f := range r.Findings {
+		id := sarifRuleID(f, i)
+		idx, ok := ruleIndex[id]
+		if !ok {
+			title := f.Title
+			if title == "" {
+				title = firstSentence(sarifText(f, id))
+			}
+			idx = len(driver.Rules)
+			ruleIndex[id] = idx
+			driver.Rules = append(driver.Rules, sarifRule{ID: id, ShortDescription: sarifMessage{Text: title}})
+		}
+
+		result := sarifResult{
+			RuleID:    id,
+			RuleIndex: idx,
+			Level:     sarifLevel(f.Severity),
+			Message:   sarifMessage{Text: sarifText(f, id)},
+		}
+		if f.File != "" {
+			loc := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: filepath.ToSlash(strings.TrimPrefix(f.File, "./"))}}
+			if f.Line > 0 {
+				loc.Region = &sarifRegion{StartLine: f.Line}
+			}
+			result.Locations = []sarifLocation{{PhysicalLocation: loc}}
+		}
+		results = append(results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results}},
+	})
+}
+
+// sarifText is a finding's message, falling back to its title and then its
+// rule, since consumers reject results with an empty message
+func sarifText(f Finding, id string) string {
+	for _, text := range []string{f.Message, f.Title, f.Rule} {
+		if text = strings.TrimSpace(text); text != "" {
+			return text
+		}
+	}
+	return id
+}
+
+func sarifRuleID(f Finding, i int) string {
+	id := f.Rule
+	if id == "" {
+		id = f.Title
+	}
+	id = strings.Trim(ruleIDPattern.ReplaceAllString(strings.ToLower(id), "-"), "-")
+	if id == "" {
+		id = fmt.Sprintf("finding-%d", i+1)
+	}
+	return id
+}
+
+func sarifLevel(severity string) string {
+	switch severity {
+	case SeverityError:
+		return "error"
+	case SeverityNotice:
+		return "note"
+	default:
+		return "warning"
+	}
+}
+
+func firstSentence(s string) string {
+	if i := strings.IndexAny(s, ".\n"); i > 0 {
+		return s[:i]
+	}
+	return s
+}
@@ -0,0 +1,356 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// maxRequestBody bounds a single /ask request
+const maxRequestBody = 8 << 20
+
+// serveRequest is the body of POST /ask
+type serveRequest struct {
+	Prompt   string            `json:"prompt"` // question or @template
+	Input    string            `json:"input"`
+	Vars     map[string]string `json:"vars"`
+	Priority string            `json:"priority"` // interactive (default) or batch
+	Client   string            `json:"client"`   // defaults to X-Arc-Client, then the remote address
+	Raw      bool              `json:"raw"`      // prompt is already composed; input is its context
+	Model    string            `json:"model"`    // model or alias; defaults to the server's
+
+	// With raw, the system prompt kept apart from prompt; the server sends
+	// it by its own prompt_roles rule
+	System string `json:"system,omitempty"`
+	// Generation settings; unset ones use the server's
+	Provider      string   `json:"provider,omitempty"`
+	MaxTokens     int      `json:"max_tokens,omitempty"`
+	Temperature   *float64 `json:"temperature,omitempty"`
+	Thinking      string   `json:"thinking,omitempty"`      // a level or token budget
+	Continuations int      `json:"continuations,omitempty"` // continuation requests when an answer hits max_tokens
+}
+
+type serveResponse struct {
+	Response string `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+	Cached   bool   `json:"cached,omitempty"` // answered from the server's cache
+	Shared   bool   `json:"shared,omitempty"` // answered by an identical request in progress
+
+	Usage        *callUsage `json:"usage,omitempty"` // nil unless this request made the model call
+	FinishReason string     `json:"finish_reason,omitempty"`
+}
+
+// askServer answers questions over HTTP through a priority queue
+type askServer struct {
+	cfg     *Config
+	client  *BridgeClient
+	queue   *requestQueue
+	share   string         // what clients share: off, host, or client
+	cache   *responseCache // nil when share is off
+	flights *flightGroup
+}
+
+func newServeCmd(client *BridgeClient) *cobra.Command {
+	var (
+		addr      string
+		workers   int
+		perClient int
+		maxQueue  int
+		poll      time.Duration
+		share     string
+		cacheDir  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Answer questions over HTTP with a priority queue",
+		Long: `Serve POST /ask on a local address. Requests are queued: interactive
+requests always start before batch ones, and each client may only have
+--per-client requests running at once, so a batch job cannot starve an
+interactive question. GET /metrics reports queue depth in the Prometheus
+text format.
+
+Template files are watched while serving: added, changed, and removed
+templates take effect without a restart, and parse errors are logged.
+POST /templates/reload reloads them on demand and returns the errors.
+
+--share lets the users of one machine share the server's response cache
+and rate limit, so a team on one dev box does not pay twice for the same
+question. With host, every client shares both, and identical requests in
+progress are answered by one model call. With client, each client (the
+"client" field, X-Arc-Client, or the remote address) has its own cache
+entries and rate limit bucket. The client name is advisory: callers choose
+it, so any caller can use another client's cache entries and bucket. Point arc-ask at the server with server in
+ask.yaml or $ARC_ASK_SERVER to send plain questions through it.
+
+Request body, sent as Content-Type: application/json:
+  {"prompt": "Explain this", "input": "...", "vars": {},
+   "priority": "interactive|batch", "client": "ci", "model": "fast",
+   "provider": "...", "max_tokens": 0, "temperature": 0.2, "thinking": "low",
+   "continuations": 0}`,
+		Example: `  arc-ask serve --workers 4 --per-client 2
+  curl -s localhost:7878/ask -H 'Content-Type: application/json' -d '{"prompt":"@explain","input":"ls -la"}'
+  curl -s localhost:7878/metrics
+  curl -s -X POST localhost:7878/templates/reload
+  arc-ask serve --share host --cache-dir /srv/arc-ask/cache`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if workers < 1 {
+				return errors.NewCLIError("--workers must be at least 1")
+			}
+			scope, err := parseShareScope(share)
+			if err != nil {
+				return err
+			}
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			// The server asks the provider itself
+			client.server = ""
+			s := &askServer{
+				cfg:     cfg,
+				client:  client,
+				queue:   newRequestQueue(workers, perClient, maxQueue),
+				share:   scope,
+				flights: newFlightGroup(),
+			}
+			if scope != shareOff {
+				s.cache = newResponseCache()
+				if cacheDir != "" {
+					s.cache.dir = ask.ExpandHome(cacheDir)
+				}
+			}
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/ask", s.handleAsk)
+			mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+				s.queue.writeMetrics(w)
+			})
+			mux.HandleFunc("/templates/reload", s.handleReload)
+
+			if poll > 0 {
+				ctx, cancel := context.WithCancel(context.Background())
+				defer cancel()
+				go watchTemplates(ctx, userTemplates(), poll)
+			}
+
+			fmt.Fprintf(os.Stderr, "Listening on http://%s (%d workers, sharing: %s)\n", addr, workers, scope)
+			if err := http.ListenAndServe(addr, mux); err != nil {
+				return errors.NewCLIError("server failed").WithCause(err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:7878", "Address to listen on")
+	cmd.Flags().IntVar(&workers, "workers", 2, "Requests answered concurrently")
+	cmd.Flags().IntVar(&perClient, "per-client", 1, "Max concurrent requests per client (0 = no limit)")
+	cmd.Flags().IntVar(&maxQueue, "max-queue", 100, "Max queued requests before rejecting with 429 (0 = no limit)")
+	cmd.Flags().StringVar(&share, "share", shareOff, "Share the response cache and rate limit between clients: off, host, client")
+	cmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Response cache directory for --share (default: the server user's cache)")
+	cmd.Flags().DurationVar(&poll, "template-poll", 2*time.Second, "How often to check templates for changes (0 = never)")
+	return cmd
+}
+
+func (s *askServer) handleAsk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeServeJSON(w, http.StatusMethodNotAllowed, serveResponse{Error: "use POST"})
+		return
+	}
+
+	// Browsers can send form posts cross-site without asking; they cannot
+	// send JSON
+	if mt, _, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err != nil || mt != "application/json" {
+		writeServeJSON(w, http.StatusUnsupportedMediaType, serveResponse{Error: "Content-Type must be application/json"})
+		return
+	}
+
+	var req serveRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBody)).Decode(&req); err != nil {
+		writeServeJSON(w, http.StatusBadRequest, serveResponse{Error: "invalid JSON: " + err.Error()})
+		return
+	}
+	pri, err := parsePriority(req.Priority)
+	if err != nil {
+		writeServeJSON(w, http.StatusBadRequest, serveResponse{Error: err.Error()})
+		return
+	}
+	if strings.TrimSpace(req.Prompt) == "" {
+		writeServeJSON(w, http.StatusBadRequest, serveResponse{Error: "prompt is required"})
+		return
+	}
+	system, user, input := "", req.Prompt, ""
+	if req.Raw {
+		system, input = req.System, req.Input
+	} else {
+		system, user, err = buildPrompt(req.Prompt, req.Input, req.Vars)
+		if err != nil {
+			writeServeJSON(w, http.StatusBadRequest, serveResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	name := requestClient(r, req.Client)
+	client := *s.client
+	client.stats = &callStats{}
+	if req.Provider != "" {
+		s.cfg.useProvider(&client, req.Provider, "")
+	}
+	if req.Model != "" {
+		client.useModel(req.Model)
+	}
+	if req.MaxTokens > 0 {
+		client.maxTokens = req.MaxTokens
+	}
+	if req.Temperature != nil {
+		client.temperature = req.Temperature
+	}
+	if req.Thinking != "" {
+		if client.thinking, err = parseThinking(req.Thinking); err != nil {
+			writeServeJSON(w, http.StatusBadRequest, serveResponse{Error: err.Error()})
+			return
+		}
+	}
+	if req.Continuations > 0 {
+		client.continuations = req.Continuations
+	}
+	client.limiter = client.limiter.forClient(s.share, name)
+	asker, prompt := client.shapePrompt(system, user)
+	key := ""
+	if s.cache != nil {
+		key = scopedKey(s.share, name, responseKey(asker, prompt+"\x00"+input))
+		if answer, ok := s.cache.get(key); ok {
+			writeServeJSON(w, http.StatusOK, serveResponse{Response: answer, Cached: true})
+			return
+		}
+	}
+
+	type result struct {
+		answer string
+		err    error
+	}
+	// newJob queues the model call, which stops when ctx ends
+	newJob := func(ctx context.Context, done chan<- result) *queuedJob {
+		return &queuedJob{
+			client: name,
+			pri:    pri,
+			run: func() {
+				// The caller may have gone away while the job was queued
+				if ctx.Err() != nil {
+					done <- result{err: ctx.Err()}
+					return
+				}
+				ctx, cancel := context.WithTimeout(ctx, s.client.timeout)
+				defer cancel()
+				answer, err := asker.AskWithContext(ctx, prompt, input)
+				if err == nil && s.cache != nil {
+					s.cache.put(key, "", answer)
+				}
+				done <- result{answer: answer, err: err}
+			},
+		}
+	}
+	if s.cache != nil {
+		// Wait for an identical request in progress instead of queueing.
+		// The call outlives this request while others wait for it.
+		answer, shared, err := s.flights.do(r.Context(), key, func(ctx context.Context) (string, error) {
+			done := make(chan result, 1)
+			job := newJob(ctx, done)
+			if err := s.queue.submit(job); err != nil {
+				return "", err
+			}
+			select {
+			case <-ctx.Done():
+				s.queue.cancel(job)
+				return "", ctx.Err()
+			case res := <-done:
+				return res.answer, res.err
+			}
+		})
+		switch {
+		case err == errQueueFull:
+			writeServeJSON(w, http.StatusTooManyRequests, serveResponse{Error: err.Error()})
+		case r.Context().Err() != nil:
+		case err != nil:
+			writeServeJSON(w, http.StatusBadGateway, serveResponse{Error: err.Error()})
+		case shared:
+			writeServeJSON(w, http.StatusOK, serveResponse{Response: answer, Shared: true})
+		default:
+			writeServeJSON(w, http.StatusOK, callResponse(answer, asker.stats))
+		}
+		return
+	}
+
+	done := make(chan result, 1)
+	job := newJob(r.Context(), done)
+	if err := s.queue.submit(job); err != nil {
+		writeServeJSON(w, http.StatusTooManyRequests, serveResponse{Error: err.Error()})
+		return
+	}
+
+	select {
+	case <-r.Context().Done():
+		s.queue.cancel(job)
+	case res := <-done:
+		if res.err != nil {
+			writeServeJSON(w, http.StatusBadGateway, serveResponse{Error: res.err.Error()})
+			return
+		}
+		writeServeJSON(w, http.StatusOK, callResponse(res.answer, asker.stats))
+	}
+}
+
+// callResponse is an answer the request's own model call made, with its
+// usage
+func callResponse(answer string, stats *callStats) serveResponse {
+	usage, finish := stats.snapshot()
+	return serveResponse{Response: answer, Usage: usage, FinishReason: finish}
+}
+
+func (s *askServer) handleReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeServeJSON(w, http.StatusMethodNotAllowed, serveResponse{Error: "use POST"})
+		return
+	}
+	writeServeJSON(w, http.StatusOK, reloadTemplates(userTemplates()))
+}
+
+// requestClient identifies the caller for per-client limits. The name is
+// whatever the caller says, so it keeps honest clients apart rather than
+// isolating them from each other.
+func requestClient(r *http.Request, named string) string {
+	if named != "" {
+		return named
+	}
+	if h := r.Header.Get("X-Arc-Client"); h != "" {
+		return h
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func writeServeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,275 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// defaultSessionDir is where conversations are persisted
+const defaultSessionDir = "~/.local/share/arc/ask/sessions"
+
+// Turn roles
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+var sessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Turn is one message in a conversation
+type Turn struct {
+	Role    string    `json:"role"`
+	Content string    `json:"content"`
+	Time    time.Time `json:"time"`
+}
+
+// Session is a persisted conversation. Branches are separate sessions
+// that copy their parent's turns up to the fork point.
+type Session struct {
+	ID       string    `json:"id"`
+	Parent   string    `json:"parent,omitempty"`
+	ForkedAt int       `json:"forked_at,omitempty"` // parent turns inherited
+	Created  time.Time `json:"created"`
+	Updated  time.Time `json:"updated"`
+	Turns    []Turn    `json:"turns"`
+
+	// Summary stands in for the first Summarized turns in prompts once the
+	// conversation outgrows its token budget; the turns themselves are kept
+	Summary    string `json:"summary,omitempty"`
+	Summarized int    `json:"summarized,omitempty"`
+
+	// Title and Tags are set when the first answer is saved
+	Title string   `json:"title,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// SessionStore reads and writes sessions as JSON files in a directory
+type SessionStore struct {
+	dir string
+}
+
+// NewSessionStore creates a store in the default session directory
+func NewSessionStore() *SessionStore {
+	return &SessionStore{dir: sessionDir()}
+}
+
+func (s *SessionStore) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// Load reads a session by ID
+func (s *SessionStore) Load(id string) (*Session, error) {
+	data, err := os.ReadFile(s.path(id))
+	if os.IsNotExist(err) {
+		return nil, errors.NewCLIError(fmt.Sprintf("session %q not found", id)).
+			WithSuggestions("List sessions: arc-ask sessions list")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read session: %w", err)
+	}
+	var sess Session
+	if err := json.Unmarshal(data, &sess); err != nil {
+		return nil, fmt.Errorf("parse session %s: %w", id, err)
+	}
+	return &sess, nil
+}
+
+// Exists reports whether a session with the ID is stored
+func (s *SessionStore) Exists(id string) bool {
+	_, err := os.Stat(s.path(id))
+	return err == nil
+}
+
+// Save writes a session atomically; stateless runs keep it in memory only
+func (s *SessionStore) Save(sess *Session) error {
+	if stateless {
+		return nil
+	}
+	if err := os.MkdirAll(s.dir, 0o700); err != nil {
+		return fmt.Errorf("create session dir: %w", err)
+	}
+	sess.Updated = time.Now()
+	data, err := json.MarshalIndent(sess, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path(sess.ID) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("write session: %w", err)
+	}
+	return os.Rename(tmp, s.path(sess.ID))
+}
+
+// Delete removes a stored session; its branches are kept
+func (s *SessionStore) Delete(id string) error {
+	err := os.Remove(s.path(id))
+	if os.IsNotExist(err) {
+		return errors.NewCLIError(fmt.Sprintf("session %q not found", id)).
+			WithSuggestions("List sessions: arc-ask sessions list")
+	}
+	if err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	return nil
+}
+
+// Latest returns the most recently updated session, or nil when there is
+// none
+func (s *SessionStore) Latest() (*Session, error) {
+	sessions, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	var latest *Session
+	for _, sess := range sessions {
+		if latest == nil || sess.Updated.After(latest.Updated) {
+			latest = sess
+		}
+	}
+	return latest, nil
+}
+
+// List returns all stored sessions sorted by ID
+func (s *SessionStore) List() ([]*Session, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read session dir: %w", err)
+	}
+	var out []*Session
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		sess, err := s.Load(strings.TrimSuffix(e.Name(), ".json"))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, sess)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+// Branch forks sess at its current point into a new session named
+// "<id>.<name>" that shares the conversation so far
+func (s *SessionStore) Branch(sess *Session, name string) (*Session, error) {
+	id := sess.ID + "." + name
+	if err := validateSessionID(id); err != nil {
+		return nil, err
+	}
+	if s.Exists(id) {
+		return nil, errors.NewCLIError(fmt.Sprintf("branch %q already exists", id))
+	}
+	branch := &Session{
+		ID:         id,
+		Parent:     sess.ID,
+		ForkedAt:   len(sess.Turns),
+		Created:    time.Now(),
+		Turns:      append([]Turn(nil), sess.Turns...),
+		Summary:    sess.Summary,
+		Summarized: sess.Summarized,
+		Title:      sess.Title,
+		Tags:       sess.Tags,
+	}
+	if err := s.Save(branch); err != nil {
+		return nil, err
+	}
+	return branch, nil
+}
+
+// openSession is the session a one-shot question continues: the named one,
+// created when new, or with resume the most recently updated. It is nil
+// when neither is asked for.
+func openSession(name string, resume bool) (*Session, error) {
+	if name == "" && !resume {
+		return nil, nil
+	}
+	if name != "" && resume {
+		return nil, coded(codeUsage, errors.NewCLIError("--session and --resume cannot be combined").
+			WithSuggestions("--session NAME continues NAME; --resume continues the latest session"))
+	}
+	if err := refuseStateless("--session"); err != nil {
+		return nil, err
+	}
+	store := NewSessionStore()
+	if resume {
+		sess, err := store.Latest()
+		if err != nil {
+			return nil, err
+		}
+		if sess == nil {
+			return nil, errors.NewCLIError("no session to resume").
+				WithSuggestions("Start one: arc-ask --session NAME \"question\"")
+		}
+		fmt.Fprintf(os.Stderr, "Continuing session %s (%d turns)\n", sess.ID, len(sess.Turns))
+		return sess, nil
+	}
+	if store.Exists(name) {
+		return store.Load(name)
+	}
+	return newSession(name)
+}
+
+// newSession creates an unsaved session; an empty ID gets a timestamp
+func newSession(id string) (*Session, error) {
+	now := time.Now()
+	if id == "" {
+		id = now.Format("20060102-150405")
+	}
+	if err := validateSessionID(id); err != nil {
+		return nil, err
+	}
+	return &Session{ID: id, Created: now}, nil
+}
+
+func validateSessionID(id string) error {
+	if !sessionIDPattern.MatchString(id) {
+		return errors.NewCLIError(fmt.Sprintf("invalid session name %q", id)).
+			WithSuggestions("Use letters, digits, '.', '_' and '-'")
+	}
+	return nil
+}
+
+// Append records a turn
+func (sess *Session) Append(role, content string) {
+	sess.Turns = append(sess.Turns, Turn{Role: role, Content: content, Time: time.Now()})
+}
+
+// Prompt renders the conversation followed by a new user message, with
+// summarized turns replaced by their summary
+func (sess *Session) Prompt(message string) string {
+	if len(sess.Turns) == 0 {
+		return message
+	}
+	var b strings.Builder
+	if sess.Summary != "" {
+		_, _ = fmt.Fprintf(&b, "Summary of the earlier conversation:\n\n%s\n\n", sess.Summary)
+		b.WriteString("Recent conversation:\n\n")
+	} else {
+		b.WriteString("Conversation so far:\n\n")
+	}
+	for _, t := range sess.Turns[sess.Summarized:] {
+		label := "User"
+		if t.Role == RoleAssistant {
+			label = "Assistant"
+		}
+		_, _ = fmt.Fprintf(&b, "%s: %s\n\n", label, t.Content)
+	}
+	b.WriteString("Continue the conversation. Reply to the latest user message.\n\nUser: ")
+	b.WriteString(message)
+	return b.String()
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+)
+
+const (
+	defaultKeepLast         = 6
+	defaultSummaryMaxTokens = 1000
+	// defaultSessionBudget is the prompt budget when the model's context
+	// window is unknown
+	defaultSessionBudget = 32000
+)
+
+const sessionSummaryInstructions = `Summarize the conversation below so it can continue without the full transcript. Keep the user's goals and constraints, facts and decisions established, code, commands, file names, and error messages that are still relevant, and open questions. Drop pleasantries and superseded attempts. Write at most about %d tokens.`
+
+// ChatConfig is the chat section of ask.yaml
+type ChatConfig struct {
+	KeepLast         int `yaml:"keep_last,omitempty"`          // turns always sent verbatim, default 6
+	SummaryMaxTokens int `yaml:"summary_max_tokens,omitempty"` // default 1000
+	BudgetTokens     int `yaml:"budget_tokens,omitempty"`      // default: 3/4 of the model's context window
+}
+
+// sessionBudget decides when a conversation is folded into its summary
+type sessionBudget struct {
+	keepLast         int
+	summaryMaxTokens int
+	tokens           int
+}
+
+// newSessionBudget fills unset limits from ask.yaml, then the defaults
+func newSessionBudget(client *BridgeClient, cfg ChatConfig, b sessionBudget) sessionBudget {
+	if b.keepLast == 0 {
+		b.keepLast = cfg.KeepLast
+	}
+	if b.keepLast == 0 {
+		b.keepLast = defaultKeepLast
+	}
+	if b.summaryMaxTokens == 0 {
+		b.summaryMaxTokens = cfg.SummaryMaxTokens
+	}
+	if b.summaryMaxTokens == 0 {
+		b.summaryMaxTokens = defaultSummaryMaxTokens
+	}
+	if b.tokens == 0 {
+		b.tokens = cfg.BudgetTokens
+	}
+	if b.tokens == 0 {
+		b.tokens = defaultSessionBudget
+		if m, ok := lookupModel(client.provider, client.model); ok && m.Context > 0 {
+			b.tokens = m.Context * 3 / 4
+		}
+	}
+	return b
+}
+
+// fits reports whether the next prompt is within the budget
+func (b sessionBudget) fits(sess *Session, message string) bool {
+	return ask.EstimateTokens(sess.Prompt(message)) <= b.tokens
+}
+
+// compact folds every turn but the last keepLast into the session summary,
+// together with the previous summary. It returns the number of turns
+// newly summarized, which is 0 when there is nothing to fold.
+func (b sessionBudget) compact(ctx context.Context, client *BridgeClient, sess *Session) (int, error) {
+	end := len(sess.Turns) - b.keepLast
+	if end <= sess.Summarized {
+		return 0, nil
+	}
+
+	var t strings.Builder
+	if sess.Summary != "" {
+		_, _ = fmt.Fprintf(&t, "Summary of the conversation before this point:\n%s\n\n", sess.Summary)
+	}
+	for _, turn := range sess.Turns[sess.Summarized:end] {
+		label := "User"
+		if turn.Role == RoleAssistant {
+			label = "Assistant"
+		}
+		_, _ = fmt.Fprintf(&t, "%s: %s\n\n", label, turn.Content)
+	}
+
+	limited := *client
+	limited.maxTokens = b.summaryMaxTokens
+	summary, err := limited.Ask(ctx, fmt.Sprintf(sessionSummaryInstructions, b.summaryMaxTokens)+"\n\nConversation:\n\n"+t.String())
+	if err != nil {
+		return 0, fmt.Errorf("summarize earlier turns: %w", err)
+	}
+	n := end - sess.Summarized
+	sess.Summary, sess.Summarized = strings.TrimSpace(summary), end
+	return n, nil
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+)
+
+func newSessionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sessions",
+		Short: "Manage saved chat sessions",
+	}
+	cmd.AddCommand(newSessionsListCmd(), newSessionsShowCmd(), newSessionsDeleteCmd(), newSessionsTreeCmd(), newSessionsServeCmd())
+	return cmd
+}
+
+func newSessionsListCmd() *cobra.Command {
+	var tag string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List saved sessions with their titles, most recent first",
+		Long: `List saved sessions, most recently updated first. Sessions are titled and
+tagged when their first answer is saved; sessions saved without a title show
+their first message instead.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sessions, err := NewSessionStore().List()
+			if err != nil {
+				return err
+			}
+			if tag != "" {
+				var tagged []*Session
+				for _, s := range sessions {
+					if hasTag(s.Tags, tag) {
+						tagged = append(tagged, s)
+					}
+				}
+				sessions = tagged
+			}
+			if len(sessions) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No sessions")
+				return nil
+			}
+			sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].Updated.After(sessions[j].Updated) })
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "ID\tUPDATED\tTURNS\tTITLE\tTAGS")
+			for _, s := range sessions {
+				_, _ = fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", s.ID, s.Updated.Format("2006-01-02 15:04"),
+					len(s.Turns), sessionTitle(s), orDefault(strings.Join(s.Tags, ","), "-"))
+			}
+			return tw.Flush()
+		},
+	}
+
+	cmd.Flags().StringVar(&tag, "tag", "", "Only sessions tagged `TAG`")
+	return cmd
+}
+
+// completeSessionIDs completes stored session IDs
+func completeSessionIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	sessions, _ := NewSessionStore().List()
+	var out []string
+	for _, s := range sessions {
+		if strings.HasPrefix(s.ID, toComplete) {
+			out = append(out, s.ID+"\t"+sessionTitle(s))
+		}
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp
+}
+
+// sessionTitle is the session's title, else its first message
+func sessionTitle(s *Session) string {
+	if s.Title != "" {
+		return s.Title
+	}
+	if len(s.Turns) == 0 {
+		return "-"
+	}
+	return heuristicTitle(s.Turns[0].Content)
+}
+
+func newSessionsShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "show ID",
+		Short:             "Print a session's conversation",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessionIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			sess, err := NewSessionStore().Load(args[0])
+			if err != nil {
+				return err
+			}
+			out := cmd.OutOrStdout()
+			_, _ = fmt.Fprintf(out, "Session %s: %s\n", sess.ID, sessionTitle(sess))
+			if len(sess.Tags) > 0 {
+				_, _ = fmt.Fprintf(out, "Tags:    %s\n", strings.Join(sess.Tags, ", "))
+			}
+			if sess.Parent != "" {
+				_, _ = fmt.Fprintf(out, "Branch:  of %s at turn %d\n", sess.Parent, sess.ForkedAt)
+			}
+			_, _ = fmt.Fprintf(out, "Updated: %s, %d turns\n", sess.Updated.Local().Format("2006-01-02 15:04"), len(sess.Turns))
+			if sess.Summary != "" {
+				_, _ = fmt.Fprintf(out, "\nSummary of the first %d turns:\n%s\n", sess.Summarized, sess.Summary)
+			}
+			for _, t := range sess.Turns {
+				label := "User"
+				if t.Role == RoleAssistant {
+					label = "Assistant"
+				}
+				_, _ = fmt.Fprintf(out, "\n%s (%s):\n%s\n", label, t.Time.Local().Format("15:04"), t.Content)
+			}
+			return nil
+		},
+	}
+}
+
+func newSessionsDeleteCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:               "delete ID...",
+		Short:             "Delete saved sessions",
+		Long:              `Delete saved sessions. Branches of a deleted session are kept.`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeSessionIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := refuseStateless("sessions delete"); err != nil {
+				return err
+			}
+			store := NewSessionStore()
+			for _, id := range args {
+				if err := store.Delete(id); err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Deleted %s\n", id)
+			}
+			return nil
+		},
+	}
+}
+
+func newSessionsTreeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tree ID",
+		Short: "Show a session and its branches",
+		Long: `Show the branch tree containing a session. The tree starts at the root
+of the conversation; the requested session is marked with *.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completeSessionIDs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store := NewSessionStore()
+			sessions, err := store.List()
+			if err != nil {
+				return err
+			}
+
+			byID := make(map[string]*Session, len(sessions))
+			children := make(map[string][]*Session)
+			for _, s := range sessions {
+				byID[s.ID] = s
+				if s.Parent != "" {
+					children[s.Parent] = append(children[s.Parent], s)
+				}
+			}
+
+			current, ok := byID[args[0]]
+			if !ok {
+				_, err := store.Load(args[0])
+				return err
+			}
+			root := current
+			for root.Parent != "" && byID[root.Parent] != nil {
+				root = byID[root.Parent]
+			}
+
+			writeSessionTree(cmd.OutOrStdout(), root, children, current.ID, "", "")
+			return nil
+		},
+	}
+}
+
+// writeSessionTree prints sess and its branches with box-drawing connectors
+func writeSessionTree(w io.Writer, sess *Session, children map[string][]*Session, mark, prefix, connector string) {
+	label := fmt.Sprintf("%s (%d turns", sess.ID, len(sess.Turns))
+	if sess.Title != "" {
+		label = fmt.Sprintf("%s %q (%d turns", sess.ID, sess.Title, len(sess.Turns))
+	}
+	if sess.Parent != "" {
+		label += fmt.Sprintf(", forked at turn %d", sess.ForkedAt)
+	}
+	label += ")"
+	if sess.ID == mark {
+		label += " *"
+	}
+	_, _ = fmt.Fprintf(w, "%s%s%s\n", prefix, connector, label)
+
+	switch connector {
+	case "├── ":
+		prefix += "│   "
+	case "└── ":
+		prefix += "    "
+	}
+	kids := children[sess.ID]
+	for i, child := range kids {
+		next := "├── "
+		if i == len(kids)-1 {
+			next = "└── "
+		}
+		writeSessionTree(w, child, children, mark, prefix, next)
+	}
+}
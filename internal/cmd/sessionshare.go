@@ -0,0 +1,227 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// sharePoll is how often a live view checks the session file for new turns
+const sharePoll = 500 * time.Millisecond
+
+// shareTurn is a turn sent to the live view
+type shareTurn struct {
+	Index int `json:"index"`
+	Turn
+}
+
+// sessionShare serves a read-only live view of one session
+type sessionShare struct {
+	store *SessionStore
+	id    string
+	token string
+}
+
+func newSessionsServeCmd() *cobra.Command {
+	var (
+		addr    string
+		noToken bool
+	)
+	cmd := &cobra.Command{
+		Use:   "serve ID",
+		Short: "Share a live, read-only view of a chat session",
+		Long: `Serve a web page that follows a chat session as it happens, so a
+teammate can watch a debugging conversation without screen sharing. New
+turns appear as they are saved. The view is read-only: nothing can be
+asked or changed through it.
+
+Sessions can hold logs and secrets, so the page needs the access token in
+the printed URL. The default address only accepts local connections; use
+--addr :8089 to share on the network.
+
+GET /session.json returns the session and GET /events streams its turns as
+server-sent events ("turn", and "reset" if the session is rewritten).`,
+		Example: `  arc-ask sessions serve incident-42
+  arc-ask sessions serve incident-42 --addr :8089`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s := &sessionShare{store: NewSessionStore(), id: args[0]}
+			if _, err := s.store.Load(s.id); err != nil {
+				return err
+			}
+			if !noToken {
+				s.token = randomHex(16)
+			}
+
+			mux := http.NewServeMux()
+			mux.HandleFunc("/", s.authorized(s.handlePage))
+			mux.HandleFunc("/session.json", s.authorized(s.handleSession))
+			mux.HandleFunc("/events", s.authorized(s.handleEvents))
+
+			ln, err := net.Listen("tcp", addr)
+			if err != nil {
+				return errors.NewCLIError("server failed").WithCause(err)
+			}
+			fmt.Fprintf(os.Stderr, "Sharing session %s read-only at %s\n", s.id, s.url(ln.Addr()))
+			if err := http.Serve(ln, mux); err != nil {
+				return errors.NewCLIError("server failed").WithCause(err)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&addr, "addr", "127.0.0.1:8089", "Address to listen on")
+	cmd.Flags().BoolVar(&noToken, "no-token", false, "Serve without an access token (only on trusted networks)")
+	return cmd
+}
+
+// url is the address to hand to a teammate, with the token
+func (s *sessionShare) url(addr net.Addr) string {
+	host, port, _ := net.SplitHostPort(addr.String())
+	if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+		if name, err := os.Hostname(); err == nil {
+			host = name
+		}
+	}
+	u := "http://" + net.JoinHostPort(host, port) + "/"
+	if s.token != "" {
+		u += "?token=" + s.token
+	}
+	return u
+}
+
+// authorized rejects requests without the token and anything but GET
+func (s *sessionShare) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.Header().Set("Allow", http.MethodGet)
+			http.Error(w, "read-only", http.StatusMethodNotAllowed)
+			return
+		}
+		if s.token != "" && subtle.ConstantTimeCompare([]byte(r.URL.Query().Get("token")), []byte(s.token)) != 1 {
+			http.Error(w, "missing or wrong token", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *sessionShare) handlePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", "default-src 'none'; script-src 'unsafe-inline'; style-src 'unsafe-inline'; connect-src 'self'")
+	_, _ = fmt.Fprintf(w, sharePage, s.id)
+}
+
+func (s *sessionShare) handleSession(w http.ResponseWriter, r *http.Request) {
+	sess, err := s.store.Load(s.id)
+	if err != nil {
+		writeServeJSON(w, http.StatusInternalServerError, serveResponse{Error: err.Error()})
+		return
+	}
+	writeServeJSON(w, http.StatusOK, sess)
+}
+
+// handleEvents sends every turn, then each new one as the session file
+// changes
+func (s *sessionShare) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	var (
+		sent    int
+		modTime time.Time
+	)
+	ticker := time.NewTicker(sharePoll)
+	defer ticker.Stop()
+	for {
+		if info, err := os.Stat(s.store.path(s.id)); err == nil && !info.ModTime().Equal(modTime) {
+			modTime = info.ModTime()
+			if sess, err := s.store.Load(s.id); err == nil {
+				if len(sess.Turns) < sent {
+					// Turns are only appended; fewer means the file was rewritten
+					_, _ = fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+					sent = 0
+				}
+				for ; sent < len(sess.Turns); sent++ {
+					data, _ := json.Marshal(shareTurn{Index: sent, Turn: sess.Turns[sent]})
+					_, _ = fmt.Fprintf(w, "event: turn\ndata: %s\n\n", data)
+				}
+				flusher.Flush()
+			}
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sharePage renders turns with textContent, so session text is never
+// interpreted as HTML
+const sharePage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>arc-ask session %[1]s</title>
+<style>
+  body { font: 15px/1.5 system-ui, sans-serif; max-width: 52rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+  header { display: flex; justify-content: space-between; align-items: baseline; }
+  #status { color: #888; font-size: 13px; }
+  .turn { margin: 1rem 0; padding: .75rem 1rem; border-radius: 8px; }
+  .user { background: #eef3fb; }
+  .assistant { background: #f5f5f5; }
+  .meta { color: #777; font-size: 12px; margin-bottom: .25rem; }
+  pre { white-space: pre-wrap; word-wrap: break-word; margin: 0; font: 14px/1.45 ui-monospace, monospace; }
+</style>
+</head>
+<body>
+<header><h1>%[1]s</h1><span id="status">connecting...</span></header>
+<main id="turns"></main>
+<script>
+const token = new URLSearchParams(location.search).get("token") || "";
+const turns = document.getElementById("turns");
+const status = document.getElementById("status");
+const events = new EventSource("events?token=" + encodeURIComponent(token));
+events.onopen = () => { status.textContent = "live (read-only)"; };
+events.onerror = () => { status.textContent = "reconnecting..."; };
+events.addEventListener("reset", () => { turns.replaceChildren(); });
+events.addEventListener("turn", (e) => {
+  const t = JSON.parse(e.data);
+  if (document.getElementById("turn-" + t.index)) return;
+  const div = document.createElement("div");
+  div.id = "turn-" + t.index;
+  div.className = "turn " + t.role;
+  const meta = document.createElement("div");
+  meta.className = "meta";
+  meta.textContent = t.role + " · " + new Date(t.time).toLocaleTimeString();
+  const body = document.createElement("pre");
+  body.textContent = t.content;
+  div.append(meta, body);
+  const follow = window.innerHeight + window.scrollY >= document.body.scrollHeight - 40;
+  turns.append(div);
+  if (follow) window.scrollTo(0, document.body.scrollHeight);
+});
+</script>
+</body>
+</html>
+`
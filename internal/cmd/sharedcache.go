@@ -0,0 +1,196 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Share scopes for arc-ask serve: what clients of one server share
+const (
+	shareOff    = "off"    // no server cache; one rate limit pool, as before
+	shareHost   = "host"   // every client shares the cache and the rate limit pool
+	shareClient = "client" // each client has its own cache entries and rate limit bucket
+)
+
+func parseShareScope(s string) (string, error) {
+	switch s {
+	case shareOff, shareHost, shareClient:
+		return s, nil
+	}
+	return "", errors.NewCLIError(fmt.Sprintf("invalid --share %q", s)).
+		WithSuggestions("Use off, host, or client")
+}
+
+// scopedKey isolates a response key to a client when the scope asks for it
+func scopedKey(scope, client, key string) string {
+	if scope != shareClient {
+		return key
+	}
+	sum := sha256.Sum256([]byte(client + "\x00" + key))
+	return hex.EncodeToString(sum[:])
+}
+
+// forClient returns the limiter for one client under a scope: the shared
+// pool, or a bucket of the same size per client
+func (l *rateLimiter) forClient(scope, client string) *rateLimiter {
+	if l == nil || scope != shareClient {
+		return l
+	}
+	return &rateLimiter{name: l.name + "@" + client, limit: l.limit}
+}
+
+// flight is an answer being generated for one key
+type flight struct {
+	done    chan struct{}
+	answer  string
+	err     error
+	waiters int                // requests still waiting for the answer
+	cancel  context.CancelFunc // stops the call once no one is waiting
+}
+
+// flightGroup merges identical requests in progress, so clients asking
+// the same thing at once share one model call
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{flights: make(map[string]*flight)}
+}
+
+// do runs fn for key unless a call for key is already running, in which
+// case it waits for that call's result. shared reports the latter. fn
+// runs on a context detached from the caller's, so one client going away
+// does not fail the others; it is canceled once every waiter has gone.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(context.Context) (string, error)) (answer string, shared bool, err error) {
+	g.mu.Lock()
+	f, shared := g.flights[key]
+	if !shared {
+		flightCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.flights[key] = f
+		go func() {
+			f.answer, f.err = fn(flightCtx)
+			g.mu.Lock()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+			g.mu.Unlock()
+			cancel()
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.answer, shared, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		if f.waiters--; f.waiters == 0 {
+			// Later requests start a new call rather than join this one
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+			f.cancel()
+		}
+		g.mu.Unlock()
+		return "", shared, ctx.Err()
+	}
+}
+
+// serverURL is the arc-ask serve instance plain questions go through:
+// $ARC_ASK_SERVER, else server in ask.yaml
+func serverURL() string {
+	if u := os.Getenv("ARC_ASK_SERVER"); u != "" {
+		return strings.TrimRight(u, "/")
+	}
+	if c, err := loadConfig(); err == nil {
+		return strings.TrimRight(c.Server, "/")
+	}
+	return ""
+}
+
+// serverUnreachable warns once per process when the server is down
+var serverUnreachable sync.Once
+
+// askServer sends a composed prompt and the client's generation settings
+// to an arc-ask server, so its cache and rate limit are shared with the
+// other users on the host
+func (c *BridgeClient) askServer(ctx context.Context, prompt, input string) (string, error) {
+	body, err := json.Marshal(serveRequest{
+		Prompt:        prompt,
+		Input:         input,
+		Raw:           true,
+		Model:         c.model,
+		Client:        serverClientName(),
+		System:        c.system,
+		Provider:      c.provider,
+		MaxTokens:     c.maxTokens,
+		Temperature:   c.temperature,
+		Thinking:      c.thinking,
+		Continuations: c.continuations,
+	})
+	if err != nil {
+		return "", err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.server+"/ask", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var out serveResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", fmt.Errorf("arc-ask server returned %s", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", &serverError{status: resp.StatusCode, msg: out.Error}
+	}
+	if out.Cached || out.Shared {
+		fmt.Fprintln(os.Stderr, "Answered from the shared server cache")
+	}
+	if out.Usage != nil {
+		c.stats.record(*out.Usage, out.FinishReason, 0, 0)
+	}
+	return out.Response, nil
+}
+
+// serverError is an error the server answered with, as opposed to not
+// being reachable
+type serverError struct {
+	status int
+	msg    string
+}
+
+func (e *serverError) Error() string {
+	return fmt.Sprintf("arc-ask server: %s", e.msg)
+}
+
+// serverClientName identifies this user to the server, for per-client
+// limits and --share client
+func serverClientName() string {
+	if u := os.Getenv("USER"); u != "" {
+		return u
+	}
+	return fmt.Sprintf("uid%d", os.Getuid())
+}
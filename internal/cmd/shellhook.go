@@ -0,0 +1,213 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// shellLogEnv names the session log written by the shell hook
+const shellLogEnv = "ARC_ASK_SHELL_LOG"
+
+// maxShellLogRead bounds how much of the end of a session log is parsed
+const maxShellLogRead = 4 << 20
+
+// Shell integration markers (OSC 133 and 633), invisible in the terminal
+const (
+	markCommand = "\x1b]633;E;" // followed by the command line
+	markStart   = "\x1b]133;C"  // output begins
+	markEnd     = "\x1b]133;D"  // command finished, followed by ;status
+)
+
+// The hook records the session with script(1), which keeps the terminal a
+// real tty, and marks each command's output with shell integration escapes
+const bashHook = `# arc-ask shell hook: eval "$(arc-ask shell-init bash)"
+if [[ $- == *i* ]] && [ -z "${ARC_ASK_SHELL_LOG:-}" ] && [ -t 1 ] && command -v script >/dev/null; then
+  export ARC_ASK_SHELL_LOG="%[1]s/$$.typescript"
+  mkdir -p "%[1]s" && chmod 700 "%[1]s"
+  find "%[1]s" -name '*.typescript' -mtime +1 -delete 2>/dev/null
+  if [ "$(uname)" = Darwin ]; then
+    exec script -q -F "$ARC_ASK_SHELL_LOG" "$BASH"
+  else
+    exec script -q -f "$ARC_ASK_SHELL_LOG" -c "$BASH"
+  fi
+fi
+if [ -n "${ARC_ASK_SHELL_LOG:-}" ]; then
+  trap 'rm -f "$ARC_ASK_SHELL_LOG"' EXIT
+  __arc_ask_preexec() { printf '\e]633;E;%%s\a\e]133;C\a' "$(HISTTIMEFORMAT= history 1 | sed 's/^ *[0-9]* *//')"; }
+  __arc_ask_precmd() { printf '\e]133;D;%%s\a' "$1"; }
+  PS0='$(__arc_ask_preexec)'"${PS0:-}"
+  PROMPT_COMMAND='__arc_ask_precmd $?'"${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+fi
+`
+
+const zshHook = `# arc-ask shell hook: eval "$(arc-ask shell-init zsh)"
+if [[ -o interactive ]] && [ -z "${ARC_ASK_SHELL_LOG:-}" ] && [ -t 1 ] && command -v script >/dev/null; then
+  export ARC_ASK_SHELL_LOG="%[1]s/$$.typescript"
+  mkdir -p "%[1]s" && chmod 700 "%[1]s"
+  find "%[1]s" -name '*.typescript' -mtime +1 -delete 2>/dev/null
+  if [ "$(uname)" = Darwin ]; then
+    exec script -q -F "$ARC_ASK_SHELL_LOG" zsh
+  else
+    exec script -q -f "$ARC_ASK_SHELL_LOG" -c zsh
+  fi
+fi
+if [ -n "${ARC_ASK_SHELL_LOG:-}" ]; then
+  trap 'rm -f "$ARC_ASK_SHELL_LOG"' EXIT
+  __arc_ask_preexec() { printf '\e]633;E;%%s\a\e]133;C\a' "$1"; }
+  __arc_ask_precmd() { printf '\e]133;D;%%s\a' "$?"; }
+  autoload -Uz add-zsh-hook
+  add-zsh-hook preexec __arc_ask_preexec
+  add-zsh-hook precmd __arc_ask_precmd
+fi
+`
+
+var shellHooks = map[string]string{"bash": bashHook, "zsh": zshHook}
+
+func newShellInitCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "shell-init bash|zsh",
+		Short: "Print a shell hook that records command output for --last-output",
+		Long: `Print a shell hook that records each command's output so --last-output
+can use it as context, even outside tmux. Add it to your shell's rc file:
+
+  eval "$(arc-ask shell-init bash)"
+
+The hook runs the interactive shell under script(1) and marks where each
+command's output starts and ends. The recording lives in the state
+directory and is removed when the shell exits (or after a day, if the
+shell was killed). Set ARC_ASK_SHELL_LOG to
+any value before the hook runs to disable it for a shell.`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgs:         []string{"bash", "zsh"},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error { return nil },
+		RunE: func(cmd *cobra.Command, args []string) error {
+			hook, ok := shellHooks[args[0]]
+			if !ok {
+				return errors.NewCLIError(fmt.Sprintf("unsupported shell %q", args[0])).
+					WithSuggestions("Supported shells: bash, zsh")
+			}
+			_, err := fmt.Fprintf(cmd.OutOrStdout(), hook, shellLogDir())
+			return err
+		},
+	}
+}
+
+func shellLogDir() string {
+	return filepath.Join(stateDir(), "shell")
+}
+
+// shellOutput is one finished command and what it printed
+type shellOutput struct {
+	Command string
+	Status  string
+	Output  string
+}
+
+// lastShellOutputs returns up to n of the most recent finished commands in
+// the current shell's session log, oldest first
+func lastShellOutputs(n int) ([]shellOutput, error) {
+	path := os.Getenv(shellLogEnv)
+	if path == "" {
+		return nil, errors.NewCLIError("--last-output needs the arc-ask shell hook").
+			WithSuggestions(`Add to your shell rc: eval "$(arc-ask shell-init bash)"`, "Inside tmux, use --pane instead")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.NewCLIError("failed to read shell session log").WithCause(err)
+	}
+	defer f.Close()
+	if info, err := f.Stat(); err == nil && info.Size() > maxShellLogRead {
+		_, _ = f.Seek(-maxShellLogRead, io.SeekEnd)
+	}
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, errors.NewCLIError("failed to read shell session log").WithCause(err)
+	}
+	return parseShellLog(string(data), n), nil
+}
+
+// parseShellLog splits a session recording into finished commands. The
+// command still running (arc-ask itself) has no end marker and is skipped.
+func parseShellLog(log string, n int) []shellOutput {
+	var out []shellOutput
+	for _, block := range strings.Split(log, markStart)[1:] {
+		body, rest, finished := strings.Cut(block, markEnd)
+		if !finished {
+			continue
+		}
+		o := shellOutput{Output: cleanTerminalText(strings.TrimPrefix(body, "\a"))}
+		if status, _, ok := strings.Cut(strings.TrimPrefix(rest, ";"), "\a"); ok {
+			o.Status = status
+		}
+		out = append(out, o)
+	}
+
+	// Command lines precede each start marker
+	cmds := strings.Split(log, markCommand)
+	if len(cmds) > 1 {
+		var lines []string
+		for _, c := range cmds[1:] {
+			line, _, _ := strings.Cut(c, "\a")
+			lines = append(lines, line)
+		}
+		// Pair from the end: the last command line belongs to the running command
+		for i, j := len(out)-1, len(lines)-2; i >= 0 && j >= 0; i, j = i-1, j-1 {
+			out[i].Command = lines[j]
+		}
+	}
+
+	if len(out) > n {
+		out = out[len(out)-n:]
+	}
+	return out
+}
+
+var (
+	ansiEscape   = regexp.MustCompile(`\x1b(\[[0-9;?]*[ -/]*[@-~]|\][^\a\x1b]*(\a|\x1b\\)|[()][A-Za-z0-9]|[=>])`)
+	controlChars = regexp.MustCompile(`[\x00-\x08\x0b\x0c\x0e-\x1f\x7f]`)
+)
+
+// cleanTerminalText strips escape sequences and applies carriage returns
+// so progress bars collapse to their final state
+func cleanTerminalText(s string) string {
+	s = ansiEscape.ReplaceAllString(s, "")
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		if j := strings.LastIndex(line, "\r"); j >= 0 {
+			line = line[j+1:]
+		}
+		lines[i] = controlChars.ReplaceAllString(line, "")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// formatShellOutputs renders recent commands as prompt input
+func formatShellOutputs(outputs []shellOutput) string {
+	var b strings.Builder
+	for i, o := range outputs {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		cmd := o.Command
+		if cmd == "" {
+			cmd = "(unknown command)"
+		}
+		fmt.Fprintf(&b, "$ %s", cmd)
+		if o.Status != "" && o.Status != "0" {
+			fmt.Fprintf(&b, "  # exit status %s", o.Status)
+		}
+		b.WriteString("\n")
+		b.WriteString(o.Output)
+	}
+	return b.String()
+}
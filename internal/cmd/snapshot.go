@@ -0,0 +1,94 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Files in an input snapshot directory
+const (
+	snapshotInputFile    = "input.txt"
+	snapshotManifestFile = "manifest.json"
+	snapshotVersion      = 1
+)
+
+// snapshotManifest describes how a snapshot's input was assembled, so a
+// re-run can ask the same question of the same input
+type snapshotManifest struct {
+	Version    int               `json:"version"`
+	Created    time.Time         `json:"created"`
+	Question   string            `json:"question,omitempty"` // question or @template
+	Vars       map[string]string `json:"vars,omitempty"`
+	Provider   string            `json:"provider,omitempty"`
+	Model      string            `json:"model,omitempty"`
+	Sources    []runSource       `json:"sources,omitempty"`
+	Truncation []string          `json:"truncation,omitempty"`
+	Untrusted  []string          `json:"untrusted_sources,omitempty"`
+	Redacted   bool              `json:"redacted,omitempty"`
+	Bytes      int               `json:"bytes"`
+	SHA256     string            `json:"sha256"`
+}
+
+// saveInputSnapshot writes the assembled input and its manifest to dir,
+// refusing to overwrite an earlier snapshot
+func saveInputSnapshot(dir, input string, m snapshotManifest) error {
+	if fileExists(filepath.Join(dir, snapshotManifestFile)) {
+		return errors.NewCLIError(dir + " already holds an input snapshot").
+			WithSuggestions("Choose a new directory for --snapshot-input")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return errors.NewCLIError("cannot create snapshot directory").WithCause(err)
+	}
+	sum := sha256.Sum256([]byte(input))
+	m.Version, m.Created = snapshotVersion, time.Now().UTC()
+	m.Bytes, m.SHA256 = len(input), hex.EncodeToString(sum[:])
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	// The input may hold logs and source, so it stays private
+	if err := os.WriteFile(filepath.Join(dir, snapshotInputFile), []byte(input), 0o600); err != nil {
+		return errors.NewCLIError("failed to save input snapshot").WithCause(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, snapshotManifestFile), append(data, '\n'), 0o600); err != nil {
+		return errors.NewCLIError("failed to save input snapshot").WithCause(err)
+	}
+	return nil
+}
+
+// loadInputSnapshot reads a snapshot, warning when the input was edited
+// since it was taken
+func loadInputSnapshot(dir string) (string, *snapshotManifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, snapshotManifestFile))
+	if err != nil {
+		return "", nil, errors.NewCLIError("cannot read input snapshot " + dir).WithCause(err).
+			WithSuggestions("Create one with: arc-ask ... --snapshot-input " + dir)
+	}
+	var m snapshotManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return "", nil, errors.NewCLIError("invalid snapshot manifest in " + dir).WithCause(err)
+	}
+	if m.Version != snapshotVersion {
+		return "", nil, errors.NewCLIError(fmt.Sprintf("unsupported snapshot version %d in %s", m.Version, dir))
+	}
+	input, err := os.ReadFile(filepath.Join(dir, snapshotInputFile))
+	if err != nil {
+		return "", nil, errors.NewCLIError("cannot read input snapshot " + dir).WithCause(err)
+	}
+	sum := sha256.Sum256(input)
+	if hex.EncodeToString(sum[:]) != m.SHA256 {
+		fmt.Fprintf(os.Stderr, "Warning: %s was edited after the snapshot was taken\n", filepath.Join(dir, snapshotInputFile))
+	}
+	return string(input), &m, nil
+}
@@ -0,0 +1,243 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Speech backends for --speak
+const (
+	speechSay    = "say"    // macOS
+	speechEspeak = "espeak" // espeak-ng or espeak
+	speechOpenAI = "openai" // OpenAI-compatible /audio/speech
+)
+
+const (
+	defaultSpeechURL   = "https://api.openai.com/v1/audio/speech"
+	defaultSpeechModel = "gpt-4o-mini-tts"
+	defaultSpeechVoice = "alloy"
+	speechTimeout      = 2 * time.Minute
+)
+
+// SpeechConfig is the speech section of ask.yaml
+type SpeechConfig struct {
+	Backend string `yaml:"backend,omitempty"` // say, espeak, or openai; default: say on macOS, else espeak
+	Voice   string `yaml:"voice,omitempty"`
+	Rate    int    `yaml:"rate,omitempty"`    // words per minute, for say and espeak
+	Model   string `yaml:"model,omitempty"`   // openai model
+	URL     string `yaml:"url,omitempty"`     // openai endpoint
+	APIKey  string `yaml:"api_key,omitempty"` // default: OPENAI_API_KEY
+	Player  string `yaml:"player,omitempty"`  // plays openai audio; default: afplay, mpv, or ffplay
+}
+
+var (
+	markdownLink     = regexp.MustCompile(`!?\[([^\]]*)\]\([^)]*\)`)
+	markdownMarks    = regexp.MustCompile("(?m)^\\s*(#{1,6}|[-*+]|>|\\d+\\.)\\s+|[*_`~]+")
+	markdownRules    = regexp.MustCompile(`(?m)^\s*([-*_]\s*){3,}$`)
+	markdownTableSep = regexp.MustCompile(`(?m)^\s*\|?[\s:|-]+\|?\s*$`)
+)
+
+// speakableText is the prose of an answer: code blocks are skipped and
+// markdown punctuation removed so it is not read aloud
+func speakableText(answer string) string {
+	text := ask.StripCodeBlocks(answer)
+	text = markdownLink.ReplaceAllString(text, "$1")
+	text = markdownRules.ReplaceAllString(text, "")
+	text = markdownTableSep.ReplaceAllString(text, "")
+	text = markdownMarks.ReplaceAllString(text, "")
+
+	// Each line becomes a sentence so list items and table rows get a pause
+	var paras []string
+	for _, p := range strings.Split(text, "\n\n") {
+		var sentences []string
+		for _, line := range strings.Split(p, "\n") {
+			var cells []string
+			for _, cell := range strings.Split(line, "|") {
+				if cell = strings.Join(strings.Fields(cell), " "); cell != "" {
+					cells = append(cells, cell)
+				}
+			}
+			line = strings.Join(cells, ", ")
+			if line == "" {
+				continue
+			}
+			if !strings.ContainsAny(line[len(line)-1:], ".!?:;") {
+				line += "."
+			}
+			sentences = append(sentences, line)
+		}
+		if len(sentences) > 0 {
+			paras = append(paras, strings.Join(sentences, " "))
+		}
+	}
+	return strings.Join(paras, "\n\n")
+}
+
+// speak reads the prose of an answer aloud and waits until it finishes
+func speak(ctx context.Context, cfg SpeechConfig, answer string) error {
+	text := speakableText(answer)
+	if text == "" {
+		return nil
+	}
+
+	backend := cfg.Backend
+	if backend == "" {
+		backend = speechEspeak
+		if runtime.GOOS == "darwin" {
+			backend = speechSay
+		}
+	}
+	switch backend {
+	case speechSay:
+		args := []string{}
+		if cfg.Voice != "" {
+			args = append(args, "-v", cfg.Voice)
+		}
+		if cfg.Rate > 0 {
+			args = append(args, "-r", fmt.Sprint(cfg.Rate))
+		}
+		return runSpeech("say", args, text)
+	case speechEspeak:
+		command := "espeak-ng"
+		if _, err := exec.LookPath(command); err != nil {
+			command = "espeak"
+		}
+		args := []string{"--stdin"}
+		if cfg.Voice != "" {
+			args = append(args, "-v", cfg.Voice)
+		}
+		if cfg.Rate > 0 {
+			args = append(args, "-s", fmt.Sprint(cfg.Rate))
+		}
+		return runSpeech(command, args, text)
+	case speechOpenAI:
+		return speakAPI(ctx, cfg, text)
+	}
+	return errors.NewCLIError(fmt.Sprintf("unknown speech backend %q", backend)).
+		WithSuggestions("Set speech.backend to say, espeak, or openai")
+}
+
+// runSpeech feeds text to a local synthesizer on stdin
+func runSpeech(command string, args []string, text string) error {
+	if _, err := exec.LookPath(command); err != nil {
+		return errors.NewCLIError(command + " not found for --speak").
+			WithSuggestions("Install it, or choose another speech.backend in ask.yaml")
+	}
+	c := execCommand(command, args...)
+	c.Stdin = strings.NewReader(text)
+	if out, err := c.CombinedOutput(); err != nil {
+		return errors.NewCLIError("failed to speak the answer").
+			WithCause(fmt.Errorf("%s: %w: %s", command, err, strings.TrimSpace(string(out))))
+	}
+	return nil
+}
+
+// speakAPI synthesizes speech with an OpenAI-compatible endpoint and plays it
+func speakAPI(ctx context.Context, cfg SpeechConfig, text string) error {
+	key := cfg.APIKey
+	if key == "" {
+		key = os.Getenv("OPENAI_API_KEY")
+	}
+	if key == "" {
+		return errors.NewCLIError("no API key for --speak").
+			WithSuggestions("Set OPENAI_API_KEY or speech.api_key in ask.yaml")
+	}
+	player, err := pickPlayer(cfg.Player)
+	if err != nil {
+		return err
+	}
+	dir, err := privateTempDir()
+	if err != nil {
+		return err
+	}
+	audio, err := os.CreateTemp(dir, tempPrefix+"speech-*.mp3")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(audio.Name())
+	err = synthesizeSpeech(ctx, cfg, key, text, audio)
+	if cerr := audio.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return errors.NewCLIError("speech synthesis failed").WithCause(err)
+	}
+	return runSpeech(player[0], append(player[1:], audio.Name()), "")
+}
+
+// synthesizeSpeech writes mp3 audio of text to w
+func synthesizeSpeech(ctx context.Context, cfg SpeechConfig, key, text string, w io.Writer) error {
+	url, model, voice := cfg.URL, cfg.Model, cfg.Voice
+	if url == "" {
+		url = defaultSpeechURL
+	}
+	if model == "" {
+		model = defaultSpeechModel
+	}
+	if voice == "" {
+		voice = defaultSpeechVoice
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"model": model, "voice": voice, "input": text, "response_format": "mp3",
+	})
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, speechTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+key)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("POST %s: %s: %s", url, resp.Status, strings.TrimSpace(string(msg)))
+	}
+
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
+
+// players play an audio file without a window, in order of preference
+var players = [][]string{
+	{"afplay"},
+	{"mpv", "--really-quiet", "--no-video"},
+	{"ffplay", "-nodisp", "-autoexit", "-loglevel", "error"},
+}
+
+func pickPlayer(name string) ([]string, error) {
+	if name != "" {
+		return strings.Fields(name), nil
+	}
+	for _, p := range players {
+		if _, err := exec.LookPath(p[0]); err == nil {
+			return p, nil
+		}
+	}
+	return nil, errors.NewCLIError("--speak needs an audio player for openai speech").
+		WithSuggestions("Install mpv or ffmpeg, or set speech.player in ask.yaml")
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Languages whose stack traces are recognized
+const (
+	langGo     = "go"
+	langPython = "python"
+	langJS     = "javascript"
+	langJava   = "java"
+)
+
+// stackFrame is one call in a stack trace
+type stackFrame struct {
+	Function string `json:"function,omitempty"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Path     string `json:"path,omitempty"` // the file on this machine, when found
+	Library  bool   `json:"library,omitempty"`
+}
+
+func (f stackFrame) String() string {
+	loc := fmt.Sprintf("%s:%d", f.File, f.Line)
+	if f.Function == "" {
+		return loc
+	}
+	return fmt.Sprintf("%s (%s)", loc, f.Function)
+}
+
+// stackTrace is a parsed trace with the innermost frame first
+type stackTrace struct {
+	Lang     string       `json:"language"`
+	Messages []string     `json:"messages"` // the error, then any causes
+	Frames   []stackFrame `json:"frames"`
+}
+
+var (
+	goFrameFile     = regexp.MustCompile(`^\s+(\S+\.go):(\d+)`)
+	goPanic         = regexp.MustCompile(`^(panic|fatal error): `)
+	goGoroutine     = regexp.MustCompile(`^goroutine \d+ \[`)
+	pyTraceback     = regexp.MustCompile(`^Traceback \(most recent call last\):`)
+	pyFrame         = regexp.MustCompile(`^\s*File "([^"]+)", line (\d+)(?:, in (.+))?`)
+	pyException     = regexp.MustCompile(`^[A-Za-z_][\w.]*(Error|Exception|Warning|Exit|Interrupt)\b.*`)
+	jsFrame         = regexp.MustCompile(`^\s+at (?:(?:async )?(.+?) \()?(.+?):(\d+):\d+\)?\s*$`)
+	jsError         = regexp.MustCompile(`^(?:Uncaught )?([A-Z]\w*(Error|Exception)|Error)(:|$)`)
+	javaFrame       = regexp.MustCompile(`^\s+at ([\w$.<>/]+)\(([\w$]+\.(?:java|kt|scala)):(\d+)\)`)
+	javaException   = regexp.MustCompile(`^(?:Exception in thread "[^"]*" |Caused by: )?([a-z][\w$]*\.)+[A-Z][\w$]*(Exception|Error|Throwable)\b.*`)
+	libraryPathPart = []string{"/site-packages/", "/dist-packages/", "/lib/python", "node_modules/", "node:", "/pkg/mod/", "/go/src/runtime/", "/usr/lib/", "/usr/local/go/"}
+)
+
+// parseStackTrace recognizes a Go, Python, JavaScript, or Java trace in
+// the input; it returns nil when there is none
+func parseStackTrace(input string) *stackTrace {
+	lines := strings.Split(strings.ReplaceAll(input, "\r\n", "\n"), "\n")
+	var t *stackTrace
+	switch {
+	case matchAny(lines, pyTraceback):
+		t = parsePythonTrace(lines)
+	case matchAny(lines, goGoroutine) || (matchAny(lines, goPanic) && matchAny(lines, goFrameFile)):
+		t = parseGoTrace(lines)
+	case matchAny(lines, javaFrame):
+		t = parseJavaTrace(lines)
+	case matchAny(lines, jsFrame):
+		t = parseJSTrace(lines)
+	}
+	if t == nil || len(t.Frames) == 0 {
+		return nil
+	}
+	for i := range t.Frames {
+		f := &t.Frames[i]
+		f.Library = isLibraryPath(f.File)
+		if !f.Library {
+			f.Path = findSourceFile(t.Lang, f)
+		}
+	}
+	return t
+}
+
+func matchAny(lines []string, re *regexp.Regexp) bool {
+	for _, l := range lines {
+		if re.MatchString(l) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseGoTrace reads the first goroutine, which is the one that panicked
+func parseGoTrace(lines []string) *stackTrace {
+	t := &stackTrace{Lang: langGo}
+	// Without goroutine headers every frame belongs to the panic
+	inGoroutine := !matchAny(lines, goGoroutine)
+	for i, line := range lines {
+		switch {
+		case goPanic.MatchString(line) && len(t.Messages) == 0:
+			t.Messages = append(t.Messages, strings.TrimSpace(line))
+		case goGoroutine.MatchString(line):
+			if inGoroutine {
+				return t
+			}
+			inGoroutine = true
+		case inGoroutine:
+			m := goFrameFile.FindStringSubmatch(line)
+			if m == nil || i == 0 {
+				continue
+			}
+			fn := strings.TrimSpace(lines[i-1])
+			if j := strings.LastIndex(fn, "("); j > 0 {
+				fn = fn[:j]
+			}
+			n, _ := strconv.Atoi(m[2])
+			t.Frames = append(t.Frames, stackFrame{Function: fn, File: m[1], Line: n})
+		}
+	}
+	return t
+}
+
+// parsePythonTrace reads the last traceback, reversing it so the innermost
+// call comes first; earlier chained exceptions become causes
+func parsePythonTrace(lines []string) *stackTrace {
+	t := &stackTrace{Lang: langPython}
+	var frames []stackFrame
+	var messages []string
+	inTrace := false
+	for _, line := range lines {
+		switch {
+		case pyTraceback.MatchString(line):
+			frames, inTrace = nil, true
+		case inTrace && pyFrame.MatchString(line):
+			m := pyFrame.FindStringSubmatch(line)
+			n, _ := strconv.Atoi(m[2])
+			frames = append(frames, stackFrame{Function: m[3], File: m[1], Line: n})
+		case inTrace && pyException.MatchString(line):
+			messages = append(messages, strings.TrimSpace(line))
+			inTrace = false
+		}
+	}
+	for i := len(frames) - 1; i >= 0; i-- {
+		t.Frames = append(t.Frames, frames[i])
+	}
+	// The last exception is the one raised; earlier ones caused it
+	for i := len(messages) - 1; i >= 0; i-- {
+		t.Messages = append(t.Messages, messages[i])
+	}
+	return t
+}
+
+func parseJSTrace(lines []string) *stackTrace {
+	t := &stackTrace{Lang: langJS}
+	for _, line := range lines {
+		if m := jsFrame.FindStringSubmatch(line); m != nil {
+			file := strings.TrimPrefix(m[2], "file://")
+			n, _ := strconv.Atoi(m[3])
+			t.Frames = append(t.Frames, stackFrame{Function: m[1], File: file, Line: n})
+			continue
+		}
+		if len(t.Messages) == 0 && jsError.MatchString(strings.TrimSpace(line)) {
+			t.Messages = append(t.Messages, strings.TrimSpace(line))
+		}
+	}
+	return t
+}
+
+func parseJavaTrace(lines []string) *stackTrace {
+	t := &stackTrace{Lang: langJava}
+	for _, line := range lines {
+		if m := javaFrame.FindStringSubmatch(line); m != nil {
+			n, _ := strconv.Atoi(m[3])
+			t.Frames = append(t.Frames, stackFrame{Function: m[1], File: m[2], Line: n})
+			continue
+		}
+		if javaException.MatchString(strings.TrimSpace(line)) {
+			t.Messages = append(t.Messages, strings.TrimSpace(line))
+		}
+	}
+	return t
+}
+
+func isLibraryPath(file string) bool {
+	for _, part := range libraryPathPart {
+		if strings.Contains(file, part) {
+			return true
+		}
+	}
+	return strings.HasPrefix(file, "<") // <frozen importlib>, <anonymous>
+}
+
+// findSourceFile locates a frame's file on this machine. Paths from other
+// machines, such as CI, are matched by their longest suffix that exists
+// under the working directory; Java frames only name the file, so the
+// class's package is used.
+func findSourceFile(lang string, f *stackFrame) string {
+	if lang == langJava {
+		class := f.Function
+		if i := strings.LastIndex(class, "."); i > 0 {
+			class = class[:i] // drop the method
+		}
+		pkg := ""
+		if i := strings.LastIndex(class, "."); i > 0 {
+			pkg = strings.ReplaceAll(class[:i], ".", "/")
+		}
+		for _, root := range []string{".", "src/main/java", "src/main/kotlin", "src/test/java", "src", "app/src/main/java"} {
+			path := filepath.Join(root, pkg, f.File)
+			if fileExists(path) {
+				return path
+			}
+		}
+		return ""
+	}
+
+	if fileExists(f.File) {
+		return f.File
+	}
+	parts := strings.Split(filepath.ToSlash(f.File), "/")
+	for i := 1; i < len(parts); i++ {
+		path := filepath.Join(parts[i:]...)
+		if fileExists(path) {
+			return path
+		}
+	}
+	return ""
+}
+
+// sourceSnippet returns the lines around line in path, numbered, with the
+// failing line marked
+func sourceSnippet(path string, line, around int) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	var b strings.Builder
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		if n < line-around {
+			continue
+		}
+		if n > line+around {
+			break
+		}
+		marker := "  "
+		if n == line {
+			marker = "> "
+		}
+		fmt.Fprintf(&b, "%s%5d  %s\n", marker, n, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	if b.Len() == 0 {
+		return "", fmt.Errorf("%s has no line %d", path, line)
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/yourorg/arc-sdk/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// stateless is --stateless: nothing is written to the config or state
+// directories, and the config comes from the environment rather than
+// ask.yaml
+var stateless bool
+
+// Environment variables for stateless runs
+const (
+	statelessEnv   = "ARC_ASK_STATELESS"    // 1 or true turns --stateless on
+	configYAMLEnv  = "ARC_ASK_CONFIG"       // a whole ask.yaml, inline
+	providerEnv    = "ARC_ASK_PROVIDER"     // overrides provider
+	modelEnv       = "ARC_ASK_MODEL"        // overrides model
+	apiKeyEnv      = "ARC_ASK_API_KEY"      // overrides api_key
+	templateDirEnv = "ARC_ASK_TEMPLATE_DIR" // overrides template_dir
+)
+
+// statelessDefault is --stateless's default, from $ARC_ASK_STATELESS
+func statelessDefault() bool {
+	switch strings.ToLower(os.Getenv(statelessEnv)) {
+	case "1", "true", "yes", "on":
+		return true
+	}
+	return false
+}
+
+// configFromEnv is the config of a stateless run: $ARC_ASK_CONFIG parsed
+// as ask.yaml, with the single-field variables on top
+func configFromEnv() (*Config, error) {
+	var c Config
+	if data := os.Getenv(configYAMLEnv); data != "" {
+		if err := yaml.Unmarshal([]byte(data), &c); err != nil {
+			return nil, errors.NewCLIError("invalid $" + configYAMLEnv).WithCause(err)
+		}
+	}
+	for env, field := range map[string]*string{
+		providerEnv:    &c.Provider,
+		modelEnv:       &c.Model,
+		apiKeyEnv:      &c.APIKey,
+		templateDirEnv: &c.TemplateDir,
+	} {
+		if v := os.Getenv(env); v != "" {
+			*field = v
+		}
+	}
+	return &c, nil
+}
+
+// refuseStateless fails a command whose purpose is to write state
+func refuseStateless(command string) error {
+	if !stateless {
+		return nil
+	}
+	return coded(codeUsage, errors.NewCLIError(fmt.Sprintf("%q writes to disk, which --stateless rules out", command)).
+		WithSuggestions("Run it without --stateless (or $"+statelessEnv+")"))
+}
@@ -0,0 +1,443 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// statsEvent is one command run as usage stats record it. It never holds
+// questions, input, answers, or file names.
+type statsEvent struct {
+	Time     time.Time `json:"time"` // to the hour
+	Command  string    `json:"command"`
+	Template string    `json:"template,omitempty"`
+	Provider string    `json:"provider,omitempty"`
+	Model    string    `json:"model,omitempty"`
+	Latency  string    `json:"latency"` // a latencyBuckets name
+	Outcome  string    `json:"outcome"` // ok, or the failure code
+}
+
+// latencyBuckets are upper bounds; the last catches everything slower
+var latencyBuckets = []struct {
+	max  time.Duration
+	name string
+}{
+	{time.Second, "<1s"},
+	{5 * time.Second, "1-5s"},
+	{15 * time.Second, "5-15s"},
+	{time.Minute, "15-60s"},
+	{0, ">60s"},
+}
+
+func latencyBucket(d time.Duration) string {
+	for _, b := range latencyBuckets[:len(latencyBuckets)-1] {
+		if d < b.max {
+			return b.name
+		}
+	}
+	return latencyBuckets[len(latencyBuckets)-1].name
+}
+
+// statsUnrecorded are the commands that only read or manage the stats
+var statsUnrecorded = []string{"stats", "usage", "template stats", "help", "completion", "__complete", "__completeNoDesc"}
+
+func statsDir() string {
+	return filepath.Join(stateDir(), "stats")
+}
+
+func statsEventsPath() string { return filepath.Join(statsDir(), "events.jsonl") }
+
+// statsSwitchPath exists while usage stats are on; it holds when they
+// were turned on
+func statsSwitchPath() string { return filepath.Join(statsDir(), "enabled") }
+
+// statsOverride reports whether the environment turns stats off
+func statsOverride() bool {
+	v := strings.ToLower(os.Getenv("ARC_ASK_STATS"))
+	return v == "off" || v == "0" || v == "false" || os.Getenv("DO_NOT_TRACK") == "1"
+}
+
+// statsEnabled is true once arc-ask stats on has run, unless the
+// environment turns stats off
+func statsEnabled() bool {
+	if stateless || statsOverride() {
+		return false
+	}
+	_, err := os.Stat(statsSwitchPath())
+	return err == nil
+}
+
+// statsRun is the command being timed, set as it starts
+var statsRun struct {
+	event  *statsEvent
+	start  time.Time
+	client *BridgeClient
+}
+
+// beginStats notes the command about to run. A command that runs another
+// one, as recipe run does, is recorded as the inner one, timed from the
+// outer start.
+func beginStats(cmd *cobra.Command, args []string, client *BridgeClient) {
+	path := strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name())
+	path = strings.TrimSpace(path)
+	if path == "" {
+		path = "ask"
+	}
+	for _, skip := range statsUnrecorded {
+		if path == skip || strings.HasPrefix(path, skip+" ") {
+			statsRun.event = nil
+			return
+		}
+	}
+	e := &statsEvent{Command: path}
+	if len(args) > 0 && ask.IsTemplateRef(args[0]) {
+		e.Template = strings.TrimPrefix(args[0], "@")
+	}
+	if statsRun.start.IsZero() {
+		statsRun.start = time.Now()
+	}
+	statsRun.event, statsRun.client = e, client
+}
+
+// RecordStats appends the command that just finished to the local usage
+// stats, when they are on. Failing to record is never an error.
+func RecordStats(err error) {
+	e := statsRun.event
+	if e == nil || !statsEnabled() {
+		return
+	}
+	e.Time = time.Now().UTC().Truncate(time.Hour)
+	e.Latency = latencyBucket(time.Since(statsRun.start))
+	e.Outcome = "ok"
+	if err != nil {
+		e.Outcome = classifyError(err).Code
+	}
+	if c := statsRun.client; c != nil {
+		e.Provider, e.Model = c.provider, c.model
+	}
+	data, jerr := json.Marshal(e)
+	if jerr != nil {
+		return
+	}
+	f, ferr := os.OpenFile(statsEventsPath(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if ferr != nil {
+		return
+	}
+	_, _ = fmt.Fprintf(f, "%s\n", data)
+	_ = f.Close()
+}
+
+// readStats returns the recorded events since the given time
+func readStats(since time.Time) ([]statsEvent, error) {
+	f, err := os.Open(statsEventsPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []statsEvent
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e statsEvent
+		if json.Unmarshal(sc.Bytes(), &e) == nil && !e.Time.Before(since) {
+			events = append(events, e)
+		}
+	}
+	return events, sc.Err()
+}
+
+// statsRow sums the events that share a key
+type statsRow struct {
+	Key     string         `json:"key"`
+	Runs    int            `json:"runs"`
+	Failed  int            `json:"failed"`
+	Latency map[string]int `json:"latency"` // runs per latencyBuckets name
+	Last    time.Time      `json:"last"`
+}
+
+// summarizeStats groups events by key, most runs first. Events with an
+// empty key are left out.
+func summarizeStats(events []statsEvent, key func(statsEvent) string) []statsRow {
+	rows := make(map[string]*statsRow)
+	for _, e := range events {
+		k := key(e)
+		if k == "" {
+			continue
+		}
+		r, ok := rows[k]
+		if !ok {
+			r = &statsRow{Key: k, Latency: make(map[string]int)}
+			rows[k] = r
+		}
+		r.Runs++
+		if e.Outcome != "ok" {
+			r.Failed++
+		}
+		r.Latency[e.Latency]++
+		if e.Time.After(r.Last) {
+			r.Last = e.Time
+		}
+	}
+	out := make([]statsRow, 0, len(rows))
+	for _, r := range rows {
+		out = append(out, *r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Runs != out[j].Runs {
+			return out[i].Runs > out[j].Runs
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+func writeStatsTable(w io.Writer, heading string, rows []statsRow) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	head := []string{strings.ToUpper(heading), "RUNS", "FAILED"}
+	for _, b := range latencyBuckets {
+		head = append(head, b.name)
+	}
+	_, _ = fmt.Fprintln(tw, strings.Join(append(head, "LAST"), "\t"))
+	for _, r := range rows {
+		cols := []string{fitColumn(r.Key, 40), strconv.Itoa(r.Runs), strconv.Itoa(r.Failed)}
+		for _, b := range latencyBuckets {
+			cols = append(cols, strconv.Itoa(r.Latency[b.name]))
+		}
+		cols = append(cols, r.Last.Local().Format("2006-01-02"))
+		_, _ = fmt.Fprintln(tw, strings.Join(cols, "\t"))
+	}
+	return tw.Flush()
+}
+
+// parseSince reads --since: a number of days such as 30d, or a duration
+func parseSince(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return time.Now().AddDate(0, 0, -n), nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return time.Time{}, errors.NewCLIError(fmt.Sprintf("invalid --since %q", s)).
+			WithSuggestions("Use days (30d) or a duration (12h)")
+	}
+	return time.Now().Add(-d), nil
+}
+
+// statsKeys are what usage --by groups on
+var statsKeys = map[string]func(statsEvent) string{
+	"command":  func(e statsEvent) string { return e.Command },
+	"template": func(e statsEvent) string { return e.Template },
+	"model":    func(e statsEvent) string { return e.Model },
+	"provider": func(e statsEvent) string { return e.Provider },
+	"outcome":  func(e statsEvent) string { return e.Outcome },
+	"day":      func(e statsEvent) string { return e.Time.Local().Format("2006-01-02") },
+}
+
+// reportStats prints events grouped by key as a table or JSON
+func reportStats(w io.Writer, by, since, format string) error {
+	key, ok := statsKeys[by]
+	if !ok {
+		names := make([]string, 0, len(statsKeys))
+		for name := range statsKeys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return errors.NewCLIError(fmt.Sprintf("invalid --by %q", by)).
+			WithSuggestions("Use one of: " + strings.Join(names, ", "))
+	}
+	if format != "text" && format != "json" {
+		return errors.NewCLIError(fmt.Sprintf("invalid --format %q", format)).
+			WithSuggestions("Use text or json")
+	}
+	from, err := parseSince(since)
+	if err != nil {
+		return err
+	}
+	events, err := readStats(from)
+	if err != nil {
+		return errors.NewCLIError("cannot read usage stats").WithCause(err)
+	}
+	rows := summarizeStats(events, key)
+	if format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+	if len(rows) == 0 {
+		if !statsEnabled() {
+			_, _ = fmt.Fprintln(w, "No usage recorded. Usage stats are off; turn them on with: arc-ask stats on")
+			return nil
+		}
+		_, _ = fmt.Fprintln(w, "No usage recorded yet")
+		return nil
+	}
+	return writeStatsTable(w, by, rows)
+}
+
+func newUsageCmd() *cobra.Command {
+	var by, since, format string
+
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Summarize local usage stats by command, template, or model",
+		Long: `Summarize the usage stats recorded on this machine while they are on (see
+arc-ask stats): runs, failures, and latency, grouped by command, template,
+model, provider, outcome, or day. Nothing is ever sent anywhere.`,
+		Example: `  arc-ask usage
+  arc-ask usage --by model --since 30d
+  arc-ask usage --by day --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return reportStats(cmd.OutOrStdout(), by, since, format)
+		},
+	}
+	cmd.Flags().StringVar(&by, "by", "command", "Group by command, template, model, provider, outcome, or day")
+	cmd.Flags().StringVar(&since, "since", "", "Only runs in the last `PERIOD` (e.g. 30d, 12h)")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json")
+	return cmd
+}
+
+func newTemplateStatsCmd() *cobra.Command {
+	var since, format string
+
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show how often each template runs, fails, and how long it takes",
+		Long: `Show runs, failures, latency, and last use of each template from the local
+usage stats (see arc-ask stats).`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return reportStats(cmd.OutOrStdout(), "template", since, format)
+		},
+	}
+	cmd.Flags().StringVar(&since, "since", "", "Only runs in the last `PERIOD` (e.g. 30d, 12h)")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json")
+	return cmd
+}
+
+func newStatsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Turn local usage stats on or off, or purge them",
+		Long: `Usage stats are off until turned on. While on, each run records its
+command, template name, provider, model, a latency bucket, and whether it
+failed, in the state directory. Questions, input, and answers are never
+recorded, and nothing leaves this machine. arc-ask usage and
+arc-ask template stats report on them.
+
+ARC_ASK_STATS=off or DO_NOT_TRACK=1 turns them off for a run.`,
+	}
+	cmd.AddCommand(newStatsOnCmd(), newStatsOffCmd(), newStatsStatusCmd(), newStatsPurgeCmd())
+	return cmd
+}
+
+func newStatsOnCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "on",
+		Short: "Start recording usage stats on this machine",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := refuseStateless("stats on"); err != nil {
+				return err
+			}
+			if err := os.MkdirAll(statsDir(), 0o700); err != nil {
+				return errors.NewCLIError("cannot turn usage stats on").WithCause(err)
+			}
+			if _, err := os.Stat(statsSwitchPath()); err != nil {
+				stamp := time.Now().UTC().Format(time.RFC3339) + "\n"
+				if err := os.WriteFile(statsSwitchPath(), []byte(stamp), 0o600); err != nil {
+					return errors.NewCLIError("cannot turn usage stats on").WithCause(err)
+				}
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Usage stats are on, kept in %s\n", statsEventsPath())
+			if statsOverride() {
+				fmt.Fprintln(os.Stderr, "Note: ARC_ASK_STATS or DO_NOT_TRACK keeps them off in this environment")
+			}
+			return nil
+		},
+	}
+}
+
+func newStatsOffCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "off",
+		Short: "Stop recording usage stats; what was recorded is kept until purged",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := refuseStateless("stats off"); err != nil {
+				return err
+			}
+			if err := os.Remove(statsSwitchPath()); err != nil && !os.IsNotExist(err) {
+				return errors.NewCLIError("cannot turn usage stats off").WithCause(err)
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Usage stats are off")
+			return nil
+		},
+	}
+}
+
+func newStatsStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show whether usage stats are on and how much is recorded",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w := cmd.OutOrStdout()
+			state := "off"
+			if stamp, err := os.ReadFile(statsSwitchPath()); err == nil {
+				state = "on since " + strings.TrimSpace(string(stamp))
+				if statsOverride() {
+					state += " (off in this environment)"
+				}
+			}
+			events, err := readStats(time.Time{})
+			if err != nil {
+				return errors.NewCLIError("cannot read usage stats").WithCause(err)
+			}
+			_, _ = fmt.Fprintf(w, "Usage stats: %s\n", state)
+			_, _ = fmt.Fprintf(w, "Recorded:    %d run(s) in %s\n", len(events), statsEventsPath())
+			return nil
+		},
+	}
+}
+
+func newStatsPurgeCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "purge",
+		Short: "Delete every recorded usage stat",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := refuseStateless("stats purge"); err != nil {
+				return err
+			}
+			if err := os.Remove(statsEventsPath()); err != nil && !os.IsNotExist(err) {
+				return errors.NewCLIError("cannot purge usage stats").WithCause(err)
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "Usage stats purged")
+			return nil
+		},
+	}
+}
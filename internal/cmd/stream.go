@@ -0,0 +1,54 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+)
+
+// textStream watches pi's JSON event stream and passes each piece of
+// answer text to fn as it arrives
+type textStream struct {
+	mu      sync.Mutex
+	pending []byte
+	fn      func(delta string)
+}
+
+// Write takes stream output in any chunking and handles complete lines
+func (s *textStream) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = append(s.pending, p...)
+	for {
+		i := bytes.IndexByte(s.pending, '\n')
+		if i < 0 {
+			break
+		}
+		s.line(s.pending[:i])
+		s.pending = s.pending[i+1:]
+	}
+	return len(p), nil
+}
+
+func (s *textStream) line(line []byte) {
+	line = bytes.TrimSpace(line)
+	if !bytes.HasPrefix(line, []byte("{")) || !bytes.Contains(line, []byte(`"text_delta"`)) {
+		return
+	}
+	var event struct {
+		Type  string `json:"type"`
+		Event struct {
+			Type  string `json:"type"`
+			Delta string `json:"delta"`
+		} `json:"assistantMessageEvent"`
+	}
+	if json.Unmarshal(line, &event) != nil {
+		return
+	}
+	if event.Type == "message_update" && event.Event.Type == "text_delta" && event.Event.Delta != "" {
+		s.fn(event.Event.Delta)
+	}
+}
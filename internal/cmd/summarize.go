@@ -0,0 +1,251 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// summaryLengths are the supported --length values
+var summaryLengths = map[string]string{
+	"short":   "Write the summary as two or three sentences.",
+	"bullets": "Write the summary as 3 to 7 concise bullet points, most important first.",
+	"1p":      "Write the summary as one paragraph of about 100 to 150 words.",
+}
+
+// summaryFocuses are tuned instructions for common --focus values; any
+// other value is passed through as the focus
+var summaryFocuses = map[string]string{
+	"errors":    "Focus on errors, failures, and warnings: quote the exact messages, say how often they repeat and when they started, and name the likely cause. Skip routine output.",
+	"actions":   "Focus on action items: what needs to be done, by whom if stated, and any deadlines.",
+	"decisions": "Focus on decisions that were made, the reasons given, and open questions that remain.",
+	"changes":   "Focus on what changed: behavior, interfaces, configuration, and anything that breaks compatibility.",
+}
+
+const summarizeInstructions = `Summarize the following input for an engineer who has not read it. Lead with what matters most, keep exact names, numbers, and error messages, and do not pad with generic advice.`
+
+const summarizeChunkInstructions = `This is part %d of %d of a longer input. Write dense notes on everything in this part that belongs in a summary of the whole: keep exact names, numbers, timestamps, and error messages, and note anything that seems to continue from or into other parts. Notes only, no introduction.`
+
+const summarizeMergeInstructions = `These are notes on consecutive parts of one long input, in order. Write a single summary of the whole input from them, for an engineer who has not read it. Merge repeated points, keep exact names, numbers, and error messages, and lead with what matters most.`
+
+const (
+	// defaultSummaryChunk is the chunk size in tokens when the model's
+	// context window is unknown
+	defaultSummaryChunk = 12000
+	minSummaryChunk     = 2000
+	maxSummaryChunk     = 50000
+	summarizeParallel   = 4
+)
+
+func newSummarizeCmd(client *BridgeClient) *cobra.Command {
+	var (
+		length       string
+		focus        string
+		pane         string
+		lines        int
+		contextFiles []string
+		chunkTokens  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "summarize",
+		Short: "Summarize piped input, a pane, or files",
+		Long: `Summarize the input with prompts tuned for logs, diffs, and documents.
+
+Inputs too long for one request are split into chunks that are summarized
+separately and then merged. The chunk size defaults to a quarter of the
+model's context window (12k tokens when it is unknown).
+
+Focus presets: errors, actions, decisions, changes. Any other --focus text
+is used as is.`,
+		Example: `  kubectl logs deploy/api | arc-ask summarize --focus errors
+  arc-ask summarize --pane dev:0.1 --lines 2000 --length short
+  arc-ask summarize -c docs/design.md --length 1p
+  git log -p v1.2.0..HEAD | arc-ask summarize --focus changes`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			lengthHint, ok := summaryLengths[length]
+			if !ok {
+				return errors.NewCLIError(fmt.Sprintf("invalid --length %q", length)).
+					WithSuggestions("Use one of: short, bullets, 1p")
+			}
+			if chunkTokens != 0 && chunkTokens < minSummaryChunk {
+				return errors.NewCLIError(fmt.Sprintf("--chunk-tokens must be at least %d", minSummaryChunk))
+			}
+
+			input, err := gatherInput(cmd, pane, lines, captureFilter{})
+			if err != nil {
+				return err
+			}
+			input, _, err = mergeContext(input, contextFiles, contextOptions{})
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(input) == "" {
+				return errors.NewCLIError("no input to summarize").
+					WithSuggestions("Pipe input: cat app.log | arc-ask summarize", "Or use --pane or -c FILE")
+			}
+
+			if chunkTokens == 0 {
+				chunkTokens = summaryChunkSize(client)
+			}
+			chunks := splitChunks(input, chunkTokens)
+			if len(chunks) > 1 {
+				fmt.Fprintf(os.Stderr, "Input is ~%s tokens; summarizing in %d chunks\n", formatTokens(ask.EstimateTokens(input)), len(chunks))
+			}
+
+			focusHint := ""
+			if focus != "" {
+				focusHint = summaryFocusHint(focus)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), client.timeout*time.Duration(1+(len(chunks)+summarizeParallel-1)/summarizeParallel))
+			defer cancel()
+			summary, err := summarizeChunks(ctx, client, chunks, chunkTokens, lengthHint, focusHint)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), strings.TrimSpace(summary))
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&length, "length", "bullets", "Summary length: short, bullets, 1p (one paragraph)")
+	cmd.Flags().StringVar(&focus, "focus", "", "What to emphasize: errors, actions, decisions, changes, or free text")
+	cmd.Flags().StringVar(&pane, "pane", "", "Capture from tmux pane (e.g., session:0.0)")
+	cmd.Flags().IntVar(&lines, "lines", 200, "Lines to capture from pane")
+	cmd.Flags().StringArrayVarP(&contextFiles, "context", "c", nil, "Add context file(s)")
+	cmd.Flags().IntVar(&chunkTokens, "chunk-tokens", 0, "Tokens per chunk for long inputs (0 = from the model's context window)")
+	_ = cmd.RegisterFlagCompletionFunc("length", cobra.FixedCompletions([]string{"short", "bullets", "1p"}, cobra.ShellCompDirectiveNoFileComp))
+	_ = cmd.RegisterFlagCompletionFunc("focus", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		names := make([]string, 0, len(summaryFocuses))
+		for name := range summaryFocuses {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+// summaryFocusHint is the instruction for a --focus value
+func summaryFocusHint(focus string) string {
+	if hint, ok := summaryFocuses[focus]; ok {
+		return hint
+	}
+	return "Focus on: " + focus
+}
+
+// summaryChunkSize leaves room in the model's context for the prompt and
+// the notes it writes
+func summaryChunkSize(client *BridgeClient) int {
+	m, ok := lookupModel(client.provider, client.model)
+	if !ok || m.Context == 0 {
+		return defaultSummaryChunk
+	}
+	return min(max(m.Context/4, minSummaryChunk), maxSummaryChunk)
+}
+
+// splitChunks splits text at line boundaries into pieces of about
+// maxTokens; longer lines are cut
+func splitChunks(text string, maxTokens int) []string {
+	if ask.EstimateTokens(text) <= maxTokens {
+		return []string{text}
+	}
+	var (
+		chunks []string
+		b      strings.Builder
+		tokens int
+	)
+	flush := func() {
+		if b.Len() > 0 {
+			chunks = append(chunks, b.String())
+			b.Reset()
+			tokens = 0
+		}
+	}
+	for _, line := range strings.SplitAfter(text, "\n") {
+		n := ask.EstimateTokens(line)
+		if tokens+n > maxTokens {
+			flush()
+		}
+		for n > maxTokens {
+			// Cut an overlong line at roughly maxTokens
+			cut := len(line) * maxTokens / n
+			for cut > 0 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+			chunks = append(chunks, line[:cut])
+			line = line[cut:]
+			n = ask.EstimateTokens(line)
+		}
+		b.WriteString(line)
+		tokens += n
+	}
+	flush()
+	return chunks
+}
+
+// summarizeChunks summarizes one chunk directly, or takes notes on each
+// chunk and merges them, repeating while the notes are still too long
+func summarizeChunks(ctx context.Context, client ask.Client, chunks []string, chunkTokens int, lengthHint, focusHint string) (string, error) {
+	instructions := strings.TrimSpace(lengthHint + "\n" + focusHint)
+	if len(chunks) == 1 {
+		return askSummary(ctx, client, summarizeInstructions+"\n"+instructions+"\n\nInput:\n"+chunks[0])
+	}
+
+	notes := make([]string, len(chunks))
+	errs := make([]error, len(chunks))
+	sem := make(chan struct{}, summarizeParallel)
+	var wg sync.WaitGroup
+	for i, chunk := range chunks {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, chunk string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			prompt := fmt.Sprintf(summarizeChunkInstructions, i+1, len(chunks))
+			if focusHint != "" {
+				prompt += "\n" + focusHint
+			}
+			notes[i], errs[i] = askSummary(ctx, client, prompt+"\n\nInput:\n"+chunk)
+		}(i, chunk)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return "", err
+		}
+	}
+
+	for i := range notes {
+		notes[i] = fmt.Sprintf("Notes on part %d:\n%s", i+1, strings.TrimSpace(notes[i]))
+	}
+	merged := strings.Join(notes, "\n\n")
+	// Notes that still do not fit are summarized again, as long as that
+	// makes progress
+	if next := splitChunks(merged, chunkTokens); len(next) > 1 && len(next) < len(chunks) {
+		return summarizeChunks(ctx, client, next, chunkTokens, lengthHint, focusHint)
+	}
+	return askSummary(ctx, client, summarizeMergeInstructions+"\n"+instructions+"\n\n"+merged)
+}
+
+func askSummary(ctx context.Context, client ask.Client, prompt string) (string, error) {
+	answer, err := client.Ask(ctx, prompt)
+	if err != nil {
+		return "", errors.NewCLIError("AI query failed").WithCause(err)
+	}
+	return answer, nil
+}
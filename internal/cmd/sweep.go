@@ -0,0 +1,277 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+const sweepInstructions = `This is the recent output of tmux pane %s, running %s. Start your answer with one sentence stating the most important finding, or that nothing needs attention.`
+
+// maxFindingLength keeps the table to one line per pane
+const maxFindingLength = 100
+
+// Sweep statuses besides the lowercased verdicts
+const (
+	sweepEmpty = "empty"
+	sweepError = "error"
+)
+
+// tmuxPane is a pane listed by tmux
+type tmuxPane struct {
+	Target  string // session:window.pane
+	Command string
+}
+
+// sweepResult is one pane's row in the sweep report
+type sweepResult struct {
+	Pane     string `json:"pane"`
+	Command  string `json:"command,omitempty"`
+	Status   string `json:"status"` // pass, warn, fail, empty, or error
+	Finding  string `json:"finding,omitempty"`
+	Response string `json:"response,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+func newSweepCmd(client *BridgeClient) *cobra.Command {
+	var (
+		session  string
+		lines    int
+		parallel int
+		format   string
+		vars     []string
+		plan     bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "sweep [@template|question]",
+		Short: "Check every pane in a tmux session at once",
+		Long: `Capture every pane in a tmux session and ask the same question of each
+concurrently, then print one row per pane: its status (from the answer's
+VERDICT line) and the main finding. Empty panes are skipped.
+
+Without a question, each pane is checked for errors, failures, and
+stuck processes. The command fails when any pane's verdict is FAIL.`,
+		Example: `  arc-ask sweep --session dev
+  arc-ask sweep --session dev @check-health
+  arc-ask sweep --session dev "are any tests failing?" --format json
+  arc-ask sweep --session dev --plan`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "text" && format != "json" {
+				return errors.NewCLIError(fmt.Sprintf("invalid --format %q", format)).
+					WithSuggestions("Use text or json")
+			}
+			if parallel < 1 {
+				return errors.NewCLIError("--parallel must be at least 1")
+			}
+			templateVars, err := parseVars(vars)
+			if err != nil {
+				return err
+			}
+			question := "Is anything in this output failing, erroring, or stuck? Quote the relevant lines."
+			if len(args) > 0 {
+				question = args[0]
+			}
+
+			panes, err := listSessionPanes(session)
+			if err != nil {
+				return err
+			}
+
+			if plan {
+				return writePlan(cmd.OutOrStdout(), planSweep(client, panes, question, templateVars, lines), format)
+			}
+
+			ctx, cancel := interruptibleContext(client.timeout)
+			defer cancel()
+			results := sweepPanes(ctx, client, panes, question, templateVars, lines, parallel)
+
+			if format == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(results); err != nil {
+					return err
+				}
+			} else if err := writeSweepTable(cmd.OutOrStdout(), results); err != nil {
+				return err
+			}
+
+			failed := 0
+			for _, r := range results {
+				if r.Status == strings.ToLower(VerdictFail) {
+					failed++
+				}
+			}
+			if failed > 0 {
+				return coded(codeCheckFailed, errors.NewCLIError(fmt.Sprintf("%d of %d panes need attention", failed, len(results))))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&session, "session", "", "tmux session to sweep (required)")
+	cmd.Flags().IntVar(&lines, "lines", 200, "Lines to capture from each pane")
+	cmd.Flags().IntVar(&parallel, "parallel", 8, "Panes checked at once")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Template variable (key=value)")
+	cmd.Flags().BoolVar(&plan, "plan", false, "List the requests with estimated tokens and cost, without sending them")
+	_ = cmd.MarkFlagRequired("session")
+	return cmd
+}
+
+// listSessionPanes lists the panes in every window of a session
+func listSessionPanes(session string) ([]tmuxPane, error) {
+	out, err := execCommand("tmux", "list-panes", "-s", "-t", session,
+		"-F", "#{session_name}:#{window_index}.#{pane_index}\t#{pane_current_command}").Output()
+	if err != nil {
+		return nil, errors.NewCLIError(fmt.Sprintf("cannot list panes in session %q", session)).
+			WithCause(err).
+			WithSuggestions("Check the session exists: tmux ls")
+	}
+	var panes []tmuxPane
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		target, command, _ := strings.Cut(line, "\t")
+		if target != "" {
+			panes = append(panes, tmuxPane{Target: target, Command: command})
+		}
+	}
+	if len(panes) == 0 {
+		return nil, errors.NewCLIError(fmt.Sprintf("session %q has no panes", session))
+	}
+	return panes, nil
+}
+
+// sweepPanes captures and checks each pane, at most parallel at a time
+func sweepPanes(ctx context.Context, client *BridgeClient, panes []tmuxPane, question string, vars map[string]string, lines, parallel int) []sweepResult {
+	results := make([]sweepResult, len(panes))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, p := range panes {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, p tmuxPane) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = sweepPane(ctx, client, p, question, vars, lines)
+		}(i, p)
+	}
+	wg.Wait()
+	return results
+}
+
+func sweepPane(ctx context.Context, client *BridgeClient, p tmuxPane, question string, vars map[string]string, lines int) sweepResult {
+	r := sweepResult{Pane: p.Target, Command: p.Command}
+	failed := func(err error) sweepResult {
+		r.Status, r.Error = sweepError, err.Error()
+		return r
+	}
+
+	asker, prompt, err := sweepPrompt(client, p, question, vars, lines)
+	if err != nil {
+		return failed(err)
+	}
+	if prompt == "" {
+		r.Status = sweepEmpty
+		return r
+	}
+	answer, err := asker.Ask(ctx, prompt)
+	if err != nil {
+		return failed(err)
+	}
+
+	r.Response = strings.TrimSpace(answer)
+	r.Status = sweepError
+	if verdict, ok := parseVerdict(answer); ok {
+		r.Status = strings.ToLower(verdict)
+	} else {
+		r.Error = "answer has no VERDICT line"
+	}
+	r.Finding = sweepFinding(answer)
+	return r
+}
+
+// sweepPrompt captures a pane and builds the prompt that checks it, with
+// the client to send it with; the prompt is empty when the pane is
+func sweepPrompt(client *BridgeClient, p tmuxPane, question string, vars map[string]string, lines int) (*BridgeClient, string, error) {
+	content, err := capturePane(p.Target, captureFilter{mode: captureSmart}.scrollback(lines))
+	if err != nil {
+		return nil, "", err
+	}
+	content = captureFilter{mode: captureSmart}.apply(content, lines)
+	if strings.TrimSpace(content) == "" {
+		return nil, "", nil
+	}
+
+	system, user, err := buildPrompt(question, content, vars)
+	if err != nil {
+		return nil, "", err
+	}
+	user = fmt.Sprintf(sweepInstructions, p.Target, p.Command) + "\n\n" + user
+	if !strings.Contains(user, ask.VerdictInstructions) {
+		user += "\n\n" + ask.VerdictInstructions
+	}
+	asker, prompt := client.shapePrompt(system, user)
+	return asker, prompt, nil
+}
+
+// planSweep captures each pane and prices its check without sending it
+func planSweep(client *BridgeClient, panes []tmuxPane, question string, vars map[string]string, lines int) *runPlan {
+	plan := newRunPlan(client)
+	for _, p := range panes {
+		name := p.Target
+		if p.Command != "" {
+			name += " (" + p.Command + ")"
+		}
+		asker, prompt, err := sweepPrompt(client, p, question, vars, lines)
+		switch {
+		case err != nil:
+			plan.skip(name, err.Error())
+		case prompt == "":
+			plan.skip(name, "empty pane")
+		default:
+			plan.addPrompt(client, name, asker.folded(prompt))
+		}
+	}
+	return plan
+}
+
+// sweepFinding is the answer's first sentence, shortened for the table
+func sweepFinding(answer string) string {
+	for _, line := range strings.Split(answer, "\n") {
+		line = strings.Trim(strings.TrimSpace(line), "#*- ")
+		if line == "" || verdictPattern.MatchString(line) {
+			continue
+		}
+		finding := firstSentence(line)
+		if len(finding) > maxFindingLength {
+			finding = strings.TrimSpace(finding[:maxFindingLength-3]) + "..."
+		}
+		return finding
+	}
+	return ""
+}
+
+func writeSweepTable(w io.Writer, results []sweepResult) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "PANE\tCOMMAND\tSTATUS\tFINDING")
+	for _, r := range results {
+		finding := r.Finding
+		if r.Error != "" && finding == "" {
+			finding = r.Error
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Pane, r.Command, r.Status, finding)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,80 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+)
+
+const (
+	// staleTempAge is when a temp file left by a killed run is removed
+	staleTempAge = time.Hour
+	// tempPrefix marks the files arc-ask may clean up
+	tempPrefix = "arc-ask-"
+)
+
+// privateTempDir returns the directory for arc-ask's temp files: temp_dir
+// from ask.yaml, else $XDG_RUNTIME_DIR/arc-ask, else a per-user directory
+// under the system temp dir. It is created 0700 and refused if it is a
+// symlink or cannot be made private. Files left by killed runs are removed.
+func privateTempDir() (string, error) {
+	dir := ""
+	if cfg, err := loadConfig(); err == nil && cfg.TempDir != "" {
+		dir = ask.ExpandHome(cfg.TempDir)
+	} else if runtime := os.Getenv("XDG_RUNTIME_DIR"); runtime != "" {
+		dir = filepath.Join(runtime, "arc-ask")
+	} else {
+		dir = filepath.Join(os.TempDir(), "arc-ask-"+strconv.Itoa(os.Getuid()))
+	}
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("create temp dir: %w", err)
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return "", fmt.Errorf("check temp dir: %w", err)
+	}
+	if info.Mode()&os.ModeSymlink != 0 || !info.IsDir() {
+		return "", fmt.Errorf("temp dir %s is not a directory", dir)
+	}
+	// Tighten an existing directory; this fails if it is someone else's
+	if info.Mode().Perm()&0o077 != 0 {
+		if err := os.Chmod(dir, 0o700); err != nil {
+			return "", fmt.Errorf("temp dir %s is readable by others and cannot be made private: %w", dir, err)
+		}
+	}
+	removeStaleTemp(dir)
+	return dir, nil
+}
+
+// removeStaleTemp deletes arc-ask temp files old enough that the run that
+// made them must have been killed. Sandboxes are kept while the run that
+// made them is alive, as tool runs can outlast the age limit.
+func removeStaleTemp(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if !strings.HasPrefix(e.Name(), tempPrefix) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil || time.Since(info.ModTime()) <= staleTempAge {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		if strings.HasPrefix(e.Name(), sandboxPrefix) && sandboxInUse(path) {
+			continue
+		}
+		_ = os.RemoveAll(path)
+	}
+}
@@ -0,0 +1,474 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	maxTemplateIndex     = 4 << 20
+	maxTemplatePack      = 16 << 20
+	templateFetchTimeout = time.Minute
+)
+
+// templateIndex lists community template packs; it may be JSON or YAML
+type templateIndex struct {
+	Packs []templatePack `yaml:"packs"`
+}
+
+// templatePack is one installable pack: a .tar.gz of template files
+type templatePack struct {
+	Name        string   `yaml:"name"`
+	Description string   `yaml:"description"`
+	Rating      float64  `yaml:"rating"`
+	Templates   []string `yaml:"templates"`
+	URL         string   `yaml:"url"` // absolute, or relative to the index
+	SHA256      string   `yaml:"sha256"`
+}
+
+// packTemplate is a template read from a downloaded pack
+type packTemplate struct {
+	name     string // file name without the extension
+	template *ask.Template
+	pins     []string // model or provider settings found in the file
+}
+
+func newTemplateCmd(client *BridgeClient) *cobra.Command {
+	var index string
+
+	cmd := &cobra.Command{
+		Use:   "template",
+		Short: "Browse and install community template packs, and compare versions",
+		Long: `Browse a template index and install packs from it. The index is a JSON or
+YAML document listing packs by name with a description, rating, download
+URL, and SHA-256 checksum. Set its location with template_index in
+` + defaultConfigPath + ` or --index; it may be a URL or a local file.
+
+template try compares two versions of a template on the same input, and
+template stats shows how each template is used.`,
+	}
+	cmd.PersistentFlags().StringVar(&index, "index", "", "Template index URL or file (default: template_index from the config)")
+	cmd.AddCommand(newTemplateBrowseCmd(&index), newTemplateInstallCmd(&index), newTemplateTryCmd(client), newTemplateStatsCmd())
+	return cmd
+}
+
+func newTemplateBrowseCmd(index *string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "browse [QUERY]",
+		Short: "List template packs in the index, best rated first",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			idx, _, err := loadTemplateIndex(*index)
+			if err != nil {
+				return err
+			}
+			packs := idx.Packs
+			if len(args) == 1 {
+				query := strings.ToLower(args[0])
+				packs = nil
+				for _, p := range idx.Packs {
+					text := strings.ToLower(p.Name + " " + p.Description + " " + strings.Join(p.Templates, " "))
+					if strings.Contains(text, query) {
+						packs = append(packs, p)
+					}
+				}
+			}
+			if len(packs) == 0 {
+				_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No template packs found")
+				return nil
+			}
+			sort.SliceStable(packs, func(i, j int) bool { return packs[i].Rating > packs[j].Rating })
+
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "NAME\tRATING\tTEMPLATES\tDESCRIPTION")
+			for _, p := range packs {
+				rating := "-"
+				if p.Rating > 0 {
+					rating = fmt.Sprintf("%.1f", p.Rating)
+				}
+				templates := "-"
+				if len(p.Templates) > 0 {
+					templates = fmt.Sprint(len(p.Templates))
+				}
+				_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", p.Name, rating, templates, p.Description)
+			}
+			if err := tw.Flush(); err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), "\nInstall with: arc-ask template install NAME")
+			return nil
+		},
+	}
+}
+
+func newTemplateInstallCmd(index *string) *cobra.Command {
+	var (
+		yes   bool
+		force bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "install NAME",
+		Short: "Install a template pack after checking and reviewing it",
+		Long: `Download a pack from the index, verify it against the index's SHA-256
+checksum, and check that every template in it parses. A review is shown
+before anything is written: the templates, which installed ones they
+replace, and any model or provider pinning they include. Templates you
+already have are only replaced with --force.
+
+A pack is installed in its own namespace, a subdirectory of the template
+directory, so its templates are used as @PACK/NAME and never shadow yours
+or the built-ins.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := refuseStateless("template install"); err != nil {
+				return err
+			}
+			idx, base, err := loadTemplateIndex(*index)
+			if err != nil {
+				return err
+			}
+			var pack *templatePack
+			for i := range idx.Packs {
+				if idx.Packs[i].Name == args[0] {
+					pack = &idx.Packs[i]
+				}
+			}
+			if pack == nil {
+				return errors.NewCLIError(fmt.Sprintf("no template pack %q in the index", args[0])).
+					WithSuggestions("List packs: arc-ask template browse")
+			}
+			if strings.ContainsAny(pack.Name, `/\`) || pack.Name == "" || strings.HasPrefix(pack.Name, ".") {
+				return errors.NewCLIError(fmt.Sprintf("invalid pack name %q in the index", pack.Name))
+			}
+			if pack.SHA256 == "" {
+				return errors.NewCLIError(fmt.Sprintf("pack %q has no checksum in the index; refusing to install", pack.Name))
+			}
+
+			location, err := resolveIndexRef(base, pack.URL)
+			if err != nil {
+				return errors.NewCLIError(fmt.Sprintf("invalid url for pack %q", pack.Name)).WithCause(err)
+			}
+			data, err := fetchIndexResource(location, maxTemplatePack)
+			if err != nil {
+				return errors.NewCLIError("failed to download pack " + pack.Name).WithCause(err)
+			}
+			sum := sha256.Sum256(data)
+			if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, pack.SHA256) {
+				return errors.NewCLIError(fmt.Sprintf("checksum mismatch for pack %q", pack.Name)).
+					WithCause(fmt.Errorf("index has %s, download is %s", pack.SHA256, got)).
+					WithSuggestions("The pack changed since the index was published; do not install it until the index is updated")
+			}
+
+			staging, err := os.MkdirTemp("", "arc-ask-pack-*")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(staging)
+			templates, err := unpackTemplates(data, staging)
+			if err != nil {
+				return errors.NewCLIError("invalid pack " + pack.Name).WithCause(err)
+			}
+
+			dir := filepath.Join(ask.ExpandHome(templateDir()), pack.Name)
+			conflicts := reviewPack(cmd.OutOrStdout(), pack, templates, staging, dir)
+			if len(conflicts) > 0 && !force {
+				return errors.NewCLIError(fmt.Sprintf("pack %q would replace installed files: %s", pack.Name, strings.Join(conflicts, ", "))).
+					WithSuggestions("Replace them with: arc-ask template install " + pack.Name + " --force")
+			}
+			if !yes {
+				ok, err := confirm("Install these templates?")
+				if err != nil {
+					return errors.NewCLIError(err.Error())
+				}
+				if !ok {
+					return nil
+				}
+			}
+
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return errors.NewCLIError("failed to create template directory").WithCause(err)
+			}
+			entries, err := os.ReadDir(staging)
+			if err != nil {
+				return err
+			}
+			for _, e := range entries {
+				data, err := os.ReadFile(filepath.Join(staging, e.Name()))
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(filepath.Join(dir, e.Name()), data, 0o644); err != nil {
+					return errors.NewCLIError("failed to install templates").WithCause(err)
+				}
+			}
+			for _, t := range templates {
+				_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Installed @%s/%s\n", pack.Name, t.name)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Install without confirmation")
+	cmd.Flags().BoolVar(&force, "force", false, "Replace templates you already have")
+	return cmd
+}
+
+// loadTemplateIndex reads the index and returns it with the location
+// relative pack URLs resolve against
+func loadTemplateIndex(flag string) (*templateIndex, string, error) {
+	location := flag
+	if location == "" {
+		if cfg, err := loadConfig(); err == nil {
+			location = cfg.TemplateIndex
+		}
+	}
+	if location == "" {
+		return nil, "", errors.NewCLIError("no template index configured").
+			WithSuggestions("Set template_index in "+displayPath(configPath()), "Or pass --index URL")
+	}
+	data, err := fetchIndexResource(location, maxTemplateIndex)
+	if err != nil {
+		return nil, "", errors.NewCLIError("failed to read template index").WithCause(err)
+	}
+	var idx templateIndex
+	if err := yaml.Unmarshal(data, &idx); err != nil {
+		return nil, "", errors.NewCLIError("invalid template index " + location).WithCause(err)
+	}
+	return &idx, location, nil
+}
+
+// resolveIndexRef resolves a pack URL against the index location
+func resolveIndexRef(base, ref string) (string, error) {
+	if ref == "" {
+		return "", fmt.Errorf("missing url")
+	}
+	if isURL(ref) || filepath.IsAbs(ref) {
+		return ref, nil
+	}
+	if isURL(base) {
+		b, err := url.Parse(base)
+		if err != nil {
+			return "", err
+		}
+		r, err := url.Parse(ref)
+		if err != nil {
+			return "", err
+		}
+		return b.ResolveReference(r).String(), nil
+	}
+	return filepath.Join(filepath.Dir(ask.ExpandHome(base)), ref), nil
+}
+
+// fetchIndexResource reads a URL or local file, up to limit bytes
+func fetchIndexResource(location string, limit int64) ([]byte, error) {
+	var r io.Reader
+	if isURL(location) {
+		ctx, cancel := context.WithTimeout(context.Background(), templateFetchTimeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, location, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("GET %s: %s", location, resp.Status)
+		}
+		r = resp.Body
+	} else {
+		f, err := os.Open(ask.ExpandHome(location))
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%s is larger than %d MB", location, limit>>20)
+	}
+	return data, nil
+}
+
+// unpackTemplates extracts the template and schema files of a .tar.gz pack
+// into dir, flattening directories, and checks every template loads
+func unpackTemplates(data []byte, dir string) ([]packTemplate, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	tr := tar.NewReader(gz)
+	seen := make(map[string]bool)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Base(h.Name)
+		switch filepath.Ext(name) {
+		case ".yaml", ".yml", ".json":
+		default:
+			continue
+		}
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("pack has two files named %s", name)
+		}
+		seen[name] = true
+		content, err := io.ReadAll(io.LimitReader(tr, maxTemplatePack))
+		if err != nil {
+			return nil, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), content, 0o644); err != nil {
+			return nil, err
+		}
+	}
+
+	store := &ask.Templates{Dir: dir}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var out []packTemplate
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if ext != ".yaml" && ext != ".yml" {
+			continue
+		}
+		t, err := store.Load(strings.TrimSuffix(e.Name(), ext))
+		if err != nil {
+			return nil, err
+		}
+		raw, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, packTemplate{name: strings.TrimSuffix(e.Name(), ext), template: t, pins: modelPins(raw)})
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("pack contains no templates")
+	}
+	return out, nil
+}
+
+// modelPins finds model and provider settings in a template file, at the
+// top level or under defaults
+func modelPins(data []byte) []string {
+	var raw map[string]any
+	if yaml.Unmarshal(data, &raw) != nil {
+		return nil
+	}
+	var pins []string
+	scan := func(m map[string]any, prefix string) {
+		for _, key := range []string{"provider", "model", "models"} {
+			if v, ok := m[key]; ok {
+				pins = append(pins, fmt.Sprintf("%s%s: %v", prefix, key, v))
+			}
+		}
+	}
+	scan(raw, "")
+	if d, ok := raw["defaults"].(map[string]any); ok {
+		scan(d, "defaults.")
+	}
+	return pins
+}
+
+// reviewPack describes what installing a pack does and returns the
+// installed files it would replace
+func reviewPack(w io.Writer, pack *templatePack, templates []packTemplate, staging, dir string) []string {
+	_, _ = fmt.Fprintf(w, "Pack %s (sha256 verified)\n", pack.Name)
+	if pack.Description != "" {
+		_, _ = fmt.Fprintf(w, "  %s\n", pack.Description)
+	}
+	_, _ = fmt.Fprintf(w, "\nTemplates, installed to %s:\n", dir)
+
+	var conflicts []string
+	pinned := false
+	for _, pt := range templates {
+		t := pt.template
+		note := ""
+		name := pack.Name + "/" + pt.name
+		if fileExists(filepath.Join(dir, pt.name+".yaml")) || fileExists(filepath.Join(dir, pt.name+".yml")) {
+			note = " (replaces the installed one)"
+			conflicts = append(conflicts, "@"+name)
+		}
+		_, _ = fmt.Fprintf(w, "  @%-20s %s%s\n", name, t.Description, note)
+		if t.Defaults != nil {
+			var flags []string
+			for name, value := range defaultFlags(t.Defaults) {
+				flags = append(flags, "--"+name+"="+value)
+			}
+			sort.Strings(flags)
+			if len(flags) > 0 {
+				_, _ = fmt.Fprintf(w, "  %-21s defaults: %s\n", "", strings.Join(flags, " "))
+			}
+		}
+		for _, pin := range pt.pins {
+			_, _ = fmt.Fprintf(w, "  %-21s pins %s\n", "", pin)
+			pinned = true
+		}
+	}
+	if entries, err := os.ReadDir(staging); err == nil {
+		for _, e := range entries {
+			if filepath.Ext(e.Name()) != ".json" {
+				continue
+			}
+			note := ""
+			if fileExists(filepath.Join(dir, e.Name())) {
+				note = " (replaces the installed one)"
+				conflicts = append(conflicts, e.Name())
+			}
+			_, _ = fmt.Fprintf(w, "  %-21s %s\n", e.Name(), "schema"+note)
+		}
+	}
+
+	if pinned {
+		_, _ = fmt.Fprintln(w, "\nModel pinning is not applied: templates run on the provider and model you choose.")
+	} else {
+		_, _ = fmt.Fprintln(w, "\nNo model pinning.")
+	}
+	return conflicts
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
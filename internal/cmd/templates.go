@@ -0,0 +1,113 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// defaultTemplateDir is where user templates are loaded from unless
+// ask.yaml sets template_dir: prompts/ in the config directory
+func defaultTemplateDir() string {
+	return filepath.Join(configDir(), "prompts")
+}
+
+// userTemplates is the template store for the configured template dirs,
+// with parses memoized in the state cache
+var userTemplates = sync.OnceValue(func() *ask.Templates {
+	var roots []string
+	if c, err := loadConfig(); err == nil {
+		for _, r := range c.TemplateRoots {
+			roots = append(roots, ask.ExpandHome(r))
+		}
+	}
+	cachePath := filepath.Join(cacheDir(), "templates.json")
+	if stateless {
+		cachePath = ""
+	}
+	return &ask.Templates{
+		Dir:       ask.ExpandHome(templateDir()),
+		Roots:     roots,
+		CachePath: cachePath,
+		Warn: func(msg string) {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", msg)
+		},
+	}
+})
+
+// loadTemplate resolves a template by name, suggesting where to create it
+// when it does not exist
+func loadTemplate(name string) (*ask.Template, error) {
+	t, err := userTemplates().Load(name)
+	if nf, ok := err.(*ask.NotFoundError); ok {
+		if len(nf.Qualified) > 0 {
+			return nil, coded(codeTemplateNotFound, errors.NewCLIError(nf.Error()).
+				WithSuggestions("Packs have one: @"+strings.Join(nf.Qualified, ", @")))
+		}
+		return nil, coded(codeTemplateNotFound, errors.NewCLIError(nf.Error()).
+			WithSuggestions(
+				"List templates: arc-ask --list-templates",
+				"Create one in: "+displayPath(ask.ExpandHome(templateDir()))+"/"+nf.Name+".yaml",
+			))
+	}
+	return t, err
+}
+
+// buildPrompt resolves an @template or plain question into system and user prompts
+func buildPrompt(arg, input string, vars map[string]string) (string, string, error) {
+	if ask.IsTemplateRef(arg) {
+		// Resolve first for the CLI's not-found suggestions
+		if _, err := loadTemplate(arg); err != nil {
+			return "", "", err
+		}
+	}
+	system, user, err := userTemplates().BuildPrompt(arg, input, vars)
+	return system, user, coded(codeTemplateInvalid, err)
+}
+
+// parseVars converts key=value flag values into a map
+func parseVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		k, v, ok := strings.Cut(p, "=")
+		if !ok || k == "" {
+			return nil, errors.NewCLIError(fmt.Sprintf("invalid --var %q", p)).
+				WithSuggestions("Format: --var key=value")
+		}
+		vars[k] = v
+	}
+	return vars, nil
+}
+
+// completeVars completes --var for the template named in the first argument:
+// enum variables offer name=choice, others name=
+func completeVars(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 || !ask.IsTemplateRef(args[0]) {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	t, err := loadTemplate(args[0])
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var out []string
+	for _, v := range t.Vars {
+		if v.Type == ask.VarEnum {
+			for _, c := range v.Choices {
+				out = append(out, v.Name+"="+c+"\t"+v.Description)
+			}
+			continue
+		}
+		out = append(out, v.Name+"=\t"+v.Description)
+	}
+	return out, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
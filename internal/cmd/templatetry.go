@@ -0,0 +1,204 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// tryRun is one template version's prompt and answer
+type tryRun struct {
+	Version      string `json:"version"`
+	Prompt       string `json:"prompt"`
+	Answer       string `json:"answer"`
+	PromptTokens int    `json:"prompt_tokens"`
+	AnswerTokens int    `json:"answer_tokens"`
+}
+
+// tryResult compares two template versions on one input
+type tryResult struct {
+	Runs       [2]tryRun `json:"runs"`
+	PromptDiff []diffOp  `json:"prompt_diff"`
+	AnswerDiff []diffOp  `json:"answer_diff"`
+}
+
+// loadTemplateVersion resolves --against: a template file, NAME@REV for
+// the template's file at a git revision, or another template's name
+func loadTemplateVersion(ref string) (*ask.Template, error) {
+	if strings.HasSuffix(ref, ".yaml") || strings.HasSuffix(ref, ".yml") {
+		data, err := os.ReadFile(ask.ExpandHome(ref))
+		if err != nil {
+			return nil, errors.NewCLIError("cannot read " + ref).WithCause(err)
+		}
+		name := strings.TrimSuffix(filepath.Base(ref), filepath.Ext(ref))
+		return ask.ParseTemplate(name, ref, data)
+	}
+
+	name, rev, ok := strings.Cut(strings.TrimPrefix(ref, "@"), "@")
+	current, err := loadTemplate("@" + name)
+	if err != nil || !ok {
+		return current, err
+	}
+	if current.Path == "" {
+		return nil, errors.NewCLIError(fmt.Sprintf("@%s is built in and has no revisions", name))
+	}
+	dir, file := filepath.Split(current.Path)
+	data, err := execCommand("git", "-C", dir, "show", rev+":./"+file).Output()
+	if err != nil {
+		return nil, errors.NewCLIError(fmt.Sprintf("cannot read @%s at %s", name, rev)).WithCause(err).
+			WithSuggestions("NAME@REV needs the template's directory in git; REV is a commit, tag, or e.g. HEAD~1")
+	}
+	return ask.ParseTemplate(current.Name, current.Path, data)
+}
+
+// runTemplateVersion renders t for the input and asks it
+func runTemplateVersion(ctx context.Context, client *BridgeClient, t *ask.Template, label, input string, vars map[string]string) (tryRun, error) {
+	system, user, err := t.Render(ask.TemplateData{Input: input, Vars: vars})
+	if err != nil {
+		return tryRun{}, err
+	}
+	if t.Output != nil {
+		user += "\n\n" + t.Output.Instructions()
+	}
+	asker, sent := client.shapePrompt(system, user)
+	answer, err := asker.Ask(ctx, sent)
+	if err != nil {
+		return tryRun{}, errors.NewCLIError(fmt.Sprintf("%s: AI query failed", label)).WithCause(err)
+	}
+	return tryRun{
+		Version:      label,
+		Prompt:       client.JoinPrompt(system, user),
+		Answer:       answer,
+		PromptTokens: ask.EstimateTokens(client.JoinPrompt(system, user)),
+		AnswerTokens: ask.EstimateTokens(answer),
+	}, nil
+}
+
+func newTemplateTryCmd(client *BridgeClient) *cobra.Command {
+	var (
+		inputPath string
+		against   string
+		vars      []string
+		view      string
+		width     int
+		format    string
+	)
+	cmd := &cobra.Command{
+		Use:   "try NAME",
+		Short: "Compare two versions of a template on the same input",
+		Long: `Run a template and another version of it on the same input, then show how
+their prompts and answers differ, to speed up prompt iteration.
+
+--against is the other version:
+  NAME@REV    the template's file at a git revision (commit, tag, HEAD~1)
+  FILE.yaml   a template file, such as a draft
+  OTHER       another template
+
+Answers are shown side by side (--view side) or as an inline word diff
+(--view words, [-removed-] and {+added+}). Prompts are always shown as a
+word diff, since their differences are usually small.`,
+		Example: `  arc-ask template try code-review --input sample.diff --against code-review@v1
+  arc-ask template try code-review --input sample.diff --against ./code-review-draft.yaml
+  git diff | arc-ask template try code-review --against code-review@HEAD~1 --format json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if against == "" {
+				return errors.NewCLIError("--against is required").
+					WithSuggestions("Compare with a git revision: --against " + strings.TrimPrefix(args[0], "@") + "@HEAD~1")
+			}
+			if view != "side" && view != "words" {
+				return errors.NewCLIError(fmt.Sprintf("invalid --view %q", view)).WithSuggestions("Use side or words")
+			}
+			if format != "text" && format != "json" {
+				return errors.NewCLIError(fmt.Sprintf("invalid --format %q", format)).WithSuggestions("Use text or json")
+			}
+			templateVars, err := parseVars(vars)
+			if err != nil {
+				return err
+			}
+			var input string
+			if inputPath != "" {
+				data, err := os.ReadFile(inputPath)
+				if err != nil {
+					return errors.NewCLIError("cannot read --input").WithCause(err)
+				}
+				input = string(data)
+			} else if input, err = gatherInput(cmd, "", 0, captureFilter{}); err != nil {
+				return err
+			}
+
+			name := "@" + strings.TrimPrefix(args[0], "@")
+			current, err := loadTemplate(name)
+			if err != nil {
+				return err
+			}
+			other, err := loadTemplateVersion(against)
+			if err != nil {
+				return err
+			}
+
+			ctx, cancel := interruptibleContext(client.timeout)
+			defer cancel()
+			var result tryResult
+			if result.Runs[0], err = runTemplateVersion(ctx, client, current, name, input, templateVars); err != nil {
+				return err
+			}
+			if result.Runs[1], err = runTemplateVersion(ctx, client, other, against, input, templateVars); err != nil {
+				return err
+			}
+			result.PromptDiff = wordDiff(result.Runs[1].Prompt, result.Runs[0].Prompt)
+			result.AnswerDiff = wordDiff(result.Runs[1].Answer, result.Runs[0].Answer)
+
+			if format == "json" {
+				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc.SetIndent("", "  ")
+				return enc.Encode(result)
+			}
+			writeTryResult(cmd.OutOrStdout(), result, view, width)
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&inputPath, "input", "", "Sample input file (default: stdin)")
+	cmd.Flags().StringVar(&against, "against", "", "Version to compare with: NAME@REV, FILE.yaml, or another template")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Template variable (key=value), for both versions")
+	cmd.Flags().StringVar(&view, "view", "side", "Answer diff: side, words")
+	cmd.Flags().IntVar(&width, "width", 160, "Width of the side-by-side view")
+	cmd.Flags().StringVar(&format, "format", "text", "Output format: text, json")
+	return cmd
+}
+
+// writeTryResult shows the older version on the left and NAME on the right
+func writeTryResult(w io.Writer, r tryResult, view string, width int) {
+	old, cur := r.Runs[1], r.Runs[0]
+	_, _ = fmt.Fprintf(w, "%s (~%d prompt, ~%d answer tokens) -> %s (~%d prompt, ~%d answer tokens)\n\n",
+		old.Version, old.PromptTokens, old.AnswerTokens, cur.Version, cur.PromptTokens, cur.AnswerTokens)
+
+	_, _ = fmt.Fprintln(w, "=== prompt ===")
+	if diffChanged(r.PromptDiff) {
+		writeWordDiff(w, r.PromptDiff)
+	} else {
+		_, _ = fmt.Fprintln(w, "(identical)")
+	}
+
+	_, _ = fmt.Fprintln(w, "\n=== answer ===")
+	switch {
+	case !diffChanged(r.AnswerDiff):
+		_, _ = fmt.Fprintln(w, "(identical)")
+		_, _ = fmt.Fprintln(w, cur.Answer)
+	case view == "words":
+		writeWordDiff(w, r.AnswerDiff)
+	default:
+		writeSideBySide(w, old.Answer, cur.Answer, width)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+)
+
+// templateReload is the result of reloading the template directory
+type templateReload struct {
+	Loaded int      `json:"loaded"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// reloadTemplates parses every user template again and logs the ones
+// that fail, which keep failing requests until they are fixed
+func reloadTemplates(templates *ask.Templates) templateReload {
+	n, errs := templates.Reload()
+	r := templateReload{Loaded: n}
+	for _, err := range errs {
+		r.Errors = append(r.Errors, err.Error())
+		fmt.Fprintf(os.Stderr, "Template error: %v\n", err)
+	}
+	return r
+}
+
+// templateSnapshot identifies the template files in each root and its
+// namespaces by modification time and size, the same way the parse cache
+// does
+func templateSnapshot(dirs []string) map[string]string {
+	snap := make(map[string]string)
+	var scan func(dir string, depth int)
+	scan = func(dir string, depth int) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return
+		}
+		for _, e := range entries {
+			path := filepath.Join(dir, e.Name())
+			if e.IsDir() {
+				if depth == 0 {
+					scan(path, 1)
+				}
+				continue
+			}
+			ext := filepath.Ext(e.Name())
+			if ext != ".yaml" && ext != ".yml" {
+				continue
+			}
+			info, err := e.Info()
+			if err != nil {
+				continue
+			}
+			snap[path] = fmt.Sprintf("%d/%d", info.ModTime().UnixNano(), info.Size())
+		}
+	}
+	for _, dir := range dirs {
+		scan(dir, 0)
+	}
+	return snap
+}
+
+// watchTemplates polls the template roots and reloads them when a
+// template is added, changed, or removed
+func watchTemplates(ctx context.Context, templates *ask.Templates, every time.Duration) {
+	last := templateSnapshot(templates.Dirs())
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		snap := templateSnapshot(templates.Dirs())
+		if sameSnapshot(last, snap) {
+			continue
+		}
+		last = snap
+		r := reloadTemplates(templates)
+		fmt.Fprintf(os.Stderr, "Reloaded templates: %d loaded, %d with errors\n", r.Loaded, len(r.Errors))
+	}
+}
+
+func sameSnapshot(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, v := range a {
+		if b[name] != v {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxDiffCells bounds the LCS table; longer texts are compared by line
+const maxDiffCells = 4 << 20
+
+// Diff operations
+const (
+	opEqual  = "="
+	opDelete = "-"
+	opInsert = "+"
+)
+
+// diffOp is a run of tokens kept, removed, or added
+type diffOp struct {
+	Op   string `json:"op"`
+	Text string `json:"text"`
+}
+
+var wordToken = regexp.MustCompile(`\s+|\w+|[^\w\s]`)
+
+// wordDiff compares two texts word by word, or line by line when they
+// are too long for that
+func wordDiff(a, b string) []diffOp {
+	ta, tb := wordToken.FindAllString(a, -1), wordToken.FindAllString(b, -1)
+	if len(ta)*len(tb) > maxDiffCells {
+		ta, tb = splitLinesKeep(a), splitLinesKeep(b)
+	}
+	return diffTokens(ta, tb)
+}
+
+// splitLinesKeep splits text into lines that keep their newline
+func splitLinesKeep(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.SplitAfter(text, "\n")
+}
+
+// diffTokens is a longest-common-subsequence diff, with adjacent tokens
+// of the same operation merged
+func diffTokens(a, b []string) []diffOp {
+	if len(a)*len(b) > maxDiffCells {
+		// Too big to align: everything changed
+		return mergeOps([]diffOp{{opDelete, strings.Join(a, "")}, {opInsert, strings.Join(b, "")}})
+	}
+	// lcs[i][j] is the LCS length of a[i:] and b[j:]
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var ops []diffOp
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, diffOp{opEqual, a[i]})
+			i, j = i+1, j+1
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{opDelete, a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{opInsert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, diffOp{opDelete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, diffOp{opInsert, b[j]})
+	}
+	return mergeOps(ops)
+}
+
+func mergeOps(ops []diffOp) []diffOp {
+	var out []diffOp
+	for _, op := range ops {
+		if op.Text == "" {
+			continue
+		}
+		if n := len(out); n > 0 && out[n-1].Op == op.Op {
+			out[n-1].Text += op.Text
+			continue
+		}
+		out = append(out, op)
+	}
+	return out
+}
+
+// diffChanged reports whether a diff has any insertions or deletions
+func diffChanged(ops []diffOp) bool {
+	for _, op := range ops {
+		if op.Op != opEqual {
+			return true
+		}
+	}
+	return false
+}
+
+// writeWordDiff prints a diff inline, git word-diff style: [-removed-]
+// and {+added+}
+func writeWordDiff(w io.Writer, ops []diffOp) {
+	for _, op := range ops {
+		switch op.Op {
+		case opDelete:
+			_, _ = fmt.Fprintf(w, "[-%s-]", op.Text)
+		case opInsert:
+			_, _ = fmt.Fprintf(w, "{+%s+}", op.Text)
+		default:
+			_, _ = io.WriteString(w, op.Text)
+		}
+	}
+	if n := len(ops); n == 0 || !strings.HasSuffix(ops[n-1].Text, "\n") || ops[n-1].Op != opEqual {
+		_, _ = fmt.Fprintln(w)
+	}
+}
+
+// writeSideBySide prints two texts in columns, aligned by line. The
+// middle column marks lines that differ (|), were removed (<), or added
+// (>).
+func writeSideBySide(w io.Writer, a, b string, width int) {
+	col := (width - 3) / 2
+	if col < 10 {
+		col = 10
+	}
+	la := strings.Split(strings.TrimRight(a, "\n"), "\n")
+	lb := strings.Split(strings.TrimRight(b, "\n"), "\n")
+	ops := diffLines(la, lb)
+	for k := 0; k < len(ops); {
+		if ops[k].Op == opEqual {
+			row(w, ops[k].Text, " ", ops[k].Text, col)
+			k++
+			continue
+		}
+		// Pair a run of removed lines with the added lines after it
+		var dels, ins []string
+		for ; k < len(ops) && ops[k].Op == opDelete; k++ {
+			dels = append(dels, ops[k].Text)
+		}
+		for ; k < len(ops) && ops[k].Op == opInsert; k++ {
+			ins = append(ins, ops[k].Text)
+		}
+		for n := 0; n < max(len(dels), len(ins)); n++ {
+			switch {
+			case n < len(dels) && n < len(ins):
+				row(w, dels[n], "|", ins[n], col)
+			case n < len(dels):
+				row(w, dels[n], "<", "", col)
+			default:
+				row(w, "", ">", ins[n], col)
+			}
+		}
+	}
+}
+
+// diffLines is diffTokens over whole lines, one op per line
+func diffLines(a, b []string) []diffOp {
+	if len(a)*len(b) > maxDiffCells {
+		var ops []diffOp
+		for _, l := range a {
+			ops = append(ops, diffOp{opDelete, l})
+		}
+		for _, l := range b {
+			ops = append(ops, diffOp{opInsert, l})
+		}
+		return ops
+	}
+	// Tag each line with its newline so merged runs split back exactly
+	ta, tb := make([]string, len(a)), make([]string, len(b))
+	for i, l := range a {
+		ta[i] = l + "\n"
+	}
+	for i, l := range b {
+		tb[i] = l + "\n"
+	}
+	var ops []diffOp
+	for _, op := range diffTokens(ta, tb) {
+		for _, l := range strings.Split(strings.TrimSuffix(op.Text, "\n"), "\n") {
+			ops = append(ops, diffOp{op.Op, l})
+		}
+	}
+	return ops
+}
+
+func row(w io.Writer, left, mark, right string, col int) {
+	left = fitColumn(left, col)
+	_, _ = fmt.Fprintf(w, "%s%s %s %s\n", left, strings.Repeat(" ", col-utf8.RuneCountInString(left)), mark, fitColumn(right, col))
+}
+
+// fitColumn cuts a line to width runes, tabs expanded
+func fitColumn(s string, width int) string {
+	s = strings.ReplaceAll(s, "\t", "    ")
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	r := []rune(s)
+	return string(r[:width-1]) + "…"
+}
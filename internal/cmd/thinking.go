@@ -0,0 +1,63 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// thinkingLevels are pi's reasoning levels, in order, with the token
+// budget each allows
+var thinkingLevels = []struct {
+	Name   string
+	Budget int
+}{
+	{"off", 0},
+	{"minimal", 1024},
+	{"low", 4096},
+	{"medium", 10240},
+	{"high", 32768},
+}
+
+// parseThinking reads --thinking: a level name, or a token budget rounded
+// up to the level that allows it
+func parseThinking(spec string) (string, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+	names := make([]string, len(thinkingLevels))
+	for i, l := range thinkingLevels {
+		if l.Name == spec {
+			return l.Name, nil
+		}
+		names[i] = l.Name
+	}
+	budget, err := strconv.Atoi(spec)
+	if err != nil || budget < 0 {
+		return "", errors.NewCLIError(fmt.Sprintf("invalid --thinking %q", spec)).
+			WithSuggestions("Use a level (" + strings.Join(names, ", ") + ") or a token budget, e.g. --thinking 8000")
+	}
+	for _, l := range thinkingLevels {
+		if budget <= l.Budget {
+			return l.Name, nil
+		}
+	}
+	return thinkingLevels[len(thinkingLevels)-1].Name, nil
+}
+
+// writeThinking shows a reasoning trace before the answer, set apart so
+// it is not mistaken for it
+func writeThinking(w io.Writer, thinking string) {
+	if thinking == "" {
+		return
+	}
+	_, _ = fmt.Fprintln(w, "Thinking:")
+	for _, line := range strings.Split(thinking, "\n") {
+		_, _ = fmt.Fprintln(w, "  │ "+line)
+	}
+	_, _ = fmt.Fprintln(w)
+}
@@ -0,0 +1,299 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// ticketInstructions asks the model for an issue draft as JSON
+const ticketInstructions = `Draft an issue ticket from the input. Reply with a single JSON object:
+{"title": "concise summary under 80 characters", "body": "markdown body with context, impact, and steps to reproduce or next steps", "labels": ["bug"]}
+Only use labels that clearly apply. Do not wrap the JSON in prose.`
+
+// ticketCreateTimeout bounds the tracker request, which starts only after
+// the draft is confirmed
+const ticketCreateTimeout = 30 * time.Second
+
+// ticketDraft is the issue the model proposes
+type ticketDraft struct {
+	Title  string   `json:"title"`
+	Body   string   `json:"body"`
+	Labels []string `json:"labels"`
+}
+
+// ticketTarget creates issues in a tracker
+type ticketTarget interface {
+	Name() string
+	Payload(d ticketDraft) any
+	Create(ctx context.Context, d ticketDraft) (string, error)
+}
+
+// httpClient is an abstraction for testing
+var httpClient = http.DefaultClient
+
+func newTicketCmd(client *BridgeClient) *cobra.Command {
+	var (
+		jira         string
+		github       string
+		issueType    string
+		labels       []string
+		pane         string
+		lines        int
+		contextFiles []string
+		dryRun       bool
+		yes          bool
+		scanMode     string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "ticket [focus]",
+		Short: "Draft an issue from the input and create it",
+		Long: `Turn the analyzed input into a drafted issue (title, body, labels).
+
+The draft is shown and created only after confirmation. Use --dry-run
+to print the API payload without creating anything.
+
+GitHub uses GITHUB_TOKEN. Jira uses JIRA_URL, JIRA_EMAIL, and
+JIRA_API_TOKEN.`,
+		Example: `  arc-tmux follow --pane prod:logs.0 | arc-ask ticket --github org/repo
+  cat crash.log | arc-ask ticket --jira OPS "focus on the OOM"
+  git diff | arc-ask ticket --github org/repo --dry-run`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateScanMode(scanMode); err != nil {
+				return err
+			}
+
+			var target ticketTarget
+			switch {
+			case jira != "" && github != "":
+				return errors.NewCLIError("use either --jira or --github, not both")
+			case jira != "":
+				target = &jiraTarget{project: jira, issueType: issueType}
+			case github != "":
+				owner, repo, ok := strings.Cut(github, "/")
+				if !ok || owner == "" || repo == "" {
+					return errors.NewCLIError(fmt.Sprintf("invalid --github %q", github)).
+						WithSuggestions("Format: --github org/repo")
+				}
+				target = &githubTarget{owner: owner, repo: repo}
+			default:
+				return errors.NewCLIError("no ticket target").
+					WithSuggestions("GitHub: --github org/repo", "Jira: --jira PROJECT")
+			}
+
+			input, err := gatherInput(cmd, pane, lines, captureFilter{})
+			if err != nil {
+				return err
+			}
+			input, _, err = mergeContext(input, contextFiles, contextOptions{})
+			if err != nil {
+				return err
+			}
+			if input == "" {
+				return errors.NewCLIError("no input to draft a ticket from").
+					WithSuggestions("Pipe input: cat error.log | arc-ask ticket --github org/repo")
+			}
+
+			prompt := ticketInstructions
+			if len(args) > 0 {
+				prompt += "\n\nFocus: " + args[0]
+			}
+			prompt += "\n\nInput:\n" + input
+
+			ctx, cancel := context.WithTimeout(context.Background(), client.timeout)
+			defer cancel()
+			answer, err := client.Ask(ctx, prompt)
+			if err != nil {
+				return errors.NewCLIError("AI query failed").WithCause(err)
+			}
+
+			draft, err := parseTicketDraft(answer)
+			if err != nil {
+				return err
+			}
+			draft.Labels = append(draft.Labels, labels...)
+
+			// Drafts leave the machine, so scan them like any other output
+			warn := func(msg string) { _, _ = fmt.Fprintln(cmd.ErrOrStderr(), msg) }
+			draft.Title, _ = scanOutput(scanMode, draft.Title, warn)
+			draft.Body, _ = scanOutput(scanMode, draft.Body, warn)
+
+			out := cmd.OutOrStdout()
+			if dryRun {
+				enc := json.NewEncoder(out)
+				enc.SetIndent("", "  ")
+				return enc.Encode(target.Payload(draft))
+			}
+
+			printTicketDraft(cmd.ErrOrStderr(), target, draft)
+			if !yes {
+				ok, err := confirm("Create this issue?")
+				if err != nil {
+					return err
+				}
+				if !ok {
+					return errors.NewCLIError("aborted, no issue created")
+				}
+			}
+
+			// The ask deadline may have passed while the user read the draft
+			createCtx, cancelCreate := context.WithTimeout(context.Background(), ticketCreateTimeout)
+			defer cancelCreate()
+			url, err := target.Create(createCtx, draft)
+			if err != nil {
+				return errors.NewCLIError("failed to create issue").WithCause(err)
+			}
+			_, _ = fmt.Fprintln(out, url)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&jira, "jira", "", "Create in Jira project KEY")
+	cmd.Flags().StringVar(&github, "github", "", "Create in GitHub repo org/repo")
+	cmd.Flags().StringVar(&issueType, "type", "Task", "Jira issue type")
+	cmd.Flags().StringSliceVar(&labels, "label", nil, "Extra labels to add")
+	cmd.Flags().StringVar(&pane, "pane", "", "Capture from tmux pane (e.g., session:0.0)")
+	cmd.Flags().IntVar(&lines, "lines", 200, "Lines to capture from pane")
+	cmd.Flags().StringArrayVarP(&contextFiles, "context", "c", nil, "Add context file(s)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the API payload without creating the issue")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Create without confirmation")
+	cmd.Flags().StringVar(&scanMode, "scan-output", scanOff, "Scan the draft for secrets/PII: off, warn, redact")
+	return cmd
+}
+
+// parseTicketDraft decodes the model's JSON draft, tolerating code fences
+func parseTicketDraft(answer string) (ticketDraft, error) {
+	text := strings.TrimSpace(answer)
+	if blocks := ask.CodeBlocks(answer); len(blocks) > 0 {
+		text = blocks[0].Code
+	} else if i, j := strings.Index(text, "{"), strings.LastIndex(text, "}"); i >= 0 && j > i {
+		text = text[i : j+1]
+	}
+
+	var d ticketDraft
+	if err := json.Unmarshal([]byte(text), &d); err != nil {
+		return d, errors.NewCLIError("model did not return a valid ticket draft").WithCause(err)
+	}
+	if strings.TrimSpace(d.Title) == "" {
+		return d, errors.NewCLIError("model returned a ticket draft without a title")
+	}
+	return d, nil
+}
+
+func printTicketDraft(w io.Writer, target ticketTarget, d ticketDraft) {
+	_, _ = fmt.Fprintf(w, "Target: %s\n", target.Name())
+	_, _ = fmt.Fprintf(w, "Title:  %s\n", d.Title)
+	if len(d.Labels) > 0 {
+		_, _ = fmt.Fprintf(w, "Labels: %s\n", strings.Join(d.Labels, ", "))
+	}
+	_, _ = fmt.Fprintf(w, "\n%s\n\n", d.Body)
+}
+
+type githubTarget struct {
+	owner, repo string
+}
+
+func (t *githubTarget) Name() string { return "github.com/" + t.owner + "/" + t.repo }
+
+func (t *githubTarget) Payload(d ticketDraft) any {
+	return map[string]any{"title": d.Title, "body": d.Body, "labels": d.Labels}
+}
+
+func (t *githubTarget) Create(ctx context.Context, d ticketDraft) (string, error) {
+	token := os.Getenv("GITHUB_TOKEN")
+	if token == "" {
+		return "", fmt.Errorf("GITHUB_TOKEN is not set")
+	}
+	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/issues", t.owner, t.repo)
+	var resp struct {
+		HTMLURL string `json:"html_url"`
+	}
+	err := postJSON(ctx, url, t.Payload(d), &resp, map[string]string{
+		"Authorization": "Bearer " + token,
+		"Accept":        "application/vnd.github+json",
+	})
+	return resp.HTMLURL, err
+}
+
+type jiraTarget struct {
+	project, issueType string
+}
+
+func (t *jiraTarget) Name() string { return "jira project " + t.project }
+
+func (t *jiraTarget) Payload(d ticketDraft) any {
+	fields := map[string]any{
+		"project":     map[string]string{"key": t.project},
+		"summary":     d.Title,
+		"description": d.Body,
+		"issuetype":   map[string]string{"name": t.issueType},
+	}
+	if len(d.Labels) > 0 {
+		fields["labels"] = d.Labels
+	}
+	return map[string]any{"fields": fields}
+}
+
+func (t *jiraTarget) Create(ctx context.Context, d ticketDraft) (string, error) {
+	base := strings.TrimRight(os.Getenv("JIRA_URL"), "/")
+	email, token := os.Getenv("JIRA_EMAIL"), os.Getenv("JIRA_API_TOKEN")
+	if base == "" || email == "" || token == "" {
+		return "", fmt.Errorf("JIRA_URL, JIRA_EMAIL, and JIRA_API_TOKEN must be set")
+	}
+	var resp struct {
+		Key string `json:"key"`
+	}
+	auth := base64.StdEncoding.EncodeToString([]byte(email + ":" + token))
+	headers := map[string]string{"Authorization": "Basic " + auth}
+	if err := postJSON(ctx, base+"/rest/api/2/issue", t.Payload(d), &resp, headers); err != nil {
+		return "", err
+	}
+	return base + "/browse/" + resp.Key, nil
+}
+
+// postJSON sends a JSON body and decodes a JSON response
+func postJSON(ctx context.Context, url string, body, out any, headers map[string]string) error {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s returned %s: %s", url, resp.Status, strings.TrimSpace(string(respBody)))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(respBody, out)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Default per-phase timeouts
+const (
+	defaultCaptureTimeout = 10 * time.Second
+	defaultConnectTimeout = 30 * time.Second
+	defaultTotalTimeout   = 60 * time.Second
+)
+
+// partialAnswerError reports a generation cut short after some text arrived
+type partialAnswerError struct {
+	Partial string
+	Cause   error
+}
+
+func (e *partialAnswerError) Error() string {
+	return fmt.Sprintf("generation interrupted (%v); answer is incomplete", e.Cause)
+}
+
+func (e *partialAnswerError) Unwrap() error { return e.Cause }
+
+// withPhaseTimeout runs fn, giving up after d (0 means no limit). flag
+// names the option that raises the limit.
+func withPhaseTimeout[T any](phase, flag string, d time.Duration, fn func() (T, error)) (T, error) {
+	if d <= 0 {
+		return fn()
+	}
+
+	type result struct {
+		v   T
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		v, err := fn()
+		ch <- result{v, err}
+	}()
+
+	select {
+	case r := <-ch:
+		return r.v, r.err
+	case <-time.After(d):
+		var zero T
+		return zero, coded(codeTimeout, errors.NewCLIError(fmt.Sprintf("%s timed out after %s", phase, d)).
+			WithSuggestions("Raise the limit with "+flag))
+	}
+}
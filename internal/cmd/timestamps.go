@@ -0,0 +1,284 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Timestamp normalization modes
+const (
+	timestampsUTC      = "utc"      // RFC 3339 in UTC
+	timestampsLocal    = "local"    // RFC 3339 in the tz= zone
+	timestampsRelative = "relative" // offsets from the earliest timestamp
+)
+
+// timestampNorm is --timestamps: a mode, the zone that timestamps without
+// one are in, and how slashed dates are ordered
+type timestampNorm struct {
+	mode string
+	zone *time.Location
+	dmy  bool      // 02/01/2006 is the 2nd of January
+	ref  time.Time // the latest dated timestamp, which gives syslog timestamps their year
+}
+
+// parseTimestampNorm parses --timestamps: a mode optionally followed by
+// comma-separated tz=ZONE and order=mdy|dmy options
+func parseTimestampNorm(spec string) (timestampNorm, error) {
+	n := timestampNorm{zone: time.Local}
+	fail := func(msg string) (timestampNorm, error) {
+		return timestampNorm{}, errors.NewCLIError(fmt.Sprintf("invalid --timestamps %q: %s", spec, msg)).
+			WithSuggestions("Use utc, local, or relative, optionally with ,tz=Europe/Berlin and ,order=dmy")
+	}
+	for i, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		key, value, hasValue := strings.Cut(part, "=")
+		switch {
+		case i == 0 && !hasValue:
+			switch part {
+			case timestampsUTC, timestampsLocal, timestampsRelative:
+				n.mode = part
+			default:
+				return fail("unknown mode")
+			}
+		case key == "tz":
+			loc, err := time.LoadLocation(value)
+			if err != nil {
+				return fail("unknown zone " + value)
+			}
+			n.zone = loc
+		case key == "order" && (value == "mdy" || value == "dmy"):
+			n.dmy = value == "dmy"
+		default:
+			return fail("unknown option " + part)
+		}
+	}
+	if n.mode == "" {
+		return fail("no mode")
+	}
+	return n, nil
+}
+
+// timestampFormat is a way timestamps are written and how to read one
+type timestampFormat struct {
+	pattern *regexp.Regexp
+	parse   func(s string, n timestampNorm) (time.Time, bool)
+	number  bool // written as a number, so quoted when rewritten inside JSON
+	noYear  bool // written without a year
+}
+
+var (
+	isoZone     = regexp.MustCompile(`([+-]\d{2})(\d{2})$`)
+	syslogMonth = map[string]time.Month{}
+)
+
+func init() {
+	for m := time.January; m <= time.December; m++ {
+		syslogMonth[m.String()[:3]] = m
+	}
+}
+
+// timestampFormats are tried in order; each leaves text the earlier
+// ones produce alone
+var timestampFormats = []timestampFormat{
+	// 2024-05-01T10:00:00Z, 2024-05-01 10:00:00,123 +0200
+	{pattern: regexp.MustCompile(`\b\d{4}-\d{2}-\d{2}[T ]\d{2}:\d{2}:\d{2}(?:[.,]\d{1,9})?(?:Z|\s?[+-]\d{2}:?\d{2}\b)?`), parse: func(s string, n timestampNorm) (time.Time, bool) {
+		s = strings.Replace(strings.Replace(s[:10]+"T"+s[11:], ",", ".", 1), " ", "", 1)
+		s = isoZone.ReplaceAllString(s, "$1:$2")
+		if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+			return t, true
+		}
+		t, err := time.ParseInLocation("2006-01-02T15:04:05.999999999", s, n.zone)
+		return t, err == nil
+	}},
+	// 2024/05/01 10:00:00, as Go's log package writes
+	{pattern: regexp.MustCompile(`\b\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(?:\.\d{1,9})?\b`), parse: func(s string, n timestampNorm) (time.Time, bool) {
+		t, err := time.ParseInLocation("2006/01/02 15:04:05.999999999", s, n.zone)
+		return t, err == nil
+	}},
+	// 01/May/2024:10:00:00 +0000, the Apache and nginx access log format
+	{pattern: regexp.MustCompile(`\b\d{2}/[A-Z][a-z]{2}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\b`), parse: func(s string, n timestampNorm) (time.Time, bool) {
+		t, err := time.Parse("02/Jan/2006:15:04:05 -0700", s)
+		return t, err == nil
+	}},
+	// 05/01/2024 10:00:00, month or day first by order=
+	{pattern: regexp.MustCompile(`\b\d{1,2}/\d{1,2}/\d{4},? \d{1,2}:\d{2}(?::\d{2})?\b`), parse: func(s string, n timestampNorm) (time.Time, bool) {
+		s = strings.Replace(s, ",", "", 1)
+		layout := "1/2/2006 15:04:05"
+		if n.dmy {
+			layout = "2/1/2006 15:04:05"
+		}
+		if strings.Count(s, ":") == 1 {
+			layout = strings.TrimSuffix(layout, ":05")
+		}
+		t, err := time.ParseInLocation(layout, s, n.zone)
+		return t, err == nil
+	}},
+	// May  1 10:00:00, as syslog writes, in the year of the latest dated
+	// timestamp, else the current one
+	{pattern: regexp.MustCompile(`\b(?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec) [ \d]\d \d{2}:\d{2}:\d{2}\b`), noYear: true, parse: func(s string, n timestampNorm) (time.Time, bool) {
+		now := time.Now().In(n.zone)
+		if !n.ref.IsZero() {
+			now = n.ref.In(n.zone)
+		}
+		day, _ := strconv.Atoi(strings.TrimSpace(s[4:6]))
+		var h, m, sec int
+		if _, err := fmt.Sscanf(s[7:], "%d:%d:%d", &h, &m, &sec); err != nil {
+			return time.Time{}, false
+		}
+		t := time.Date(now.Year(), syslogMonth[s[:3]], day, h, m, sec, 0, n.zone)
+		if t.After(now.AddDate(0, 0, 1)) {
+			t = t.AddDate(-1, 0, 0)
+		}
+		return t, true
+	}},
+	// Unix epoch seconds or milliseconds between 2017 and 2036
+	{pattern: regexp.MustCompile(`\b(?:1[5-9]|20)\d{8}(?:\.\d{1,9}|\d{3})?\b`), number: true, parse: func(s string, n timestampNorm) (time.Time, bool) {
+		if len(s) == 13 && !strings.Contains(s, ".") {
+			ms, err := strconv.ParseInt(s, 10, 64)
+			return time.UnixMilli(ms), err == nil
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return time.Time{}, false
+		}
+		sec := int64(f)
+		return time.Unix(sec, int64((f-float64(sec))*1e9)).Round(time.Millisecond), true
+	}},
+}
+
+// jsonNumberBefore and jsonNumberAfter find a number that is a JSON value
+var (
+	jsonNumberBefore = regexp.MustCompile(`":\s*$`)
+	jsonNumberAfter  = regexp.MustCompile(`^\s*[,}\]]`)
+)
+
+// eachTimestamp replaces every timestamp found in text with what replace
+// returns for it
+func eachTimestamp(text string, n timestampNorm, replace func(match string, t time.Time) string) string {
+	for _, f := range timestampFormats {
+		var b strings.Builder
+		last := 0
+		for _, loc := range f.pattern.FindAllStringIndex(text, -1) {
+			match := text[loc[0]:loc[1]]
+			t, ok := f.parse(match, n)
+			if !ok {
+				continue
+			}
+			out := replace(match, t)
+			if f.number && jsonNumberBefore.MatchString(text[last:loc[0]]) && jsonNumberAfter.MatchString(text[loc[1]:]) {
+				out = strconv.Quote(out)
+			}
+			b.WriteString(text[last:loc[0]])
+			b.WriteString(out)
+			last = loc[1]
+		}
+		b.WriteString(text[last:])
+		text = b.String()
+	}
+	return text
+}
+
+// dateFrom sets the reference that timestamps without a year take theirs
+// from: the latest dated timestamp in text
+func (n *timestampNorm) dateFrom(text string) {
+	for _, f := range timestampFormats {
+		if f.noYear {
+			continue
+		}
+		for _, match := range f.pattern.FindAllString(text, -1) {
+			if t, ok := f.parse(match, *n); ok && t.After(n.ref) {
+				n.ref = t
+			}
+		}
+	}
+}
+
+// firstTimestamp reads the timestamp that starts earliest in line
+func (n timestampNorm) firstTimestamp(line string) (time.Time, bool) {
+	var (
+		found time.Time
+		at    = -1
+	)
+	for _, f := range timestampFormats {
+		loc := f.pattern.FindStringIndex(line)
+		if loc == nil || at >= 0 && loc[0] >= at {
+			continue
+		}
+		if t, ok := f.parse(line[loc[0]:loc[1]], n); ok {
+			found, at = t, loc[0]
+		}
+	}
+	return found, at >= 0
+}
+
+// apply rewrites the timestamps in text and returns how many it rewrote.
+// A header line tells the model what the timestamps now mean.
+func (n timestampNorm) apply(text string) (string, int) {
+	n.dateFrom(text)
+	var base time.Time
+	if n.mode == timestampsRelative {
+		eachTimestamp(text, n, func(match string, t time.Time) string {
+			if base.IsZero() || t.Before(base) {
+				base = t
+			}
+			return match
+		})
+	}
+
+	count := 0
+	out := eachTimestamp(text, n, func(match string, t time.Time) string {
+		count++
+		precise := t.Nanosecond() != 0
+		switch n.mode {
+		case timestampsRelative:
+			d := t.Sub(base)
+			return formatOffset(d, d%time.Second != 0)
+		case timestampsLocal:
+			if precise {
+				return t.In(n.zone).Format("2006-01-02T15:04:05.000Z07:00")
+			}
+			return t.In(n.zone).Format(time.RFC3339)
+		}
+		if precise {
+			return t.UTC().Format("2006-01-02T15:04:05.000Z")
+		}
+		return t.UTC().Format(time.RFC3339)
+	})
+	if count == 0 {
+		return text, 0
+	}
+
+	var header string
+	switch n.mode {
+	case timestampsRelative:
+		header = fmt.Sprintf("[Timestamps are offsets (T+hours:minutes:seconds) from the earliest, %s]", base.UTC().Format(time.RFC3339Nano))
+	case timestampsLocal:
+		header = fmt.Sprintf("[Timestamps normalized to %s]", n.zone)
+	default:
+		header = "[Timestamps normalized to UTC]"
+	}
+	return header + "\n" + out, count
+}
+
+// formatOffset writes d as T+HH:MM:SS, with milliseconds when precise
+func formatOffset(d time.Duration, precise bool) string {
+	sign := "+"
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	h := int(d / time.Hour)
+	m := int(d % time.Hour / time.Minute)
+	s := int(d % time.Minute / time.Second)
+	if precise {
+		return fmt.Sprintf("T%s%02d:%02d:%02d.%03d", sign, h, m, s, int(d%time.Second/time.Millisecond))
+	}
+	return fmt.Sprintf("T%s%02d:%02d:%02d", sign, h, m, s)
+}
@@ -0,0 +1,72 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// processStart approximates when the binary started, to time startup
+var processStart = time.Now()
+
+// phaseTimer records how long each phase of a request took, for --timing
+// and for tracing. A nil timer records nothing.
+type phaseTimer struct {
+	last   time.Time
+	phases []phaseTime
+	print  bool    // --timing
+	tracer *tracer // nil unless OTEL env vars enable tracing
+}
+
+type phaseTime struct {
+	name  string
+	start time.Time
+	d     time.Duration
+}
+
+func newPhaseTimer(print bool) *phaseTimer {
+	tr := newTracerFromEnv()
+	if !print && tr == nil {
+		return nil
+	}
+	t := &phaseTimer{last: processStart, print: print, tracer: tr}
+	t.mark("startup")
+	return t
+}
+
+// mark ends the current phase under the given name
+func (t *phaseTimer) mark(name string) {
+	if t == nil {
+		return
+	}
+	now := time.Now()
+	t.phases = append(t.phases, phaseTime{name: name, start: t.last, d: now.Sub(t.last)})
+	t.last = now
+}
+
+// finish prints timings and exports the trace; err is the run's outcome
+func (t *phaseTimer) finish(attrs map[string]string, err error) {
+	if t == nil {
+		return
+	}
+	if t.print {
+		t.report(os.Stderr)
+	}
+	if t.tracer != nil {
+		if exportErr := t.tracer.export(t.phases, attrs, err); exportErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: trace export failed: %v\n", exportErr)
+		}
+	}
+}
+
+func (t *phaseTimer) report(w io.Writer) {
+	_, _ = fmt.Fprintln(w, "Timing:")
+	for _, p := range t.phases {
+		_, _ = fmt.Fprintf(w, "  %-12s %8.1fms\n", p.name, float64(p.d.Microseconds())/1000)
+	}
+	_, _ = fmt.Fprintf(w, "  %-12s %8.1fms\n", "total", float64(time.Since(processStart).Microseconds())/1000)
+}
@@ -0,0 +1,204 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+)
+
+// noTitle is --no-title: sessions and history entries are saved without a
+// title or tags, and no title model is asked
+var noTitle bool
+
+const (
+	// titleMaxLen is the longest title kept
+	titleMaxLen = 60
+
+	// titleSample is how much of the conversation the title model sees
+	titleSample = 3000
+
+	// titleTimeout bounds the title model; a slow one leaves the
+	// heuristic title
+	titleTimeout = 15 * time.Second
+
+	titleMaxTags = 3
+)
+
+// titleInstructions ask the title model for a title and tags as JSON
+const titleInstructions = `Give the conversation below a short title (at most 6 words, no quotes, no trailing period) and up to 3 lowercase one-word topic tags. Reply with only JSON: {"title": "...", "tags": ["..."]}`
+
+// tagWords map words in a question to the tag they suggest, for titles
+// made without a model
+var tagWords = map[string]string{
+	"go": "go", "golang": "go", "python": "python", "pip": "python", "rust": "rust", "cargo": "rust",
+	"javascript": "javascript", "typescript": "typescript", "node": "javascript", "npm": "javascript",
+	"java": "java", "ruby": "ruby", "bash": "shell", "shell": "shell", "zsh": "shell",
+	"docker": "docker", "container": "docker", "kubernetes": "kubernetes", "k8s": "kubernetes", "kubectl": "kubernetes",
+	"git": "git", "rebase": "git", "merge": "git", "commit": "git",
+	"sql": "sql", "postgres": "sql", "mysql": "sql", "query": "sql",
+	"nginx": "nginx", "tmux": "tmux", "ci": "ci", "pipeline": "ci", "terraform": "terraform",
+	"test": "testing", "tests": "testing", "flaky": "testing",
+	"error": "debugging", "panic": "debugging", "crash": "debugging", "stacktrace": "debugging", "traceback": "debugging",
+	"security": "security", "cve": "security", "vulnerability": "security",
+	"performance": "performance", "slow": "performance", "latency": "performance", "memory": "performance",
+	"review": "review", "diff": "review", "refactor": "refactoring", "log": "logs", "logs": "logs",
+}
+
+var titleWordPattern = regexp.MustCompile(`[A-Za-z0-9+#]+`)
+
+// heuristicTitle is the question's first line, shortened at a word
+func heuristicTitle(question string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(question), "\n")
+	line = strings.Join(strings.Fields(line), " ")
+	if len(line) <= titleMaxLen {
+		return line
+	}
+	cut := strings.LastIndex(line[:titleMaxLen], " ")
+	if cut < titleMaxLen/2 {
+		cut = titleMaxLen
+	}
+	return strings.TrimRight(line[:cut], " ,.;:") + "…"
+}
+
+// heuristicTags are the tags the text's words suggest, most mentioned
+// first, with a template's name as the first tag
+func heuristicTags(question, text string) []string {
+	counts := make(map[string]int)
+	for _, w := range titleWordPattern.FindAllString(strings.ToLower(question+"\n"+text), -1) {
+		if tag, ok := tagWords[w]; ok {
+			counts[tag]++
+		}
+	}
+	tags := make([]string, 0, len(counts))
+	for tag := range counts {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if counts[tags[i]] != counts[tags[j]] {
+			return counts[tags[i]] > counts[tags[j]]
+		}
+		return tags[i] < tags[j]
+	})
+	if ask.IsTemplateRef(question) {
+		name, _ := ask.SplitVariant(question)
+		tags = append([]string{name}, tags...)
+	}
+	if len(tags) > titleMaxTags {
+		tags = tags[:titleMaxTags]
+	}
+	return tags
+}
+
+// titleModel is the model that titles conversations: title_model from
+// ask.yaml, else the provider's cheapest small model. Empty means titles
+// are made without a model.
+func titleModel(client *BridgeClient) string {
+	if cfg, err := loadConfig(); err == nil && cfg.TitleModel != "" {
+		return cfg.TitleModel
+	}
+	return cheapestSmallModel(client)
+}
+
+// titleConversation titles a conversation from its question and what
+// followed, asking the title model when there is one and the question is
+// not already short enough to be its own title. It returns no title for
+// such questions, and the heuristic title when the model fails.
+func titleConversation(client *BridgeClient, question, text string) (string, []string) {
+	tags := heuristicTags(question, text)
+	short := !strings.Contains(strings.TrimSpace(question), "\n") && len(question) <= titleMaxLen && !ask.IsTemplateRef(question)
+	model := titleModel(client)
+	if short || model == "" || client.fixtures != nil {
+		if short {
+			return "", tags
+		}
+		return heuristicTitle(question), tags
+	}
+
+	sample := question + "\n\n" + text
+	if len(sample) > titleSample {
+		sample = sample[:titleSample]
+	}
+	titler := client.WithModel(model)
+	titler.stats = &callStats{} // kept out of the question's usage
+	titler.onText = nil
+	ctx, cancel := context.WithTimeout(context.Background(), titleTimeout)
+	defer cancel()
+	answer, err := titler.Ask(ctx, titleInstructions+"\n\nConversation:\n"+sample)
+	if err != nil {
+		return heuristicTitle(question), tags
+	}
+
+	var reply struct {
+		Title string   `json:"title"`
+		Tags  []string `json:"tags"`
+	}
+	body := strings.TrimSpace(answer)
+	if blocks := ask.CodeBlocks(answer); len(blocks) > 0 {
+		body = blocks[0].Code
+	}
+	if start, end := strings.Index(body, "{"), strings.LastIndex(body, "}"); start >= 0 && end > start {
+		body = body[start : end+1]
+	}
+	if json.Unmarshal([]byte(body), &reply) != nil || strings.TrimSpace(reply.Title) == "" {
+		return heuristicTitle(question), tags
+	}
+	title := heuristicTitle(strings.Trim(reply.Title, `"'. `))
+	var modelTags []string
+	for _, t := range reply.Tags {
+		t = strings.ToLower(strings.Join(strings.Fields(t), "-"))
+		if t != "" && len(modelTags) < titleMaxTags {
+			modelTags = append(modelTags, t)
+		}
+	}
+	if len(modelTags) == 0 {
+		modelTags = tags
+	}
+	return title, modelTags
+}
+
+// hasTag reports whether tags include tag, ignoring case
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// titleHistory titles a history entry, reusing the title of an earlier
+// identical invocation
+func titleHistory(client *BridgeClient, e *historyEntry, answer string) {
+	if noTitle || !historyEnabled() {
+		return
+	}
+	if past, err := readHistory(); err == nil {
+		key := strings.Join(e.Args, "\x00")
+		for _, p := range past {
+			if strings.Join(p.Args, "\x00") == key && (p.Title != "" || len(p.Tags) > 0) {
+				e.Title, e.Tags = p.Title, p.Tags
+				return
+			}
+		}
+	}
+	e.Title, e.Tags = titleConversation(client, e.Question, answer)
+}
+
+// titleSession titles a session once it has its first answer
+func titleSession(client *BridgeClient, sess *Session) {
+	if noTitle || stateless || sess.Title != "" || len(sess.Turns) < 2 {
+		return
+	}
+	sess.Title, sess.Tags = titleConversation(client, sess.Turns[0].Content, sess.Turns[1].Content)
+	if sess.Title == "" {
+		sess.Title = heuristicTitle(sess.Turns[0].Content)
+	}
+}
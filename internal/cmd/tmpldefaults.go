@@ -0,0 +1,59 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// defaultFlags maps each set template default to the flag it stands in for
+func defaultFlags(d *ask.TemplateDefaults) map[string]string {
+	out := make(map[string]string)
+	if d.MaxTokens != nil {
+		out["max-tokens"] = strconv.Itoa(*d.MaxTokens)
+	}
+	if d.Temperature != nil {
+		out["temperature"] = strconv.FormatFloat(*d.Temperature, 'f', -1, 64)
+	}
+	if d.Output != "" {
+		out["output"] = d.Output
+	}
+	if d.Extract != "" {
+		out["extract"] = d.Extract
+	}
+	if d.Model != "" {
+		out["model"] = d.Model
+	}
+	if d.Thinking != "" {
+		out["thinking"] = d.Thinking
+	}
+	return out
+}
+
+// applyTemplateDefaults sets the template's default flags that the user did
+// not pass explicitly
+func applyTemplateDefaults(cmd *cobra.Command, arg string) error {
+	if !ask.IsTemplateRef(arg) {
+		return nil
+	}
+	t, err := loadTemplate(arg)
+	if err != nil || t.Defaults == nil {
+		// A missing template is reported when the prompt is built
+		return nil
+	}
+	for name, value := range defaultFlags(t.Defaults) {
+		if cmd.Flags().Changed(name) {
+			continue
+		}
+		if err := cmd.Flags().Set(name, value); err != nil {
+			return errors.NewCLIError(fmt.Sprintf("template @%s: invalid default for --%s", t.Name, name)).WithCause(err)
+		}
+	}
+	return nil
+}
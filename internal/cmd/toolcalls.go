@@ -0,0 +1,142 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// maxToolResult is how much of a tool's result is kept in the trace
+const maxToolResult = 2000
+
+// toolCall is one tool invocation in the --output json trace
+type toolCall struct {
+	ID        string          `json:"id,omitempty"`
+	Name      string          `json:"name"`
+	Args      json.RawMessage `json:"args,omitempty"`
+	Result    string          `json:"result"`
+	Truncated bool            `json:"truncated,omitempty"` // result cut to maxToolResult bytes
+	Error     bool            `json:"error,omitempty"`
+	Duration  float64         `json:"duration"` // seconds
+}
+
+// toolRecorder watches pi's JSON event stream as it arrives and records
+// each tool call, timing it from its start event to its end event
+type toolRecorder struct {
+	mu      sync.Mutex // pi's output may still arrive after runPi returns
+	pending []byte
+	started map[string]time.Time
+	calls   []toolCall
+	now     func() time.Time
+}
+
+func newToolRecorder() *toolRecorder {
+	return &toolRecorder{started: make(map[string]time.Time), now: time.Now}
+}
+
+// Write takes stream output in any chunking and handles complete lines
+func (r *toolRecorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pending = append(r.pending, p...)
+	for {
+		i := bytes.IndexByte(r.pending, '\n')
+		if i < 0 {
+			break
+		}
+		r.line(r.pending[:i])
+		r.pending = r.pending[i+1:]
+	}
+	return len(p), nil
+}
+
+// snapshot returns the calls recorded so far
+func (r *toolRecorder) snapshot() []toolCall {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]toolCall(nil), r.calls...)
+}
+
+func (r *toolRecorder) line(line []byte) {
+	line = bytes.TrimSpace(line)
+	if !bytes.HasPrefix(line, []byte("{")) || !bytes.Contains(line, []byte(`"tool_execution_`)) {
+		return
+	}
+	var event struct {
+		Type       string          `json:"type"`
+		ToolCallID string          `json:"toolCallId"`
+		ToolName   string          `json:"toolName"`
+		Args       json.RawMessage `json:"args"`
+		Result     json.RawMessage `json:"result"`
+		IsError    bool            `json:"isError"`
+	}
+	if json.Unmarshal(line, &event) != nil {
+		return
+	}
+	switch event.Type {
+	case "tool_execution_start":
+		r.started[event.ToolCallID] = r.now()
+		r.calls = append(r.calls, toolCall{ID: event.ToolCallID, Name: event.ToolName, Args: event.Args})
+	case "tool_execution_end":
+		c := r.call(event.ToolCallID, event.ToolName)
+		if start, ok := r.started[event.ToolCallID]; ok {
+			c.Duration = r.now().Sub(start).Round(time.Millisecond).Seconds()
+		}
+		c.Result, c.Truncated = truncateToolResult(toolResultText(event.Result))
+		c.Error = event.IsError
+	}
+}
+
+// call finds the started call with id, adding one if the start event was
+// missed
+func (r *toolRecorder) call(id, name string) *toolCall {
+	for i := len(r.calls) - 1; i >= 0; i-- {
+		if r.calls[i].ID == id {
+			return &r.calls[i]
+		}
+	}
+	r.calls = append(r.calls, toolCall{ID: id, Name: name})
+	return &r.calls[len(r.calls)-1]
+}
+
+// toolResultText returns the text parts of a tool result, or the raw
+// JSON when it has none
+func toolResultText(raw json.RawMessage) string {
+	var result struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	if json.Unmarshal(raw, &result) == nil && len(result.Content) > 0 {
+		var parts []string
+		for _, c := range result.Content {
+			if c.Type == "text" {
+				parts = append(parts, c.Text)
+			}
+		}
+		return strings.Join(parts, "\n")
+	}
+	var text string
+	if json.Unmarshal(raw, &text) == nil {
+		return text
+	}
+	return string(raw)
+}
+
+func truncateToolResult(text string) (string, bool) {
+	if len(text) <= maxToolResult {
+		return text, false
+	}
+	cut := maxToolResult
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut], true
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// traceExportTimeout bounds how long exporting may delay exit
+const traceExportTimeout = 3 * time.Second
+
+// spanNames maps timer phases to span names
+var spanNames = map[string]string{
+	"startup":    "startup",
+	"input":      "capture",
+	"context":    "context",
+	"prompt":     "prompt assembly",
+	"generation": "provider call",
+	"output":     "render",
+}
+
+var traceparentPattern = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-[0-9a-f]{2}$`)
+
+// tracer exports one trace per invocation as OTLP/HTTP JSON. It is
+// configured only by the standard OTEL_* environment variables.
+type tracer struct {
+	endpoint string
+	headers  map[string]string
+	resource map[string]string
+	traceID  string
+	parentID string // from TRACEPARENT, so the run joins a caller's trace
+}
+
+// newTracerFromEnv returns nil unless an OTLP endpoint is configured
+func newTracerFromEnv() *tracer {
+	if strings.EqualFold(os.Getenv("OTEL_SDK_DISABLED"), "true") || os.Getenv("OTEL_TRACES_EXPORTER") == "none" {
+		return nil
+	}
+	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if endpoint == "" {
+		base := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
+		if base == "" {
+			return nil
+		}
+		endpoint = strings.TrimRight(base, "/") + "/v1/traces"
+	}
+
+	headers := parseOTELList(os.Getenv("OTEL_EXPORTER_OTLP_TRACES_HEADERS"))
+	if len(headers) == 0 {
+		headers = parseOTELList(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"))
+	}
+	resource := parseOTELList(os.Getenv("OTEL_RESOURCE_ATTRIBUTES"))
+	if name := os.Getenv("OTEL_SERVICE_NAME"); name != "" {
+		resource["service.name"] = name
+	} else if resource["service.name"] == "" {
+		resource["service.name"] = "arc-ask"
+	}
+
+	t := &tracer{endpoint: endpoint, headers: headers, resource: resource, traceID: randomHex(16)}
+	if m := traceparentPattern.FindStringSubmatch(os.Getenv("TRACEPARENT")); m != nil {
+		t.traceID, t.parentID = m[1], m[2]
+	}
+	return t
+}
+
+// parseOTELList parses the k=v,k=v format with URL-encoded values
+func parseOTELList(s string) map[string]string {
+	out := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		if dv, err := url.QueryUnescape(strings.TrimSpace(v)); err == nil {
+			v = dv
+		}
+		out[strings.TrimSpace(k)] = v
+	}
+	return out
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// OTLP JSON encoding (opentelemetry-proto, trace/v1)
+type otlpKeyValue struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue string `json:"stringValue"`
+	} `json:"value"`
+}
+
+type otlpSpan struct {
+	TraceID      string         `json:"traceId"`
+	SpanID       string         `json:"spanId"`
+	ParentSpanID string         `json:"parentSpanId,omitempty"`
+	Name         string         `json:"name"`
+	Kind         int            `json:"kind"`
+	Start        string         `json:"startTimeUnixNano"`
+	End          string         `json:"endTimeUnixNano"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+	Status       struct {
+		Code    int    `json:"code,omitempty"`
+		Message string `json:"message,omitempty"`
+	} `json:"status"`
+}
+
+// Span kinds and status codes from the OTLP spec
+const (
+	spanKindInternal = 1
+	spanKindClient   = 3
+	statusError      = 2
+)
+
+func otlpAttrs(m map[string]string) []otlpKeyValue {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	out := make([]otlpKeyValue, len(keys))
+	for i, k := range keys {
+		out[i].Key = k
+		out[i].Value.StringValue = m[k]
+	}
+	return out
+}
+
+func unixNano(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// export sends a root "arc-ask" span with one child span per phase
+func (t *tracer) export(phases []phaseTime, attrs map[string]string, runErr error) error {
+	if len(phases) == 0 {
+		return nil
+	}
+	end := phases[len(phases)-1].start.Add(phases[len(phases)-1].d)
+
+	root := otlpSpan{
+		TraceID:      t.traceID,
+		SpanID:       randomHex(8),
+		ParentSpanID: t.parentID,
+		Name:         "arc-ask",
+		Kind:         spanKindInternal,
+		Start:        unixNano(phases[0].start),
+		End:          unixNano(end),
+		Attributes:   otlpAttrs(attrs),
+	}
+	if runErr != nil {
+		root.Status.Code = statusError
+		root.Status.Message = runErr.Error()
+	}
+
+	spans := []otlpSpan{root}
+	for _, p := range phases {
+		name := spanNames[p.name]
+		if name == "" {
+			name = p.name
+		}
+		kind := spanKindInternal
+		if p.name == "generation" {
+			kind = spanKindClient
+		}
+		spans = append(spans, otlpSpan{
+			TraceID:      t.traceID,
+			SpanID:       randomHex(8),
+			ParentSpanID: root.SpanID,
+			Name:         name,
+			Kind:         kind,
+			Start:        unixNano(p.start),
+			End:          unixNano(p.start.Add(p.d)),
+		})
+	}
+
+	body := map[string]any{
+		"resourceSpans": []any{map[string]any{
+			"resource": map[string]any{"attributes": otlpAttrs(t.resource)},
+			"scopeSpans": []any{map[string]any{
+				"scope": map[string]any{"name": "arc-ask"},
+				"spans": spans,
+			}},
+		}},
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), traceExportTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range t.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("%s returned %s", t.endpoint, resp.Status)
+	}
+	return nil
+}
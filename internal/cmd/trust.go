@@ -0,0 +1,133 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Trust levels for input sources
+const (
+	trustTrusted   = "trusted"
+	trustUntrusted = "untrusted"
+)
+
+const (
+	maxURLContext  = 2 << 20
+	urlContextWait = 30 * time.Second
+)
+
+// TrustConfig is the trust section of ask.yaml
+type TrustConfig struct {
+	Input     string   `yaml:"input,omitempty"`     // stdin, pane, and shell output: trusted (default) or untrusted
+	URLs      string   `yaml:"urls,omitempty"`      // URL context: untrusted (default) or trusted
+	Untrusted []string `yaml:"untrusted,omitempty"` // context path globs that are always untrusted
+}
+
+// trustPolicy decides which sources are untrusted. Untrusted sources are
+// fenced off in the prompt, scanned for injection phrasing, and keep
+// tools from being enabled.
+type trustPolicy struct {
+	all      bool // --harden
+	input    bool
+	urls     bool
+	patterns []*regexp.Regexp
+	paths    []string // --context-untrusted files and directories
+}
+
+func newTrustPolicy(cfg TrustConfig, hardenMode string, untrustedPaths []string) (*trustPolicy, error) {
+	for key, level := range map[string]string{"trust.input": cfg.Input, "trust.urls": cfg.URLs} {
+		if level != "" && level != trustTrusted && level != trustUntrusted {
+			return nil, errors.NewCLIError(fmt.Sprintf("invalid %s %q in config", key, level)).
+				WithSuggestions("Use trusted or untrusted")
+		}
+	}
+	all := hardenMode != hardenOff
+	p := &trustPolicy{
+		all:      all,
+		input:    all || cfg.Input == trustUntrusted,
+		urls:     all || cfg.URLs != trustTrusted,
+		patterns: compileExcludes(cfg.Untrusted),
+	}
+	for _, path := range untrustedPaths {
+		p.paths = append(p.paths, filepath.Clean(path))
+	}
+	return p, nil
+}
+
+// untrusted reports whether a context path or URL is untrusted
+func (p *trustPolicy) untrusted(path string) bool {
+	if p.all {
+		return true
+	}
+	if isURL(path) {
+		return p.urls || matchesAny(p.patterns, path)
+	}
+	clean := filepath.Clean(path)
+	for _, u := range p.paths {
+		if clean == u || strings.HasPrefix(clean, u+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return excluded(p.patterns, path, path)
+}
+
+// sourceGuard fences untrusted sources and records what it saw
+type sourceGuard struct {
+	strip   bool
+	sources []string
+	hits    []injectionHit
+}
+
+// fence scans an untrusted source for injection phrasing, warns about any,
+// and wraps it for the prompt
+func (g *sourceGuard) fence(name, text string) string {
+	text, hits := scanInjection(text, g.strip)
+	if len(hits) > 0 {
+		verb := "found"
+		if g.strip {
+			verb = "removed"
+		}
+		fmt.Fprintf(os.Stderr, "Warning: possible prompt injection %s in %s: %s\n", verb, name, describeInjectionHits(hits))
+		for i := range hits {
+			hits[i].Source = name
+		}
+		g.hits = append(g.hits, hits...)
+	}
+	g.sources = append(g.sources, name)
+	return fenceUntrusted(text)
+}
+
+func isURL(path string) bool {
+	return strings.HasPrefix(path, "https://") || strings.HasPrefix(path, "http://")
+}
+
+// fetchContextURL downloads a URL given as --context
+func fetchContextURL(url string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), urlContextWait)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxURLContext))
+}
@@ -0,0 +1,120 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// confirm asks a yes/no question on the controlling terminal.
+// Stdin is often a pipe carrying the input, so the answer is read from
+// /dev/tty; without a terminal the question is declined.
+func confirm(question string) (bool, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return false, fmt.Errorf("no terminal available to confirm (use --yes to skip)")
+	}
+	defer tty.Close()
+
+	_, _ = fmt.Fprintf(tty, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(tty).ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
+
+// promptLine asks for a line of text on the controlling terminal,
+// returning def when the answer is empty
+func promptLine(question, def string) (string, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", fmt.Errorf("no terminal available to ask for %s", question)
+	}
+	defer tty.Close()
+
+	if def != "" {
+		_, _ = fmt.Fprintf(tty, "%s [%s]: ", question, def)
+	} else {
+		_, _ = fmt.Fprintf(tty, "%s: ", question)
+	}
+	answer, err := bufio.NewReader(tty).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// promptSecret asks for a line on the controlling terminal without echoing it
+func promptSecret(question string) (string, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", fmt.Errorf("no terminal available to ask for %s", question)
+	}
+	defer tty.Close()
+
+	if err := setEcho(tty, false); err == nil {
+		defer func() {
+			_ = setEcho(tty, true)
+			_, _ = fmt.Fprintln(tty)
+		}()
+	}
+
+	_, _ = fmt.Fprintf(tty, "%s: ", question)
+	answer, err := bufio.NewReader(tty).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(answer), nil
+}
+
+// setEcho toggles terminal echo with stty
+func setEcho(tty *os.File, on bool) error {
+	mode := "-echo"
+	if on {
+		mode = "echo"
+	}
+	_, err := stty(tty, mode)
+	return err
+}
+
+// stty runs stty on the terminal and returns what it printed
+func stty(tty *os.File, args ...string) (string, error) {
+	c := exec.Command("stty", args...)
+	c.Stdin = tty
+	out, err := c.Output()
+	return strings.TrimSpace(string(out)), err
+}
+
+// readKey shows prompt on the terminal and reads one keypress without
+// waiting for Enter. Ctrl-C arrives as a key rather than a signal, so the
+// terminal is always restored.
+func readKey(tty *os.File, prompt string) (byte, error) {
+	saved, err := stty(tty, "-g")
+	if err != nil {
+		return 0, err
+	}
+	if _, err := stty(tty, "-icanon", "-echo", "-isig", "min", "1"); err != nil {
+		return 0, err
+	}
+	defer func() { _, _ = stty(tty, saved) }()
+
+	_, _ = fmt.Fprint(tty, prompt)
+	var key [1]byte
+	_, err = tty.Read(key[:])
+	_, _ = fmt.Fprint(tty, "\r\x1b[K")
+	return key[0], err
+}
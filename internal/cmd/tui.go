@@ -0,0 +1,567 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// TUI layout
+const (
+	tuiSidebarWidth  = 26
+	tuiSourceLines   = 5 // preview lines of the input source
+	tuiPromptLines   = 3
+	tuiMinimumWidth  = 60
+	tuiMinimumHeight = 16
+)
+
+// TUI panes that take keys, in tab order
+const (
+	focusPrompt = iota
+	focusResponse
+	focusHistory
+	numFocus
+)
+
+var (
+	tuiBorder        = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("240"))
+	tuiFocusedBorder = tuiBorder.BorderForeground(lipgloss.Color("75"))
+	tuiTitle         = lipgloss.NewStyle().Bold(true)
+	tuiDim           = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	tuiSelected      = lipgloss.NewStyle().Reverse(true)
+	tuiError         = lipgloss.NewStyle().Foreground(lipgloss.Color("203"))
+	tuiUserLabel     = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("114"))
+)
+
+// tuiSource is where the TUI's input comes from: a pane, stdin, and
+// context files
+type tuiSource struct {
+	pane   string
+	lines  int
+	files  []string
+	label  string
+	text   string
+	stdin  string // read once at start; a pane is recaptured on demand
+	loaded bool
+}
+
+// load captures the pane, if any, and merges the context files
+func (s *tuiSource) load() error {
+	text := s.stdin
+	var parts []string
+	if s.pane != "" {
+		content, err := capturePane(s.pane, s.lines)
+		if err != nil {
+			return err
+		}
+		text = content
+		parts = append(parts, "pane "+s.pane)
+	} else if s.stdin != "" {
+		parts = append(parts, "stdin")
+	}
+	if len(s.files) > 0 {
+		merged, _, err := mergeContext(text, s.files, contextOptions{})
+		if err != nil {
+			return err
+		}
+		text = merged
+		parts = append(parts, fmt.Sprintf("%d context path(s)", len(s.files)))
+	}
+	s.text, s.loaded = text, true
+	s.label = "no input"
+	if len(parts) > 0 {
+		s.label = strings.Join(parts, " + ")
+	}
+	return nil
+}
+
+// Messages from the ask running in the background
+type (
+	tuiDeltaMsg  string
+	tuiAnswerMsg struct {
+		answer string
+		err    error
+	}
+)
+
+// tuiModel is the state of arc-ask tui
+type tuiModel struct {
+	client *BridgeClient
+	store  *SessionStore
+	sess   *Session
+	source *tuiSource
+
+	sessions []*Session // history, most recent first
+	selected int
+
+	prompt   textarea.Model
+	response viewport.Model
+	focus    int
+
+	width, height int
+
+	busy      bool
+	pending   string // the question being answered
+	streamed  string // answer text so far
+	deltas    chan string
+	cancel    context.CancelFunc
+	status    string
+	statusErr bool
+}
+
+func newTUICmd(client *BridgeClient) *cobra.Command {
+	var (
+		sessionID    string
+		pane         string
+		lines        int
+		contextFiles []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Full-screen interactive mode for exploratory debugging",
+		Long: `Open a full-screen view with four parts: the history of saved sessions,
+a preview of the input source (a tmux pane, stdin, and context files), the
+streaming response rendered as markdown, and a prompt editor.
+
+Every question is sent with the current input, and the conversation is
+saved like arc-ask chat, so it can be continued there or here later.
+
+Keys:
+  enter       send the prompt (ctrl+j for a new line)
+  tab         move between prompt, response, and history
+  up/down     scroll the response, or pick a session in history
+  enter       open the selected session (in history)
+  ctrl+r      recapture the pane
+  ctrl+n      start a new session
+  esc         stop the answer in progress
+  ctrl+c      quit`,
+		Example: `  arc-ask tui --pane dev:1.0
+  make test 2>&1 | arc-ask tui --context internal/
+  arc-ask tui --session debug-auth`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			src := &tuiSource{pane: pane, lines: lines, files: contextFiles}
+			stat, _ := os.Stdin.Stat()
+			piped := stat.Mode()&os.ModeCharDevice == 0
+			if piped && pane == "" {
+				text, err := gatherInput(cmd, "", 0, captureFilter{})
+				if err != nil {
+					return err
+				}
+				src.stdin = text
+			}
+			if err := src.load(); err != nil {
+				return err
+			}
+
+			store := NewSessionStore()
+			var (
+				sess *Session
+				err  error
+			)
+			if sessionID != "" && store.Exists(sessionID) {
+				sess, err = store.Load(sessionID)
+			} else {
+				sess, err = newSession(sessionID)
+			}
+			if err != nil {
+				return err
+			}
+
+			// Ask the terminal for its background before the program takes
+			// it over, or the answer is read as input
+			_ = lipgloss.HasDarkBackground()
+			m := newTUIModel(client, store, sess, src)
+			opts := []tea.ProgramOption{tea.WithAltScreen()}
+			if piped {
+				opts = append(opts, tea.WithInputTTY())
+			}
+			if _, err := tea.NewProgram(m, opts...).Run(); err != nil {
+				return errors.NewCLIError("tui failed").WithCause(err).
+					WithSuggestions("arc-ask tui needs a terminal; use arc-ask chat over a plain stream")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&sessionID, "session", "", "Session to create or continue")
+	cmd.Flags().StringVarP(&pane, "pane", "p", "", "Tmux pane to capture (recapture with ctrl+r)")
+	cmd.Flags().IntVarP(&lines, "lines", "n", 100, "Lines to capture from the pane")
+	cmd.Flags().StringArrayVarP(&contextFiles, "context", "c", nil, "Add context file(s)")
+	return cmd
+}
+
+func newTUIModel(client *BridgeClient, store *SessionStore, sess *Session, src *tuiSource) *tuiModel {
+	ta := textarea.New()
+	ta.Placeholder = "Ask about the input…"
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.KeyMap.InsertNewline = key.NewBinding(key.WithKeys("ctrl+j"))
+	ta.Focus()
+
+	m := &tuiModel{
+		client:   client,
+		store:    store,
+		sess:     sess,
+		source:   src,
+		prompt:   ta,
+		response: viewport.New(0, 0),
+	}
+	m.loadHistory()
+	return m
+}
+
+// loadHistory lists saved sessions, most recently updated first
+func (m *tuiModel) loadHistory() {
+	sessions, err := m.store.List()
+	if err != nil {
+		m.setStatus("cannot list sessions: "+err.Error(), true)
+		return
+	}
+	sort.SliceStable(sessions, func(i, j int) bool { return sessions[i].Updated.After(sessions[j].Updated) })
+	m.sessions = sessions
+	m.selected = 0
+	for i, s := range sessions {
+		if s.ID == m.sess.ID {
+			m.selected = i
+		}
+	}
+}
+
+func (m *tuiModel) setStatus(msg string, isErr bool) {
+	m.status, m.statusErr = msg, isErr
+}
+
+func (m *tuiModel) Init() tea.Cmd {
+	return textarea.Blink
+}
+
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.layout()
+		m.refreshResponse()
+		return m, nil
+
+	case tuiDeltaMsg:
+		m.streamed += string(msg)
+		m.refreshResponse()
+		return m, m.waitForDelta()
+
+	case tuiAnswerMsg:
+		return m, m.finish(msg)
+
+	case tea.KeyMsg:
+		if cmd, handled := m.handleKey(msg); handled {
+			return m, cmd
+		}
+	}
+
+	var cmd tea.Cmd
+	switch m.focus {
+	case focusPrompt:
+		m.prompt, cmd = m.prompt.Update(msg)
+	case focusResponse:
+		m.response, cmd = m.response.Update(msg)
+	}
+	return m, cmd
+}
+
+// handleKey runs the TUI's own key bindings and reports whether it used
+// the key
+func (m *tuiModel) handleKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	switch msg.String() {
+	case "ctrl+c":
+		if m.cancel != nil {
+			m.cancel()
+		}
+		return tea.Quit, true
+	case "esc":
+		if m.busy && m.cancel != nil {
+			m.cancel()
+			m.setStatus("Stopping…", false)
+		}
+		return nil, true
+	case "tab", "shift+tab":
+		step := 1
+		if msg.String() == "shift+tab" {
+			step = numFocus - 1
+		}
+		m.focus = (m.focus + step) % numFocus
+		if m.focus == focusPrompt {
+			return m.prompt.Focus(), true
+		}
+		m.prompt.Blur()
+		return nil, true
+	case "ctrl+r":
+		if m.source.pane == "" {
+			m.setStatus("No pane to recapture (start with --pane)", true)
+			return nil, true
+		}
+		if err := m.source.load(); err != nil {
+			m.setStatus(err.Error(), true)
+		} else {
+			m.setStatus("Recaptured "+m.source.pane, false)
+		}
+		return nil, true
+	case "ctrl+n":
+		if m.busy {
+			return nil, true
+		}
+		sess, err := newSession("")
+		if err != nil {
+			m.setStatus(err.Error(), true)
+			return nil, true
+		}
+		m.sess = sess
+		m.setStatus("New session "+sess.ID, false)
+		m.refreshResponse()
+		return nil, true
+	}
+
+	switch m.focus {
+	case focusPrompt:
+		if msg.String() == "enter" {
+			return m.send(), true
+		}
+	case focusHistory:
+		switch msg.String() {
+		case "up", "k":
+			m.selected = max(m.selected-1, 0)
+		case "down", "j":
+			m.selected = min(m.selected+1, len(m.sessions)-1)
+		case "enter":
+			m.openSelected()
+		}
+		return nil, true
+	}
+	return nil, false
+}
+
+// openSelected switches to the session picked in the history
+func (m *tuiModel) openSelected() {
+	if m.busy || m.selected < 0 || m.selected >= len(m.sessions) {
+		return
+	}
+	sess, err := m.store.Load(m.sessions[m.selected].ID)
+	if err != nil {
+		m.setStatus(err.Error(), true)
+		return
+	}
+	m.sess = sess
+	m.setStatus(fmt.Sprintf("Opened %s (%d turns)", sess.ID, len(sess.Turns)), false)
+	m.refreshResponse()
+}
+
+// send asks the prompt in the background, streaming the answer
+func (m *tuiModel) send() tea.Cmd {
+	question := strings.TrimSpace(m.prompt.Value())
+	if question == "" || m.busy {
+		return nil
+	}
+	m.prompt.Reset()
+	m.busy, m.pending, m.streamed = true, question, ""
+	m.setStatus("Asking…", false)
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.client.timeout)
+	m.cancel = cancel
+	deltas := make(chan string, 64)
+	m.deltas = deltas
+
+	client := *m.client
+	client.onText = func(delta string) { deltas <- delta }
+	// The captured source goes in the prompt, as for a plain question
+	prompt := m.sess.Prompt(question)
+	if m.source.text != "" {
+		prompt = fmt.Sprintf("%s\n\nInput:\n%s", prompt, m.source.text)
+	}
+	m.refreshResponse()
+
+	ask := func() tea.Msg {
+		defer cancel()
+		answer, err := client.Ask(ctx, prompt)
+		close(deltas)
+		return tuiAnswerMsg{answer: answer, err: err}
+	}
+	return tea.Batch(ask, m.waitForDelta())
+}
+
+// waitForDelta delivers the next piece of streamed text
+func (m *tuiModel) waitForDelta() tea.Cmd {
+	deltas := m.deltas
+	if deltas == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		delta, ok := <-deltas
+		if !ok {
+			return nil
+		}
+		return tuiDeltaMsg(delta)
+	}
+}
+
+// finish records an answer in the session
+func (m *tuiModel) finish(msg tuiAnswerMsg) tea.Cmd {
+	m.busy, m.cancel, m.deltas = false, nil, nil
+	answer := msg.answer
+	if partial, ok := msg.err.(*partialAnswerError); ok {
+		answer = partial.Partial
+	}
+	if msg.err != nil && answer == "" {
+		m.setStatus("AI query failed: "+msg.err.Error(), true)
+		m.prompt.SetValue(m.pending)
+		m.pending, m.streamed = "", ""
+		m.refreshResponse()
+		return nil
+	}
+
+	m.sess.Append(RoleUser, m.pending)
+	m.sess.Append(RoleAssistant, answer)
+	m.pending, m.streamed = "", ""
+	if err := m.store.Save(m.sess); err != nil {
+		m.setStatus("cannot save session: "+err.Error(), true)
+	} else if msg.err != nil {
+		m.setStatus("Answer incomplete: "+msg.err.Error(), true)
+	} else {
+		m.setStatus(fmt.Sprintf("Answered (~%d tokens)", ask.EstimateTokens(answer)), false)
+	}
+	m.loadHistory()
+	m.refreshResponse()
+	return nil
+}
+
+// layout sizes the panes to the window
+func (m *tuiModel) layout() {
+	mainWidth := m.width - tuiSidebarWidth - 4 // two borders each side
+	// Borders and titles: source 3, prompt 3, response 3, status 1
+	respHeight := m.height - tuiSourceLines - tuiPromptLines - 10
+	m.prompt.SetWidth(max(mainWidth, 10))
+	m.prompt.SetHeight(tuiPromptLines)
+	m.response.Width = max(mainWidth, 10)
+	m.response.Height = max(respHeight, 3)
+}
+
+// refreshResponse renders the conversation, with the answer in progress
+func (m *tuiModel) refreshResponse() {
+	width := m.response.Width
+	var b strings.Builder
+	for _, t := range m.sess.Turns {
+		writeTUITurn(&b, t.Role, t.Content, width)
+	}
+	if m.pending != "" {
+		writeTUITurn(&b, RoleUser, m.pending, width)
+		if m.streamed != "" {
+			writeTUITurn(&b, RoleAssistant, m.streamed+" ▍", width)
+		} else {
+			b.WriteString(tuiDim.Render("…") + "\n")
+		}
+	}
+	if b.Len() == 0 {
+		b.WriteString(tuiDim.Render("Ask a question about the input below. Answers appear here."))
+	}
+	m.response.SetContent(b.String())
+	m.response.GotoBottom()
+}
+
+func writeTUITurn(b *strings.Builder, role, content string, width int) {
+	if role == RoleUser {
+		b.WriteString(tuiUserLabel.Render("You") + "\n")
+		b.WriteString(lipgloss.NewStyle().Width(width).Render(content) + "\n\n")
+		return
+	}
+	b.WriteString(renderMarkdown(content, width) + "\n\n")
+}
+
+func (m *tuiModel) View() string {
+	if m.width < tuiMinimumWidth || m.height < tuiMinimumHeight {
+		return fmt.Sprintf("Window too small for arc-ask tui (need %dx%d). Resize, or ctrl+c to quit.", tuiMinimumWidth, tuiMinimumHeight)
+	}
+	mainWidth := m.response.Width
+
+	box := func(focused bool, title, body string, width, height int) string {
+		style := tuiBorder
+		if focused {
+			style = tuiFocusedBorder
+		}
+		return style.Width(width).Height(height).Render(tuiTitle.Render(title) + "\n" + body)
+	}
+
+	// Source preview: first and last lines, sized
+	src := m.source
+	title := fmt.Sprintf("Input: %s (%d lines, ~%s tokens)", src.label, countLines(src.text), formatTokens(ask.EstimateTokens(src.text)))
+	source := box(false, fitColumn(title, mainWidth), tuiDim.Render(previewLines(src.text, tuiSourceLines, mainWidth)), mainWidth, tuiSourceLines+1)
+
+	response := box(m.focus == focusResponse, "Response · "+m.sess.ID, m.response.View(), mainWidth, m.response.Height+1)
+	prompt := box(m.focus == focusPrompt, "Prompt", m.prompt.View(), mainWidth, tuiPromptLines+1)
+	main := lipgloss.JoinVertical(lipgloss.Left, source, response, prompt)
+
+	// History sidebar fills the height of the main column
+	var hist strings.Builder
+	for i, s := range m.sessions {
+		line := fitColumn(fmt.Sprintf("%s (%d)", s.ID, len(s.Turns)), tuiSidebarWidth-2)
+		switch {
+		case i == m.selected && m.focus == focusHistory:
+			line = tuiSelected.Render(line)
+		case s.ID == m.sess.ID:
+			line = tuiTitle.Render(line)
+		}
+		hist.WriteString("  " + line + "\n")
+	}
+	if len(m.sessions) == 0 {
+		hist.WriteString(tuiDim.Render("  no saved sessions"))
+	}
+	sidebar := box(m.focus == focusHistory, "History", hist.String(), tuiSidebarWidth, lipgloss.Height(main)-2)
+
+	status := tuiDim.Render("enter send · ctrl+j newline · tab focus · ctrl+r recapture · ctrl+n new · esc stop · ctrl+c quit")
+	if m.status != "" {
+		style := tuiDim
+		if m.statusErr {
+			style = tuiError
+		}
+		status = style.Render(fitColumn(m.status, m.width))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lipgloss.JoinHorizontal(lipgloss.Top, sidebar, main), status)
+}
+
+// previewLines shows the first and last lines of text, n in all
+func previewLines(text string, n, width int) string {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return "(empty)"
+	}
+	lines := strings.Split(text, "\n")
+	if len(lines) > n {
+		head := n / 2
+		tail := n - head - 1
+		omitted := fmt.Sprintf("… %d more lines …", len(lines)-head-tail)
+		lines = append(append(lines[:head:head], omitted), lines[len(lines)-tail:]...)
+	}
+	for i, l := range lines {
+		lines[i] = fitColumn(l, width)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func countLines(text string) int {
+	if text == "" {
+		return 0
+	}
+	return strings.Count(strings.TrimRight(text, "\n"), "\n") + 1
+}
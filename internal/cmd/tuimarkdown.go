@@ -0,0 +1,95 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Markdown styles for the TUI response pane
+var (
+	mdHeading = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("75"))
+	mdCode    = lipgloss.NewStyle().Foreground(lipgloss.Color("180"))
+	mdFence   = lipgloss.NewStyle().Foreground(lipgloss.Color("244"))
+	mdBold    = lipgloss.NewStyle().Bold(true)
+	mdQuote   = lipgloss.NewStyle().Foreground(lipgloss.Color("244")).Italic(true)
+)
+
+var (
+	mdHeadingLine = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	mdBullet      = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	mdInlineCode  = regexp.MustCompile("`([^`]+)`")
+	mdStrong      = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+)
+
+// renderMarkdown styles the common parts of a markdown answer for the
+// terminal: headings, lists, quotes, inline code, bold, and fenced code
+// blocks, which are kept unwrapped. Prose is wrapped to width.
+func renderMarkdown(text string, width int) string {
+	if width < 20 {
+		width = 20
+	}
+	wrap := lipgloss.NewStyle().Width(width)
+	var (
+		out    []string
+		inCode bool
+	)
+	for _, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inCode = !inCode
+			out = append(out, mdFence.Render(fitColumn(line, width)))
+			continue
+		}
+		if inCode {
+			out = append(out, mdCode.Render(fitColumn(line, width)))
+			continue
+		}
+		switch {
+		case trimmed == "":
+			out = append(out, "")
+		case mdHeadingLine.MatchString(trimmed):
+			m := mdHeadingLine.FindStringSubmatch(trimmed)
+			out = append(out, mdHeading.Width(width).Render(m[2]))
+		case mdBullet.MatchString(line):
+			m := mdBullet.FindStringSubmatch(line)
+			indent := len(m[1])
+			body := lipgloss.NewStyle().Width(max(width-indent-2, 10)).Render(renderInline(m[2]))
+			out = append(out, indentBlock(body, strings.Repeat(" ", indent)+"• ", strings.Repeat(" ", indent+2)))
+		case strings.HasPrefix(trimmed, ">"):
+			body := strings.TrimSpace(strings.TrimPrefix(trimmed, ">"))
+			out = append(out, indentBlock(mdQuote.Width(max(width-2, 10)).Render(body), "│ ", "│ "))
+		default:
+			out = append(out, wrap.Render(renderInline(line)))
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// renderInline styles inline code and bold text
+func renderInline(s string) string {
+	s = mdInlineCode.ReplaceAllStringFunc(s, func(m string) string {
+		return mdCode.Render(mdInlineCode.FindStringSubmatch(m)[1])
+	})
+	return mdStrong.ReplaceAllStringFunc(s, func(m string) string {
+		return mdBold.Render(mdStrong.FindStringSubmatch(m)[1])
+	})
+}
+
+// indentBlock prefixes the first line of a block with first and the rest
+// with rest
+func indentBlock(block, first, rest string) string {
+	lines := strings.Split(block, "\n")
+	for i, l := range lines {
+		if i == 0 {
+			lines[i] = first + l
+		} else {
+			lines[i] = rest + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
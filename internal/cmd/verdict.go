@@ -0,0 +1,31 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Verdict values emitted by verdict-style templates
+const (
+	VerdictPass = "PASS"
+	VerdictWarn = "WARN"
+	VerdictFail = "FAIL"
+)
+
+var verdictPattern = regexp.MustCompile(`(?i)^\**verdict\**\s*:\s*\**\s*(pass|warn|fail)\b`)
+
+// parseVerdict finds the last VERDICT line in an answer.
+// It returns the normalized verdict and whether one was found.
+func parseVerdict(answer string) (string, bool) {
+	lines := strings.Split(answer, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		m := verdictPattern.FindStringSubmatch(strings.TrimSpace(lines[i]))
+		if m != nil {
+			return strings.ToUpper(m[1]), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,267 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Transcription providers for --mic
+const (
+	transcribeWhisperCpp = "whisper-cpp" // local whisper.cpp
+	transcribeOpenAI     = "openai"      // OpenAI-compatible /audio/transcriptions
+)
+
+const (
+	defaultWhisperCommand  = "whisper-cli"
+	defaultTranscribeURL   = "https://api.openai.com/v1/audio/transcriptions"
+	defaultTranscribeModel = "whisper-1"
+	defaultMicMax          = 2 * time.Minute
+	minRecordingBytes      = 1024 // a WAV header and a moment of audio
+	transcribeTimeout      = 2 * time.Minute
+)
+
+// TranscriptionConfig is the transcription section of ask.yaml
+type TranscriptionConfig struct {
+	Provider string `yaml:"provider,omitempty"` // whisper-cpp (default) or openai
+	Model    string `yaml:"model,omitempty"`    // ggml model file, or API model name
+	Command  string `yaml:"command,omitempty"`  // whisper.cpp binary, default whisper-cli
+	URL      string `yaml:"url,omitempty"`      // transcription endpoint for openai
+	APIKey   string `yaml:"api_key,omitempty"`  // default: OPENAI_API_KEY
+	Recorder string `yaml:"recorder,omitempty"` // sox or ffmpeg; default: whichever is installed
+}
+
+// recorders build the command that records mono 16 kHz WAV, which
+// whisper expects, from the default input device
+var recorders = map[string]func(path string, max time.Duration) []string{
+	"sox": func(path string, max time.Duration) []string {
+		return []string{"sox", "-q", "-d", "-c", "1", "-r", "16000", "-b", "16", path, "trim", "0", seconds(max)}
+	},
+	"ffmpeg": func(path string, max time.Duration) []string {
+		input := []string{"-f", "pulse", "-i", "default"}
+		if runtime.GOOS == "darwin" {
+			input = []string{"-f", "avfoundation", "-i", ":0"}
+		}
+		args := append([]string{"ffmpeg", "-loglevel", "error", "-nostdin", "-y"}, input...)
+		return append(args, "-ac", "1", "-ar", "16000", "-t", seconds(max), path)
+	},
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprint(int(d.Seconds()))
+}
+
+// pickRecorder returns the configured recorder, or the first installed one
+func pickRecorder(name string) (string, error) {
+	if name != "" {
+		if _, ok := recorders[name]; !ok {
+			return "", errors.NewCLIError(fmt.Sprintf("unknown recorder %q", name)).
+				WithSuggestions("Set transcription.recorder to sox or ffmpeg")
+		}
+		return name, nil
+	}
+	for _, name := range []string{"sox", "ffmpeg"} {
+		if _, err := exec.LookPath(name); err == nil {
+			return name, nil
+		}
+	}
+	return "", errors.NewCLIError("--mic needs sox or ffmpeg to record").
+		WithSuggestions("Install sox: brew install sox / apt install sox")
+}
+
+// check reports missing settings before anything is recorded
+func (c TranscriptionConfig) check() error {
+	switch c.Provider {
+	case "", transcribeWhisperCpp:
+		if c.Model == "" {
+			return errors.NewCLIError("whisper.cpp needs a model file").
+				WithSuggestions("Set transcription.model in ask.yaml, e.g. ~/models/ggml-base.en.bin",
+					"Or use an API: transcription.provider: openai")
+		}
+	case transcribeOpenAI:
+		if c.apiKey() == "" {
+			return errors.NewCLIError("no API key for transcription").
+				WithSuggestions("Set OPENAI_API_KEY or transcription.api_key in ask.yaml")
+		}
+	default:
+		return errors.NewCLIError(fmt.Sprintf("unknown transcription provider %q", c.Provider)).
+			WithSuggestions("Set transcription.provider to whisper-cpp or openai")
+	}
+	return nil
+}
+
+func (c TranscriptionConfig) apiKey() string {
+	if c.APIKey != "" {
+		return c.APIKey
+	}
+	return os.Getenv("OPENAI_API_KEY")
+}
+
+// recordQuestion records until Enter is pressed or max elapses and
+// returns the transcript
+func recordQuestion(ctx context.Context, cfg TranscriptionConfig, max time.Duration) (string, error) {
+	if err := cfg.check(); err != nil {
+		return "", err
+	}
+	name, err := pickRecorder(cfg.Recorder)
+	if err != nil {
+		return "", err
+	}
+	base, err := privateTempDir()
+	if err != nil {
+		return "", err
+	}
+	dir, err := os.MkdirTemp(base, tempPrefix+"mic-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "question.wav")
+
+	if err := recordAudio(name, path, max); err != nil {
+		return "", err
+	}
+	fmt.Fprintln(os.Stderr, "Transcribing...")
+	text, err := transcribe(ctx, cfg, path)
+	if err != nil {
+		return "", errors.NewCLIError("transcription failed").WithCause(err)
+	}
+	if text == "" {
+		return "", errors.NewCLIError("no speech recognized").
+			WithSuggestions("Check the input device and speak after \"Recording\" appears")
+	}
+	return text, nil
+}
+
+// recordAudio runs the recorder; Enter on the terminal stops it early
+func recordAudio(name, path string, max time.Duration) error {
+	argv := recorders[name](path, max)
+	rec := execCommand(argv[0], argv[1:]...)
+	var stderr bytes.Buffer
+	rec.Stderr = &stderr
+	if err := rec.Start(); err != nil {
+		return errors.NewCLIError("failed to start " + name).WithCause(err)
+	}
+
+	stopped := make(chan struct{})
+	if tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0); err == nil {
+		defer tty.Close()
+		_, _ = fmt.Fprintf(tty, "Recording... press Enter to stop (max %s)\n", max)
+		go func() {
+			if _, err := bufio.NewReader(tty).ReadString('\n'); err == nil {
+				// Both recorders finish the file cleanly on SIGINT
+				close(stopped)
+				_ = rec.Process.Signal(os.Interrupt)
+			}
+		}()
+	} else {
+		fmt.Fprintf(os.Stderr, "Recording for %s...\n", max)
+	}
+
+	err := rec.Wait()
+	select {
+	case <-stopped:
+		err = nil
+	default:
+	}
+	if err != nil {
+		return errors.NewCLIError(name + " failed to record").
+			WithCause(fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))).
+			WithSuggestions("Check that a microphone is available to " + name)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() < minRecordingBytes {
+		return errors.NewCLIError("recording is empty").
+			WithSuggestions("Check the input device and microphone permissions")
+	}
+	return nil
+}
+
+// transcribe turns a WAV file into text with the configured provider
+func transcribe(ctx context.Context, cfg TranscriptionConfig, path string) (string, error) {
+	if cfg.Provider == transcribeOpenAI {
+		return transcribeAPI(ctx, cfg, path)
+	}
+	return transcribeWhisperCppFile(cfg, path)
+}
+
+func transcribeWhisperCppFile(cfg TranscriptionConfig, path string) (string, error) {
+	command := cfg.Command
+	if command == "" {
+		command = defaultWhisperCommand
+	}
+	out, err := execCommand(command, "-m", ask.ExpandHome(cfg.Model), "-f", path, "-nt", "-np").Output()
+	if err != nil {
+		if exit, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("%s: %w: %s", command, err, strings.TrimSpace(string(exit.Stderr)))
+		}
+		return "", fmt.Errorf("%s: %w", command, err)
+	}
+	return strings.Join(strings.Fields(string(out)), " "), nil
+}
+
+func transcribeAPI(ctx context.Context, cfg TranscriptionConfig, path string) (string, error) {
+	url, model := cfg.URL, cfg.Model
+	if url == "" {
+		url = defaultTranscribeURL
+	}
+	if model == "" {
+		model = defaultTranscribeModel
+	}
+
+	audio, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	_ = form.WriteField("model", model)
+	_ = form.WriteField("response_format", "text")
+	part, err := form.CreateFormFile("file", filepath.Base(path))
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(audio); err != nil {
+		return "", err
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, transcribeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, &body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+cfg.apiKey())
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	text, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("POST %s: %s: %s", url, resp.Status, strings.TrimSpace(string(text)))
+	}
+	return strings.TrimSpace(string(text)), nil
+}
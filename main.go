@@ -4,7 +4,6 @@
 package main
 
 import (
-	"fmt"
 	"os"
 
 	"github.com/yourorg/arc-ask/internal/cmd"
@@ -12,8 +11,9 @@ import (
 
 func main() {
 	root := cmd.NewRootCmd()
-	if err := root.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "arc-ask: %v\n", err)
-		os.Exit(1)
+	err := root.Execute()
+	cmd.RecordStats(err)
+	if err != nil {
+		os.Exit(cmd.ReportError(err))
 	}
 }
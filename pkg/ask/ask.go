@@ -0,0 +1,47 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package ask is the arc-ask pipeline as a library: input gathering,
+// template resolution, prompt assembly, the provider call, and output
+// shaping. Programs that embed it supply a Client, such as pi.Client from
+// the pi subpackage, and the input; the arc-ask command adds context
+// packing, caching, and output formats on top.
+//
+//	runner := &ask.Runner{
+//		Client:    &pi.Client{Provider: "anthropic"},
+//		Templates: &ask.Templates{Dir: ask.ExpandHome("~/.config/arc/prompts")},
+//	}
+//	res, err := runner.Run(ctx, ask.Request{Prompt: "@code-review", Input: diff})
+package ask
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// Client answers prompts. pi.Client runs pi, which the arc-ask command
+// wraps with rate limits and retries; fixture.Client replays recorded
+// answers.
+type Client interface {
+	Ask(ctx context.Context, prompt string) (string, error)
+	AskWithContext(ctx context.Context, prompt, context string) (string, error)
+	AskWithTools(ctx context.Context, prompt string, tools []string) (string, error)
+	IsDaemonRunning() bool
+}
+
+// PromptShaper is a Client with its own rule for sending a system prompt,
+// such as in a role of its own. ShapePrompt returns the client to ask and
+// the prompt to send it; other clients are sent JoinPrompt's result.
+type PromptShaper interface {
+	ShapePrompt(system, user string) (Client, string)
+}
+
+// ExpandHome replaces a leading ~/ with the user's home directory
+func ExpandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		return home + path[1:]
+	}
+	return path
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ask
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Output contract types
+const (
+	contractJSONSchema = "json-schema"
+	contractRegex      = "regex"
+	contractCodeOnly   = "code-only"
+)
+
+// OutputContract is a template's promise about the shape of its answer
+type OutputContract struct {
+	Type       string         `yaml:"type"`
+	Schema     map[string]any `yaml:"schema"`      // json-schema, inline
+	SchemaFile string         `yaml:"schema_file"` // json-schema, relative to the template
+	Pattern    string         `yaml:"pattern"`     // regex
+
+	schema map[string]any // resolved on load, never cached
+}
+
+// load validates a template's contract and resolves its schema. It runs on
+// every load so edits to a schema_file are picked up.
+func (c *OutputContract) load(t *Template) error {
+	fail := func(msg string) error {
+		return errors.NewCLIError(fmt.Sprintf("template @%s: output contract %s", t.Name, msg))
+	}
+	switch c.Type {
+	case contractJSONSchema:
+		c.schema = c.Schema
+		if c.SchemaFile != "" {
+			if c.Schema != nil {
+				return fail("sets both schema and schema_file")
+			}
+			path := c.SchemaFile
+			if !filepath.IsAbs(path) && t.Path != "" {
+				path = filepath.Join(filepath.Dir(t.Path), path)
+			}
+			data, err := os.ReadFile(ExpandHome(path))
+			if err != nil {
+				return fail("schema_file: " + err.Error())
+			}
+			if err := json.Unmarshal(data, &c.schema); err != nil {
+				return fail("schema_file is not valid JSON: " + err.Error())
+			}
+		}
+		if c.schema == nil {
+			return fail("json-schema needs schema or schema_file")
+		}
+		c.schema, _ = normalizeJSON(c.schema).(map[string]any)
+	case contractRegex:
+		if _, err := regexp.Compile(c.Pattern); err != nil || c.Pattern == "" {
+			return fail("regex needs a valid pattern")
+		}
+	case contractCodeOnly:
+	default:
+		return fail(fmt.Sprintf("has unknown type %q (use json-schema, regex, or code-only)", c.Type))
+	}
+	return nil
+}
+
+// Instructions tells the model about the contract up front
+func (c *OutputContract) Instructions() string {
+	switch c.Type {
+	case contractJSONSchema:
+		schema, _ := json.MarshalIndent(c.schema, "", "  ")
+		return "Respond with only a JSON value matching this JSON Schema, with no prose or code fences:\n" + string(schema)
+	case contractRegex:
+		return "Your entire answer must match this regular expression: " + c.Pattern
+	case contractCodeOnly:
+		return "Respond with only code. Do not add explanations before or after it."
+	}
+	return ""
+}
+
+// Enforce validates an answer and returns it normalized (bare JSON, bare
+// code). The error describes why the answer violates the contract.
+func (c *OutputContract) Enforce(answer string) (string, error) {
+	switch c.Type {
+	case contractJSONSchema:
+		text := strings.TrimSpace(answer)
+		if blocks := CodeBlocks(answer); len(blocks) > 0 {
+			text = strings.TrimSpace(blocks[0].Code)
+		}
+		var v any
+		if err := json.Unmarshal([]byte(text), &v); err != nil {
+			return "", fmt.Errorf("answer is not valid JSON: %w", err)
+		}
+		if err := validateSchema(c.schema, v); err != nil {
+			return "", err
+		}
+		return text, nil
+	case contractRegex:
+		if !regexp.MustCompile(c.Pattern).MatchString(strings.TrimSpace(answer)) {
+			return "", fmt.Errorf("answer does not match %s", c.Pattern)
+		}
+		return strings.TrimSpace(answer), nil
+	case contractCodeOnly:
+		blocks := CodeBlocks(answer)
+		if len(blocks) == 0 {
+			return answer, nil
+		}
+		if len(blocks) > 1 || strings.TrimSpace(StripCodeBlocks(answer)) != "" {
+			return "", fmt.Errorf("answer contains prose or several code blocks; only code was expected")
+		}
+		return blocks[0].Code, nil
+	}
+	return answer, nil
+}
+
+// EnforceContract validates an answer against t's output contract and, if
+// it fails, retries once with the validation error appended to the prompt.
+// It reports whether it retried.
+func EnforceContract(ctx context.Context, client Client, t *Template, prompt, answer string) (string, bool, error) {
+	c := t.Output
+	fixed, err := c.Enforce(answer)
+	if err == nil {
+		return fixed, false, nil
+	}
+
+	retry := fmt.Sprintf("%s\n\nYour previous answer was rejected: %v\n\nPrevious answer:\n%s\n\n%s", prompt, err, answer, c.Instructions())
+	answer, askErr := client.Ask(ctx, retry)
+	if askErr != nil {
+		return "", true, errors.NewCLIError("AI query failed").WithCause(askErr)
+	}
+	if fixed, err = c.Enforce(answer); err != nil {
+		return "", true, errors.NewCLIError(fmt.Sprintf("answer does not satisfy @%s output contract", t.Name)).
+			WithCause(err)
+	}
+	return fixed, true, nil
+}
@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ask
+
+import (
+	"fmt"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Extract modes: how an answer is post-processed
+const (
+	ExtractModeNone = "none"
+	ExtractModeCode = "code" // first fenced block
+)
+
+// TemplateDefaults are generation settings a template applies unless the
+// caller sets them
+type TemplateDefaults struct {
+	MaxTokens   *int     `yaml:"max_tokens"`
+	Temperature *float64 `yaml:"temperature"`
+	Output      string   `yaml:"output"`   // arc-ask --output value
+	Extract     string   `yaml:"extract"`  // an extract mode
+	Model       string   `yaml:"model"`    // a model ID or alias
+	Thinking    string   `yaml:"thinking"` // a reasoning level or token budget
+}
+
+func (d *TemplateDefaults) check(t *Template) error {
+	fail := func(msg string) error {
+		return errors.NewCLIError(fmt.Sprintf("template @%s: defaults %s", t.Name, msg))
+	}
+	if d.MaxTokens != nil && *d.MaxTokens < 1 {
+		return fail("max_tokens must be at least 1")
+	}
+	if d.Temperature != nil && (*d.Temperature < 0 || *d.Temperature > 2) {
+		return fail("temperature must be between 0 and 2")
+	}
+	if err := ValidateExtract(d.Extract); d.Extract != "" && err != nil {
+		return fail(err.Error())
+	}
+	return nil
+}
+
+// ValidateExtract checks an extract mode
+func ValidateExtract(mode string) error {
+	switch mode {
+	case ExtractModeNone, ExtractModeCode:
+		return nil
+	}
+	return fmt.Errorf("invalid extract mode %q (use none or code)", mode)
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ask
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AnswerCache keeps answers across template evaluations. Keys are hashes
+// of the full prompt, so a changed template or input misses; answers from
+// a different client or model are not told apart, so use one cache per
+// client.
+type AnswerCache interface {
+	Get(key string) (string, bool)
+	Put(key, answer string)
+}
+
+// TemplateFuncs lets text/template and html/template call arc-ask:
+//
+//	funcs := &ask.TemplateFuncs{Runner: runner, Timeout: time.Minute}
+//	t, err := template.New("report").Funcs(funcs.FuncMap(ctx)).Parse(src)
+//
+// registers
+//
+//	{{ask "summarize" .Data}}
+//	{{ask "@code-review" .Diff}}
+//	{{askWith "@release-notes" .Vars .Log}}
+//
+// Input that is not text is passed as indented JSON. A template that
+// repeats a call, or is executed again with the same FuncMap, gets the
+// first answer without asking again. Failures stop the template's
+// execution and are not remembered, so executing it again retries them.
+type TemplateFuncs struct {
+	Runner *Runner
+
+	// Timeout bounds each call; zero leaves only the FuncMap's context
+	Timeout time.Duration
+
+	// MaxCalls caps the calls one FuncMap sends to the client, so a
+	// range over a large list fails instead of asking once per item;
+	// zero allows any number. Repeats answered from memory are free.
+	MaxCalls int
+
+	// Cache, when set, shares answers between FuncMaps and processes
+	Cache AnswerCache
+}
+
+// funcCall is a call that is answered or in flight; concurrent
+// executions asking the same wait for one answer
+type funcCall struct {
+	done   chan struct{}
+	answer string
+	err    error
+}
+
+// FuncMap returns the ask and askWith functions bound to ctx. Each
+// FuncMap remembers its own answers; it is safe to share between
+// templates executing concurrently.
+func (f *TemplateFuncs) FuncMap(ctx context.Context) map[string]any {
+	var (
+		mu    sync.Mutex
+		memo  = make(map[string]*funcCall)
+		calls int
+	)
+	run := func(prompt string, vars map[string]string, input []any) (string, error) {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		req := Request{Prompt: prompt, Vars: vars}
+		parts := make([]string, 0, len(input))
+		for _, v := range input {
+			text, err := templateInput(v)
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", prompt, err)
+			}
+			parts = append(parts, text)
+		}
+		req.Input = strings.Join(parts, "\n\n")
+
+		full, err := f.Runner.Prompt(req)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", prompt, err)
+		}
+		sum := sha256.Sum256([]byte(full))
+		key := hex.EncodeToString(sum[:])
+
+		mu.Lock()
+		if c, ok := memo[key]; ok {
+			mu.Unlock()
+			<-c.done
+			return c.answer, c.err
+		}
+		if f.Cache != nil {
+			if answer, ok := f.Cache.Get(key); ok {
+				mu.Unlock()
+				return answer, nil
+			}
+		}
+		if f.MaxCalls > 0 && calls >= f.MaxCalls {
+			mu.Unlock()
+			return "", fmt.Errorf("%s: more than %d calls in one template", prompt, f.MaxCalls)
+		}
+		calls++
+		c := &funcCall{done: make(chan struct{})}
+		memo[key] = c
+		mu.Unlock()
+
+		callCtx := ctx
+		if f.Timeout > 0 {
+			var cancel context.CancelFunc
+			callCtx, cancel = context.WithTimeout(ctx, f.Timeout)
+			defer cancel()
+		}
+		res, err := f.Runner.Run(callCtx, req)
+		if err != nil {
+			c.err = fmt.Errorf("%s: %w", prompt, err)
+			mu.Lock()
+			delete(memo, key)
+			mu.Unlock()
+		} else {
+			c.answer = strings.TrimSpace(res.Response)
+			if f.Cache != nil {
+				f.Cache.Put(key, c.answer)
+			}
+		}
+		close(c.done)
+		return c.answer, c.err
+	}
+
+	return map[string]any{
+		"ask": func(prompt string, input ...any) (string, error) {
+			return run(prompt, nil, input)
+		},
+		"askWith": func(prompt string, vars any, input ...any) (string, error) {
+			v, err := templateVars(vars)
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", prompt, err)
+			}
+			return run(prompt, v, input)
+		},
+	}
+}
+
+// templateInput turns a template value into prompt input: text as is,
+// anything else as indented JSON
+func templateInput(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case []byte:
+		return string(v), nil
+	case fmt.Stringer:
+		return v.String(), nil
+	case error:
+		return v.Error(), nil
+	}
+	// Named string types, such as template.HTML
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.String {
+		return rv.String(), nil
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("input is not text and cannot be JSON: %w", err)
+	}
+	return string(data), nil
+}
+
+// templateVars accepts a map of template variables with string keys
+func templateVars(v any) (map[string]string, error) {
+	switch v := v.(type) {
+	case nil:
+		return nil, nil
+	case map[string]string:
+		return v, nil
+	case map[string]any:
+		out := make(map[string]string, len(v))
+		for k, val := range v {
+			text, err := templateInput(val)
+			if err != nil {
+				return nil, fmt.Errorf("variable %s: %w", k, err)
+			}
+			out[k] = text
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("variables must be a map with string keys, not %T", v)
+}
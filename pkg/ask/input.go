@@ -0,0 +1,52 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ask
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/yourorg/arc-tmux/pkg/tmux"
+)
+
+// ReadStdin returns what is piped to standard input, or "" when it is a
+// terminal
+func ReadStdin() (string, error) {
+	stat, err := os.Stdin.Stat()
+	if err != nil || stat.Mode()&os.ModeCharDevice != 0 {
+		return "", nil
+	}
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// CapturePane returns the last lines of a tmux pane, given as
+// session:window.pane
+func CapturePane(target string, lines int) (string, error) {
+	if err := tmux.ValidateTarget(target); err != nil {
+		return "", fmt.Errorf("invalid pane target %q: %w", target, err)
+	}
+	content, err := tmux.Capture(target, lines)
+	if err != nil {
+		return "", fmt.Errorf("capture pane %s: %w", target, err)
+	}
+	return content, nil
+}
+
+// ReadContextFiles reads files for Request.Context, in order
+func ReadContextFiles(paths ...string) ([]ContextFile, error) {
+	files := make([]ContextFile, 0, len(paths))
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, ContextFile{Name: p, Text: string(data)})
+	}
+	return files, nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ask
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yourorg/arc-sdk/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Model capabilities a template may require
+const (
+	CapabilityVision    = "vision"    // takes images
+	CapabilityReasoning = "reasoning" // thinks before answering
+	CapabilityTools     = "tools"     // calls tools
+)
+
+// ModelCapabilities lists every capability name
+var ModelCapabilities = []string{CapabilityVision, CapabilityReasoning, CapabilityTools}
+
+// ModelTiers are the model tiers, smallest first
+var ModelTiers = []string{"small", "medium", "large"}
+
+// tierAliases let min_tier name a familiar model family instead
+var tierAliases = map[string]string{
+	"haiku":  "small",
+	"mini":   "small",
+	"flash":  "small",
+	"sonnet": "medium",
+	"opus":   "large",
+}
+
+// TierRank orders a tier or family alias, 1 being the smallest; ok is
+// false for unknown names
+func TierRank(tier string) (rank int, ok bool) {
+	tier = strings.ToLower(tier)
+	if t, found := tierAliases[tier]; found {
+		tier = t
+	}
+	for i, t := range ModelTiers {
+		if t == tier {
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
+
+// StringList is a YAML list that may also be written as a single string
+type StringList []string
+
+// UnmarshalYAML accepts both "vision" and [vision, tools]
+func (l *StringList) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*l = StringList{value.Value}
+		return nil
+	}
+	var list []string
+	if err := value.Decode(&list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
+// checkModelRequirements reports unknown capabilities and tiers
+func (t *Template) checkModelRequirements() error {
+	for _, c := range t.Requires {
+		known := false
+		for _, k := range ModelCapabilities {
+			known = known || c == k
+		}
+		if !known {
+			return errors.NewCLIError(fmt.Sprintf("template @%s: requires unknown capability %q", t.Name, c)).
+				WithSuggestions("Use one of: " + strings.Join(ModelCapabilities, ", "))
+		}
+	}
+	if _, ok := TierRank(t.MinTier); t.MinTier != "" && !ok {
+		return errors.NewCLIError(fmt.Sprintf("template @%s: unknown min_tier %q", t.Name, t.MinTier)).
+			WithSuggestions("Use one of: " + strings.Join(ModelTiers, ", ") + " (or haiku, sonnet, opus)")
+	}
+	return nil
+}
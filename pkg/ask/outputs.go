@@ -0,0 +1,199 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ask
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// How an answer with named outputs is split into them
+const (
+	OutputsSections = "sections" // === name === delimiter lines
+	OutputsJSON     = "json"     // one JSON object keyed by name
+)
+
+// Output destinations; file: and note: take a path or folder after the colon
+const (
+	DestStdout = "stdout"
+	DestStderr = "stderr"
+	DestFile   = "file"
+	DestNote   = "note"
+)
+
+var (
+	outputNamePattern   = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+	outputMarkerPattern = regexp.MustCompile(`(?m)^[ \t]*=== *([A-Za-z0-9_-]+) *===[ \t]*$`)
+)
+
+// NamedOutput is one part of an answer a template asks for, and where it
+// goes
+type NamedOutput struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	To          string `yaml:"to"` // stdout (default), stderr, file:PATH, or note:FOLDER
+	Optional    bool   `yaml:"optional"`
+}
+
+// ParseDest splits a destination into its kind and argument
+func ParseDest(to string) (kind, arg string, err error) {
+	if to == "" {
+		return DestStdout, "", nil
+	}
+	kind, arg, _ = strings.Cut(to, ":")
+	switch kind {
+	case DestStdout, DestStderr:
+		if arg != "" {
+			return "", "", fmt.Errorf("%s takes no argument", kind)
+		}
+	case DestFile:
+		if arg == "" {
+			return "", "", fmt.Errorf("file needs a path (file:PATH)")
+		}
+	case DestNote:
+		if arg == "" {
+			return "", "", fmt.Errorf("note needs a folder (note:FOLDER)")
+		}
+	default:
+		return "", "", fmt.Errorf("unknown destination %q (use stdout, stderr, file:PATH, or note:FOLDER)", to)
+	}
+	return kind, arg, nil
+}
+
+// checkOutputs reports named outputs that can never be routed
+func (t *Template) checkOutputs() error {
+	if len(t.Outputs) == 0 {
+		if t.OutputsFormat != "" {
+			return errors.NewCLIError(fmt.Sprintf("template @%s: outputs_format is set without outputs", t.Name))
+		}
+		return nil
+	}
+	fail := func(msg string) error {
+		return errors.NewCLIError(fmt.Sprintf("template @%s: outputs %s", t.Name, msg))
+	}
+	if t.Output != nil {
+		return fail("cannot be combined with an output contract")
+	}
+	switch t.OutputsFormat {
+	case "", OutputsSections, OutputsJSON:
+	default:
+		return fail(fmt.Sprintf("has unknown outputs_format %q (use sections or json)", t.OutputsFormat))
+	}
+	seen := make(map[string]bool)
+	for _, o := range t.Outputs {
+		if !outputNamePattern.MatchString(o.Name) {
+			return fail(fmt.Sprintf("name %q must be letters, digits, - or _", o.Name))
+		}
+		if seen[o.Name] {
+			return fail(fmt.Sprintf("declare %q twice", o.Name))
+		}
+		seen[o.Name] = true
+		kind, arg, err := ParseDest(o.To)
+		if err != nil {
+			return fail(fmt.Sprintf("%q: %v", o.Name, err))
+		}
+		// Templates may come from shared packs, so they only write below
+		// the working directory; --route can send a part anywhere
+		if kind == DestFile && (strings.HasPrefix(arg, "~") || !filepath.IsLocal(arg)) {
+			return fail(fmt.Sprintf("%q: file path %q must be relative and stay inside the working directory", o.Name, arg))
+		}
+	}
+	return nil
+}
+
+// OutputsInstructions tells the model which parts to produce and how to
+// mark them
+func (t *Template) OutputsInstructions() string {
+	var b strings.Builder
+	if t.OutputsFormat == OutputsJSON {
+		b.WriteString("Respond with only a JSON object, with no prose or code fences, with these string fields:\n")
+	} else {
+		b.WriteString("Structure your answer as these parts, each starting with its marker line exactly as shown (e.g. === name ===), with nothing before the first marker:\n")
+	}
+	for _, o := range t.Outputs {
+		fmt.Fprintf(&b, "- %s", o.Name)
+		if o.Description != "" {
+			fmt.Fprintf(&b, ": %s", o.Description)
+		}
+		if o.Optional {
+			b.WriteString(" (optional; omit it if it does not apply)")
+		}
+		b.WriteByte('\n')
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// SplitOutputs divides an answer into the template's named outputs. The
+// error names the required outputs that are missing, so the model can fix
+// them on a retry.
+func (t *Template) SplitOutputs(answer string) (map[string]string, error) {
+	var parts map[string]string
+	if t.OutputsFormat == OutputsJSON {
+		var err error
+		if parts, err = splitJSONOutputs(answer); err != nil {
+			return nil, err
+		}
+	} else {
+		parts = splitSections(answer)
+	}
+
+	var missing []string
+	for _, o := range t.Outputs {
+		if strings.TrimSpace(parts[o.Name]) == "" && !o.Optional {
+			missing = append(missing, o.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("answer is missing the %s output(s)", strings.Join(missing, ", "))
+	}
+	return parts, nil
+}
+
+// splitSections collects the text after each === name === marker
+func splitSections(answer string) map[string]string {
+	parts := make(map[string]string)
+	marks := outputMarkerPattern.FindAllStringSubmatchIndex(answer, -1)
+	for i, m := range marks {
+		end := len(answer)
+		if i+1 < len(marks) {
+			end = marks[i+1][0]
+		}
+		name := answer[m[2]:m[3]]
+		parts[name] = strings.TrimSpace(answer[m[1]:end])
+	}
+	return parts
+}
+
+// splitJSONOutputs reads one JSON object; values that are not strings are
+// kept as indented JSON
+func splitJSONOutputs(answer string) (map[string]string, error) {
+	// A bare object may hold fenced code in its values, so only look for a
+	// fence around it when it does not parse as it is
+	var obj map[string]json.RawMessage
+	err := json.Unmarshal([]byte(strings.TrimSpace(answer)), &obj)
+	if blocks := CodeBlocks(answer); err != nil && len(blocks) > 0 {
+		err = json.Unmarshal([]byte(strings.TrimSpace(blocks[0].Code)), &obj)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("answer is not a JSON object: %w", err)
+	}
+	parts := make(map[string]string, len(obj))
+	for name, raw := range obj {
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			parts[name] = s
+			continue
+		}
+		var v any
+		_ = json.Unmarshal(raw, &v)
+		data, _ := json.MarshalIndent(v, "", "  ")
+		parts[name] = string(data)
+	}
+	return parts, nil
+}
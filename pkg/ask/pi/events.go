@@ -0,0 +1,112 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package pi
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// assistantMessage returns the text of the last assistant message in pi's
+// JSON event stream and the reason it stopped, e.g. StopLength
+func assistantMessage(out []byte) (string, string) {
+	var text, stop string
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var event struct {
+			Message struct {
+				Role    string `json:"role"`
+				Content []struct {
+					Type string `json:"type"`
+					Text string `json:"text"`
+				} `json:"content"`
+				StopReason string `json:"stopReason"`
+			} `json:"message"`
+		}
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			continue
+		}
+		if event.Message.Role != "assistant" {
+			continue
+		}
+		var b strings.Builder
+		for _, c := range event.Message.Content {
+			if c.Type == "text" {
+				b.WriteString(c.Text)
+			}
+		}
+		if b.Len() > 0 {
+			text, stop = b.String(), event.Message.StopReason
+		}
+	}
+	return text, stop
+}
+
+// assistantUsage returns the usage pi reported for the last assistant
+// message in its JSON event stream
+func assistantUsage(out []byte) Usage {
+	var usage Usage
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var event struct {
+			Message struct {
+				Role  string `json:"role"`
+				Usage struct {
+					Input  int `json:"input"`
+					Output int `json:"output"`
+				} `json:"usage"`
+			} `json:"message"`
+		}
+		if err := json.Unmarshal([]byte(line), &event); err != nil || event.Message.Role != "assistant" {
+			continue
+		}
+		if u := event.Message.Usage; u.Input > 0 || u.Output > 0 {
+			usage = Usage{InputTokens: u.Input, OutputTokens: u.Output}
+		}
+	}
+	return usage
+}
+
+// assistantThinking returns the reasoning trace of the last assistant
+// message in pi's JSON event stream, or "" for models that emit none
+func assistantThinking(out []byte) string {
+	var thinking string
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "{") {
+			continue
+		}
+		var event struct {
+			Message struct {
+				Role    string `json:"role"`
+				Content []struct {
+					Type     string `json:"type"`
+					Thinking string `json:"thinking"`
+				} `json:"content"`
+			} `json:"message"`
+		}
+		if err := json.Unmarshal([]byte(line), &event); err != nil || event.Message.Role != "assistant" {
+			continue
+		}
+		var b strings.Builder
+		for _, c := range event.Message.Content {
+			if c.Type == "thinking" && c.Thinking != "" {
+				if b.Len() > 0 {
+					b.WriteString("\n\n")
+				}
+				b.WriteString(c.Thinking)
+			}
+		}
+		if b.Len() > 0 {
+			thinking = b.String()
+		}
+	}
+	return strings.TrimSpace(thinking)
+}
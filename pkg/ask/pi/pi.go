@@ -0,0 +1,343 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package pi is an ask.Client that runs the pi coding agent once per
+// request (npm install -g @mariozechner/pi-coding-agent). The arc-ask
+// command wraps it with rate limits, egress rules, and retries.
+//
+//	client := &pi.Client{Provider: "anthropic", Thinking: "low"}
+//	runner := &ask.Runner{Client: client}
+package pi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+)
+
+var (
+	_ ask.Client       = (*Client)(nil)
+	_ ask.PromptShaper = (*Client)(nil)
+)
+
+const (
+	// SpillThreshold is the request size above which pi gets the prompt
+	// and input as files rather than in argv; Linux caps a single
+	// argument at 128 KiB
+	SpillThreshold = 64 << 10
+	// StopLength is the stop reason of an answer cut off by MaxTokens
+	StopLength = "length"
+)
+
+// ErrNotFound is returned when pi is not on $PATH
+var ErrNotFound = errors.New("pi not found. Install: npm install -g @mariozechner/pi-coding-agent")
+
+// Client runs pi for each request. The zero value uses pi's defaults.
+type Client struct {
+	Provider      string   // empty uses pi's default
+	Model         string   // empty uses pi's default
+	MaxTokens     int      // 0 uses the provider's default
+	Temperature   *float64 // nil uses the provider's default
+	Thinking      string   // reasoning level; empty uses the model's default
+	System        string   // appended to pi's system prompt
+	ReplaceSystem bool     // System replaces pi's system prompt instead
+	Dir           string   // pi's working directory; empty is the current one
+	Env           []string // added to pi's environment, e.g. API keys
+
+	// ConnectTimeout is the time allowed until pi's first output; 0 means
+	// no limit
+	ConnectTimeout time.Duration
+	// TempDir holds requests too big for argv while pi reads them; empty
+	// uses the system temp dir
+	TempDir string
+	// Observe, if set, sees pi's JSON event stream as it arrives
+	Observe io.Writer
+	// Command builds the pi process; nil uses exec.Command
+	Command func(name string, args ...string) *exec.Cmd
+}
+
+// Request is one pi run
+type Request struct {
+	Prompt string
+	Input  string   // sent on stdin
+	Tools  []string // tools pi may use; nil leaves pi's default
+}
+
+// Usage is the token usage pi reported
+type Usage struct {
+	InputTokens  int
+	OutputTokens int
+}
+
+// Result is what one run produced. After a failure it holds whatever
+// arrived before it.
+type Result struct {
+	Text       string // the answer, with its surrounding whitespace
+	StopReason string // why the model stopped, e.g. StopLength
+	Usage      Usage  // zero when pi reported none
+	Thinking   string // the model's reasoning trace, if it emitted one
+}
+
+// ConnectTimeoutError is returned when pi sends nothing within
+// ConnectTimeout
+type ConnectTimeoutError struct {
+	After time.Duration
+}
+
+func (e *ConnectTimeoutError) Error() string {
+	return fmt.Sprintf("no response from pi within %s", e.After)
+}
+
+// ExitError is returned when pi exits with an error
+type ExitError struct {
+	Stderr string
+}
+
+func (e *ExitError) Error() string {
+	return "pi failed: " + e.Stderr
+}
+
+// lookPi resolves pi once per process, on first use
+var lookPi = sync.OnceValues(func() (string, error) { return exec.LookPath("pi") })
+
+// Installed returns ErrNotFound when pi is not on $PATH
+func Installed() error {
+	if _, err := lookPi(); err != nil {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Ask sends a prompt
+func (c *Client) Ask(ctx context.Context, prompt string) (string, error) {
+	return c.answer(ctx, Request{Prompt: prompt})
+}
+
+// AskWithContext sends a prompt with input on stdin
+func (c *Client) AskWithContext(ctx context.Context, prompt, context string) (string, error) {
+	return c.answer(ctx, Request{Prompt: prompt, Input: context})
+}
+
+// AskWithTools sends a prompt and lets pi use the given tools
+func (c *Client) AskWithTools(ctx context.Context, prompt string, tools []string) (string, error) {
+	return c.answer(ctx, Request{Prompt: prompt, Tools: tools})
+}
+
+// IsDaemonRunning is false: each request runs pi directly
+func (c *Client) IsDaemonRunning() bool {
+	return false
+}
+
+// ShapePrompt sends the system prompt in pi's system prompt rather than
+// folding it into the user prompt
+func (c *Client) ShapePrompt(system, user string) (ask.Client, string) {
+	cp := *c
+	cp.System = system
+	return &cp, user
+}
+
+func (c *Client) answer(ctx context.Context, req Request) (string, error) {
+	res, err := c.Run(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(res.Text), nil
+}
+
+// args are pi's arguments for the client's settings
+func (c *Client) args() []string {
+	var args []string
+	if c.Provider != "" {
+		args = append(args, "--provider", c.Provider)
+	}
+	if c.Model != "" {
+		args = append(args, "--model", c.Model)
+	}
+	if c.MaxTokens > 0 {
+		args = append(args, "--max-tokens", strconv.Itoa(c.MaxTokens))
+	}
+	if c.Temperature != nil {
+		args = append(args, "--temperature", strconv.FormatFloat(*c.Temperature, 'f', -1, 64))
+	}
+	if c.Thinking != "" {
+		args = append(args, "--thinking", c.Thinking)
+	}
+	if c.System != "" {
+		if c.ReplaceSystem {
+			args = append(args, "--system-prompt", c.System)
+		} else {
+			args = append(args, "--append-system-prompt", c.System)
+		}
+	}
+	return append(args, "--mode", "json", "--print")
+}
+
+// Run runs pi once. The prompt goes in argv and the input on stdin, or
+// both in temp files when they are too big for argv; neither passes
+// through a shell.
+func (c *Client) Run(ctx context.Context, req Request) (*Result, error) {
+	res := &Result{}
+	if err := Installed(); err != nil {
+		return res, err
+	}
+	args := c.args()
+	if len(req.Tools) > 0 {
+		args = append(args, "--tools", strings.Join(req.Tools, ","))
+	}
+	spill := len(req.Prompt)+len(req.Input) > SpillThreshold
+	if spill {
+		files, remove, err := c.spill(req.Prompt, req.Input)
+		defer remove()
+		if err != nil {
+			return res, err
+		}
+		args = append(args, files...)
+	} else {
+		args = append(args, req.Prompt)
+	}
+
+	command := c.Command
+	if command == nil {
+		command = exec.Command
+	}
+	cmd := command("pi", args...)
+	cmd.Env = append(os.Environ(), c.Env...)
+	cmd.Dir = c.Dir
+	if req.Input != "" && !spill {
+		cmd.Stdin = strings.NewReader(req.Input + "\n")
+	}
+
+	out, err := c.run(ctx, cmd)
+	res.Text, res.StopReason = assistantMessage(out)
+	if err != nil {
+		return res, err
+	}
+	if strings.TrimSpace(res.Text) == "" {
+		// Not a JSON event stream: the output is the answer
+		res.Text = strings.TrimSpace(string(out))
+	}
+	res.Usage = assistantUsage(out)
+	res.Thinking = assistantThinking(out)
+	return res, nil
+}
+
+// spill writes a large prompt and its input to temp files and returns the
+// pi arguments that attach them, with a message asking pi to answer them.
+// remove deletes the files and must be deferred.
+func (c *Client) spill(prompt, input string) (args []string, remove func(), err error) {
+	var paths []string
+	remove = func() {
+		for _, p := range paths {
+			_ = os.Remove(p)
+		}
+	}
+	write := func(pattern, content string) error {
+		f, err := os.CreateTemp(c.TempDir, pattern)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, f.Name())
+		_, err = f.WriteString(content)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		return err
+	}
+	if err := write("arc-ask-prompt-*.md", prompt); err != nil {
+		return nil, remove, fmt.Errorf("spill prompt to a temp file: %w", err)
+	}
+	message := "The attached file holds the full request. Answer it."
+	if input != "" {
+		if err := write("arc-ask-input-*.txt", input); err != nil {
+			return nil, remove, fmt.Errorf("spill input to a temp file: %w", err)
+		}
+		message = "The first attached file holds the full request and the second its input. Answer the request."
+	}
+	for _, p := range paths {
+		args = append(args, "@"+p)
+	}
+	return append(args, message), remove, nil
+}
+
+// run starts pi and collects its stdout. It fails if no output arrives
+// within ConnectTimeout. When ctx ends or pi fails, the output received
+// so far is returned along with the error.
+func (c *Client) run(ctx context.Context, cmd *exec.Cmd) ([]byte, error) {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run pi: %w", err)
+	}
+
+	var (
+		mu    sync.Mutex
+		out   bytes.Buffer
+		first = make(chan struct{})
+		done  = make(chan error, 1)
+	)
+	go func() {
+		var once sync.Once
+		buf := make([]byte, 32*1024)
+		for {
+			n, err := stdout.Read(buf)
+			if n > 0 {
+				mu.Lock()
+				out.Write(buf[:n])
+				mu.Unlock()
+				if c.Observe != nil {
+					_, _ = c.Observe.Write(buf[:n])
+				}
+				once.Do(func() { close(first) })
+			}
+			if err != nil {
+				done <- cmd.Wait()
+				return
+			}
+		}
+	}()
+
+	snapshot := func() []byte {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]byte(nil), out.Bytes()...)
+	}
+
+	var connectC <-chan time.Time
+	if c.ConnectTimeout > 0 {
+		t := time.NewTimer(c.ConnectTimeout)
+		defer t.Stop()
+		connectC = t.C
+	}
+
+	for {
+		select {
+		case <-first:
+			first, connectC = nil, nil
+		case <-connectC:
+			_ = cmd.Process.Kill()
+			return nil, &ConnectTimeoutError{After: c.ConnectTimeout}
+		case <-ctx.Done():
+			_ = cmd.Process.Kill()
+			return snapshot(), ctx.Err()
+		case err := <-done:
+			if err != nil {
+				return snapshot(), &ExitError{Stderr: strings.TrimSpace(stderr.String())}
+			}
+			return snapshot(), nil
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ask
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Runner answers requests end to end: it resolves the template, assembles
+// the prompt, queries the client, and shapes the answer
+type Runner struct {
+	Client    Client
+	Templates *Templates // nil allows only built-in templates
+}
+
+// Request is one question
+type Request struct {
+	Prompt  string            // a question, or @template
+	Input   string            // piped or captured text
+	Vars    map[string]string // template variables
+	Context []ContextFile     // appended to the input in order
+	Tools   []string          // tools the backend may use
+	Extract string            // an extract mode; empty keeps the answer as is
+}
+
+// ContextFile is extra material for the prompt, usually a file
+type ContextFile struct {
+	Name string
+	Text string
+}
+
+// Result is the shaped answer to a Request
+type Result struct {
+	Response string
+	Prompt   string // the full prompt sent
+	Template string // template name, empty for plain questions
+	Retried  bool   // the answer broke the output contract and was asked again
+}
+
+// Run answers a request
+func (r *Runner) Run(ctx context.Context, req Request) (*Result, error) {
+	if err := ValidateExtract(req.Extract); req.Extract != "" && err != nil {
+		return nil, err
+	}
+	tmpl, client, prompt, err := r.prepare(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var answer string
+	if len(req.Tools) > 0 {
+		answer, err = client.AskWithTools(ctx, prompt, req.Tools)
+	} else {
+		answer, err = client.Ask(ctx, prompt)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := &Result{Prompt: prompt}
+	if tmpl != nil {
+		res.Template = tmpl.Name
+		if tmpl.Output != nil {
+			if answer, res.Retried, err = EnforceContract(ctx, client, tmpl, prompt, answer); err != nil {
+				return nil, err
+			}
+		}
+	}
+	extract := req.Extract
+	if extract == "" && tmpl != nil && tmpl.Defaults != nil {
+		extract = tmpl.Defaults.Extract
+	}
+	if extract == ExtractModeCode {
+		answer = ExtractCode(answer)
+	}
+	res.Response = answer
+	return res, nil
+}
+
+// Prompt assembles the prompt Run would send for a request, without
+// sending it
+func (r *Runner) Prompt(req Request) (string, error) {
+	_, _, prompt, err := r.prepare(req)
+	return prompt, err
+}
+
+// prepare resolves the request's template and assembles its prompt, and
+// returns the client to send it with
+func (r *Runner) prepare(req Request) (*Template, Client, string, error) {
+	templates := r.Templates
+	if templates == nil {
+		templates = &Templates{}
+	}
+
+	input := req.Input
+	for _, c := range req.Context {
+		input = AppendContext(input, c.Name, c.Text)
+	}
+
+	var (
+		tmpl         *Template
+		system, user string
+		err          error
+	)
+	if IsTemplateRef(req.Prompt) {
+		if tmpl, err = templates.Load(req.Prompt); err != nil {
+			return nil, nil, "", err
+		}
+		system, user, err = tmpl.Render(TemplateData{Input: input, Vars: req.Vars})
+	} else {
+		system, user, err = templates.BuildPrompt(req.Prompt, input, req.Vars)
+	}
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if tmpl != nil && tmpl.Output != nil {
+		user += "\n\n" + tmpl.Output.Instructions()
+	}
+	if s, ok := r.Client.(PromptShaper); ok {
+		client, prompt := s.ShapePrompt(system, user)
+		return tmpl, client, prompt, nil
+	}
+	return tmpl, r.Client, JoinPrompt(system, user), nil
+}
+
+// BuildPrompt resolves an @template or plain question into system and user prompts
+func (s *Templates) BuildPrompt(arg, input string, vars map[string]string) (string, string, error) {
+	if IsTemplateRef(arg) {
+		t, err := s.Load(arg)
+		if err != nil {
+			return "", "", err
+		}
+		return t.Render(TemplateData{Input: input, Vars: vars})
+	}
+
+	prompt := arg
+	if input != "" {
+		prompt = fmt.Sprintf("%s\n\nInput:\n%s", prompt, input)
+	}
+	return "", prompt, nil
+}
+
+// AppendContext adds a named piece of context after the input
+func AppendContext(input, name, text string) string {
+	var b strings.Builder
+	b.WriteString(input)
+	b.WriteString("\n\nContext (")
+	b.WriteString(name)
+	b.WriteString("):\n")
+	b.WriteString(text)
+	return b.String()
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ask
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// validateSchema checks a decoded JSON value against a JSON Schema. It
+// supports the subset output contracts need: type, enum, const, required,
+// properties, additionalProperties (boolean), items, minItems, maxItems,
+// minLength, maxLength, pattern, minimum, and maximum.
+func validateSchema(schema map[string]any, v any) error {
+	return validateAt("$", schema, v)
+}
+
+func validateAt(path string, schema map[string]any, v any) error {
+	if t, ok := schema["type"]; ok {
+		if err := checkType(path, t, v); err != nil {
+			return err
+		}
+	}
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			if jsonEqual(e, v) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%s: value is not one of the allowed values", path)
+		}
+	}
+	if c, ok := schema["const"]; ok && !jsonEqual(c, v) {
+		return fmt.Errorf("%s: value must be %v", path, c)
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		return validateObject(path, schema, val)
+	case []any:
+		if n, ok := schemaInt(schema, "minItems"); ok && len(val) < n {
+			return fmt.Errorf("%s: needs at least %d items", path, n)
+		}
+		if n, ok := schemaInt(schema, "maxItems"); ok && len(val) > n {
+			return fmt.Errorf("%s: allows at most %d items", path, n)
+		}
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range val {
+				if err := validateAt(fmt.Sprintf("%s[%d]", path, i), items, item); err != nil {
+					return err
+				}
+			}
+		}
+	case string:
+		n := len([]rune(val))
+		if min, ok := schemaInt(schema, "minLength"); ok && n < min {
+			return fmt.Errorf("%s: shorter than %d characters", path, min)
+		}
+		if max, ok := schemaInt(schema, "maxLength"); ok && n > max {
+			return fmt.Errorf("%s: longer than %d characters", path, max)
+		}
+		if p, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return fmt.Errorf("%s: invalid pattern in schema: %w", path, err)
+			}
+			if !re.MatchString(val) {
+				return fmt.Errorf("%s: does not match pattern %s", path, p)
+			}
+		}
+	case float64:
+		if min, ok := schema["minimum"].(float64); ok && val < min {
+			return fmt.Errorf("%s: below the minimum %v", path, min)
+		}
+		if max, ok := schema["maximum"].(float64); ok && val > max {
+			return fmt.Errorf("%s: above the maximum %v", path, max)
+		}
+	}
+	return nil
+}
+
+func validateObject(path string, schema map[string]any, obj map[string]any) error {
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			name, _ := r.(string)
+			if _, ok := obj[name]; !ok {
+				return fmt.Errorf("%s: missing required property %q", path, name)
+			}
+		}
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+	keys := make([]string, 0, len(obj))
+	for k := range obj {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		sub, ok := props[k].(map[string]any)
+		if !ok {
+			if extra, ok := schema["additionalProperties"].(bool); ok && !extra {
+				return fmt.Errorf("%s: unexpected property %q", path, k)
+			}
+			continue
+		}
+		if err := validateAt(path+"."+k, sub, obj[k]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkType(path string, t any, v any) error {
+	var allowed []string
+	switch tt := t.(type) {
+	case string:
+		allowed = []string{tt}
+	case []any:
+		for _, x := range tt {
+			if s, ok := x.(string); ok {
+				allowed = append(allowed, s)
+			}
+		}
+	}
+	actual := jsonType(v)
+	for _, a := range allowed {
+		if a == actual || (a == "number" && actual == "integer") {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: expected %s, got %s", path, strings.Join(allowed, " or "), actual)
+}
+
+func jsonType(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if val == math.Trunc(val) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+func schemaInt(schema map[string]any, key string) (int, bool) {
+	f, ok := schema[key].(float64)
+	return int(f), ok
+}
+
+// jsonEqual compares values after a JSON round trip, so YAML and JSON
+// decoded numbers compare equal
+func jsonEqual(a, b any) bool {
+	return reflect.DeepEqual(normalizeJSON(a), normalizeJSON(b))
+}
+
+// normalizeJSON converts a YAML-decoded value into the types encoding/json
+// produces (float64 numbers, map[string]any objects)
+func normalizeJSON(v any) any {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
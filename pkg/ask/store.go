@@ -0,0 +1,360 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ask
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NotFoundError is returned for a name that is neither a user template
+// nor a built-in
+type NotFoundError struct {
+	Name string
+	// Qualified lists namespaced templates with the same base name, such
+	// as security/audit for audit
+	Qualified []string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("template @%s not found", e.Name)
+}
+
+// Templates resolves @name references, preferring YAML files in Dir, then
+// in Roots in order, over the built-ins. A name with a namespace, such as
+// @security/audit, is a file in that subdirectory of a root; installed
+// packs live there, so they never shadow an unqualified name. Parsed files
+// are memoized in CachePath across processes, keyed by path and
+// invalidated when a file's mtime or size changes.
+type Templates struct {
+	Dir       string   // user templates; empty uses only the built-ins
+	Roots     []string // further template directories, after Dir
+	CachePath string   // parse cache; empty disables it
+
+	// Warn is called once per name when a template shadows another with
+	// the same name in a later root; nil ignores collisions
+	Warn func(msg string)
+
+	once    sync.Once
+	mu      sync.Mutex
+	entries map[string]cachedTemplate
+	warned  map[string]bool
+
+	hits, misses int
+}
+
+// templateCacheVersion must change whenever Template's fields do, so
+// entries parsed by an older binary are not reused
+const templateCacheVersion = 7
+
+type templateCacheFile struct {
+	Version int                       `json:"version"`
+	Entries map[string]cachedTemplate `json:"entries"`
+}
+
+type cachedTemplate struct {
+	ModTime  time.Time `json:"mod_time"`
+	Size     int64     `json:"size"`
+	Template Template  `json:"template"`
+}
+
+// templateFile is a template file and the name it is referenced by
+type templateFile struct {
+	name, path string
+}
+
+// Dirs returns the template roots in precedence order
+func (s *Templates) Dirs() []string {
+	var dirs []string
+	for _, d := range append([]string{s.Dir}, s.Roots...) {
+		if d != "" {
+			dirs = append(dirs, d)
+		}
+	}
+	return dirs
+}
+
+// validName accepts "name" and "namespace/name"; anything else could
+// escape the template roots
+func validName(name string) bool {
+	parts := strings.Split(name, "/")
+	if len(parts) > 2 {
+		return false
+	}
+	for _, p := range parts {
+		if p == "" || p == "." || p == ".." || strings.ContainsAny(p, `\`) {
+			return false
+		}
+	}
+	return true
+}
+
+// find returns the files defining name in every root, first match first
+func (s *Templates) find(name string) []string {
+	var paths []string
+	for _, dir := range s.Dirs() {
+		for _, ext := range []string{".yaml", ".yml"} {
+			path := filepath.Join(dir, filepath.FromSlash(name)+ext)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				paths = append(paths, path)
+				break
+			}
+		}
+	}
+	return paths
+}
+
+// Load resolves a template by name, with or without the leading @
+func (s *Templates) Load(name string) (*Template, error) {
+	name, variant := SplitVariant(name)
+	t, err := s.load(name)
+	if err != nil || variant == "" {
+		return t, err
+	}
+	return t.withVariant(variant)
+}
+
+// load resolves a template name without a variant
+func (s *Templates) load(name string) (*Template, error) {
+	if !validName(name) {
+		return nil, &NotFoundError{Name: name}
+	}
+
+	if paths := s.find(name); len(paths) > 0 {
+		if len(paths) > 1 {
+			s.warn(name, fmt.Sprintf("@%s in %s shadows %s", name, paths[0], strings.Join(paths[1:], ", ")))
+		}
+		return s.loadFile(name, paths[0])
+	}
+
+	if t, ok := builtinTemplates[name]; ok {
+		return t, nil
+	}
+	nf := &NotFoundError{Name: name}
+	if !strings.Contains(name, "/") {
+		files, _ := s.files()
+		seen := make(map[string]bool)
+		for _, f := range files {
+			if strings.HasSuffix(f.name, "/"+name) && !seen[f.name] {
+				seen[f.name] = true
+				nf.Qualified = append(nf.Qualified, f.name)
+			}
+		}
+	}
+	return nil, nf
+}
+
+// loadFile parses the template at path, or reuses the cached parse
+func (s *Templates) loadFile(name, path string) (*Template, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("read template %s: %w", path, err)
+	}
+	if t, ok := s.cached(path, info); ok {
+		return withContract(t)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read template %s: %w", path, err)
+	}
+	t, err := parseTemplate(name, path, data)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(name, "/") {
+		t.Name = name // a pack's own name for it lacks the namespace
+	}
+	s.store(path, info, t)
+	return withContract(t)
+}
+
+func (s *Templates) warn(name, msg string) {
+	if s.Warn == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.warned == nil {
+		s.warned = make(map[string]bool)
+	}
+	first := !s.warned[name]
+	s.warned[name] = true
+	s.mu.Unlock()
+	if first {
+		s.Warn(msg)
+	}
+}
+
+// files lists every template file in the roots, in precedence order:
+// each root's own templates, then its namespaces
+func (s *Templates) files() ([]templateFile, error) {
+	var files []templateFile
+	for _, dir := range s.Dirs() {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read template dir: %w", err)
+		}
+		var namespaces []string
+		for _, e := range entries {
+			if e.IsDir() {
+				if !strings.HasPrefix(e.Name(), ".") {
+					namespaces = append(namespaces, e.Name())
+				}
+				continue
+			}
+			if name, ok := templateName(e.Name()); ok {
+				files = append(files, templateFile{name: name, path: filepath.Join(dir, e.Name())})
+			}
+		}
+		for _, ns := range namespaces {
+			sub, err := os.ReadDir(filepath.Join(dir, ns))
+			if err != nil {
+				continue
+			}
+			for _, e := range sub {
+				if name, ok := templateName(e.Name()); ok && !e.IsDir() {
+					files = append(files, templateFile{name: ns + "/" + name, path: filepath.Join(dir, ns, e.Name())})
+				}
+			}
+		}
+	}
+	return files, nil
+}
+
+// templateName is the name a template file is referenced by
+func templateName(file string) (string, bool) {
+	ext := filepath.Ext(file)
+	if ext != ".yaml" && ext != ".yml" {
+		return "", false
+	}
+	return strings.TrimSuffix(file, ext), true
+}
+
+// List returns built-in and user templates sorted by name
+func (s *Templates) List() ([]*Template, error) {
+	byName := make(map[string]*Template, len(builtinTemplates))
+	for name, t := range builtinTemplates {
+		byName[name] = t
+	}
+
+	files, err := s.files()
+	if err != nil {
+		return nil, err
+	}
+	loaded := make(map[string]bool)
+	for _, f := range files {
+		if loaded[f.name] {
+			continue // shadowed by an earlier root
+		}
+		loaded[f.name] = true
+		t, err := s.Load(f.name)
+		if err != nil {
+			return nil, err
+		}
+		byName[f.name] = t
+	}
+
+	out := make([]*Template, 0, len(byName))
+	for _, t := range byName {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+// Reload drops the parsed templates and parses every file in the roots
+// again, including shadowed ones. It returns the number of templates
+// loaded and an error for each file that failed to parse.
+func (s *Templates) Reload() (int, []error) {
+	s.loadCache()
+	s.mu.Lock()
+	s.entries = make(map[string]cachedTemplate)
+	s.mu.Unlock()
+
+	files, err := s.files()
+	if err != nil {
+		return 0, []error{err}
+	}
+	loaded := 0
+	var errs []error
+	for _, f := range files {
+		if _, err := s.loadFile(f.name, f.path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		loaded++
+	}
+	return loaded, errs
+}
+
+// CacheStats reports parse cache hits and misses in this process
+func (s *Templates) CacheStats() (hits, misses int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.hits, s.misses
+}
+
+func (s *Templates) loadCache() {
+	s.once.Do(func() {
+		s.entries = make(map[string]cachedTemplate)
+		if s.CachePath == "" {
+			return
+		}
+		data, err := os.ReadFile(s.CachePath)
+		if err != nil {
+			return
+		}
+		// A corrupt or outdated cache is simply rebuilt
+		var f templateCacheFile
+		if json.Unmarshal(data, &f) == nil && f.Version == templateCacheVersion && f.Entries != nil {
+			s.entries = f.Entries
+		}
+	})
+}
+
+func (s *Templates) cached(path string, info os.FileInfo) (*Template, bool) {
+	s.loadCache()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[path]
+	if !ok || !e.ModTime.Equal(info.ModTime()) || e.Size != info.Size() {
+		s.misses++
+		return nil, false
+	}
+	s.hits++
+	t := e.Template
+	return &t, true
+}
+
+// store records a parsed template; the cache is best effort, so write
+// failures are ignored
+func (s *Templates) store(path string, info os.FileInfo, t *Template) {
+	s.loadCache()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[path] = cachedTemplate{ModTime: info.ModTime(), Size: info.Size(), Template: *t}
+	if s.CachePath == "" {
+		return
+	}
+
+	data, err := json.Marshal(templateCacheFile{Version: templateCacheVersion, Entries: s.entries})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(s.CachePath), 0o700); err != nil {
+		return
+	}
+	tmp := s.CachePath + ".tmp"
+	if os.WriteFile(tmp, data, 0o600) == nil {
+		_ = os.Rename(tmp, s.CachePath)
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ask
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/yourorg/arc-sdk/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Template is a reusable prompt definition
+type Template struct {
+	Name        string        `yaml:"name"`
+	Description string        `yaml:"description"`
+	System      string        `yaml:"system"`
+	Prompt      string        `yaml:"prompt"`
+	Vars        []TemplateVar `yaml:"vars"`
+
+	// Output is an optional contract the answer must satisfy
+	Output *OutputContract `yaml:"output"`
+
+	// Outputs split the answer into named parts, each routed to its own
+	// destination; OutputsFormat is sections (default) or json
+	Outputs       []NamedOutput `yaml:"outputs"`
+	OutputsFormat string        `yaml:"outputs_format"`
+
+	// Cache set to false keeps answers to this template out of the response cache
+	Cache *bool `yaml:"cache"`
+
+	// Defaults apply generation flags the command line does not set
+	Defaults *TemplateDefaults `yaml:"defaults"`
+
+	// Requires lists capabilities the model must have, and MinTier the
+	// smallest model tier that does the template justice
+	Requires StringList `yaml:"requires"`
+	MinTier  string     `yaml:"min_tier"`
+
+	// Variants are named configurations selected as @name:variant
+	Variants map[string]TemplateVariant `yaml:"variants"`
+
+	// Path is the file the template was loaded from (empty for built-ins)
+	Path string `yaml:"-"`
+}
+
+// TemplateVar declares a variable a template accepts via --var
+type TemplateVar struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Default     string `yaml:"default"`
+	Required    bool   `yaml:"required"`
+
+	// Type is string (default), enum, int, or path
+	Type    string   `yaml:"type"`
+	Choices []string `yaml:"choices"` // enum
+	Min     *int     `yaml:"min"`     // int
+	Max     *int     `yaml:"max"`     // int
+}
+
+// TemplateData is the data passed to template rendering
+type TemplateData struct {
+	Input string
+	Vars  map[string]string
+}
+
+// builtinTemplates ship with arc-ask and can be overridden by user templates
+var builtinTemplates = map[string]*Template{
+	"code-review": {
+		Name:        "code-review",
+		Description: "Review code changes",
+		System:      "You are a senior engineer performing a careful code review.",
+		Prompt:      "Review the following changes. Point out bugs, risky patterns, and missing tests. Be specific and concise.\n\n{{.Input}}",
+	},
+	"explain": {
+		Name:        "explain",
+		Description: "Explain complex code",
+		System:      "You are a patient engineer explaining code to a colleague.",
+		Prompt:      "Explain what the following code does, step by step, and call out anything surprising.\n\n{{.Input}}",
+	},
+	"summarize": {
+		Name:        "summarize",
+		Description: "Summarize text/logs",
+		Prompt:      "Summarize the following input. Lead with the most important points.\n\n{{.Input}}",
+	},
+	"security-check": {
+		Name:        "security-check",
+		Description: "Check for vulnerabilities",
+		System:      "You are an application security reviewer.",
+		Prompt:      "Check the following input for security vulnerabilities. For each finding give severity, location, and a fix.\n\n{{.Input}}",
+	},
+	"commit-lint": {
+		Name:        "commit-lint",
+		Description: "Lint a commit message",
+		Prompt: "Review this commit message. Check for a concise imperative subject (under 72 characters), " +
+			"a blank line before the body, and a body that explains why. List concrete problems only.\n" +
+			VerdictInstructions + " Use FAIL only for messages that are empty or meaningless.\n\n{{.Input}}",
+	},
+	"diff-risk": {
+		Name:        "diff-risk",
+		Description: "Assess the risk of a diff before pushing",
+		System:      "You are a senior engineer gatekeeping pushes to a shared branch.",
+		Prompt: "Assess the risk of the following diff. Flag leaked secrets, debugging leftovers, " +
+			"destructive migrations, and obvious bugs. Keep it short.\n" +
+			VerdictInstructions + "\n\n{{.Input}}",
+	},
+}
+
+// IsTemplateRef reports whether a prompt argument names a template (@name)
+func IsTemplateRef(arg string) bool {
+	return strings.HasPrefix(arg, "@") && len(arg) > 1 && !strings.ContainsAny(arg, " \n\t")
+}
+
+// withContract resolves the template's output contract, if it has one
+func withContract(t *Template) (*Template, error) {
+	if t.Output == nil {
+		return t, nil
+	}
+	if err := t.Output.load(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// ParseTemplate parses a template file's content, such as an older
+// revision of it; path locates its output schema
+func ParseTemplate(name, path string, data []byte) (*Template, error) {
+	t, err := parseTemplate(name, path, data)
+	if err != nil {
+		return nil, err
+	}
+	return withContract(t)
+}
+
+func parseTemplate(name, path string, data []byte) (*Template, error) {
+	var t Template
+	if err := yaml.Unmarshal(data, &t); err != nil {
+		return nil, errors.NewCLIError(fmt.Sprintf("invalid template %s", path)).WithCause(err)
+	}
+	if t.Name == "" {
+		t.Name = name
+	}
+	if strings.TrimSpace(t.Prompt) == "" {
+		return nil, errors.NewCLIError(fmt.Sprintf("template %s has no prompt", path))
+	}
+	if err := t.checkSchema(); err != nil {
+		return nil, err
+	}
+	t.Path = path
+	return &t, nil
+}
+
+// Render executes the template and returns the system and user prompts.
+// Templates that never reference {{.Input}} get the input appended.
+func (t *Template) Render(data TemplateData) (string, string, error) {
+	vars, err := t.ResolveVars(data.Vars)
+	if err != nil {
+		return "", "", err
+	}
+	data.Vars = vars
+
+	system, err := t.execute("system", t.System, data)
+	if err != nil {
+		return "", "", err
+	}
+	user, err := t.execute("prompt", t.Prompt, data)
+	if err != nil {
+		return "", "", err
+	}
+	if data.Input != "" && !strings.Contains(t.Prompt, ".Input") {
+		user = fmt.Sprintf("%s\n\nInput:\n%s", user, data.Input)
+	}
+	return system, user, nil
+}
+
+func (t *Template) execute(part, text string, data TemplateData) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	tmpl, err := template.New(t.Name + "." + part).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", errors.NewCLIError(fmt.Sprintf("template @%s: invalid %s", t.Name, part)).WithCause(err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", errors.NewCLIError(fmt.Sprintf("template @%s: render %s", t.Name, part)).WithCause(err)
+	}
+	return buf.String(), nil
+}
+
+// ResolveVars applies defaults and enforces required variables and types
+func (t *Template) ResolveVars(given map[string]string) (map[string]string, error) {
+	vars := make(map[string]string, len(given)+len(t.Vars))
+	for k, v := range given {
+		vars[k] = v
+	}
+	for _, v := range t.Vars {
+		value, ok := vars[v.Name]
+		if !ok {
+			if v.Required {
+				return nil, errors.NewCLIError(fmt.Sprintf("template @%s requires variable %q", t.Name, v.Name)).
+					WithSuggestions(fmt.Sprintf("Pass it with: --var %s=VALUE", v.Name))
+			}
+			value = v.Default
+			vars[v.Name] = value
+		}
+		// Unset optional variables stay empty whatever their type
+		if value == "" && !v.Required {
+			continue
+		}
+		if err := v.validate(t.Name, value); err != nil {
+			return nil, err
+		}
+	}
+	return vars, nil
+}
+
+// JoinPrompt folds a system prompt into the user prompt for backends
+// that take a single prompt string
+func JoinPrompt(system, user string) string {
+	if system == "" {
+		return user
+	}
+	return system + "\n\n" + user
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ask
+
+import "strings"
+
+// VerdictInstructions asks the model to finish with a parseable verdict line
+const VerdictInstructions = `End your answer with a final line of exactly "VERDICT: PASS", "VERDICT: WARN", or "VERDICT: FAIL".`
+
+// CodeBlock is a fenced block found in a model answer
+type CodeBlock struct {
+	Lang string
+	Code string
+}
+
+// CodeBlocks returns all fenced (```) blocks in order of appearance
+func CodeBlocks(text string) []CodeBlock {
+	var (
+		blocks []CodeBlock
+		cur    *CodeBlock
+		body   strings.Builder
+	)
+	for _, line := range strings.SplitAfter(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			if cur == nil {
+				cur = &CodeBlock{Lang: strings.TrimSpace(strings.TrimPrefix(trimmed, "```"))}
+				body.Reset()
+				continue
+			}
+			cur.Code = body.String()
+			blocks = append(blocks, *cur)
+			cur = nil
+			continue
+		}
+		if cur != nil {
+			body.WriteString(line)
+		}
+	}
+	return blocks
+}
+
+// ExtractCode returns the body of the first fenced code block in an answer,
+// or the whole answer when it contains no fences.
+func ExtractCode(answer string) string {
+	blocks := CodeBlocks(answer)
+	if len(blocks) == 0 {
+		return answer
+	}
+	return blocks[0].Code
+}
+
+// StripCodeBlocks returns the text outside fenced blocks
+func StripCodeBlocks(text string) string {
+	var (
+		b      strings.Builder
+		inside bool
+	)
+	for _, line := range strings.SplitAfter(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inside = !inside
+			continue
+		}
+		if !inside {
+			b.WriteString(line)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,15 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ask
+
+import "unicode/utf8"
+
+// charsPerToken is a conservative average for English text and code
+const charsPerToken = 4
+
+// EstimateTokens approximates the token count of text without a tokenizer
+func EstimateTokens(text string) int {
+	n := utf8.RuneCountInString(text)
+	return (n + charsPerToken - 1) / charsPerToken
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ask
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// TemplateVariant is a named configuration of a template, such as strict
+// or verbose, selected as @template:variant. Its variables and defaults
+// apply where the command line sets nothing.
+type TemplateVariant struct {
+	Description string            `yaml:"description"`
+	Vars        map[string]string `yaml:"vars"`
+	Defaults    *TemplateDefaults `yaml:"defaults"`
+}
+
+// SplitVariant splits a template name from its variant: code-review:strict
+// is code-review and strict
+func SplitVariant(name string) (string, string) {
+	base, variant, _ := strings.Cut(strings.TrimPrefix(name, "@"), ":")
+	return base, variant
+}
+
+// VariantNames lists the template's variants in order
+func (t *Template) VariantNames() []string {
+	names := make([]string, 0, len(t.Variants))
+	for name := range t.Variants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// withVariant returns a copy of the template with the variant's variables
+// as defaults and its generation defaults over the template's own
+func (t *Template) withVariant(name string) (*Template, error) {
+	v, ok := t.Variants[name]
+	if !ok {
+		err := errors.NewCLIError(fmt.Sprintf("template @%s has no variant %q", t.Name, name))
+		if names := t.VariantNames(); len(names) > 0 {
+			return nil, err.WithSuggestions("Variants: " + strings.Join(names, ", "))
+		}
+		return nil, err.WithSuggestions("Define variants: in " + t.source())
+	}
+
+	cp := *t
+	cp.Name = t.Name + ":" + name
+	cp.Vars = append([]TemplateVar(nil), t.Vars...)
+	keys := make([]string, 0, len(v.Vars))
+	for k := range v.Vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		i := cp.varIndex(k)
+		if i < 0 {
+			cp.Vars = append(cp.Vars, TemplateVar{Name: k})
+			i = len(cp.Vars) - 1
+		}
+		cp.Vars[i].Default = v.Vars[k]
+		cp.Vars[i].Required = false
+	}
+
+	if v.Defaults != nil {
+		d := TemplateDefaults{}
+		if t.Defaults != nil {
+			d = *t.Defaults
+		}
+		if v.Defaults.MaxTokens != nil {
+			d.MaxTokens = v.Defaults.MaxTokens
+		}
+		if v.Defaults.Temperature != nil {
+			d.Temperature = v.Defaults.Temperature
+		}
+		for _, f := range []struct{ dst, src *string }{
+			{&d.Output, &v.Defaults.Output},
+			{&d.Extract, &v.Defaults.Extract},
+			{&d.Model, &v.Defaults.Model},
+			{&d.Thinking, &v.Defaults.Thinking},
+		} {
+			if *f.src != "" {
+				*f.dst = *f.src
+			}
+		}
+		cp.Defaults = &d
+	}
+	return &cp, nil
+}
+
+func (t *Template) varIndex(name string) int {
+	for i, v := range t.Vars {
+		if v.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// source names where the template is defined, for messages
+func (t *Template) source() string {
+	if t.Path != "" {
+		return t.Path
+	}
+	return "a template file of the same name"
+}
+
+// checkVariants validates variant names, variable values, and defaults
+func (t *Template) checkVariants() error {
+	for _, name := range t.VariantNames() {
+		if name == "" || strings.ContainsAny(name, ":/ \t\n") {
+			return errors.NewCLIError(fmt.Sprintf("template @%s: invalid variant name %q", t.Name, name)).
+				WithSuggestions("Use letters, digits, '-' and '_'")
+		}
+		v := t.Variants[name]
+		for k, value := range v.Vars {
+			// Paths are checked when used, as they may not exist yet
+			if i := t.varIndex(k); i >= 0 && t.Vars[i].Type != VarPath {
+				if err := t.Vars[i].validate(t.Name+":"+name, value); err != nil {
+					return err
+				}
+			}
+		}
+		if v.Defaults != nil {
+			if err := v.Defaults.check(&Template{Name: t.Name + ":" + name}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+package ask
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/yourorg/arc-sdk/errors"
+)
+
+// Template variable types
+const (
+	VarString = "string"
+	VarEnum   = "enum"
+	VarInt    = "int"
+	VarPath   = "path"
+)
+
+// validate checks a value against the variable's declared type
+func (v TemplateVar) validate(template, value string) error {
+	fail := func(msg string, suggestions ...string) error {
+		return errors.NewCLIError(fmt.Sprintf("template @%s: --var %s=%s: %s", template, v.Name, value, msg)).
+			WithSuggestions(suggestions...)
+	}
+
+	switch v.Type {
+	case "", VarString:
+		return nil
+	case VarEnum:
+		if slices.Contains(v.Choices, value) {
+			return nil
+		}
+		return fail("not a valid choice", "Choose one of: "+strings.Join(v.Choices, ", "))
+	case VarInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fail("not an integer")
+		}
+		if v.Min != nil && n < *v.Min {
+			return fail(fmt.Sprintf("below the minimum %d", *v.Min))
+		}
+		if v.Max != nil && n > *v.Max {
+			return fail(fmt.Sprintf("above the maximum %d", *v.Max))
+		}
+		return nil
+	case VarPath:
+		if _, err := os.Stat(ExpandHome(value)); err != nil {
+			return fail("path does not exist")
+		}
+		return nil
+	}
+	return errors.NewCLIError(fmt.Sprintf("template @%s: variable %q has unknown type %q", template, v.Name, v.Type)).
+		WithSuggestions("Use one of: string, enum, int, path")
+}
+
+// checkSchema reports variable declarations that can never validate
+func (t *Template) checkSchema() error {
+	for _, v := range t.Vars {
+		if v.Type == VarEnum && len(v.Choices) == 0 {
+			return errors.NewCLIError(fmt.Sprintf("template @%s: enum variable %q has no choices", t.Name, v.Name))
+		}
+		if v.Min != nil && v.Max != nil && *v.Min > *v.Max {
+			return errors.NewCLIError(fmt.Sprintf("template @%s: variable %q has min above max", t.Name, v.Name))
+		}
+	}
+	if err := t.checkOutputs(); err != nil {
+		return err
+	}
+	if err := t.checkModelRequirements(); err != nil {
+		return err
+	}
+	if err := t.checkVariants(); err != nil {
+		return err
+	}
+	if t.Defaults != nil {
+		return t.Defaults.check(t)
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2025 Arc Engineering
+// SPDX-License-Identifier: MIT
+
+// Package fixture records provider interactions to disk and replays them,
+// so templates, extractors, and output formatting can be tested without
+// calling a real model.
+//
+// A directory of fixtures is written by running arc-ask with
+// --record-fixtures DIR and replayed with --replay-fixtures DIR, or from Go
+// tests through Client.
+package fixture
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/yourorg/arc-ask/pkg/ask"
+)
+
+var _ ask.Client = (*Client)(nil)
+
+// ErrNotRecorded is returned when replaying a request that has no fixture
+var ErrNotRecorded = errors.New("no recorded fixture for this request")
+
+// Interaction is one provider request and its answer
+type Interaction struct {
+	Key      string    `json:"key"`
+	Provider string    `json:"provider,omitempty"`
+	Model    string    `json:"model,omitempty"`
+	Prompt   string    `json:"prompt"`
+	Response string    `json:"response"`
+	Error    string    `json:"error,omitempty"`
+	Recorded time.Time `json:"recorded"`
+}
+
+// Store reads and writes fixtures as one JSON file per interaction
+type Store struct {
+	Dir string
+
+	// Sanitize, if set, is applied to prompts and responses before they are
+	// written, e.g. to redact secrets. Lookups use the unsanitized prompt.
+	Sanitize func(string) string
+}
+
+// Key identifies a request by provider, model, and prompt
+func Key(provider, model, prompt string) string {
+	sum := sha256.Sum256([]byte(provider + "\x00" + model + "\x00" + prompt))
+	return hex.EncodeToString(sum[:8])
+}
+
+func (s *Store) path(key string) string {
+	return filepath.Join(s.Dir, key+".json")
+}
+
+// Record writes an interaction, replacing any earlier one for the same request
+func (s *Store) Record(provider, model, prompt, response string, askErr error) error {
+	in := Interaction{
+		Key:      Key(provider, model, prompt),
+		Provider: provider,
+		Model:    model,
+		Prompt:   prompt,
+		Response: response,
+		Recorded: time.Now().UTC(),
+	}
+	if askErr != nil {
+		in.Error = askErr.Error()
+	}
+	if s.Sanitize != nil {
+		in.Prompt = s.Sanitize(in.Prompt)
+		in.Response = s.Sanitize(in.Response)
+		in.Error = s.Sanitize(in.Error)
+	}
+
+	data, err := json.MarshalIndent(in, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(s.path(in.Key), append(data, '\n'), 0o644)
+}
+
+// Lookup returns the recorded interaction for a request, or ErrNotRecorded
+func (s *Store) Lookup(provider, model, prompt string) (Interaction, error) {
+	var in Interaction
+	key := Key(provider, model, prompt)
+	data, err := os.ReadFile(s.path(key))
+	if os.IsNotExist(err) {
+		return in, fmt.Errorf("%w (key %s in %s)", ErrNotRecorded, key, s.Dir)
+	}
+	if err != nil {
+		return in, err
+	}
+	if err := json.Unmarshal(data, &in); err != nil {
+		return in, fmt.Errorf("invalid fixture %s: %w", s.path(key), err)
+	}
+	return in, nil
+}
+
+// Replay returns the recorded answer for a request. A recorded failure is
+// returned as an error.
+func (s *Store) Replay(provider, model, prompt string) (string, error) {
+	in, err := s.Lookup(provider, model, prompt)
+	if err != nil {
+		return "", err
+	}
+	if in.Error != "" {
+		return in.Response, errors.New(in.Error)
+	}
+	return in.Response, nil
+}
+
+// Client answers from recorded fixtures. It implements ask.Client, so tests
+// can substitute it for a provider.
+type Client struct {
+	Store    *Store
+	Provider string
+	Model    string
+}
+
+// NewClient returns a replaying client for a fixture directory
+func NewClient(dir string) *Client {
+	return &Client{Store: &Store{Dir: dir}}
+}
+
+// Ask replays the answer to a prompt
+func (c *Client) Ask(ctx context.Context, prompt string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	return c.Store.Replay(c.Provider, c.Model, prompt)
+}
+
+// AskWithContext replays the answer to a prompt with piped context
+func (c *Client) AskWithContext(ctx context.Context, prompt, input string) (string, error) {
+	return c.Ask(ctx, RequestPrompt(prompt, input))
+}
+
+// AskWithTools replays the answer to a prompt; tools do not affect the key
+func (c *Client) AskWithTools(ctx context.Context, prompt string, tools []string) (string, error) {
+	return c.Ask(ctx, prompt)
+}
+
+// IsDaemonRunning always reports false; fixtures stand in for the daemon
+func (c *Client) IsDaemonRunning() bool {
+	return false
+}
+
+// RequestPrompt combines a prompt with piped input the way fixtures key it
+func RequestPrompt(prompt, input string) string {
+	if input == "" {
+		return prompt
+	}
+	return prompt + "\n\n" + input
+}